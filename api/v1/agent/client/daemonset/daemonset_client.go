@@ -41,6 +41,12 @@ type ClientService interface {
 
 	GetWorkloadendpoint(params *GetWorkloadendpointParams, opts ...ClientOption) (*GetWorkloadendpointOK, error)
 
+	PostCoordinatorGateway(params *PostCoordinatorGatewayParams, opts ...ClientOption) (*PostCoordinatorGatewayOK, error)
+
+	PostIpamConflict(params *PostIpamConflictParams, opts ...ClientOption) (*PostIpamConflictOK, error)
+
+	PostIpamGc(params *PostIpamGcParams, opts ...ClientOption) (*PostIpamGcOK, error)
+
 	PostIpamIP(params *PostIpamIPParams, opts ...ClientOption) (*PostIpamIPOK, error)
 
 	PostIpamIps(params *PostIpamIpsParams, opts ...ClientOption) (*PostIpamIpsOK, error)
@@ -208,6 +214,135 @@ func (a *Client) GetWorkloadendpoint(params *GetWorkloadendpointParams, opts ...
 	panic(msg)
 }
 
+/*
+	PostCoordinatorGateway reports an unreachable gateway to spiderpool daemon
+
+	Send a request to daemonset to report the gateway of a pod
+
+which is unreachable in the warn-only detection mode, an Event
+will be recorded on the pod
+*/
+func (a *Client) PostCoordinatorGateway(params *PostCoordinatorGatewayParams, opts ...ClientOption) (*PostCoordinatorGatewayOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewPostCoordinatorGatewayParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "PostCoordinatorGateway",
+		Method:             "POST",
+		PathPattern:        "/coordinator/gateway",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &PostCoordinatorGatewayReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*PostCoordinatorGatewayOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for PostCoordinatorGateway: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
+/*
+	PostIpamConflict reports an IP conflict to spiderpool daemon
+
+	Send a request to daemonset to report the IP address of a pod
+
+conflicting with another host, the IP address will be excluded
+from allocation until it is no longer in conflict
+*/
+func (a *Client) PostIpamConflict(params *PostIpamConflictParams, opts ...ClientOption) (*PostIpamConflictOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewPostIpamConflictParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "PostIpamConflict",
+		Method:             "POST",
+		PathPattern:        "/ipam/conflict",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &PostIpamConflictReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*PostIpamConflictOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for PostIpamConflict: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
+/*
+	PostIpamGc garbages collect the stale ip allocations
+
+	Send a request to daemonset to release the ip allocations of
+
+the local node whose pod sandbox is not in the valid attachments
+supplied by the container runtime
+*/
+func (a *Client) PostIpamGc(params *PostIpamGcParams, opts ...ClientOption) (*PostIpamGcOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewPostIpamGcParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "PostIpamGc",
+		Method:             "POST",
+		PathPattern:        "/ipam/gc",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &PostIpamGcReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*PostIpamGcOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for PostIpamGc: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 /*
 PostIpamIP gets ip from spiderpool daemon
 
@@ -10,6 +10,7 @@ package models
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/strfmt"
@@ -25,18 +26,33 @@ type CoordinatorConfig struct {
 	// detect gateway
 	DetectGateway bool `json:"detectGateway,omitempty"`
 
+	// detect gateway options
+	DetectGatewayOptions *DetectGatewayConfig `json:"detectGatewayOptions,omitempty"`
+
 	// detect IP conflict
 	DetectIPConflict bool `json:"detectIPConflict,omitempty"`
 
 	// extra c ID r
 	ExtraCIDR []string `json:"extraCIDR"`
 
+	// gratuitous neighbor
+	GratuitousNeighbor *GratuitousNeighborConfig `json:"gratuitousNeighbor,omitempty"`
+
+	// the smallest MTU of the host's routes to the Pod and Service CIDRs
+	HostMTU int64 `json:"hostMTU,omitempty"`
+
 	// host r p filter
 	HostRPFilter int64 `json:"hostRPFilter,omitempty"`
 
 	// host rule table
 	HostRuleTable int64 `json:"hostRuleTable,omitempty"`
 
+	// mtu
+	Mtu string `json:"mtu,omitempty"`
+
+	// pod bandwidth
+	PodBandwidth []*PodBandwidth `json:"podBandwidth"`
+
 	// pod c ID r
 	// Required: true
 	PodCIDR []string `json:"podCIDR"`
@@ -47,6 +63,12 @@ type CoordinatorConfig struct {
 	// pod m a c prefix
 	PodMACPrefix string `json:"podMACPrefix,omitempty"`
 
+	// pod override
+	PodOverride *PodCoordinatorOverride `json:"podOverride,omitempty"`
+
+	// pod routes
+	PodRoutes []*PodRoute `json:"podRoutes"`
+
 	// service c ID r
 	// Required: true
 	ServiceCIDR []string `json:"serviceCIDR"`
@@ -64,10 +86,30 @@ type CoordinatorConfig struct {
 func (m *CoordinatorConfig) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateDetectGatewayOptions(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateGratuitousNeighbor(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validatePodBandwidth(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validatePodCIDR(formats); err != nil {
 		res = append(res, err)
 	}
 
+	if err := m.validatePodOverride(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validatePodRoutes(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateServiceCIDR(formats); err != nil {
 		res = append(res, err)
 	}
@@ -86,6 +128,70 @@ func (m *CoordinatorConfig) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *CoordinatorConfig) validateDetectGatewayOptions(formats strfmt.Registry) error {
+	if swag.IsZero(m.DetectGatewayOptions) { // not required
+		return nil
+	}
+
+	if m.DetectGatewayOptions != nil {
+		if err := m.DetectGatewayOptions.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("detectGatewayOptions")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("detectGatewayOptions")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *CoordinatorConfig) validateGratuitousNeighbor(formats strfmt.Registry) error {
+	if swag.IsZero(m.GratuitousNeighbor) { // not required
+		return nil
+	}
+
+	if m.GratuitousNeighbor != nil {
+		if err := m.GratuitousNeighbor.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("gratuitousNeighbor")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("gratuitousNeighbor")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *CoordinatorConfig) validatePodBandwidth(formats strfmt.Registry) error {
+	if swag.IsZero(m.PodBandwidth) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.PodBandwidth); i++ {
+		if swag.IsZero(m.PodBandwidth[i]) { // not required
+			continue
+		}
+
+		if m.PodBandwidth[i] != nil {
+			if err := m.PodBandwidth[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("podBandwidth" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("podBandwidth" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
 func (m *CoordinatorConfig) validatePodCIDR(formats strfmt.Registry) error {
 
 	if err := validate.Required("podCIDR", "body", m.PodCIDR); err != nil {
@@ -95,6 +201,51 @@ func (m *CoordinatorConfig) validatePodCIDR(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *CoordinatorConfig) validatePodOverride(formats strfmt.Registry) error {
+	if swag.IsZero(m.PodOverride) { // not required
+		return nil
+	}
+
+	if m.PodOverride != nil {
+		if err := m.PodOverride.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("podOverride")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("podOverride")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *CoordinatorConfig) validatePodRoutes(formats strfmt.Registry) error {
+	if swag.IsZero(m.PodRoutes) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.PodRoutes); i++ {
+		if swag.IsZero(m.PodRoutes[i]) { // not required
+			continue
+		}
+
+		if m.PodRoutes[i] != nil {
+			if err := m.PodRoutes[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("podRoutes" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("podRoutes" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
 func (m *CoordinatorConfig) validateServiceCIDR(formats strfmt.Registry) error {
 
 	if err := validate.Required("serviceCIDR", "body", m.ServiceCIDR); err != nil {
@@ -122,8 +273,121 @@ func (m *CoordinatorConfig) validateTunePodRoutes(formats strfmt.Registry) error
 	return nil
 }
 
-// ContextValidate validates this coordinator config based on context it is used
+// ContextValidate validate this coordinator config based on the context it is used
 func (m *CoordinatorConfig) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.contextValidateDetectGatewayOptions(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidateGratuitousNeighbor(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidatePodBandwidth(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidatePodOverride(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidatePodRoutes(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *CoordinatorConfig) contextValidateDetectGatewayOptions(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.DetectGatewayOptions != nil {
+		if err := m.DetectGatewayOptions.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("detectGatewayOptions")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("detectGatewayOptions")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *CoordinatorConfig) contextValidateGratuitousNeighbor(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.GratuitousNeighbor != nil {
+		if err := m.GratuitousNeighbor.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("gratuitousNeighbor")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("gratuitousNeighbor")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *CoordinatorConfig) contextValidatePodBandwidth(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.PodBandwidth); i++ {
+
+		if m.PodBandwidth[i] != nil {
+			if err := m.PodBandwidth[i].ContextValidate(ctx, formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("podBandwidth" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("podBandwidth" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *CoordinatorConfig) contextValidatePodOverride(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.PodOverride != nil {
+		if err := m.PodOverride.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("podOverride")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("podOverride")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *CoordinatorConfig) contextValidatePodRoutes(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.PodRoutes); i++ {
+
+		if m.PodRoutes[i] != nil {
+			if err := m.PodRoutes[i].ContextValidate(ctx, formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("podRoutes" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("podRoutes" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
 	return nil
 }
 
@@ -33,6 +33,9 @@ type IPConfig struct {
 	// ip pool
 	IPPool string `json:"ipPool,omitempty"`
 
+	// the MAC address allocated with the IP address
+	Mac string `json:"mac,omitempty"`
+
 	// nic
 	// Required: true
 	Nic *string `json:"nic"`
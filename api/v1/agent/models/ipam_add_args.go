@@ -39,10 +39,22 @@ type IpamAddArgs struct {
 	// Required: true
 	IfName *string `json:"ifName"`
 
+	// the static IP addresses requested for the interface, at most one per IP version
+	Ips []string `json:"ips"`
+
+	// the MAC address of the interface, which is recorded in the audit log and derives the EUI-64 IPv6 address
+	Mac string `json:"mac,omitempty"`
+
 	// net namespace
 	// Required: true
 	NetNamespace *string `json:"netNamespace"`
 
+	// the name of the CNI network the interface is attached to
+	Network string `json:"network,omitempty"`
+
+	// the owner of the standalone container, which is not a Pod and has an empty podName
+	Owner string `json:"owner,omitempty"`
+
 	// pod name
 	// Required: true
 	PodName *string `json:"podName"`
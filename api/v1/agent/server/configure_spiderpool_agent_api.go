@@ -83,6 +83,21 @@ func configureAPI(api *restapi.SpiderpoolAgentAPIAPI) http.Handler {
 			return middleware.NotImplemented("operation daemonset.GetWorkloadendpoint has not yet been implemented")
 		})
 	}
+	if api.DaemonsetPostCoordinatorGatewayHandler == nil {
+		api.DaemonsetPostCoordinatorGatewayHandler = daemonset.PostCoordinatorGatewayHandlerFunc(func(params daemonset.PostCoordinatorGatewayParams) middleware.Responder {
+			return middleware.NotImplemented("operation daemonset.PostCoordinatorGateway has not yet been implemented")
+		})
+	}
+	if api.DaemonsetPostIpamConflictHandler == nil {
+		api.DaemonsetPostIpamConflictHandler = daemonset.PostIpamConflictHandlerFunc(func(params daemonset.PostIpamConflictParams) middleware.Responder {
+			return middleware.NotImplemented("operation daemonset.PostIpamConflict has not yet been implemented")
+		})
+	}
+	if api.DaemonsetPostIpamGcHandler == nil {
+		api.DaemonsetPostIpamGcHandler = daemonset.PostIpamGcHandlerFunc(func(params daemonset.PostIpamGcParams) middleware.Responder {
+			return middleware.NotImplemented("operation daemonset.PostIpamGc has not yet been implemented")
+		})
+	}
 	if api.DaemonsetPostIpamIPHandler == nil {
 		api.DaemonsetPostIpamIPHandler = daemonset.PostIpamIPHandlerFunc(func(params daemonset.PostIpamIPParams) middleware.Responder {
 			return middleware.NotImplemented("operation daemonset.PostIpamIP has not yet been implemented")
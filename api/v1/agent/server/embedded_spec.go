@@ -69,6 +69,99 @@ func init() {
         }
       }
     },
+    "/coordinator/gateway": {
+      "post": {
+        "description": "Send a request to daemonset to report the gateway of a pod\nwhich is unreachable in the warn-only detection mode, an Event\nwill be recorded on the pod\n",
+        "tags": [
+          "daemonset"
+        ],
+        "summary": "Report an unreachable gateway to spiderpool daemon",
+        "parameters": [
+          {
+            "name": "gateway-unreachable-args",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/GatewayUnreachableArgs"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success"
+          },
+          "500": {
+            "description": "Report failure",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failure"
+          }
+        }
+      }
+    },
+    "/ipam/conflict": {
+      "post": {
+        "description": "Send a request to daemonset to report the IP address of a pod\nconflicting with another host, the IP address will be excluded\nfrom allocation until it is no longer in conflict\n",
+        "tags": [
+          "daemonset"
+        ],
+        "summary": "Report an IP conflict to spiderpool daemon",
+        "parameters": [
+          {
+            "name": "ipam-conflict-args",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/IpamConflictArgs"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success"
+          },
+          "500": {
+            "description": "Report failure",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failure"
+          }
+        }
+      }
+    },
+    "/ipam/gc": {
+      "post": {
+        "description": "Send a request to daemonset to release the ip allocations of\nthe local node whose pod sandbox is not in the valid attachments\nsupplied by the container runtime\n",
+        "tags": [
+          "daemonset"
+        ],
+        "summary": "Garbage collect the stale ip allocations",
+        "parameters": [
+          {
+            "name": "ipam-gc-args",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/IpamGCArgs"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success"
+          },
+          "500": {
+            "description": "Garbage collection failure",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failure"
+          }
+        }
+      }
+    },
     "/ipam/healthy": {
       "get": {
         "description": "Check spiderpool daemonset health to make sure whether it's ready\nfor CNI plugin usage\n",
@@ -272,6 +365,9 @@ func init() {
         "detectGateway": {
           "type": "boolean"
         },
+        "detectGatewayOptions": {
+          "$ref": "#/definitions/DetectGatewayConfig"
+        },
         "detectIPConflict": {
           "type": "boolean"
         },
@@ -281,12 +377,28 @@ func init() {
             "type": "string"
           }
         },
+        "gratuitousNeighbor": {
+          "$ref": "#/definitions/GratuitousNeighborConfig"
+        },
+        "hostMTU": {
+          "description": "the smallest MTU of the host's routes to the Pod and Service CIDRs",
+          "type": "integer"
+        },
         "hostRPFilter": {
           "type": "integer"
         },
         "hostRuleTable": {
           "type": "integer"
         },
+        "mtu": {
+          "type": "string"
+        },
+        "podBandwidth": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/PodBandwidth"
+          }
+        },
         "podCIDR": {
           "type": "array",
           "items": {
@@ -299,6 +411,15 @@ func init() {
         "podMACPrefix": {
           "type": "string"
         },
+        "podOverride": {
+          "$ref": "#/definitions/PodCoordinatorOverride"
+        },
+        "podRoutes": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/PodRoute"
+          }
+        },
         "serviceCIDR": {
           "type": "array",
           "items": {
@@ -340,10 +461,56 @@ func init() {
         }
       }
     },
+    "DetectGatewayConfig": {
+      "description": "Gateway detection config",
+      "type": "object",
+      "properties": {
+        "lossTolerance": {
+          "type": "integer"
+        },
+        "method": {
+          "type": "string"
+        },
+        "warnOnly": {
+          "type": "boolean"
+        }
+      }
+    },
     "Error": {
       "description": "API error",
       "type": "string"
     },
+    "GatewayUnreachableArgs": {
+      "description": "Unreachable gateway information of a pod interface",
+      "type": "object",
+      "required": [
+        "ifName",
+        "podNamespace",
+        "podName",
+        "podUID",
+        "gateway"
+      ],
+      "properties": {
+        "gateway": {
+          "type": "string"
+        },
+        "ifName": {
+          "type": "string"
+        },
+        "message": {
+          "type": "string"
+        },
+        "podName": {
+          "type": "string"
+        },
+        "podNamespace": {
+          "type": "string"
+        },
+        "podUID": {
+          "type": "string"
+        }
+      }
+    },
     "GetCoordinatorArgs": {
       "description": "Get Coordinator Args",
       "type": "object",
@@ -356,6 +523,21 @@ func init() {
         }
       }
     },
+    "GratuitousNeighborConfig": {
+      "description": "Gratuitous ARP and unsolicited neighbor advertisement config",
+      "type": "object",
+      "properties": {
+        "count": {
+          "type": "integer"
+        },
+        "enabled": {
+          "type": "boolean"
+        },
+        "interval": {
+          "type": "string"
+        }
+      }
+    },
     "IpConfig": {
       "description": "IPAM IPs struct, contains ifName, Address and Gateway",
       "type": "object",
@@ -374,6 +556,10 @@ func init() {
         "ipPool": {
           "type": "string"
         },
+        "mac": {
+          "description": "the MAC address allocated with the IP address",
+          "type": "string"
+        },
         "nic": {
           "type": "string"
         },
@@ -422,9 +608,28 @@ func init() {
         "ifName": {
           "type": "string"
         },
+        "ips": {
+          "description": "the static IP addresses requested for the interface, at most one per IP version",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "mac": {
+          "description": "the MAC address of the interface, which is recorded in the audit log and derives the EUI-64 IPv6 address",
+          "type": "string"
+        },
         "netNamespace": {
           "type": "string"
         },
+        "network": {
+          "description": "the name of the CNI network the interface is attached to",
+          "type": "string"
+        },
+        "owner": {
+          "description": "the owner of the standalone container, which is not a Pod and has an empty podName",
+          "type": "string"
+        },
         "podName": {
           "type": "string"
         },
@@ -461,6 +666,54 @@ func init() {
         }
       }
     },
+    "IpamAttachment": {
+      "description": "An attachment of a container to the network",
+      "type": "object",
+      "required": [
+        "containerID",
+        "ifName"
+      ],
+      "properties": {
+        "containerID": {
+          "type": "string"
+        },
+        "ifName": {
+          "type": "string"
+        }
+      }
+    },
+    "IpamConflictArgs": {
+      "description": "IPAM IP conflict information",
+      "type": "object",
+      "required": [
+        "ifName",
+        "podNamespace",
+        "podName",
+        "podUID",
+        "ip",
+        "mac"
+      ],
+      "properties": {
+        "ifName": {
+          "type": "string"
+        },
+        "ip": {
+          "type": "string"
+        },
+        "mac": {
+          "type": "string"
+        },
+        "podName": {
+          "type": "string"
+        },
+        "podNamespace": {
+          "type": "string"
+        },
+        "podUID": {
+          "type": "string"
+        }
+      }
+    },
     "IpamDelArgs": {
       "description": "IPAM release IP information",
       "type": "object",
@@ -492,6 +745,99 @@ func init() {
         }
       }
     },
+    "IpamGCArgs": {
+      "description": "IPAM garbage collection args",
+      "type": "object",
+      "required": [
+        "network",
+        "validAttachments"
+      ],
+      "properties": {
+        "network": {
+          "description": "the name of the CNI network being collected",
+          "type": "string"
+        },
+        "validAttachments": {
+          "description": "the attachments which are still valid for the network",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/IpamAttachment"
+          }
+        }
+      }
+    },
+    "PodBandwidth": {
+      "description": "Bandwidth of the interface of Pod applied by coordinator",
+      "type": "object",
+      "properties": {
+        "dscp": {
+          "type": "integer",
+          "x-nullable": true
+        },
+        "egressRate": {
+          "type": "string"
+        },
+        "ifName": {
+          "type": "string"
+        },
+        "ingressRate": {
+          "type": "string"
+        }
+      }
+    },
+    "PodCoordinatorOverride": {
+      "description": "Coordinator config overridden by Pod annotation",
+      "type": "object",
+      "properties": {
+        "detectGateway": {
+          "type": "boolean",
+          "x-nullable": true
+        },
+        "detectIPConflict": {
+          "type": "boolean",
+          "x-nullable": true
+        },
+        "extraCIDR": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "podDefaultRouteNIC": {
+          "type": "string",
+          "x-nullable": true
+        }
+      }
+    },
+    "PodRoute": {
+      "description": "Custom route of Pod installed by coordinator",
+      "type": "object",
+      "properties": {
+        "dst": {
+          "type": "string"
+        },
+        "gw": {
+          "type": "string"
+        },
+        "ifName": {
+          "type": "string"
+        },
+        "metric": {
+          "type": "integer",
+          "x-nullable": true
+        },
+        "policy": {
+          "type": "boolean"
+        },
+        "scope": {
+          "type": "string"
+        },
+        "table": {
+          "type": "integer",
+          "x-nullable": true
+        }
+      }
+    },
     "Route": {
       "description": "IPAM CNI types Route",
       "type": "object",
@@ -566,6 +912,99 @@ func init() {
         }
       }
     },
+    "/coordinator/gateway": {
+      "post": {
+        "description": "Send a request to daemonset to report the gateway of a pod\nwhich is unreachable in the warn-only detection mode, an Event\nwill be recorded on the pod\n",
+        "tags": [
+          "daemonset"
+        ],
+        "summary": "Report an unreachable gateway to spiderpool daemon",
+        "parameters": [
+          {
+            "name": "gateway-unreachable-args",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/GatewayUnreachableArgs"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success"
+          },
+          "500": {
+            "description": "Report failure",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failure"
+          }
+        }
+      }
+    },
+    "/ipam/conflict": {
+      "post": {
+        "description": "Send a request to daemonset to report the IP address of a pod\nconflicting with another host, the IP address will be excluded\nfrom allocation until it is no longer in conflict\n",
+        "tags": [
+          "daemonset"
+        ],
+        "summary": "Report an IP conflict to spiderpool daemon",
+        "parameters": [
+          {
+            "name": "ipam-conflict-args",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/IpamConflictArgs"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success"
+          },
+          "500": {
+            "description": "Report failure",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failure"
+          }
+        }
+      }
+    },
+    "/ipam/gc": {
+      "post": {
+        "description": "Send a request to daemonset to release the ip allocations of\nthe local node whose pod sandbox is not in the valid attachments\nsupplied by the container runtime\n",
+        "tags": [
+          "daemonset"
+        ],
+        "summary": "Garbage collect the stale ip allocations",
+        "parameters": [
+          {
+            "name": "ipam-gc-args",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/IpamGCArgs"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success"
+          },
+          "500": {
+            "description": "Garbage collection failure",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failure"
+          }
+        }
+      }
+    },
     "/ipam/healthy": {
       "get": {
         "description": "Check spiderpool daemonset health to make sure whether it's ready\nfor CNI plugin usage\n",
@@ -769,6 +1208,9 @@ func init() {
         "detectGateway": {
           "type": "boolean"
         },
+        "detectGatewayOptions": {
+          "$ref": "#/definitions/DetectGatewayConfig"
+        },
         "detectIPConflict": {
           "type": "boolean"
         },
@@ -778,12 +1220,28 @@ func init() {
             "type": "string"
           }
         },
+        "gratuitousNeighbor": {
+          "$ref": "#/definitions/GratuitousNeighborConfig"
+        },
+        "hostMTU": {
+          "description": "the smallest MTU of the host's routes to the Pod and Service CIDRs",
+          "type": "integer"
+        },
         "hostRPFilter": {
           "type": "integer"
         },
         "hostRuleTable": {
           "type": "integer"
         },
+        "mtu": {
+          "type": "string"
+        },
+        "podBandwidth": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/PodBandwidth"
+          }
+        },
         "podCIDR": {
           "type": "array",
           "items": {
@@ -796,6 +1254,15 @@ func init() {
         "podMACPrefix": {
           "type": "string"
         },
+        "podOverride": {
+          "$ref": "#/definitions/PodCoordinatorOverride"
+        },
+        "podRoutes": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/PodRoute"
+          }
+        },
         "serviceCIDR": {
           "type": "array",
           "items": {
@@ -837,10 +1304,56 @@ func init() {
         }
       }
     },
+    "DetectGatewayConfig": {
+      "description": "Gateway detection config",
+      "type": "object",
+      "properties": {
+        "lossTolerance": {
+          "type": "integer"
+        },
+        "method": {
+          "type": "string"
+        },
+        "warnOnly": {
+          "type": "boolean"
+        }
+      }
+    },
     "Error": {
       "description": "API error",
       "type": "string"
     },
+    "GatewayUnreachableArgs": {
+      "description": "Unreachable gateway information of a pod interface",
+      "type": "object",
+      "required": [
+        "ifName",
+        "podNamespace",
+        "podName",
+        "podUID",
+        "gateway"
+      ],
+      "properties": {
+        "gateway": {
+          "type": "string"
+        },
+        "ifName": {
+          "type": "string"
+        },
+        "message": {
+          "type": "string"
+        },
+        "podName": {
+          "type": "string"
+        },
+        "podNamespace": {
+          "type": "string"
+        },
+        "podUID": {
+          "type": "string"
+        }
+      }
+    },
     "GetCoordinatorArgs": {
       "description": "Get Coordinator Args",
       "type": "object",
@@ -853,6 +1366,21 @@ func init() {
         }
       }
     },
+    "GratuitousNeighborConfig": {
+      "description": "Gratuitous ARP and unsolicited neighbor advertisement config",
+      "type": "object",
+      "properties": {
+        "count": {
+          "type": "integer"
+        },
+        "enabled": {
+          "type": "boolean"
+        },
+        "interval": {
+          "type": "string"
+        }
+      }
+    },
     "IpConfig": {
       "description": "IPAM IPs struct, contains ifName, Address and Gateway",
       "type": "object",
@@ -871,6 +1399,10 @@ func init() {
         "ipPool": {
           "type": "string"
         },
+        "mac": {
+          "description": "the MAC address allocated with the IP address",
+          "type": "string"
+        },
         "nic": {
           "type": "string"
         },
@@ -919,9 +1451,28 @@ func init() {
         "ifName": {
           "type": "string"
         },
+        "ips": {
+          "description": "the static IP addresses requested for the interface, at most one per IP version",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "mac": {
+          "description": "the MAC address of the interface, which is recorded in the audit log and derives the EUI-64 IPv6 address",
+          "type": "string"
+        },
         "netNamespace": {
           "type": "string"
         },
+        "network": {
+          "description": "the name of the CNI network the interface is attached to",
+          "type": "string"
+        },
+        "owner": {
+          "description": "the owner of the standalone container, which is not a Pod and has an empty podName",
+          "type": "string"
+        },
         "podName": {
           "type": "string"
         },
@@ -958,6 +1509,54 @@ func init() {
         }
       }
     },
+    "IpamAttachment": {
+      "description": "An attachment of a container to the network",
+      "type": "object",
+      "required": [
+        "containerID",
+        "ifName"
+      ],
+      "properties": {
+        "containerID": {
+          "type": "string"
+        },
+        "ifName": {
+          "type": "string"
+        }
+      }
+    },
+    "IpamConflictArgs": {
+      "description": "IPAM IP conflict information",
+      "type": "object",
+      "required": [
+        "ifName",
+        "podNamespace",
+        "podName",
+        "podUID",
+        "ip",
+        "mac"
+      ],
+      "properties": {
+        "ifName": {
+          "type": "string"
+        },
+        "ip": {
+          "type": "string"
+        },
+        "mac": {
+          "type": "string"
+        },
+        "podName": {
+          "type": "string"
+        },
+        "podNamespace": {
+          "type": "string"
+        },
+        "podUID": {
+          "type": "string"
+        }
+      }
+    },
     "IpamDelArgs": {
       "description": "IPAM release IP information",
       "type": "object",
@@ -989,6 +1588,99 @@ func init() {
         }
       }
     },
+    "IpamGCArgs": {
+      "description": "IPAM garbage collection args",
+      "type": "object",
+      "required": [
+        "network",
+        "validAttachments"
+      ],
+      "properties": {
+        "network": {
+          "description": "the name of the CNI network being collected",
+          "type": "string"
+        },
+        "validAttachments": {
+          "description": "the attachments which are still valid for the network",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/IpamAttachment"
+          }
+        }
+      }
+    },
+    "PodBandwidth": {
+      "description": "Bandwidth of the interface of Pod applied by coordinator",
+      "type": "object",
+      "properties": {
+        "dscp": {
+          "type": "integer",
+          "x-nullable": true
+        },
+        "egressRate": {
+          "type": "string"
+        },
+        "ifName": {
+          "type": "string"
+        },
+        "ingressRate": {
+          "type": "string"
+        }
+      }
+    },
+    "PodCoordinatorOverride": {
+      "description": "Coordinator config overridden by Pod annotation",
+      "type": "object",
+      "properties": {
+        "detectGateway": {
+          "type": "boolean",
+          "x-nullable": true
+        },
+        "detectIPConflict": {
+          "type": "boolean",
+          "x-nullable": true
+        },
+        "extraCIDR": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "podDefaultRouteNIC": {
+          "type": "string",
+          "x-nullable": true
+        }
+      }
+    },
+    "PodRoute": {
+      "description": "Custom route of Pod installed by coordinator",
+      "type": "object",
+      "properties": {
+        "dst": {
+          "type": "string"
+        },
+        "gw": {
+          "type": "string"
+        },
+        "ifName": {
+          "type": "string"
+        },
+        "metric": {
+          "type": "integer",
+          "x-nullable": true
+        },
+        "policy": {
+          "type": "boolean"
+        },
+        "scope": {
+          "type": "string"
+        },
+        "table": {
+          "type": "integer",
+          "x-nullable": true
+        }
+      }
+    },
     "Route": {
       "description": "IPAM CNI types Route",
       "type": "object",
@@ -73,6 +73,15 @@ func NewSpiderpoolAgentAPIAPI(spec *loads.Document) *SpiderpoolAgentAPIAPI {
 		DaemonsetGetWorkloadendpointHandler: daemonset.GetWorkloadendpointHandlerFunc(func(params daemonset.GetWorkloadendpointParams) middleware.Responder {
 			return middleware.NotImplemented("operation daemonset.GetWorkloadendpoint has not yet been implemented")
 		}),
+		DaemonsetPostCoordinatorGatewayHandler: daemonset.PostCoordinatorGatewayHandlerFunc(func(params daemonset.PostCoordinatorGatewayParams) middleware.Responder {
+			return middleware.NotImplemented("operation daemonset.PostCoordinatorGateway has not yet been implemented")
+		}),
+		DaemonsetPostIpamConflictHandler: daemonset.PostIpamConflictHandlerFunc(func(params daemonset.PostIpamConflictParams) middleware.Responder {
+			return middleware.NotImplemented("operation daemonset.PostIpamConflict has not yet been implemented")
+		}),
+		DaemonsetPostIpamGcHandler: daemonset.PostIpamGcHandlerFunc(func(params daemonset.PostIpamGcParams) middleware.Responder {
+			return middleware.NotImplemented("operation daemonset.PostIpamGc has not yet been implemented")
+		}),
 		DaemonsetPostIpamIPHandler: daemonset.PostIpamIPHandlerFunc(func(params daemonset.PostIpamIPParams) middleware.Responder {
 			return middleware.NotImplemented("operation daemonset.PostIpamIP has not yet been implemented")
 		}),
@@ -131,6 +140,12 @@ type SpiderpoolAgentAPIAPI struct {
 	RuntimeGetRuntimeStartupHandler runtimeops.GetRuntimeStartupHandler
 	// DaemonsetGetWorkloadendpointHandler sets the operation handler for the get workloadendpoint operation
 	DaemonsetGetWorkloadendpointHandler daemonset.GetWorkloadendpointHandler
+	// DaemonsetPostCoordinatorGatewayHandler sets the operation handler for the post coordinator gateway operation
+	DaemonsetPostCoordinatorGatewayHandler daemonset.PostCoordinatorGatewayHandler
+	// DaemonsetPostIpamConflictHandler sets the operation handler for the post ipam conflict operation
+	DaemonsetPostIpamConflictHandler daemonset.PostIpamConflictHandler
+	// DaemonsetPostIpamGcHandler sets the operation handler for the post ipam gc operation
+	DaemonsetPostIpamGcHandler daemonset.PostIpamGcHandler
 	// DaemonsetPostIpamIPHandler sets the operation handler for the post ipam IP operation
 	DaemonsetPostIpamIPHandler daemonset.PostIpamIPHandler
 	// DaemonsetPostIpamIpsHandler sets the operation handler for the post ipam ips operation
@@ -236,6 +251,15 @@ func (o *SpiderpoolAgentAPIAPI) Validate() error {
 	if o.DaemonsetGetWorkloadendpointHandler == nil {
 		unregistered = append(unregistered, "daemonset.GetWorkloadendpointHandler")
 	}
+	if o.DaemonsetPostCoordinatorGatewayHandler == nil {
+		unregistered = append(unregistered, "daemonset.PostCoordinatorGatewayHandler")
+	}
+	if o.DaemonsetPostIpamConflictHandler == nil {
+		unregistered = append(unregistered, "daemonset.PostIpamConflictHandler")
+	}
+	if o.DaemonsetPostIpamGcHandler == nil {
+		unregistered = append(unregistered, "daemonset.PostIpamGcHandler")
+	}
 	if o.DaemonsetPostIpamIPHandler == nil {
 		unregistered = append(unregistered, "daemonset.PostIpamIPHandler")
 	}
@@ -365,6 +389,18 @@ func (o *SpiderpoolAgentAPIAPI) initHandlerCache() {
 	if o.handlers["POST"] == nil {
 		o.handlers["POST"] = make(map[string]http.Handler)
 	}
+	o.handlers["POST"]["/coordinator/gateway"] = daemonset.NewPostCoordinatorGateway(o.context, o.DaemonsetPostCoordinatorGatewayHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/ipam/conflict"] = daemonset.NewPostIpamConflict(o.context, o.DaemonsetPostIpamConflictHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/ipam/gc"] = daemonset.NewPostIpamGc(o.context, o.DaemonsetPostIpamGcHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
 	o.handlers["POST"]["/ipam/ip"] = daemonset.NewPostIpamIP(o.context, o.DaemonsetPostIpamIPHandler)
 	if o.handlers["POST"] == nil {
 		o.handlers["POST"] = make(map[string]http.Handler)
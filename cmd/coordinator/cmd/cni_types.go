@@ -6,20 +6,26 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/containernetworking/cni/pkg/types"
 	"github.com/containernetworking/cni/pkg/version"
 	"github.com/go-openapi/strfmt"
+	"golang.org/x/sys/unix"
 	"k8s.io/utils/pointer"
 
 	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
+	"github.com/spidernet-io/spiderpool/pkg/networking/gwconnection"
+	"github.com/spidernet-io/spiderpool/pkg/networking/networking"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
 )
 
 var (
@@ -29,6 +35,7 @@ var (
 	defaultOverlayVethName = "eth0"
 	defaultPodRuleTable    = 100
 	defaultNICPrefix       = "net"
+	defaultVethMTU         = 1500
 	BinNamePlugin          = filepath.Base(os.Args[0])
 )
 
@@ -40,6 +47,12 @@ const (
 	ModeDisable  Mode = "disable"
 )
 
+// MTUAuto sets the MTU of the veth pair to the smaller one of the Pod's
+// interface and the host's routes to the cluster, and the MTU of the Pod's
+// interface to the MTU of its master interface minus the overhead of the
+// VLAN and overlay devices under it
+const MTUAuto = "auto"
+
 type Config struct {
 	types.NetConf
 	OnlyHardware       bool           `json:"onlyHardware,omitempty"`
@@ -58,6 +71,14 @@ type Config struct {
 	IPConflict         *bool          `json:"detectIPConflict,omitempty"`
 	DetectOptions      *DetectOptions `json:"detectOptions,omitempty"`
 	LogOptions         *LogOptions    `json:"logOptions,omitempty"`
+	TraceOptions       *TraceOptions  `json:"traceOptions,omitempty"`
+
+	GratuitousNeighbor   *GratuitousNeighborOptions `json:"gratuitousNeighbor,omitempty"`
+	DetectGatewayOptions *DetectGatewayOptions      `json:"detectGatewayOptions,omitempty"`
+	Bandwidth            *BandwidthOptions          `json:"bandwidth,omitempty"`
+	VRF                  *VRFOptions                `json:"vrf,omitempty"`
+	MTU                  string                     `json:"mtu,omitempty"`
+	PodMTU               string                     `json:"podMTU,omitempty"`
 }
 
 // DetectOptions enable ip conflicting check for pod's ip
@@ -67,6 +88,38 @@ type DetectOptions struct {
 	TimeOut  string `json:"timeout,omitempty"`
 }
 
+// DetectGatewayOptions selects the method to detect the gateways, and
+// whether the unreachable gateways fail the pod
+type DetectGatewayOptions struct {
+	Method        string `json:"method,omitempty"`
+	LossTolerance *int   `json:"lossTolerance,omitempty"`
+	WarnOnly      *bool  `json:"warnOnly,omitempty"`
+}
+
+// GratuitousNeighborOptions enable sending gratuitous arp and unsolicited
+// neighbor advertisement for pod's ip after setup
+type GratuitousNeighborOptions struct {
+	Enabled  *bool  `json:"enabled,omitempty"`
+	Count    int    `json:"count,omitempty"`
+	Interval string `json:"interval,omitempty"`
+}
+
+// BandwidthOptions limits the rates of the interface in bits per second,
+// and marks the DSCP of its egress packets
+type BandwidthOptions struct {
+	IngressRate string `json:"ingressRate,omitempty"`
+	EgressRate  string `json:"egressRate,omitempty"`
+	DSCP        *int   `json:"dscp,omitempty"`
+}
+
+// VRFOptions enslaves the interface to a VRF with its own route table,
+// the table 0 means the table of the existing VRF or the rule table of
+// the interface
+type VRFOptions struct {
+	Name  string `json:"name"`
+	Table int    `json:"table,omitempty"`
+}
+
 type LogOptions struct {
 	LogLevel        string `json:"logLevel"`
 	LogFilePath     string `json:"logFile"`
@@ -75,6 +128,15 @@ type LogOptions struct {
 	LogFileMaxCount int    `json:"logMaxCount"`
 }
 
+// TraceOptions exports the spans of coordinator, the stdout exporter is
+// not supported since the stdout is reserved for the CNI result.
+type TraceOptions struct {
+	Exporter string `json:"exporter,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+	Insecure bool   `json:"insecure,omitempty"`
+	FilePath string `json:"filePath,omitempty"`
+}
+
 const (
 	CniVersion030 = "0.3.0"
 	CniVersion031 = "0.3.1"
@@ -125,6 +187,14 @@ func ParseConfig(stdin []byte, coordinatorConfig *models.CoordinatorConfig) (*Co
 		conf.LogOptions.LogFilePath = defaultLogPath
 	}
 
+	if conf.TraceOptions == nil {
+		conf.TraceOptions = &TraceOptions{}
+	}
+
+	if conf.TraceOptions.Exporter == tracing.ExporterStdout {
+		return nil, fmt.Errorf("unsupported trace exporter %s, expect '%s' or '%s'", conf.TraceOptions.Exporter, tracing.ExporterOTLP, tracing.ExporterFile)
+	}
+
 	if conf.MacPrefix == "" {
 		conf.MacPrefix = coordinatorConfig.PodMACPrefix
 	}
@@ -151,6 +221,32 @@ func ParseConfig(stdin []byte, coordinatorConfig *models.CoordinatorConfig) (*Co
 		return nil, err
 	}
 
+	conf.GratuitousNeighbor, err = ValidateGratuitousNeighborOptions(conf.GratuitousNeighbor, coordinatorConfig.GratuitousNeighbor)
+	if err != nil {
+		return nil, err
+	}
+
+	conf.DetectGatewayOptions, err = ValidateDetectGatewayOptions(conf.DetectGatewayOptions, coordinatorConfig.DetectGatewayOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.MTU == "" {
+		conf.MTU = coordinatorConfig.Mtu
+	}
+
+	if err = validateMTU(conf.MTU); err != nil {
+		return nil, err
+	}
+
+	if err = validateMTU(conf.PodMTU); err != nil {
+		return nil, err
+	}
+
+	if err = ValidateVRFOptions(conf.VRF); err != nil {
+		return nil, err
+	}
+
 	if conf.HostRuleTable == nil && coordinatorConfig.HostRuleTable > 0 {
 		conf.HostRuleTable = pointer.Int64(coordinatorConfig.HostRuleTable)
 	}
@@ -175,9 +271,42 @@ func ParseConfig(stdin []byte, coordinatorConfig *models.CoordinatorConfig) (*Co
 		conf.PodDefaultRouteNIC = coordinatorConfig.PodDefaultRouteNIC
 	}
 
+	if err = overrideByPod(&conf, coordinatorConfig.PodOverride); err != nil {
+		return nil, err
+	}
+
 	return &conf, nil
 }
 
+// overrideByPod applies the config from the Pod annotation, which takes
+// precedence over both the SpiderCoordinator and the CNI config.
+func overrideByPod(conf *Config, override *models.PodCoordinatorOverride) error {
+	if override == nil {
+		return nil
+	}
+
+	if override.DetectGateway != nil {
+		conf.DetectGateway = pointer.Bool(*override.DetectGateway)
+	}
+
+	if override.DetectIPConflict != nil {
+		conf.IPConflict = pointer.Bool(*override.DetectIPConflict)
+	}
+
+	if override.PodDefaultRouteNIC != nil {
+		conf.PodDefaultRouteNIC = *override.PodDefaultRouteNIC
+	}
+
+	if len(override.ExtraCIDR) != 0 {
+		if err := validateRoutes(override.ExtraCIDR); err != nil {
+			return err
+		}
+		conf.ExtraCIDR = append(conf.ExtraCIDR, override.ExtraCIDR...)
+	}
+
+	return nil
+}
+
 func validateHwPrefix(prefix string) error {
 	if prefix == "" {
 		return nil
@@ -288,3 +417,161 @@ func ValidateDelectOptions(config *DetectOptions) (*DetectOptions, error) {
 
 	return config, nil
 }
+
+func ValidateGratuitousNeighborOptions(config *GratuitousNeighborOptions, coordinatorConfig *models.GratuitousNeighborConfig) (*GratuitousNeighborOptions, error) {
+	if config == nil {
+		config = &GratuitousNeighborOptions{}
+	}
+
+	if coordinatorConfig != nil {
+		if config.Enabled == nil {
+			config.Enabled = pointer.Bool(coordinatorConfig.Enabled)
+		}
+		if config.Count == 0 {
+			config.Count = int(coordinatorConfig.Count)
+		}
+		if config.Interval == "" {
+			config.Interval = coordinatorConfig.Interval
+		}
+	}
+
+	if config.Enabled == nil {
+		config.Enabled = pointer.Bool(false)
+	}
+
+	if config.Count == 0 {
+		config.Count = 3
+	}
+
+	if config.Count < 0 {
+		return nil, fmt.Errorf("invalid gratuitousNeighbor.count %d, it must be positive", config.Count)
+	}
+
+	if config.Interval == "" {
+		config.Interval = "100ms"
+	}
+
+	_, err := time.ParseDuration(config.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gratuitousNeighbor.interval %s: %v, input like: 100ms or 1s", config.Interval, err)
+	}
+
+	return config, nil
+}
+
+func ValidateDetectGatewayOptions(config *DetectGatewayOptions, coordinatorConfig *models.DetectGatewayConfig) (*DetectGatewayOptions, error) {
+	if config == nil {
+		config = &DetectGatewayOptions{}
+	}
+
+	if coordinatorConfig != nil {
+		if config.Method == "" {
+			config.Method = coordinatorConfig.Method
+		}
+		if config.LossTolerance == nil {
+			config.LossTolerance = pointer.Int(int(coordinatorConfig.LossTolerance))
+		}
+		if config.WarnOnly == nil {
+			config.WarnOnly = pointer.Bool(coordinatorConfig.WarnOnly)
+		}
+	}
+
+	switch config.Method {
+	case "":
+		config.Method = gwconnection.MethodICMP
+	case gwconnection.MethodICMP, gwconnection.MethodARP, gwconnection.MethodNDP, gwconnection.MethodAny:
+	default:
+		return nil, fmt.Errorf("invalid detectGatewayOptions.method %s, available options: [%s,%s,%s,%s]", config.Method,
+			gwconnection.MethodICMP, gwconnection.MethodARP, gwconnection.MethodNDP, gwconnection.MethodAny)
+	}
+
+	if config.LossTolerance == nil {
+		config.LossTolerance = pointer.Int(0)
+	}
+
+	if *config.LossTolerance < 0 || *config.LossTolerance > 99 {
+		return nil, fmt.Errorf("invalid detectGatewayOptions.lossTolerance %d, it must be in the range of 0 to 99", *config.LossTolerance)
+	}
+
+	if config.WarnOnly == nil {
+		config.WarnOnly = pointer.Bool(false)
+	}
+
+	return config, nil
+}
+
+// validateMTU checks the mtu is empty, auto or in the range of 68 to 65535
+func validateMTU(mtu string) error {
+	if mtu == "" || mtu == MTUAuto {
+		return nil
+	}
+
+	v, err := strconv.Atoi(mtu)
+	if err != nil || v < 68 || v > 65535 {
+		return fmt.Errorf("invalid mtu %s, it must be %s or in the range of 68 to 65535", mtu, MTUAuto)
+	}
+	return nil
+}
+
+// getBandwidth returns the bandwidth of the interface, the Pod annotation
+// takes precedence over the CNI config. It returns nil if there is nothing
+// to apply.
+func getBandwidth(config *BandwidthOptions, podBandwidth []*models.PodBandwidth, ifName string) (*BandwidthOptions, error) {
+	bandwidth := &BandwidthOptions{}
+	if config != nil {
+		*bandwidth = *config
+	}
+
+	for _, b := range podBandwidth {
+		if b == nil || b.IfName != ifName {
+			continue
+		}
+		if b.IngressRate != "" {
+			bandwidth.IngressRate = b.IngressRate
+		}
+		if b.EgressRate != "" {
+			bandwidth.EgressRate = b.EgressRate
+		}
+		if b.Dscp != nil {
+			bandwidth.DSCP = pointer.Int(int(*b.Dscp))
+		}
+	}
+
+	if bandwidth.IngressRate == "" && bandwidth.EgressRate == "" && bandwidth.DSCP == nil {
+		return nil, nil
+	}
+
+	if bandwidth.IngressRate != "" {
+		if _, err := networking.ParseBandwidthRate(bandwidth.IngressRate); err != nil {
+			return nil, fmt.Errorf("invalid bandwidth.ingressRate: %v", err)
+		}
+	}
+
+	if bandwidth.EgressRate != "" {
+		if _, err := networking.ParseBandwidthRate(bandwidth.EgressRate); err != nil {
+			return nil, fmt.Errorf("invalid bandwidth.egressRate: %v", err)
+		}
+	}
+
+	if bandwidth.DSCP != nil && (*bandwidth.DSCP < 0 || *bandwidth.DSCP > 63) {
+		return nil, fmt.Errorf("invalid bandwidth.dscp %d, it must be in the range of 0 to 63", *bandwidth.DSCP)
+	}
+
+	return bandwidth, nil
+}
+
+func ValidateVRFOptions(config *VRFOptions) error {
+	if config == nil {
+		return nil
+	}
+
+	if config.Name == "" || len(config.Name) > 15 || strings.ContainsAny(config.Name, "/: \t\n") {
+		return fmt.Errorf("invalid vrf.name %q, it must be a valid interface name of 1 to 15 characters", config.Name)
+	}
+
+	if config.Table < 0 || int64(config.Table) > math.MaxUint32 || config.Table == unix.RT_TABLE_DEFAULT || config.Table == unix.RT_TABLE_MAIN || config.Table == unix.RT_TABLE_LOCAL {
+		return fmt.Errorf("invalid vrf.table %d, it must be a 32-bit unsigned integer and not the reserved table default(253), main(254) or local(255)", config.Table)
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
+	"github.com/spidernet-io/spiderpool/pkg/networking/gwconnection"
+)
+
+var _ = Describe("CNI types", Label("cni_types_test"), func() {
+	Describe("ValidateGratuitousNeighborOptions", func() {
+		It("sets the default options", func() {
+			options, err := ValidateGratuitousNeighborOptions(nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(options).To(Equal(&GratuitousNeighborOptions{
+				Enabled:  pointer.Bool(false),
+				Count:    3,
+				Interval: "100ms",
+			}))
+		})
+
+		It("inherits the options from the coordinator config", func() {
+			options, err := ValidateGratuitousNeighborOptions(nil, &models.GratuitousNeighborConfig{
+				Enabled:  true,
+				Count:    5,
+				Interval: "1s",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(options).To(Equal(&GratuitousNeighborOptions{
+				Enabled:  pointer.Bool(true),
+				Count:    5,
+				Interval: "1s",
+			}))
+		})
+
+		It("prefers the options of the CNI config to the coordinator config", func() {
+			options, err := ValidateGratuitousNeighborOptions(
+				&GratuitousNeighborOptions{Enabled: pointer.Bool(false), Count: 2, Interval: "200ms"},
+				&models.GratuitousNeighborConfig{Enabled: true, Count: 5, Interval: "1s"},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(options).To(Equal(&GratuitousNeighborOptions{
+				Enabled:  pointer.Bool(false),
+				Count:    2,
+				Interval: "200ms",
+			}))
+		})
+
+		It("fails with the negative count", func() {
+			_, err := ValidateGratuitousNeighborOptions(&GratuitousNeighborOptions{Count: -1}, nil)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("fails with the invalid interval", func() {
+			_, err := ValidateGratuitousNeighborOptions(&GratuitousNeighborOptions{Interval: "1"}, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ValidateDetectGatewayOptions", func() {
+		It("sets the default options", func() {
+			options, err := ValidateDetectGatewayOptions(nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(options).To(Equal(&DetectGatewayOptions{
+				Method:        gwconnection.MethodICMP,
+				LossTolerance: pointer.Int(0),
+				WarnOnly:      pointer.Bool(false),
+			}))
+		})
+
+		It("inherits the options from the coordinator config", func() {
+			options, err := ValidateDetectGatewayOptions(nil, &models.DetectGatewayConfig{
+				Method:        gwconnection.MethodARP,
+				LossTolerance: 50,
+				WarnOnly:      true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(options).To(Equal(&DetectGatewayOptions{
+				Method:        gwconnection.MethodARP,
+				LossTolerance: pointer.Int(50),
+				WarnOnly:      pointer.Bool(true),
+			}))
+		})
+
+		It("prefers the options of the CNI config to the coordinator config", func() {
+			options, err := ValidateDetectGatewayOptions(
+				&DetectGatewayOptions{Method: gwconnection.MethodAny, LossTolerance: pointer.Int(0), WarnOnly: pointer.Bool(false)},
+				&models.DetectGatewayConfig{Method: gwconnection.MethodNDP, LossTolerance: 50, WarnOnly: true},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(options).To(Equal(&DetectGatewayOptions{
+				Method:        gwconnection.MethodAny,
+				LossTolerance: pointer.Int(0),
+				WarnOnly:      pointer.Bool(false),
+			}))
+		})
+
+		It("fails with the unknown method", func() {
+			_, err := ValidateDetectGatewayOptions(&DetectGatewayOptions{Method: "tcp"}, nil)
+			Expect(err).To(HaveOccurred())
+		})
+
+		DescribeTable("checks the range of loss tolerance",
+			func(lossTolerance int, valid bool) {
+				_, err := ValidateDetectGatewayOptions(&DetectGatewayOptions{LossTolerance: pointer.Int(lossTolerance)}, nil)
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+			Entry("negative", -1, false),
+			Entry("min", 0, true),
+			Entry("max", 99, true),
+			Entry("all probes lost", 100, false),
+		)
+	})
+
+	Describe("ValidateVRFOptions", func() {
+		It("does nothing if VRF is disabled", func() {
+			Expect(ValidateVRFOptions(nil)).To(Succeed())
+		})
+
+		DescribeTable("checks the options",
+			func(options VRFOptions, valid bool) {
+				err := ValidateVRFOptions(&options)
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+			Entry("the table of the existing VRF or the rule table", VRFOptions{Name: "vrf-blue"}, true),
+			Entry("min table", VRFOptions{Name: "vrf-blue", Table: 1}, true),
+			Entry("table below the reserved tables", VRFOptions{Name: "vrf-blue", Table: 252}, true),
+			Entry("table above the reserved tables", VRFOptions{Name: "vrf-blue", Table: 256}, true),
+			Entry("max table", VRFOptions{Name: "vrf-blue", Table: math.MaxUint32}, true),
+			Entry("negative table", VRFOptions{Name: "vrf-blue", Table: -1}, false),
+			Entry("table beyond 32 bits", VRFOptions{Name: "vrf-blue", Table: math.MaxUint32 + 1}, false),
+			Entry("reserved table default", VRFOptions{Name: "vrf-blue", Table: 253}, false),
+			Entry("reserved table main", VRFOptions{Name: "vrf-blue", Table: 254}, false),
+			Entry("reserved table local", VRFOptions{Name: "vrf-blue", Table: 255}, false),
+			Entry("max length of name", VRFOptions{Name: "vrf-0123456789a"}, true),
+			Entry("empty name", VRFOptions{}, false),
+			Entry("too long name", VRFOptions{Name: "vrf-0123456789ab"}, false),
+			Entry("name with slash", VRFOptions{Name: "vrf/blue"}, false),
+			Entry("name with colon", VRFOptions{Name: "vrf:blue"}, false),
+			Entry("name with space", VRFOptions{Name: "vrf blue"}, false),
+		)
+	})
+
+	Describe("validateMTU", func() {
+		DescribeTable("checks the mtu",
+			func(mtu string, valid bool) {
+				err := validateMTU(mtu)
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+			Entry("default", "", true),
+			Entry("auto", MTUAuto, true),
+			Entry("min", "68", true),
+			Entry("jumbo frame", "9000", true),
+			Entry("max", "65535", true),
+			Entry("below min", "67", false),
+			Entry("beyond max", "65536", false),
+			Entry("negative", "-1500", false),
+			Entry("not a number", "jumbo", false),
+			Entry("upper case auto", "AUTO", false),
+		)
+	})
+})
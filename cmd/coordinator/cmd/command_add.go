@@ -5,8 +5,11 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"golang.org/x/sync/errgroup"
+	"net/netip"
+	"sync"
 	"time"
 
 	"github.com/containernetworking/cni/pkg/skel"
@@ -14,7 +17,10 @@ import (
 	current "github.com/containernetworking/cni/pkg/types/100"
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/vishvananda/netlink"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+	"k8s.io/utils/pointer"
 
 	"github.com/spidernet-io/spiderpool/api/v1/agent/client/daemonset"
 	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
@@ -26,6 +32,7 @@ import (
 	"github.com/spidernet-io/spiderpool/pkg/networking/ipchecking"
 	"github.com/spidernet-io/spiderpool/pkg/networking/networking"
 	"github.com/spidernet-io/spiderpool/pkg/networking/sysctl"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
 )
 
 func CmdAdd(args *skel.CmdArgs) (err error) {
@@ -77,6 +84,34 @@ func CmdAdd(args *skel.CmdArgs) (err error) {
 	)
 	logger.Info(fmt.Sprintf("start to implement ADD command in %v mode", conf.TuneMode))
 
+	shutdownTracing, err := tracing.InitTracing(context.Background(), BinNamePlugin, tracing.Config{
+		Exporter:    conf.TraceOptions.Exporter,
+		Endpoint:    conf.TraceOptions.Endpoint,
+		Insecure:    conf.TraceOptions.Insecure,
+		FilePath:    conf.TraceOptions.FilePath,
+		SampleRatio: 1,
+	})
+	if err != nil {
+		// tracing is best-effort, never fail the pod creation because of it
+		logger.Warn("failed to init tracing, continue without it", zap.Error(err))
+		shutdownTracing = func(context.Context) error { return nil }
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracing.CNIShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Warn("failed to flush spans", zap.Error(err))
+		}
+	}()
+
+	traceCtx, span := tracing.Start(context.Background(), "coordinator ADD",
+		attribute.String("k8s.pod.namespace", string(k8sArgs.K8S_POD_NAMESPACE)),
+		attribute.String("k8s.pod.name", string(k8sArgs.K8S_POD_NAME)),
+		attribute.String("cni.ifname", args.IfName),
+		attribute.String("coordinator.mode", string(conf.TuneMode)),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	// parse prevResult
 	prevResult, err := current.GetResult(conf.PrevResult)
 	if err != nil {
@@ -97,6 +132,7 @@ func CmdAdd(args *skel.CmdArgs) (err error) {
 		currentInterface: args.IfName,
 		tuneMode:         conf.TuneMode,
 		interfacePrefix:  conf.InterfacePrefix,
+		hostMTU:          int(coordinatorConfig.HostMTU),
 	}
 	c.HijackCIDR = append(c.HijackCIDR, conf.ServiceCIDR...)
 	c.HijackCIDR = append(c.HijackCIDR, conf.ExtraCIDR...)
@@ -115,19 +151,47 @@ func CmdAdd(args *skel.CmdArgs) (err error) {
 		return err
 	}
 
+	// the MTU of the pod's interface is set before the veth pair, whose MTU
+	// follows it in auto mode
+	if conf.PodMTU != "" {
+		var podMTU int
+		podMTU, err = c.setupPodMTU(conf.PodMTU)
+		if err != nil {
+			logger.Error("failed to setupPodMTU", zap.Error(err))
+			return err
+		}
+		logger.Debug("Setup the MTU of pod's interface successfully", zap.String("interface", args.IfName), zap.Int("mtu", podMTU))
+	}
+
 	// get basic info
 	switch conf.TuneMode {
 	case ModeUnderlay:
 		c.podVethName = defaultUnderlayVethName
 		c.hostVethName = getHostVethName(args.ContainerID)
 		if c.firstInvoke {
-			err = c.setupVeth(args.ContainerID)
+			c.vethMTU, err = c.getVethMTU(conf.MTU, c.hostMTU)
+			if err != nil {
+				logger.Error("failed to getVethMTU", zap.Error(err))
+				return err
+			}
+
+			_, vethSpan := tracing.Start(traceCtx, "coordinator setup veth")
+			vethSpan.SetAttributes(attribute.Int("coordinator.veth_mtu", c.vethMTU))
+			err = c.setupVeth(args.ContainerID, c.vethMTU)
+			tracing.End(vethSpan, err)
 			if err != nil {
 				logger.Error("failed to create veth-pair device", zap.Error(err))
 				return err
 			}
-			logger.Debug("Setup veth-pair device successfully", zap.String("hostVethPairName", getHostVethName(args.ContainerID)),
+			logger.Debug("Setup veth-pair device successfully", zap.String("hostVethPairName", getHostVethName(args.ContainerID)), zap.Int("mtu", c.vethMTU),
 				zap.String("hostVethMac", c.hostVethHwAddress.String()), zap.String("podVethMac", c.podVethHwAddress.String()))
+		} else {
+			// the veth pair is created by the first invoke
+			c.vethMTU, err = c.getPodLinkMTU(c.podVethName)
+			if err != nil {
+				logger.Error("failed to get the MTU of veth-pair device", zap.Error(err))
+				return err
+			}
 		}
 	case ModeOverlay:
 		c.podVethName = defaultOverlayVethName
@@ -146,9 +210,14 @@ func CmdAdd(args *skel.CmdArgs) (err error) {
 
 	logger.Sugar().Infof("Get coordinator config: %v", c)
 
-	errg, ctx := errgroup.WithContext(context.Background())
+	detectCtx, detectSpan := tracing.Start(traceCtx, "coordinator detect gateway and IP conflict")
+	errg, ctx := errgroup.WithContext(detectCtx)
 	defer ctx.Done()
 
+	// the unreachable gateways tolerated in the warn-only mode
+	unreachableGateways := map[string]string{}
+	var unreachableGatewaysLock sync.Mutex
+
 	//  we do detect gateway connection firstly
 	if *conf.DetectGateway {
 		logger.Debug("Try to detect gateway")
@@ -163,33 +232,98 @@ func CmdAdd(args *skel.CmdArgs) (err error) {
 			return nil
 		})
 		if err != nil {
+			tracing.End(detectSpan, err)
 			return err
 		}
 
 		logger.Debug("Get GetDefaultGatewayByName", zap.Strings("Gws", gws))
+		detectSpan.SetAttributes(attribute.StringSlice("coordinator.gateways", gws))
+
+		detectOptions := gwconnection.DetectOptions{
+			Method:        conf.DetectGatewayOptions.Method,
+			Count:         conf.DetectOptions.Retry,
+			LossTolerance: *conf.DetectGatewayOptions.LossTolerance,
+		}
+		detectOptions.Interval, _ = time.ParseDuration(conf.DetectOptions.Interval)
+		detectOptions.Timeout, _ = time.ParseDuration(conf.DetectOptions.TimeOut)
+		detectSpan.SetAttributes(attribute.String("coordinator.gateway_detection_method", detectOptions.Method))
 
 		for _, gw := range gws {
-			p, err := gwconnection.NewPinger(conf.DetectOptions.Retry, conf.DetectOptions.Interval, conf.DetectOptions.TimeOut, gw, logger)
+			gwAddr, err := netip.ParseAddr(gw)
 			if err != nil {
-				return fmt.Errorf("failed to run NewPinger: %v", err)
+				tracing.End(detectSpan, err)
+				return fmt.Errorf("failed to parse gateway %s: %w", gw, err)
 			}
-			errg.Go(p.DetectGateway)
+
+			errg.Go(func() error {
+				err := c.netns.Do(func(netNS ns.NetNS) error {
+					return gwconnection.Detect(c.currentInterface, gwAddr, detectOptions, logger)
+				})
+				if err != nil && *conf.DetectGatewayOptions.WarnOnly {
+					// the pod is set up anyway, an Event is recorded
+					// on it instead
+					logger.Warn("Gateway is unreachable, ignore it in the warn-only mode", zap.String("gateway", gwAddr.String()), zap.Error(err))
+					unreachableGatewaysLock.Lock()
+					unreachableGateways[gwAddr.String()] = err.Error()
+					unreachableGatewaysLock.Unlock()
+					return nil
+				}
+				return err
+			})
 		}
 	}
 
 	if conf.IPConflict != nil && *conf.IPConflict {
 		ipc, err := ipchecking.NewIPChecker(c.ipFamily, conf.DetectOptions.Retry, c.currentInterface, conf.DetectOptions.Interval, conf.DetectOptions.TimeOut, c.netns, logger)
 		if err != nil {
+			tracing.End(detectSpan, err)
 			return fmt.Errorf("failed to run NewIPChecker: %w", err)
 		}
 		ipc.DoIPConflictChecking(prevResult.IPs, errg)
 	}
 
-	if err = errg.Wait(); err != nil {
+	err = errg.Wait()
+	tracing.End(detectSpan, err)
+	if err != nil {
 		logger.Error("failed to ip checking", zap.Error(err))
+
+		var conflictErr *ipchecking.IPConflictError
+		if errors.As(err, &conflictErr) {
+			// report the conflicting IP so that it is excluded from allocation,
+			// the retry of CNI ADD will get another IP address.
+			_, reportErr := client.Daemonset.PostIpamConflict(daemonset.NewPostIpamConflictParams().WithContext(traceCtx).WithIpamConflictArgs(
+				&models.IpamConflictArgs{
+					IfName:       &args.IfName,
+					PodName:      (*string)(&k8sArgs.K8S_POD_NAME),
+					PodNamespace: (*string)(&k8sArgs.K8S_POD_NAMESPACE),
+					PodUID:       (*string)(&k8sArgs.K8S_POD_UID),
+					IP:           pointer.String(conflictErr.IP.String()),
+					Mac:          &conflictErr.MAC,
+				},
+			))
+			if reportErr != nil {
+				logger.Error("failed to report ip conflict to spiderpool-agent", zap.Error(reportErr))
+			}
+		}
 		return fmt.Errorf("failed to ip checking: %w", err)
 	}
 
+	for gw, reason := range unreachableGateways {
+		_, reportErr := client.Daemonset.PostCoordinatorGateway(daemonset.NewPostCoordinatorGatewayParams().WithContext(traceCtx).WithGatewayUnreachableArgs(
+			&models.GatewayUnreachableArgs{
+				IfName:       &args.IfName,
+				PodName:      (*string)(&k8sArgs.K8S_POD_NAME),
+				PodNamespace: (*string)(&k8sArgs.K8S_POD_NAMESPACE),
+				PodUID:       (*string)(&k8sArgs.K8S_POD_UID),
+				Gateway:      pointer.String(gw),
+				Message:      reason,
+			},
+		))
+		if reportErr != nil {
+			logger.Error("failed to report unreachable gateway to spiderpool-agent", zap.String("gateway", gw), zap.Error(reportErr))
+		}
+	}
+
 	// overwrite mac address
 	if len(conf.MacPrefix) != 0 {
 		hwAddr, err := networking.OverwriteHwAddress(logger, c.netns, conf.MacPrefix, args.IfName)
@@ -198,12 +332,46 @@ func CmdAdd(args *skel.CmdArgs) (err error) {
 		}
 
 		logger.Info("Override hardware address successfully", zap.String("interface", args.IfName), zap.String("hardware address", hwAddr))
-		if conf.OnlyHardware {
-			logger.Debug("Only override hardware address, exit now")
-			return types.PrintResult(conf.PrevResult, conf.CNIVersion)
+	}
+
+	// the bandwidth is shaped on the pod's interface, which is also done if
+	// only the hardware address is overridden
+	var bandwidth *BandwidthOptions
+	bandwidth, err = getBandwidth(conf.Bandwidth, coordinatorConfig.PodBandwidth, args.IfName)
+	if err != nil {
+		logger.Error("failed to getBandwidth", zap.Error(err))
+		return err
+	}
+	if bandwidth != nil {
+		logger.Debug("Try to setup bandwidth", zap.Any("bandwidth", bandwidth))
+		if err = c.setupBandwidth(logger, bandwidth); err != nil {
+			logger.Error("failed to setupBandwidth", zap.Error(err))
+			return fmt.Errorf("failed to setupBandwidth: %v", err)
+		}
+	}
+
+	// the ip may be re-used by pod with another mac address, so refresh the
+	// stale neighbor entries in switches once the pod's network is configured
+	announceIPs := func() {
+		if !*conf.GratuitousNeighbor.Enabled {
+			return
+		}
+
+		logger.Debug("Try to announce pod's ips", zap.Int("count", conf.GratuitousNeighbor.Count), zap.String("interval", conf.GratuitousNeighbor.Interval))
+		_, announceSpan := tracing.Start(traceCtx, "coordinator announce IPs")
+		announceErr := ipchecking.AnnounceIPs(c.netns, args.IfName, prevResult.IPs, conf.GratuitousNeighbor.Count, conf.GratuitousNeighbor.Interval, logger)
+		tracing.End(announceSpan, announceErr)
+		if announceErr != nil {
+			logger.Warn("failed to announce pod's ips", zap.Error(announceErr))
 		}
 	}
 
+	if len(conf.MacPrefix) != 0 && conf.OnlyHardware {
+		logger.Debug("Only override hardware address, exit now")
+		announceIPs()
+		return types.PrintResult(conf.PrevResult, conf.CNIVersion)
+	}
+
 	// get all ip address on the node
 	c.hostAddress, err = networking.IPAddressOnNode(logger, ipFamily)
 	if err != nil {
@@ -235,6 +403,46 @@ func CmdAdd(args *skel.CmdArgs) (err error) {
 		}
 	}
 
+	// the remaining steps tune the neighbors, rules and routes of the pod
+	_, routeSpan := tracing.Start(traceCtx, "coordinator setup routes")
+	defer func() { tracing.End(routeSpan, err) }()
+
+	if conf.VRF != nil {
+		// the interface is isolated from the cluster in the VRF, so the
+		// neighbors, the hijack routes and the pod routes are not tuned
+		if c.currentInterface == conf.PodFirstInterface {
+			logger.Error("the pod's first interface can't be enslaved to VRF", zap.String("vrf", conf.VRF.Name))
+			return fmt.Errorf("the pod's first interface %s can't be enslaved to VRF %s", c.currentInterface, conf.VRF.Name)
+		}
+
+		c.currentRuleTable = c.getRuleNumber(c.currentInterface)
+		if conf.VRF.Table == 0 && c.currentRuleTable < 0 {
+			logger.Error("failed to getRuleNumber for the table of VRF, vrf.table must be specified",
+				zap.String("currentInterface", c.currentInterface), zap.String("interfacePrefix", c.interfacePrefix))
+			return fmt.Errorf("failed to getRuleNumber for the table of VRF %s, vrf.table must be specified", conf.VRF.Name)
+		}
+
+		logger.Debug("Try to setup VRF", zap.String("vrf", conf.VRF.Name))
+		var vrfTable int
+		vrfTable, err = c.setupVRF(logger, conf.VRF)
+		if err != nil {
+			logger.Error("failed to setupVRF", zap.Error(err))
+			return fmt.Errorf("failed to setupVRF: %v", err)
+		}
+
+		if len(coordinatorConfig.PodRoutes) != 0 {
+			logger.Debug("Try to setup custom routes", zap.Int("table", vrfTable))
+			if err = c.setupCustomRoutes(logger, coordinatorConfig.PodRoutes, vrfTable); err != nil {
+				logger.Error("failed to setupCustomRoutes", zap.Error(err))
+				return fmt.Errorf("failed to setupCustomRoutes: %v", err)
+			}
+		}
+
+		announceIPs()
+		logger.Sugar().Infof("coordinator end, time cost: %v", time.Since(startTime))
+		return types.PrintResult(conf.PrevResult, conf.CNIVersion)
+	}
+
 	if err = c.setupNeighborhood(logger); err != nil {
 		logger.Error("failed to setupNeighborhood", zap.Error(err))
 		return err
@@ -257,6 +465,9 @@ func CmdAdd(args *skel.CmdArgs) (err error) {
 		return fmt.Errorf("failed to setupHijackRoutes: %v", err)
 	}
 
+	// the routes of current interface are in main table, unless they are
+	// moved to the rule table by tuning pod routes
+	customRouteTable := unix.RT_TABLE_MAIN
 	if conf.TunePodRoutes != nil && *conf.TunePodRoutes && (!c.firstInvoke || c.tuneMode == ModeOverlay) {
 		logger.Debug("Try to tune pod routes")
 		if err = c.tunePodRoutes(logger, conf.PodDefaultRouteNIC); err != nil {
@@ -264,8 +475,22 @@ func CmdAdd(args *skel.CmdArgs) (err error) {
 			return fmt.Errorf("failed to tunePodRoutes: %v", err)
 		}
 		logger.Debug("Success to tune pod routes")
+
+		if conf.PodDefaultRouteNIC != "" && conf.PodDefaultRouteNIC != c.currentInterface {
+			customRouteTable = c.currentRuleTable
+		}
 	}
 
+	if len(coordinatorConfig.PodRoutes) != 0 {
+		logger.Debug("Try to setup custom routes", zap.Int("table", customRouteTable))
+		if err = c.setupCustomRoutes(logger, coordinatorConfig.PodRoutes, customRouteTable); err != nil {
+			logger.Error("failed to setupCustomRoutes", zap.Error(err))
+			return fmt.Errorf("failed to setupCustomRoutes: %v", err)
+		}
+	}
+
+	announceIPs()
+
 	logger.Sugar().Infof("coordinator end, time cost: %v", time.Since(startTime))
 	return types.PrintResult(conf.PrevResult, conf.CNIVersion)
 }
@@ -99,6 +99,40 @@ func CmdDel(args *skel.CmdArgs) (err error) {
 				}
 			}
 		}
+
+		bandwidth, err := getBandwidth(conf.Bandwidth, coordinatorConfig.PodBandwidth, args.IfName)
+		if err != nil {
+			// ignore err
+			logger.Warn("failed to getBandwidth, ignore error", zap.Error(err))
+		} else if bandwidth != nil {
+			err = c.netns.Do(func(netNS ns.NetNS) error {
+				if err := networking.CleanBandwidth(args.IfName); err != nil {
+					return err
+				}
+				if bandwidth.DSCP != nil {
+					return networking.CleanDSCP(args.IfName, netlink.FAMILY_ALL)
+				}
+				return nil
+			})
+			if err != nil {
+				// ignore err
+				logger.Warn("failed to clean bandwidth, ignore error", zap.Error(err))
+			}
+		}
+
+		if conf.VRF != nil {
+			var deleted bool
+			err = c.netns.Do(func(netNS ns.NetNS) error {
+				deleted, err = networking.DelVRFIfUnused(conf.VRF.Name, args.IfName)
+				return err
+			})
+			if err != nil {
+				// ignore err
+				logger.Warn("failed to DelVRFIfUnused, ignore error", zap.String("vrf", conf.VRF.Name), zap.Error(err))
+			} else if deleted {
+				logger.Debug("success to del VRF", zap.String("vrf", conf.VRF.Name))
+			}
+		}
 	}
 
 	if conf.TuneMode == ModeUnderlay {
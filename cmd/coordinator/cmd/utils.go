@@ -6,6 +6,7 @@ package cmd
 import (
 	"fmt"
 	"net"
+	"os"
 	"strconv"
 	"strings"
 
@@ -15,6 +16,8 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/sys/unix"
 
+	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
+	"github.com/spidernet-io/spiderpool/pkg/constant"
 	"github.com/spidernet-io/spiderpool/pkg/networking/networking"
 )
 
@@ -27,6 +30,9 @@ type coordinator struct {
 	netns                                                        ns.NetNS
 	hostVethHwAddress, podVethHwAddress                          net.HardwareAddr
 	hostAddress, currentAddress                                  []netlink.Addr
+	// the MTU of the veth pair and the host's routes to the cluster, they
+	// are 0 if unknown
+	vethMTU, hostMTU int
 }
 
 // firstInvoke check if coordinator is first called and do some checks:
@@ -98,11 +104,11 @@ func (c *coordinator) getRuleNumber(iface string) int {
 
 // setupVeth sets up a pair of virtual ethernet devices. move one to the host and other
 // one to container.
-func (c *coordinator) setupVeth(containerID string) error {
+func (c *coordinator) setupVeth(containerID string, mtu int) error {
 	var containerInterface net.Interface
 	err := c.netns.Do(func(hostNS ns.NetNS) error {
 		var err error
-		_, containerInterface, err = ip.SetupVethWithName(c.podVethName, getHostVethName(containerID), 1500, "", hostNS)
+		_, containerInterface, err = ip.SetupVethWithName(c.podVethName, getHostVethName(containerID), mtu, "", hostNS)
 		if err != nil {
 			return err
 		}
@@ -121,6 +127,105 @@ func (c *coordinator) setupVeth(containerID string) error {
 	return err
 }
 
+// getVethMTU returns the MTU of the veth pair, see vethMTU.
+func (c *coordinator) getVethMTU(mtu string, hostMTU int) (int, error) {
+	var podMTU int
+	if mtu == MTUAuto {
+		var err error
+		podMTU, err = c.getPodLinkMTU(c.currentInterface)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return vethMTU(mtu, podMTU, hostMTU)
+}
+
+// vethMTU returns the MTU of the veth pair. With mtu auto, it is the smaller
+// one of current interface and the host's routes to the cluster, so that the
+// traffic to the cluster is not fragmented by the overlay or the node's NIC.
+func vethMTU(mtu string, podMTU, hostMTU int) (int, error) {
+	switch mtu {
+	case "":
+		return defaultVethMTU, nil
+	case MTUAuto:
+		if hostMTU > 0 && hostMTU < podMTU {
+			return hostMTU, nil
+		}
+		return podMTU, nil
+	default:
+		return strconv.Atoi(mtu)
+	}
+}
+
+// hijackRouteMTU returns the MTU of the routes to the cluster via the veth
+// pair, which is the smaller one of the veth pair and the host's routes to
+// the cluster. It returns 0 if the MTU of the veth pair is unknown.
+func (c *coordinator) hijackRouteMTU() int {
+	if c.hostMTU > 0 && c.hostMTU < c.vethMTU {
+		return c.hostMTU
+	}
+	return c.vethMTU
+}
+
+// getPodLinkMTU returns the MTU of the interface in the pod.
+func (c *coordinator) getPodLinkMTU(iface string) (int, error) {
+	var mtu int
+	err := c.netns.Do(func(_ ns.NetNS) error {
+		link, err := netlink.LinkByName(iface)
+		if err != nil {
+			return err
+		}
+		mtu = link.Attrs().MTU
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the MTU of %s: %v", iface, err)
+	}
+	return mtu, nil
+}
+
+// setupPodMTU sets the MTU of current interface. With mtu auto, it is the
+// MTU of its master interface on the host minus the overhead of the VLAN and
+// overlay devices under the master interface.
+func (c *coordinator) setupPodMTU(mtu string) (int, error) {
+	var link netlink.Link
+	err := c.netns.Do(func(_ ns.NetNS) error {
+		var err error
+		link, err = netlink.LinkByName(c.currentInterface)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s: %v", c.currentInterface, err)
+	}
+
+	var podMTU int
+	if mtu == MTUAuto {
+		// the master of macvlan and ipvlan interfaces is in the host netns
+		if link.Attrs().ParentIndex == 0 {
+			return 0, fmt.Errorf("%s has no master interface, its mtu can't be %s", c.currentInterface, MTUAuto)
+		}
+		podMTU, err = networking.GetMasterMTU(link.Attrs().ParentIndex)
+	} else {
+		podMTU, err = strconv.Atoi(mtu)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if podMTU == link.Attrs().MTU {
+		return podMTU, nil
+	}
+
+	err = c.netns.Do(func(_ ns.NetNS) error {
+		return netlink.LinkSetMTU(link, podMTU)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to set the MTU of %s to %d: %v", c.currentInterface, podMTU, err)
+	}
+	return podMTU, nil
+}
+
 // setupNeighborhood setup neighborhood tables for pod and host.
 // equivalent to: `ip neigh add ....`
 func (c *coordinator) setupNeighborhood(logger *zap.Logger) error {
@@ -185,13 +290,13 @@ func (c *coordinator) setupHijackRoutes(logger *zap.Logger, ruleTable int) error
 				return err
 			}
 
-			if err := networking.AddRoute(logger, ruleTable, netlink.SCOPE_UNIVERSE, c.podVethName, ipNet, v4Gw, v6Gw); err != nil {
+			if err := networking.AddRoute(logger, ruleTable, netlink.SCOPE_UNIVERSE, c.podVethName, ipNet, v4Gw, v6Gw, c.hijackRouteMTU()); err != nil {
 				logger.Error("failed to AddRoute for hijackCIDR", zap.String("Dst", ipNet.String()), zap.Error(err))
 				return fmt.Errorf("failed to AddRoute for hijackCIDR: %v", err)
 			}
 
 			if c.tuneMode == ModeOverlay && c.firstInvoke {
-				if err := networking.AddRoute(logger, unix.RT_TABLE_MAIN, netlink.SCOPE_UNIVERSE, c.podVethName, ipNet, v4Gw, v6Gw); err != nil {
+				if err := networking.AddRoute(logger, unix.RT_TABLE_MAIN, netlink.SCOPE_UNIVERSE, c.podVethName, ipNet, v4Gw, v6Gw, c.hijackRouteMTU()); err != nil {
 					logger.Error("failed to AddRoute for hijackCIDR", zap.String("Dst", ipNet.String()), zap.Error(err))
 					return fmt.Errorf("failed to AddRoute for hijackCIDR: %v", err)
 				}
@@ -215,13 +320,13 @@ func (c *coordinator) setupHostRoutes(logger *zap.Logger) error {
 		// eq:  "ip r add <ipAddressOnNode> dev veth0/eth0 table <ruleTable> "
 		for _, hostAddress := range c.hostAddress {
 			ipNet := networking.ConvertMaxMaskIPNet(hostAddress.IP)
-			if err = networking.AddRoute(logger, c.currentRuleTable, netlink.SCOPE_LINK, c.podVethName, ipNet, nil, nil); err != nil {
+			if err = networking.AddRoute(logger, c.currentRuleTable, netlink.SCOPE_LINK, c.podVethName, ipNet, nil, nil, c.vethMTU); err != nil {
 				logger.Error("failed to AddRoute for ipAddressOnNode", zap.Error(err))
 				return fmt.Errorf("failed to AddRouteTable for ipAddressOnNode: %v", err)
 			}
 
 			if c.tuneMode == ModeOverlay && c.firstInvoke {
-				if err = networking.AddRoute(logger, unix.RT_TABLE_MAIN, netlink.SCOPE_LINK, c.podVethName, ipNet, nil, nil); err != nil {
+				if err = networking.AddRoute(logger, unix.RT_TABLE_MAIN, netlink.SCOPE_LINK, c.podVethName, ipNet, nil, nil, c.vethMTU); err != nil {
 					logger.Error("failed to AddRoute for ipAddressOnNode", zap.Error(err))
 					return fmt.Errorf("failed to AddRouteTable for ipAddressOnNode: %v", err)
 				}
@@ -247,7 +352,7 @@ func (c *coordinator) setupHostRoutes(logger *zap.Logger) error {
 	// equivalent: ip add  <chainedIPs> dev <hostVethName> table  on host
 	for _, hostAddress := range c.currentAddress {
 		ipNet := networking.ConvertMaxMaskIPNet(hostAddress.IP)
-		if err = networking.AddRoute(logger, c.hostRuleTable, netlink.SCOPE_LINK, c.hostVethName, ipNet, nil, nil); err != nil {
+		if err = networking.AddRoute(logger, c.hostRuleTable, netlink.SCOPE_LINK, c.hostVethName, ipNet, nil, nil, c.vethMTU); err != nil {
 			logger.Error("failed to AddRouteTable for preInterfaceIPAddress", zap.Error(err))
 			return fmt.Errorf("failed to AddRouteTable for preInterfaceIPAddress: %v", err)
 		}
@@ -452,6 +557,88 @@ func (c *coordinator) tunePodRoutes(logger *zap.Logger, configDefaultRouteNIC st
 	return nil
 }
 
+// setupCustomRoutes installs the custom routes of current interface from
+// Pod annotation, which carry metric, table, scope or policy. The routes
+// without table are installed in the given rule table, or the rule table of
+// current interface for the policy routes if the given one is main table.
+// equivalent to: `ip route add <dst> via <gw> dev <iface> metric <metric> scope <scope> table <table>`
+// and `ip rule add from <currentInterfaceIPAddress> lookup <table>` for policy routes
+func (c *coordinator) setupCustomRoutes(logger *zap.Logger, routes []*models.PodRoute, ruleTable int) error {
+	err := c.netns.Do(func(_ ns.NetNS) error {
+		link, err := netlink.LinkByName(c.currentInterface)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range routes {
+			if r.IfName != c.currentInterface {
+				continue
+			}
+
+			_, dst, err := net.ParseCIDR(r.Dst)
+			if err != nil {
+				return fmt.Errorf("invalid dst of custom route: %v", err)
+			}
+
+			route := &netlink.Route{
+				LinkIndex: link.Attrs().Index,
+				Dst:       dst,
+				Table:     ruleTable,
+				Scope:     routeScope(r.Scope),
+			}
+			if r.Table != nil {
+				route.Table = int(*r.Table)
+			} else if r.Policy && route.Table == unix.RT_TABLE_MAIN {
+				// all traffic already looks up the main table, so the rule
+				// only works with the rule table of current interface
+				route.Table = c.currentRuleTable
+			}
+			if r.Metric != nil {
+				route.Priority = int(*r.Metric)
+			}
+			if r.Gw != "" {
+				route.Gw = net.ParseIP(r.Gw)
+			}
+
+			if err = netlink.RouteAdd(route); err != nil && !os.IsExist(err) {
+				logger.Error("failed to RouteAdd for custom route", zap.String("route", route.String()), zap.Error(err))
+				return fmt.Errorf("failed to RouteAdd for custom route: %v", err)
+			}
+			logger.Debug("Add custom route successfully", zap.String("route", route.String()))
+
+			if !r.Policy {
+				continue
+			}
+
+			for idx := range c.currentAddress {
+				if (c.currentAddress[idx].IP.To4() != nil) != (dst.IP.To4() != nil) {
+					continue
+				}
+
+				ipNet := networking.ConvertMaxMaskIPNet(c.currentAddress[idx].IP)
+				if err = networking.AddFromRuleTable(ipNet, route.Table); err != nil && !os.IsExist(err) {
+					logger.Error("failed to AddFromRuleTable for policy route", zap.String("Src", ipNet.String()), zap.Error(err))
+					return fmt.Errorf("failed to AddFromRuleTable for policy route: %v", err)
+				}
+			}
+		}
+		return nil
+	})
+
+	return err
+}
+
+func routeScope(scope string) netlink.Scope {
+	switch scope {
+	case constant.RouteScopeLink:
+		return netlink.SCOPE_LINK
+	case constant.RouteScopeHost:
+		return netlink.SCOPE_HOST
+	default:
+		return netlink.SCOPE_UNIVERSE
+	}
+}
+
 // getHostVethName select the first 11 characters of the containerID for the host veth.
 func getHostVethName(containerID string) string {
 	return fmt.Sprintf("veth%s", containerID[:min(len(containerID))])
@@ -463,3 +650,62 @@ func min(len int) int {
 	}
 	return len
 }
+
+// setupVRF enslaves current interface to the VRF, whose table defaults to the
+// rule table of current interface. It returns the table of the VRF.
+// equivalent to: `ip link add <vrf> type vrf table <table>` and `ip link set <iface> master <vrf>`
+func (c *coordinator) setupVRF(logger *zap.Logger, vrf *VRFOptions) (int, error) {
+	var table int
+	err := c.netns.Do(func(_ ns.NetNS) error {
+		vrfLink, err := networking.EnsureVRF(vrf.Name, uint32(vrf.Table), uint32(c.currentRuleTable))
+		if err != nil {
+			return err
+		}
+		table = int(vrfLink.Table)
+
+		if err = networking.LinkSetVRFMaster(c.currentInterface, vrfLink); err != nil {
+			return err
+		}
+		logger.Debug("Enslave interface to VRF successfully", zap.String("vrf", vrf.Name), zap.Int("table", table))
+		return nil
+	})
+
+	return table, err
+}
+
+// setupBandwidth limits the rates and marks the DSCP of current interface.
+// equivalent to: `tc qdisc replace dev <iface> root tbf ...`, `tc filter add dev <iface> parent ffff: ... police ...`
+// and `iptables -t mangle -A POSTROUTING -o <iface> -j DSCP --set-dscp <dscp>`
+func (c *coordinator) setupBandwidth(logger *zap.Logger, bandwidth *BandwidthOptions) error {
+	return c.netns.Do(func(_ ns.NetNS) error {
+		if bandwidth.EgressRate != "" {
+			rate, err := networking.ParseBandwidthRate(bandwidth.EgressRate)
+			if err != nil {
+				return err
+			}
+			if err = networking.SetupEgressBandwidth(c.currentInterface, rate); err != nil {
+				return err
+			}
+			logger.Debug("Limit egress rate successfully", zap.String("rate", bandwidth.EgressRate))
+		}
+
+		if bandwidth.IngressRate != "" {
+			rate, err := networking.ParseBandwidthRate(bandwidth.IngressRate)
+			if err != nil {
+				return err
+			}
+			if err = networking.SetupIngressBandwidth(c.currentInterface, rate); err != nil {
+				return err
+			}
+			logger.Debug("Limit ingress rate successfully", zap.String("rate", bandwidth.IngressRate))
+		}
+
+		if bandwidth.DSCP != nil {
+			if err := networking.SetupDSCP(c.currentInterface, *bandwidth.DSCP, c.ipFamily); err != nil {
+				return err
+			}
+			logger.Debug("Mark DSCP successfully", zap.Int("dscp", *bandwidth.DSCP))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Utils", Label("utils_test"), func() {
+	Describe("vethMTU", func() {
+		DescribeTable("returns the MTU of the veth pair",
+			func(mtu string, podMTU, hostMTU, expected int) {
+				Expect(vethMTU(mtu, podMTU, hostMTU)).To(Equal(expected))
+			},
+			Entry("default", "", 9000, 1450, defaultVethMTU),
+			Entry("specified", "9000", 1500, 1450, 9000),
+			Entry("auto with the smaller host MTU", MTUAuto, 1500, 1450, 1450),
+			Entry("auto with the smaller pod MTU", MTUAuto, 1400, 1450, 1400),
+			Entry("auto without host MTU", MTUAuto, 9000, 0, 9000),
+		)
+
+		It("fails with an invalid mtu", func() {
+			_, err := vethMTU("jumbo", 1500, 1500)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("hijackRouteMTU", func() {
+		DescribeTable("returns the MTU of the routes to the cluster",
+			func(vethMTU, hostMTU, expected int) {
+				c := &coordinator{vethMTU: vethMTU, hostMTU: hostMTU}
+				Expect(c.hijackRouteMTU()).To(Equal(expected))
+			},
+			Entry("unknown veth MTU in overlay mode", 0, 1450, 0),
+			Entry("the smaller host MTU", 9000, 1450, 1450),
+			Entry("the smaller veth MTU", 1400, 1450, 1400),
+			Entry("unknown host MTU", 1500, 0, 1500),
+		)
+	})
+})
@@ -18,12 +18,15 @@ import (
 
 	"github.com/spidernet-io/spiderpool/api/v1/agent/client"
 	"github.com/spidernet-io/spiderpool/api/v1/agent/server"
+	"github.com/spidernet-io/spiderpool/pkg/audit"
 	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/gatewaymonitor"
 	"github.com/spidernet-io/spiderpool/pkg/ipam"
 	"github.com/spidernet-io/spiderpool/pkg/ippoolmanager"
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
 	"github.com/spidernet-io/spiderpool/pkg/namespacemanager"
 	"github.com/spidernet-io/spiderpool/pkg/nodemanager"
+	"github.com/spidernet-io/spiderpool/pkg/nodenetwork"
 	"github.com/spidernet-io/spiderpool/pkg/podmanager"
 	"github.com/spidernet-io/spiderpool/pkg/reservedipmanager"
 	"github.com/spidernet-io/spiderpool/pkg/statefulsetmanager"
@@ -56,10 +59,40 @@ var envInfo = []envConf{
 	{"SPIDERPOOL_METRIC_HTTP_PORT", "5711", true, &agentContext.Cfg.MetricHttpPort, nil, nil},
 	{"SPIDERPOOL_GOPS_LISTEN_PORT", "5712", false, &agentContext.Cfg.GopsListenPort, nil, nil},
 	{"SPIDERPOOL_PYROSCOPE_PUSH_SERVER_ADDRESS", "", false, &agentContext.Cfg.PyroscopeAddress, nil, nil},
+	{"SPIDERPOOL_TRACE_EXPORTER", "", false, &agentContext.Cfg.TraceExporter, nil, nil},
+	{"SPIDERPOOL_TRACE_ENDPOINT", "", false, &agentContext.Cfg.TraceEndpoint, nil, nil},
+	{"SPIDERPOOL_TRACE_INSECURE", "false", false, nil, &agentContext.Cfg.TraceInsecure, nil},
+	{"SPIDERPOOL_TRACE_FILE_PATH", "", false, &agentContext.Cfg.TraceFilePath, nil, nil},
+	{"SPIDERPOOL_TRACE_SAMPLE_PERCENT", "100", false, nil, nil, &agentContext.Cfg.TraceSamplePercent},
 
 	{"SPIDERPOOL_IPPOOL_MAX_ALLOCATED_IPS", "5000", true, nil, nil, &agentContext.Cfg.IPPoolMaxAllocatedIPs},
 	{"SPIDERPOOL_WAIT_SUBNET_POOL_TIME_IN_SECOND", "2", false, nil, nil, &agentContext.Cfg.WaitSubnetPoolTime},
 	{"SPIDERPOOL_WAIT_SUBNET_POOL_MAX_RETRIES", "25", false, nil, nil, &agentContext.Cfg.WaitSubnetPoolMaxRetries},
+	{"SPIDERPOOL_NODE_NAME", "", false, &agentContext.Cfg.NodeName, nil, nil},
+	{"SPIDERPOOL_IP_CONFLICT_REPROBE_INTERVAL_IN_SECOND", "60", false, nil, nil, &agentContext.Cfg.IPConflictReprobeInterval},
+	{"SPIDERPOOL_IP_CONFLICT_MAX_AGE_IN_SECOND", "3600", false, nil, nil, &agentContext.Cfg.IPConflictMaxAge},
+
+	{"SPIDERPOOL_GATEWAY_MONITOR_ENABLED", "false", false, nil, &agentContext.Cfg.EnableGatewayMonitor, nil},
+	{"SPIDERPOOL_GATEWAY_MONITOR_METHOD", gatewaymonitor.MethodICMP, false, &agentContext.Cfg.GatewayMonitorMethod, nil, nil},
+	{"SPIDERPOOL_GATEWAY_MONITOR_INTERVAL_IN_SECOND", "30", false, nil, nil, &agentContext.Cfg.GatewayMonitorInterval},
+	{"SPIDERPOOL_GATEWAY_MONITOR_TIMEOUT_IN_MILLISECOND", "1000", false, nil, nil, &agentContext.Cfg.GatewayMonitorTimeout},
+	{"SPIDERPOOL_GATEWAY_MONITOR_FAILURE_THRESHOLD", "3", false, nil, nil, &agentContext.Cfg.GatewayMonitorFailureThreshold},
+	{"SPIDERPOOL_GATEWAY_MONITOR_NETNS_DIR", gatewaymonitor.DefaultNetnsDir, false, &agentContext.Cfg.GatewayMonitorNetnsDir, nil, nil},
+	{"SPIDERPOOL_GATEWAY_MONITOR_MARK_POOL_UNHEALTHY", "false", false, nil, &agentContext.Cfg.GatewayMonitorMarkPoolUnhealthy, nil},
+
+	{"SPIDERPOOL_AUDIT_ENABLED", "false", false, nil, &agentContext.Cfg.EnableAudit, nil},
+	{"SPIDERPOOL_AUDIT_LOG_DIR", audit.DefaultDir, false, &agentContext.Cfg.AuditLogDir, nil, nil},
+	{"SPIDERPOOL_AUDIT_LOG_MAX_SIZE", "100", false, nil, nil, &agentContext.Cfg.AuditLogMaxSize},
+	{"SPIDERPOOL_AUDIT_LOG_MAX_AGE", "30", false, nil, nil, &agentContext.Cfg.AuditLogMaxAge},
+	{"SPIDERPOOL_AUDIT_LOG_MAX_BACKUPS", "10", false, nil, nil, &agentContext.Cfg.AuditLogMaxBackups},
+	{"SPIDERPOOL_AUDIT_WEBHOOK_URL", "", false, &agentContext.Cfg.AuditWebhookURL, nil, nil},
+	{"SPIDERPOOL_AUDIT_SYSLOG_ADDRESS", "", false, &agentContext.Cfg.AuditSyslogAddress, nil, nil},
+
+	{"SPIDERPOOL_NODE_NETWORK_ENABLED", "true", false, nil, &agentContext.Cfg.EnableNodeNetwork, nil},
+	{"SPIDERPOOL_NODE_NETWORK_INTERVAL_IN_SECOND", "60", false, nil, nil, &agentContext.Cfg.NodeNetworkInterval},
+
+	{"SPIDERPOOL_STANDALONE_ENABLED", "false", false, nil, &agentContext.Cfg.EnableStandalone, nil},
+	{"SPIDERPOOL_STANDALONE_NAMESPACE", "", false, &agentContext.Cfg.StandaloneNamespace, nil, nil},
 }
 
 type Config struct {
@@ -81,10 +114,42 @@ type Config struct {
 	GopsListenPort   string
 	PyroscopeAddress string
 
+	TraceExporter      string
+	TraceEndpoint      string
+	TraceInsecure      bool
+	TraceFilePath      string
+	TraceSamplePercent int
+
 	IPPoolMaxAllocatedIPs    int
 	WaitSubnetPoolTime       int
 	WaitSubnetPoolMaxRetries int
 
+	NodeName                  string
+	IPConflictReprobeInterval int
+	IPConflictMaxAge          int
+
+	EnableGatewayMonitor            bool
+	GatewayMonitorMethod            string
+	GatewayMonitorInterval          int
+	GatewayMonitorTimeout           int
+	GatewayMonitorFailureThreshold  int
+	GatewayMonitorNetnsDir          string
+	GatewayMonitorMarkPoolUnhealthy bool
+
+	EnableAudit        bool
+	AuditLogDir        string
+	AuditLogMaxSize    int
+	AuditLogMaxAge     int
+	AuditLogMaxBackups int
+	AuditWebhookURL    string
+	AuditSyslogAddress string
+
+	EnableNodeNetwork   bool
+	NodeNetworkInterval int
+
+	EnableStandalone    bool
+	StandaloneNamespace string
+
 	// configmap
 	IpamUnixSocketPath                string   `yaml:"ipamUnixSocketPath"`
 	EnableIPv4                        bool     `yaml:"enableIPv4"`
@@ -117,6 +182,8 @@ type AgentContext struct {
 	PodManager        podmanager.PodManager
 	StsManager        statefulsetmanager.StatefulSetManager
 	SubnetManager     subnetmanager.SubnetManager
+	GatewayMonitor    *gatewaymonitor.Monitor
+	NodeNetwork       *nodenetwork.Publisher
 
 	// handler
 	HttpServer        *server.Server
@@ -126,6 +193,9 @@ type AgentContext struct {
 	// client
 	unixClient *client.SpiderpoolAgentAPI
 
+	// ShutdownTracing flushes the spans not exported yet.
+	ShutdownTracing func(context.Context) error
+
 	// probe
 	IsStartupProbe atomic.Bool
 }
@@ -6,14 +6,27 @@ package cmd
 import (
 	"fmt"
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
 	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
 	"github.com/spidernet-io/spiderpool/api/v1/agent/server/restapi/daemonset"
 	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/event"
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+	"github.com/spidernet-io/spiderpool/pkg/networking/networking"
+	"github.com/spidernet-io/spiderpool/pkg/podmanager"
+	"github.com/spidernet-io/spiderpool/pkg/types"
+	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
+	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
 )
 
-var unixGetCoordinatorConfig = &_unixGetCoordinatorConfig{}
+var (
+	unixGetCoordinatorConfig   = &_unixGetCoordinatorConfig{}
+	unixPostCoordinatorGateway = &_unixPostCoordinatorGateway{}
+)
 
 type _unixGetCoordinatorConfig struct{}
 
@@ -69,5 +82,117 @@ func (g *_unixGetCoordinatorConfig) Handle(params daemonset.GetCoordinatorConfig
 		DetectIPConflict:   *coord.Spec.DetectIPConflict,
 	}
 
+	if value, ok := pod.Annotations[constant.AnnoPodCoordinator]; ok {
+		override, err := podmanager.ParsePodCoordinatorAnnotation(value)
+		if err != nil {
+			return daemonset.NewGetCoordinatorConfigFailure().WithPayload(models.Error(fmt.Sprintf("invalid annotation %s of pod %s/%s: %v", constant.AnnoPodCoordinator, pod.Namespace, pod.Name, err)))
+		}
+
+		config.PodOverride = &models.PodCoordinatorOverride{
+			DetectGateway:      override.DetectGateway,
+			DetectIPConflict:   override.DetectIPConflict,
+			PodDefaultRouteNIC: override.PodDefaultRouteNIC,
+			ExtraCIDR:          override.ExtraCIDR,
+		}
+	}
+
+	if value, ok := pod.Annotations[constant.AnnoPodRoutes]; ok {
+		routes, err := podmanager.ParsePodRoutesAnnotation(value)
+		if err != nil {
+			return daemonset.NewGetCoordinatorConfigFailure().WithPayload(models.Error(fmt.Sprintf("invalid annotation %s of pod %s/%s: %v", constant.AnnoPodRoutes, pod.Namespace, pod.Name, err)))
+		}
+
+		// the routes which can not be carried by CNI result are installed
+		// by coordinator
+		var advancedRoutes types.AnnoPodRoutesValue
+		for _, r := range routes {
+			if r.IsAdvanced() {
+				advancedRoutes = append(advancedRoutes, r)
+			}
+		}
+		config.PodRoutes = convert.ConvertAnnoPodRoutesToOAIPodRoutes(advancedRoutes)
+	}
+
+	if value, ok := pod.Annotations[constant.AnnoPodBandwidth]; ok {
+		bandwidth, err := podmanager.ParsePodBandwidthAnnotation(value)
+		if err != nil {
+			return daemonset.NewGetCoordinatorConfigFailure().WithPayload(models.Error(fmt.Sprintf("invalid annotation %s of pod %s/%s: %v", constant.AnnoPodBandwidth, pod.Namespace, pod.Name, err)))
+		}
+		config.PodBandwidth = convert.ConvertAnnoPodBandwidthToOAIPodBandwidth(bandwidth)
+	}
+
+	if coord.Spec.MTU != nil {
+		config.Mtu = *coord.Spec.MTU
+	}
+
+	// the Pod's traffic to the cluster leaves the host through these routes,
+	// whose interfaces are the node's NIC or the tunnel of the overlay
+	hostMTU, err := networking.GetPathMTU(append(append([]string{}, config.PodCIDR...), config.ServiceCIDR...))
+	if err != nil {
+		logutils.Logger.Named("Coordinator").Warn("failed to get the MTU of the host's routes to the cluster", zap.Error(err))
+	}
+	config.HostMTU = int64(hostMTU)
+
+	if dg := coord.Spec.DetectGatewayOptions; dg != nil {
+		config.DetectGatewayOptions = &models.DetectGatewayConfig{}
+		if dg.Method != nil {
+			config.DetectGatewayOptions.Method = *dg.Method
+		}
+		if dg.LossTolerance != nil {
+			config.DetectGatewayOptions.LossTolerance = int64(*dg.LossTolerance)
+		}
+		if dg.WarnOnly != nil {
+			config.DetectGatewayOptions.WarnOnly = *dg.WarnOnly
+		}
+	}
+
+	if gn := coord.Spec.GratuitousNeighbor; gn != nil {
+		config.GratuitousNeighbor = &models.GratuitousNeighborConfig{}
+		if gn.Enabled != nil {
+			config.GratuitousNeighbor.Enabled = *gn.Enabled
+		}
+		if gn.Count != nil {
+			config.GratuitousNeighbor.Count = int64(*gn.Count)
+		}
+		if gn.Interval != nil {
+			config.GratuitousNeighbor.Interval = *gn.Interval
+		}
+	}
+
 	return daemonset.NewGetCoordinatorConfigOK().WithPayload(config)
 }
+
+type _unixPostCoordinatorGateway struct{}
+
+// Handle handles POST requests for /coordinator/gateway.
+func (g *_unixPostCoordinatorGateway) Handle(params daemonset.PostCoordinatorGatewayParams) middleware.Responder {
+	args := params.GatewayUnreachableArgs
+	if err := args.Validate(strfmt.Default); err != nil {
+		return daemonset.NewPostCoordinatorGatewayFailure().WithPayload(models.Error(err.Error()))
+	}
+
+	logger := logutils.Logger.Named("Coordinator").With(
+		zap.String("IfName", *args.IfName),
+		zap.String("PodNamespace", *args.PodNamespace),
+		zap.String("PodName", *args.PodName),
+		zap.String("PodUID", *args.PodUID),
+		zap.String("Gateway", *args.Gateway),
+	)
+	logger.Sugar().Warnf("Gateway is unreachable from the Pod: %s", args.Message)
+
+	pod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       constant.KindPod,
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: *args.PodNamespace,
+			Name:      *args.PodName,
+			UID:       apitypes.UID(*args.PodUID),
+		},
+	}
+	event.EventRecorder.Eventf(pod, corev1.EventTypeWarning, constant.EventReasonGatewayUnreachable,
+		"gateway %s of interface %s is unreachable: %s", *args.Gateway, *args.IfName, args.Message)
+
+	return daemonset.NewPostCoordinatorGatewayOK()
+}
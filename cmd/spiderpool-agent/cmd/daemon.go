@@ -21,15 +21,21 @@ import (
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/spidernet-io/spiderpool/pkg/audit"
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/event"
+	"github.com/spidernet-io/spiderpool/pkg/gatewaymonitor"
 	"github.com/spidernet-io/spiderpool/pkg/ipam"
 	"github.com/spidernet-io/spiderpool/pkg/ippoolmanager"
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
 	"github.com/spidernet-io/spiderpool/pkg/namespacemanager"
 	"github.com/spidernet-io/spiderpool/pkg/nodemanager"
+	"github.com/spidernet-io/spiderpool/pkg/nodenetwork"
 	"github.com/spidernet-io/spiderpool/pkg/podmanager"
 	"github.com/spidernet-io/spiderpool/pkg/reservedipmanager"
 	"github.com/spidernet-io/spiderpool/pkg/statefulsetmanager"
 	"github.com/spidernet-io/spiderpool/pkg/subnetmanager"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
 	"github.com/spidernet-io/spiderpool/pkg/workloadendpointmanager"
 )
 
@@ -109,6 +115,36 @@ func DaemonMain() {
 		}
 	}
 
+	// Set up tracing.
+	shutdownTracing, err := tracing.InitTracing(context.Background(), binNameAgent, tracing.Config{
+		Exporter:    agentContext.Cfg.TraceExporter,
+		Endpoint:    agentContext.Cfg.TraceEndpoint,
+		Insecure:    agentContext.Cfg.TraceInsecure,
+		FilePath:    agentContext.Cfg.TraceFilePath,
+		SampleRatio: float64(agentContext.Cfg.TraceSamplePercent) / 100,
+	})
+	if err != nil {
+		logger.Sugar().Fatalf("Failed to setup tracing: %v", err)
+	}
+	agentContext.ShutdownTracing = shutdownTracing
+
+	// Set up audit log.
+	if agentContext.Cfg.EnableAudit {
+		err := audit.Init(audit.Config{
+			Component:     constant.SpiderpoolAgent,
+			Node:          agentContext.Cfg.NodeName,
+			Dir:           agentContext.Cfg.AuditLogDir,
+			MaxSize:       agentContext.Cfg.AuditLogMaxSize,
+			MaxAge:        agentContext.Cfg.AuditLogMaxAge,
+			MaxBackups:    agentContext.Cfg.AuditLogMaxBackups,
+			WebhookURL:    agentContext.Cfg.AuditWebhookURL,
+			SyslogAddress: agentContext.Cfg.AuditSyslogAddress,
+		})
+		if err != nil {
+			logger.Sugar().Fatalf("Failed to setup audit log: %v", err)
+		}
+	}
+
 	agentContext.InnerCtx, agentContext.InnerCancel = context.WithCancel(context.Background())
 	if err := waitAPIServerReady(agentContext.InnerCtx); err != nil {
 		logger.Fatal(err.Error())
@@ -123,21 +159,70 @@ func DaemonMain() {
 		logger.Fatal(err.Error())
 	}
 	agentContext.CRDManager = mgr
+	event.EventRecorder = mgr.GetEventRecorderFor(constant.SpiderpoolAgent)
 
 	// init managers...
 	initAgentServiceManagers(agentContext.InnerCtx)
 
+	var poolHealth ipam.PoolHealthChecker
+	if agentContext.Cfg.EnableGatewayMonitor {
+		logger.Info("Begin to initialize gateway monitor")
+		gatewayMonitor, err := gatewaymonitor.NewMonitor(
+			gatewaymonitor.Config{
+				NodeName:          agentContext.Cfg.NodeName,
+				Interval:          time.Duration(agentContext.Cfg.GatewayMonitorInterval) * time.Second,
+				Timeout:           time.Duration(agentContext.Cfg.GatewayMonitorTimeout) * time.Millisecond,
+				Method:            agentContext.Cfg.GatewayMonitorMethod,
+				FailureThreshold:  agentContext.Cfg.GatewayMonitorFailureThreshold,
+				NetnsDir:          agentContext.Cfg.GatewayMonitorNetnsDir,
+				MarkPoolUnhealthy: agentContext.Cfg.GatewayMonitorMarkPoolUnhealthy,
+			},
+			mgr.GetClient(),
+		)
+		if nil != err {
+			logger.Fatal(err.Error())
+		}
+		agentContext.GatewayMonitor = gatewayMonitor
+		poolHealth = gatewayMonitor
+	} else {
+		logger.Info("Feature gateway monitor is disabled")
+	}
+
+	if agentContext.Cfg.EnableNodeNetwork {
+		logger.Info("Begin to initialize node network publisher")
+		publisher, err := nodenetwork.NewPublisher(
+			nodenetwork.Config{
+				NodeName: agentContext.Cfg.NodeName,
+				Interval: time.Duration(agentContext.Cfg.NodeNetworkInterval) * time.Second,
+			},
+			mgr.GetClient(),
+			mgr.GetAPIReader(),
+		)
+		if nil != err {
+			logger.Fatal(err.Error())
+		}
+		agentContext.NodeNetwork = publisher
+	} else {
+		logger.Info("Feature node network is disabled")
+	}
+
 	logger.Info("Begin to initialize IPAM")
 	ipam, err := ipam.NewIPAM(
 		ipam.IPAMConfig{
-			EnableIPv4:               agentContext.Cfg.EnableIPv4,
-			EnableIPv6:               agentContext.Cfg.EnableIPv6,
-			ClusterDefaultIPv4IPPool: agentContext.Cfg.ClusterDefaultIPv4IPPool,
-			ClusterDefaultIPv6IPPool: agentContext.Cfg.ClusterDefaultIPv6IPPool,
-			EnableSpiderSubnet:       agentContext.Cfg.EnableSpiderSubnet,
-			EnableStatefulSet:        agentContext.Cfg.EnableStatefulSet,
-			OperationRetries:         agentContext.Cfg.WaitSubnetPoolMaxRetries,
-			OperationGapDuration:     time.Duration(agentContext.Cfg.WaitSubnetPoolTime) * time.Second,
+			EnableIPv4:                agentContext.Cfg.EnableIPv4,
+			EnableIPv6:                agentContext.Cfg.EnableIPv6,
+			ClusterDefaultIPv4IPPool:  agentContext.Cfg.ClusterDefaultIPv4IPPool,
+			ClusterDefaultIPv6IPPool:  agentContext.Cfg.ClusterDefaultIPv6IPPool,
+			EnableSpiderSubnet:        agentContext.Cfg.EnableSpiderSubnet,
+			EnableStatefulSet:         agentContext.Cfg.EnableStatefulSet,
+			OperationRetries:          agentContext.Cfg.WaitSubnetPoolMaxRetries,
+			OperationGapDuration:      time.Duration(agentContext.Cfg.WaitSubnetPoolTime) * time.Second,
+			NodeName:                  agentContext.Cfg.NodeName,
+			IPConflictReprobeInterval: time.Duration(agentContext.Cfg.IPConflictReprobeInterval) * time.Second,
+			IPConflictMaxAge:          time.Duration(agentContext.Cfg.IPConflictMaxAge) * time.Second,
+			EnableStandalone:          agentContext.Cfg.EnableStandalone,
+			StandaloneNamespace:       agentContext.Cfg.StandaloneNamespace,
+			PoolHealth:                poolHealth,
 		},
 		agentContext.IPPoolManager,
 		agentContext.EndpointManager,
@@ -170,6 +255,24 @@ func DaemonMain() {
 		logger.Fatal("failed to wait for syncing controller-runtime cache")
 	}
 
+	if agentContext.GatewayMonitor != nil {
+		go func() {
+			logger.Info("Starting gateway monitor")
+			if err := agentContext.GatewayMonitor.Start(agentContext.InnerCtx); err != nil {
+				logger.Fatal(err.Error())
+			}
+		}()
+	}
+
+	if agentContext.NodeNetwork != nil {
+		go func() {
+			logger.Info("Starting node network publisher")
+			if err := agentContext.NodeNetwork.Start(agentContext.InnerCtx); err != nil {
+				logger.Fatal(err.Error())
+			}
+		}()
+	}
+
 	logger.Info("Begin to initialize spiderpool-agent OpenAPI HTTP server")
 	srv, err := newAgentOpenAPIHttpServer()
 	if nil != err {
@@ -247,6 +350,18 @@ func WatchSignal(sigCh chan os.Signal) {
 			}
 		}
 
+		// flush the spans not exported yet
+		if nil != agentContext.ShutdownTracing {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := agentContext.ShutdownTracing(ctx); nil != err {
+				logger.Sugar().Errorf("Failed to shut down tracing: %v", err)
+			}
+			cancel()
+		}
+
+		// flush the audit records not exported yet
+		audit.Close()
+
 		// others...
 
 	}
@@ -294,10 +409,14 @@ func waitAPIServerReady(ctx context.Context) error {
 }
 
 func initAgentServiceManagers(ctx context.Context) {
+	// Record the calls to API Server and the informer cache in spans.
+	k8sClient := tracing.NewClient(agentContext.CRDManager.GetClient())
+	apiReader := tracing.NewReader(agentContext.CRDManager.GetAPIReader())
+
 	logger.Debug("Begin to initialize Node manager")
 	nodeManager, err := nodemanager.NewNodeManager(
-		agentContext.CRDManager.GetClient(),
-		agentContext.CRDManager.GetAPIReader(),
+		k8sClient,
+		apiReader,
 	)
 	if err != nil {
 		logger.Fatal(err.Error())
@@ -306,8 +425,8 @@ func initAgentServiceManagers(ctx context.Context) {
 
 	logger.Debug("Begin to initialize Namespace manager")
 	nsManager, err := namespacemanager.NewNamespaceManager(
-		agentContext.CRDManager.GetClient(),
-		agentContext.CRDManager.GetAPIReader(),
+		k8sClient,
+		apiReader,
 	)
 	if err != nil {
 		logger.Fatal(err.Error())
@@ -316,8 +435,8 @@ func initAgentServiceManagers(ctx context.Context) {
 
 	logger.Debug("Begin to initialize Pod manager")
 	podManager, err := podmanager.NewPodManager(
-		agentContext.CRDManager.GetClient(),
-		agentContext.CRDManager.GetAPIReader(),
+		k8sClient,
+		apiReader,
 	)
 	if err != nil {
 		logger.Fatal(err.Error())
@@ -326,8 +445,8 @@ func initAgentServiceManagers(ctx context.Context) {
 
 	logger.Debug("Begin to initialize StatefulSet manager")
 	statefulSetManager, err := statefulsetmanager.NewStatefulSetManager(
-		agentContext.CRDManager.GetClient(),
-		agentContext.CRDManager.GetAPIReader(),
+		k8sClient,
+		apiReader,
 	)
 	if err != nil {
 		logger.Fatal(err.Error())
@@ -336,8 +455,8 @@ func initAgentServiceManagers(ctx context.Context) {
 
 	logger.Debug("Begin to initialize Endpoint manager")
 	endpointManager, err := workloadendpointmanager.NewWorkloadEndpointManager(
-		agentContext.CRDManager.GetClient(),
-		agentContext.CRDManager.GetAPIReader(),
+		k8sClient,
+		apiReader,
 	)
 	if err != nil {
 		logger.Fatal(err.Error())
@@ -346,8 +465,8 @@ func initAgentServiceManagers(ctx context.Context) {
 
 	logger.Debug("Begin to initialize ReservedIP manager")
 	rIPManager, err := reservedipmanager.NewReservedIPManager(
-		agentContext.CRDManager.GetClient(),
-		agentContext.CRDManager.GetAPIReader(),
+		k8sClient,
+		apiReader,
 	)
 	if err != nil {
 		logger.Fatal(err.Error())
@@ -359,8 +478,8 @@ func initAgentServiceManagers(ctx context.Context) {
 		ippoolmanager.IPPoolManagerConfig{
 			MaxAllocatedIPs: &agentContext.Cfg.IPPoolMaxAllocatedIPs,
 		},
-		agentContext.CRDManager.GetClient(),
-		agentContext.CRDManager.GetAPIReader(),
+		k8sClient,
+		apiReader,
 		agentContext.ReservedIPManager,
 	)
 	if err != nil {
@@ -371,8 +490,8 @@ func initAgentServiceManagers(ctx context.Context) {
 	if agentContext.Cfg.EnableSpiderSubnet {
 		logger.Debug("Begin to initialize Subnet manager")
 		subnetManager, err := subnetmanager.NewSubnetManager(
-			agentContext.CRDManager.GetClient(),
-			agentContext.CRDManager.GetAPIReader(),
+			k8sClient,
+			apiReader,
 			agentContext.ReservedIPManager,
 		)
 		if err != nil {
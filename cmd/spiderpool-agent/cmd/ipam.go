@@ -20,10 +20,12 @@ import (
 
 // Singleton.
 var (
-	unixPostAgentIpamIp    = &_unixPostAgentIpamIp{}
-	unixDeleteAgentIpamIp  = &_unixDeleteAgentIpamIp{}
-	unixPostAgentIpamIps   = &_unixPostAgentIpamIps{}
-	unixDeleteAgentIpamIps = &_unixDeleteAgentIpamIps{}
+	unixPostAgentIpamIp       = &_unixPostAgentIpamIp{}
+	unixDeleteAgentIpamIp     = &_unixDeleteAgentIpamIp{}
+	unixPostAgentIpamIps      = &_unixPostAgentIpamIps{}
+	unixDeleteAgentIpamIps    = &_unixDeleteAgentIpamIps{}
+	unixPostAgentIpamConflict = &_unixPostAgentIpamConflict{}
+	unixPostAgentIpamGc       = &_unixPostAgentIpamGc{}
 )
 
 type _unixPostAgentIpamIp struct{}
@@ -111,6 +113,54 @@ func (g *_unixDeleteAgentIpamIp) Handle(params daemonset.DeleteIpamIPParams) mid
 	return daemonset.NewDeleteIpamIPOK()
 }
 
+type _unixPostAgentIpamConflict struct{}
+
+// Handle handles POST requests for /ipam/conflict.
+func (g *_unixPostAgentIpamConflict) Handle(params daemonset.PostIpamConflictParams) middleware.Responder {
+	if err := params.IpamConflictArgs.Validate(strfmt.Default); err != nil {
+		return daemonset.NewPostIpamConflictFailure().WithPayload(models.Error(err.Error()))
+	}
+
+	logger := logutils.Logger.Named("IPAM").With(
+		zap.String("Operation", "CONFLICT"),
+		zap.String("IfName", *params.IpamConflictArgs.IfName),
+		zap.String("PodNamespace", *params.IpamConflictArgs.PodNamespace),
+		zap.String("PodName", *params.IpamConflictArgs.PodName),
+		zap.String("PodUID", *params.IpamConflictArgs.PodUID),
+		zap.String("IP", *params.IpamConflictArgs.IP),
+		zap.String("MAC", *params.IpamConflictArgs.Mac),
+	)
+	ctx := logutils.IntoContext(params.HTTPRequest.Context(), logger)
+
+	if err := agentContext.IPAM.ReportIPConflict(ctx, params.IpamConflictArgs); err != nil {
+		logger.Error(err.Error())
+		return daemonset.NewPostIpamConflictFailure().WithPayload(models.Error(err.Error()))
+	}
+
+	return daemonset.NewPostIpamConflictOK()
+}
+
+type _unixPostAgentIpamGc struct{}
+
+// Handle handles POST requests for /ipam/gc.
+func (g *_unixPostAgentIpamGc) Handle(params daemonset.PostIpamGcParams) middleware.Responder {
+	if err := params.IpamGcArgs.Validate(strfmt.Default); err != nil {
+		return daemonset.NewPostIpamGcFailure().WithPayload(models.Error(err.Error()))
+	}
+
+	logger := logutils.Logger.Named("IPAM").With(
+		zap.String("CNICommand", "GC"),
+	)
+	ctx := logutils.IntoContext(params.HTTPRequest.Context(), logger)
+
+	if err := agentContext.IPAM.GarbageCollect(ctx, params.IpamGcArgs); err != nil {
+		logger.Error(err.Error())
+		return daemonset.NewPostIpamGcFailure().WithPayload(models.Error(err.Error()))
+	}
+
+	return daemonset.NewPostIpamGcOK()
+}
+
 type _unixPostAgentIpamIps struct{}
 
 // Handle handles POST requests for /ipam/ips.
@@ -17,6 +17,7 @@ import (
 	agentOpenAPIClient "github.com/spidernet-io/spiderpool/api/v1/agent/client"
 	agentOpenAPIServer "github.com/spidernet-io/spiderpool/api/v1/agent/server"
 	agentOpenAPIRestapi "github.com/spidernet-io/spiderpool/api/v1/agent/server/restapi"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
 )
 
 // NewAgentOpenAPIUnixServer instantiates a new instance of the agent OpenAPI server on the unix.
@@ -39,9 +40,12 @@ func NewAgentOpenAPIUnixServer() (*agentOpenAPIServer.Server, error) {
 	api.ConnectivityGetIpamHealthyHandler = unixGetAgentHealth
 	api.DaemonsetPostIpamIPHandler = unixPostAgentIpamIp
 	api.DaemonsetDeleteIpamIPHandler = unixDeleteAgentIpamIp
+	api.DaemonsetPostIpamConflictHandler = unixPostAgentIpamConflict
+	api.DaemonsetPostIpamGcHandler = unixPostAgentIpamGc
 	api.DaemonsetPostIpamIpsHandler = unixPostAgentIpamIps
 	api.DaemonsetDeleteIpamIpsHandler = unixDeleteAgentIpamIps
 	api.DaemonsetGetCoordinatorConfigHandler = unixGetCoordinatorConfig
+	api.DaemonsetPostCoordinatorGatewayHandler = unixPostCoordinatorGateway
 
 	// new agent OpenAPI server with api
 	srv := agentOpenAPIServer.NewServer(api)
@@ -52,6 +56,9 @@ func NewAgentOpenAPIUnixServer() (*agentOpenAPIServer.Server, error) {
 	// configure API and handlers with some default values.
 	srv.ConfigureAPI()
 
+	// continue the trace context propagated by the CNI plugins.
+	srv.SetHandler(tracing.NewHandler(srv.GetHandler()))
+
 	return srv, nil
 }
 
@@ -62,13 +69,13 @@ func NewAgentOpenAPIUnixClient(unixSocketPath string) (*agentOpenAPIClient.Spide
 	}
 
 	httpClient := &http.Client{
-		Transport: &http.Transport{
+		Transport: tracing.NewTransport(&http.Transport{
 			DisableCompression: true,
 			DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
 				return net.Dial("unix", unixSocketPath)
 			},
 			DisableKeepAlives: true,
-		},
+		}),
 	}
 	clientTrans := runtime_client.NewWithClient(unixSocketPath, agentOpenAPIClient.DefaultBasePath,
 		agentOpenAPIClient.DefaultSchemes, httpClient)
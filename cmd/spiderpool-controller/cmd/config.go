@@ -19,6 +19,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/spidernet-io/spiderpool/api/v1/controller/server"
+	"github.com/spidernet-io/spiderpool/pkg/audit"
 	"github.com/spidernet-io/spiderpool/pkg/election"
 	"github.com/spidernet-io/spiderpool/pkg/gcmanager"
 	"github.com/spidernet-io/spiderpool/pkg/ippoolmanager"
@@ -91,12 +92,27 @@ var envInfo = []envConf{
 
 	{"SPIDERPOOL_MULTUS_CONFIG_ENABLED", "false", false, nil, &controllerContext.Cfg.EnableMultusConfig, nil},
 	{"SPIDERPOOL_MULTUS_CONFIG_INFORMER_RESYNC_PERIOD", "60", false, nil, nil, &controllerContext.Cfg.MultusConfigInformerResyncPeriod},
+	{"SPIDERPOOL_MULTUS_CONFIG_INTERFACE_CHECK", "warn", false, &controllerContext.Cfg.MultusConfigInterfaceCheck, nil, nil},
 
 	{"SPIDERPOOL_IPPOOL_INFORMER_RESYNC_PERIOD", "300", false, nil, nil, &controllerContext.Cfg.IPPoolInformerResyncPeriod},
 	{"SPIDERPOOL_IPPOOL_INFORMER_WORKERS", "3", true, nil, nil, &controllerContext.Cfg.IPPoolInformerWorkers},
 	{"SPIDERPOOL_AUTO_IPPOOL_HANDLER_MAX_WORKQUEUE_LENGTH", "10000", true, nil, nil, &controllerContext.Cfg.IPPoolInformerMaxWorkQueueLength},
 	{"SPIDERPOOL_WORKQUEUE_MAX_RETRIES", "500", true, nil, nil, &controllerContext.Cfg.WorkQueueMaxRetries},
 	{"SPIDERPOOL_WORKQUEUE_RETRY_DELAY_DURATION", "5", true, nil, nil, &controllerContext.Cfg.WorkQueueRequeueDelayDuration},
+
+	{"SPIDERPOOL_IP_UTILIZATION_WINDOW", "3600", false, nil, nil, &controllerContext.Cfg.IPUtilizationWindow},
+	{"SPIDERPOOL_IP_UTILIZATION_THRESHOLDS", "80,95", false, &controllerContext.Cfg.IPUtilizationThresholds, nil, nil},
+
+	{"SPIDERPOOL_AUDIT_ENABLED", "false", false, nil, &controllerContext.Cfg.EnableAudit, nil},
+	{"SPIDERPOOL_AUDIT_LOG_DIR", audit.DefaultDir, false, &controllerContext.Cfg.AuditLogDir, nil, nil},
+	{"SPIDERPOOL_AUDIT_LOG_MAX_SIZE", "100", false, nil, nil, &controllerContext.Cfg.AuditLogMaxSize},
+	{"SPIDERPOOL_AUDIT_LOG_MAX_AGE", "30", false, nil, nil, &controllerContext.Cfg.AuditLogMaxAge},
+	{"SPIDERPOOL_AUDIT_LOG_MAX_BACKUPS", "10", false, nil, nil, &controllerContext.Cfg.AuditLogMaxBackups},
+	{"SPIDERPOOL_AUDIT_WEBHOOK_URL", "", false, &controllerContext.Cfg.AuditWebhookURL, nil, nil},
+	{"SPIDERPOOL_AUDIT_SYSLOG_ADDRESS", "", false, &controllerContext.Cfg.AuditSyslogAddress, nil, nil},
+
+	{"SPIDERPOOL_SCHEDULER_EXTENDER_ENABLED", "false", false, nil, &controllerContext.Cfg.EnableSchedulerExtender, nil},
+	{"SPIDERPOOL_SCHEDULER_EXTENDER_PORT", "5725", false, &controllerContext.Cfg.SchedulerExtenderPort, nil, nil},
 }
 
 type Config struct {
@@ -142,10 +158,25 @@ type Config struct {
 	WorkQueueMaxRetries              int
 	WorkQueueRequeueDelayDuration    int
 
+	IPUtilizationWindow     int
+	IPUtilizationThresholds string
+
 	CoordinatorInformerResyncPeriod int
 
 	EnableMultusConfig               bool
 	MultusConfigInformerResyncPeriod int
+	MultusConfigInterfaceCheck       string
+
+	EnableAudit        bool
+	AuditLogDir        string
+	AuditLogMaxSize    int
+	AuditLogMaxAge     int
+	AuditLogMaxBackups int
+	AuditWebhookURL    string
+	AuditSyslogAddress string
+
+	EnableSchedulerExtender bool
+	SchedulerExtenderPort   string
 
 	// configmap
 	EnableIPv4                        bool `yaml:"enableIPv4"`
@@ -181,8 +212,9 @@ type ControllerContext struct {
 	Leader            election.SpiderLeaseElector
 
 	// handler
-	HttpServer        *server.Server
-	MetricsHttpServer *http.Server
+	HttpServer                  *server.Server
+	MetricsHttpServer           *http.Server
+	SchedulerExtenderHttpServer *http.Server
 
 	// webhook http client
 	webhookClient *http.Client
@@ -18,6 +18,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/spidernet-io/spiderpool/pkg/ippoolmanager"
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
 )
 
@@ -58,6 +59,24 @@ func newCRDManager() (ctrl.Manager, error) {
 		return nil, err
 	}
 
+	// the scheduler extender selects the members of IPPool groups.
+	if err := mgr.GetFieldIndexer().IndexField(controllerContext.InnerCtx, &spiderpoolv2beta1.SpiderIPPool{}, "spec.poolGroup.name", func(raw client.Object) []string {
+		ipPool := raw.(*spiderpoolv2beta1.SpiderIPPool)
+		if ipPool.Spec.PoolGroup == nil {
+			return nil
+		}
+		return []string{ipPool.Spec.PoolGroup.Name}
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(controllerContext.InnerCtx, &spiderpoolv2beta1.SpiderIPClaim{}, ippoolmanager.IPClaimIPPoolIndex, func(raw client.Object) []string {
+		ipClaim := raw.(*spiderpoolv2beta1.SpiderIPClaim)
+		return []string{ipClaim.Spec.IPPool}
+	}); err != nil {
+		return nil, err
+	}
+
 	if err := mgr.GetFieldIndexer().IndexField(controllerContext.InnerCtx, &spiderpoolv2beta1.SpiderReservedIP{}, "spec.ipVersion", func(raw client.Object) []string {
 		reservedIP := raw.(*spiderpoolv2beta1.SpiderReservedIP)
 		return []string{strconv.FormatInt(*reservedIP.Spec.IPVersion, 10)}
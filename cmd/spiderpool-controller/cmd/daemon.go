@@ -21,6 +21,7 @@ import (
 
 	"github.com/spidernet-io/spiderpool/pkg/applicationcontroller"
 	"github.com/spidernet-io/spiderpool/pkg/applicationcontroller/applicationinformers"
+	"github.com/spidernet-io/spiderpool/pkg/audit"
 	"github.com/spidernet-io/spiderpool/pkg/constant"
 	"github.com/spidernet-io/spiderpool/pkg/coordinatormanager"
 	"github.com/spidernet-io/spiderpool/pkg/election"
@@ -36,6 +37,7 @@ import (
 	"github.com/spidernet-io/spiderpool/pkg/reservedipmanager"
 	"github.com/spidernet-io/spiderpool/pkg/statefulsetmanager"
 	"github.com/spidernet-io/spiderpool/pkg/subnetmanager"
+	"github.com/spidernet-io/spiderpool/pkg/utilization"
 	"github.com/spidernet-io/spiderpool/pkg/workloadendpointmanager"
 )
 
@@ -115,6 +117,22 @@ func DaemonMain() {
 		}
 	}
 
+	// Set up audit log.
+	if controllerContext.Cfg.EnableAudit {
+		err := audit.Init(audit.Config{
+			Component:     constant.SpiderpoolController,
+			Dir:           controllerContext.Cfg.AuditLogDir,
+			MaxSize:       controllerContext.Cfg.AuditLogMaxSize,
+			MaxAge:        controllerContext.Cfg.AuditLogMaxAge,
+			MaxBackups:    controllerContext.Cfg.AuditLogMaxBackups,
+			WebhookURL:    controllerContext.Cfg.AuditWebhookURL,
+			SyslogAddress: controllerContext.Cfg.AuditSyslogAddress,
+		})
+		if err != nil {
+			logger.Sugar().Fatalf("Failed to setup audit log: %v", err)
+		}
+	}
+
 	controllerContext.InnerCtx, controllerContext.InnerCancel = context.WithCancel(context.Background())
 	logger.Info("Begin to initialize spiderpool-controller metrics HTTP server")
 	initControllerMetricsServer(controllerContext.InnerCtx)
@@ -176,6 +194,11 @@ func DaemonMain() {
 	logger.Info("Begin to initialize IP GC Manager")
 	initGCManager(controllerContext.InnerCtx)
 
+	if controllerContext.Cfg.EnableSchedulerExtender {
+		logger.Info("Begin to initialize scheduler extender HTTP server")
+		initSchedulerExtenderServer()
+	}
+
 	logger.Info("Set spiderpool-controller Startup probe ready")
 	controllerContext.webhookClient = newWebhookHealthCheckClient()
 	controllerContext.IsStartupProbe.Store(true)
@@ -212,6 +235,15 @@ func WatchSignal(sigCh chan os.Signal) {
 			}
 		}
 
+		if nil != controllerContext.SchedulerExtenderHttpServer {
+			if err := controllerContext.SchedulerExtenderHttpServer.Close(); nil != err {
+				logger.Sugar().Errorf("Failed to shutdown spiderpool-controller scheduler extender HTTP server: %v", err)
+			}
+		}
+
+		// flush the audit records not exported yet
+		audit.Close()
+
 		// others...
 
 	}
@@ -319,6 +351,26 @@ func initControllerServiceManagers(ctx context.Context) {
 		logger.Fatal(err.Error())
 	}
 
+	logger.Debug("Begin to set up Pod webhook")
+	if err := (&podmanager.PodWebhook{
+		Client:             controllerContext.CRDManager.GetClient(),
+		EnableIPv4:         controllerContext.Cfg.EnableIPv4,
+		EnableIPv6:         controllerContext.Cfg.EnableIPv6,
+		EnableSpiderSubnet: controllerContext.Cfg.EnableSpiderSubnet,
+	}).SetupWebhookWithManager(controllerContext.CRDManager); err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	logger.Debug("Begin to set up workload webhook")
+	if err := (&podmanager.WorkloadWebhook{
+		Client:             controllerContext.CRDManager.GetClient(),
+		EnableIPv4:         controllerContext.Cfg.EnableIPv4,
+		EnableIPv6:         controllerContext.Cfg.EnableIPv6,
+		EnableSpiderSubnet: controllerContext.Cfg.EnableSpiderSubnet,
+	}).SetupWebhookWithManager(controllerContext.CRDManager); err != nil {
+		logger.Fatal(err.Error())
+	}
+
 	if controllerContext.Cfg.EnableSpiderSubnet {
 		logger.Debug("Begin to initialize Subnet manager")
 		subnetManager, err := subnetmanager.NewSubnetManager(
@@ -349,7 +401,10 @@ func initControllerServiceManagers(ctx context.Context) {
 
 	if controllerContext.Cfg.EnableMultusConfig {
 		logger.Debug("Begin to set up MultusConfig webhook")
-		if err := (&multuscniconfig.MultusConfigWebhook{}).SetupWebhookWithManager(controllerContext.CRDManager); nil != err {
+		if err := (&multuscniconfig.MultusConfigWebhook{
+			Client:         controllerContext.CRDManager.GetClient(),
+			InterfaceCheck: controllerContext.Cfg.MultusConfigInterfaceCheck,
+		}).SetupWebhookWithManager(controllerContext.CRDManager); nil != err {
 			logger.Fatal(err.Error())
 		}
 	}
@@ -455,6 +510,11 @@ func setupInformers() {
 		logger.Fatal(err.Error())
 	}
 
+	utilizationThresholds, err := utilization.ParseThresholds(controllerContext.Cfg.IPUtilizationThresholds)
+	if nil != err {
+		logger.Fatal(err.Error())
+	}
+
 	logger.Info("Begin to set up IPPool informer")
 	ipPoolController := ippoolmanager.NewIPPoolController(
 		ippoolmanager.IPPoolControllerConfig{
@@ -465,6 +525,8 @@ func setupInformers() {
 			WorkQueueRequeueDelayDuration: time.Duration(controllerContext.Cfg.WorkQueueRequeueDelayDuration) * time.Second,
 			WorkQueueMaxRetries:           controllerContext.Cfg.WorkQueueMaxRetries,
 			ResyncPeriod:                  time.Duration(controllerContext.Cfg.IPPoolInformerResyncPeriod) * time.Second,
+			UtilizationWindow:             time.Duration(controllerContext.Cfg.IPUtilizationWindow) * time.Second,
+			UtilizationThresholds:         utilizationThresholds,
 		},
 		controllerContext.CRDManager.GetClient(),
 		controllerContext.DynamicClient,
@@ -483,6 +545,8 @@ func setupInformers() {
 			ResyncPeriod:            time.Duration(controllerContext.Cfg.SubnetInformerResyncPeriod) * time.Second,
 			SubnetControllerWorkers: controllerContext.Cfg.SubnetInformerWorkers,
 			MaxWorkqueueLength:      controllerContext.Cfg.SubnetInformerMaxWorkqueueLength,
+			UtilizationWindow:       time.Duration(controllerContext.Cfg.IPUtilizationWindow) * time.Second,
+			UtilizationThresholds:   utilizationThresholds,
 			DynamicClient:           controllerContext.DynamicClient,
 		}).SetupInformer(controllerContext.InnerCtx, crdClient, controllerContext.Leader); err != nil {
 			logger.Fatal(err.Error())
@@ -521,6 +585,7 @@ func setupInformers() {
 				WorkQueueRequeueDelayDuration: time.Duration(controllerContext.Cfg.WorkQueueRequeueDelayDuration) * time.Second,
 				LeaderRetryElectGap:           time.Duration(controllerContext.Cfg.LeaseRetryGap) * time.Second,
 				ResyncPeriod:                  time.Duration(controllerContext.Cfg.MultusConfigInformerResyncPeriod) * time.Second,
+				InterfaceCheck:                controllerContext.Cfg.MultusConfigInterfaceCheck,
 			},
 			controllerContext.CRDManager.GetClient())
 		err = multusConfigController.SetupInformer(controllerContext.InnerCtx, crdClient, controllerContext.Leader)
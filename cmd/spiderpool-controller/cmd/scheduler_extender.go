@@ -0,0 +1,57 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spidernet-io/spiderpool/pkg/ipam"
+	"github.com/spidernet-io/spiderpool/pkg/schedulerextender"
+)
+
+// initSchedulerExtenderServer will start the kube-scheduler extender HTTP
+// server, which filters and scores Nodes by the IPPool candidates of Pods.
+func initSchedulerExtenderServer() {
+	checker, err := ipam.NewNodeChecker(
+		ipam.IPAMConfig{
+			EnableIPv4:         controllerContext.Cfg.EnableIPv4,
+			EnableIPv6:         controllerContext.Cfg.EnableIPv6,
+			EnableSpiderSubnet: controllerContext.Cfg.EnableSpiderSubnet,
+			EnableStatefulSet:  controllerContext.Cfg.EnableStatefulSet,
+		},
+		controllerContext.IPPoolManager,
+		controllerContext.EndpointManager,
+		controllerContext.NodeManager,
+		controllerContext.NSManager,
+		controllerContext.PodManager,
+		controllerContext.StsManager,
+		controllerContext.SubnetManager,
+	)
+	if nil != err {
+		logger.Fatal(err.Error())
+	}
+
+	extender, err := schedulerextender.NewExtender(checker)
+	if nil != err {
+		logger.Fatal(err.Error())
+	}
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%s", controllerContext.Cfg.SchedulerExtenderPort),
+		Handler: extender.Handler(),
+	}
+
+	go func() {
+		if err := srv.ListenAndServe(); nil != err {
+			if err == http.ErrServerClosed {
+				return
+			}
+
+			logger.Fatal(err.Error())
+		}
+	}()
+
+	controllerContext.SchedulerExtenderHttpServer = srv
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"time"
 
+	netv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -16,6 +17,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/spidernet-io/spiderpool/pkg/bootstrap"
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
 )
@@ -25,6 +27,7 @@ var scheme = runtime.NewScheme()
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(spiderpoolv2beta1.AddToScheme(scheme))
+	utilruntime.Must(netv1.AddToScheme(scheme))
 }
 
 const retryIntervalSec = 2
@@ -123,6 +126,30 @@ func (c *CoreClient) WaitForIPPoolCreated(ctx context.Context, ipPool *spiderpoo
 	}
 }
 
+func (c *CoreClient) WaitForBootstrapApplied(ctx context.Context, manifest *bootstrap.Manifest, opts bootstrap.Options) error {
+	logger := logutils.FromContext(ctx)
+
+	for {
+		changes, err := bootstrap.Apply(ctx, c, manifest, opts)
+		if err == nil {
+			for _, change := range changes {
+				logger.Sugar().Infof("Bootstrap %s %s %s %s", change.Action, change.Kind, change.Name, change.Reason)
+			}
+			logger.Sugar().Infof("Succeed to apply bootstrap manifest with %d objects", manifest.Len())
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			interval := retryIntervalSec * time.Second
+			logger.Sugar().Infof("Failed to apply bootstrap manifest, reapply in %s: %v", interval, err)
+			time.Sleep(interval)
+		}
+	}
+}
+
 func (c *CoreClient) WaitForEndpointReady(ctx context.Context, namespace, name string) error {
 	logger := logutils.FromContext(ctx)
 
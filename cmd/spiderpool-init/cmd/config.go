@@ -10,6 +10,7 @@ import (
 
 	"github.com/spidernet-io/spiderpool/pkg/constant"
 	spiderpoolip "github.com/spidernet-io/spiderpool/pkg/ip"
+	"github.com/spidernet-io/spiderpool/pkg/ipammigration"
 )
 
 const (
@@ -34,6 +35,12 @@ const (
 	ENVDefaultIPv6CIDR       = "SPIDERPOOL_INIT_DEFAULT_IPV6_IPPOOL_SUBNET"
 	ENVDefaultIPv6IPRanges   = "SPIDERPOOL_INIT_DEFAULT_IPV6_IPPOOL_IPRANGES"
 	ENVDefaultIPv6Gateway    = "SPIDERPOOL_INIT_DEFAULT_IPV6_IPPOOL_GATEWAY"
+
+	ENVMigrateFrom          = "SPIDERPOOL_INIT_MIGRATE_FROM"
+	ENVMigrateCreateSubnets = "SPIDERPOOL_INIT_MIGRATE_CREATE_SUBNETS"
+
+	ENVBootstrapFile  = "SPIDERPOOL_INIT_BOOTSTRAP_FILE"
+	ENVBootstrapPrune = "SPIDERPOOL_INIT_BOOTSTRAP_PRUNE"
 )
 
 type InitDefaultConfig struct {
@@ -60,6 +67,12 @@ type InitDefaultConfig struct {
 	V6CIDR       string
 	V6IPRanges   []string
 	V6Gateway    string
+
+	MigrateFrom          string
+	MigrateCreateSubnets bool
+
+	BootstrapFile  string
+	BootstrapPrune bool
 }
 
 func NewInitDefaultConfig() InitDefaultConfig {
@@ -210,6 +223,37 @@ func parseENVAsDefault() InitDefaultConfig {
 			config.V6IPPoolName,
 		)
 	}
+
+	// Migration
+	config.MigrateFrom = strings.ReplaceAll(os.Getenv(ENVMigrateFrom), "\"", "")
+	switch config.MigrateFrom {
+	case "":
+	case ipammigration.SourceWhereabouts:
+		if v := strings.ReplaceAll(os.Getenv(ENVMigrateCreateSubnets), "\"", ""); len(v) != 0 {
+			cs, err := strconv.ParseBool(v)
+			if err != nil {
+				logger.Sugar().Fatalf("ENV %s %s: %v", ENVMigrateCreateSubnets, v, err)
+			}
+			config.MigrateCreateSubnets = cs
+		}
+	default:
+		// host-local keeps its allocations on each node, which is migrated by
+		// 'spiderpoolctl migrate' running on the nodes.
+		logger.Sugar().Fatalf("ENV %s %s: only '%s' is supported", ENVMigrateFrom, config.MigrateFrom, ipammigration.SourceWhereabouts)
+	}
+
+	// Bootstrap
+	config.BootstrapFile = strings.ReplaceAll(os.Getenv(ENVBootstrapFile), "\"", "")
+	if len(config.BootstrapFile) != 0 {
+		if v := strings.ReplaceAll(os.Getenv(ENVBootstrapPrune), "\"", ""); len(v) != 0 {
+			prune, err := strconv.ParseBool(v)
+			if err != nil {
+				logger.Sugar().Fatalf("ENV %s %s: %v", ENVBootstrapPrune, v, err)
+			}
+			config.BootstrapPrune = prune
+		}
+	}
+
 	logger.Sugar().Infof("Init default config: %+v", config)
 
 	return config
@@ -5,13 +5,16 @@ package cmd
 
 import (
 	"context"
+	"os"
 	"time"
 
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
+	"github.com/spidernet-io/spiderpool/pkg/bootstrap"
 	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/ipammigration"
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
 )
@@ -146,6 +149,40 @@ func Execute() {
 		}
 	}
 
+	if len(config.BootstrapFile) != 0 {
+		logger.Sugar().Infof("Try to apply bootstrap manifest %s", config.BootstrapFile)
+
+		f, err := os.Open(config.BootstrapFile)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		manifest, err := bootstrap.ReadManifest(f, config.Namespace)
+		f.Close()
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+
+		if err := client.WaitForBootstrapApplied(ctx, manifest, bootstrap.Options{Prune: config.BootstrapPrune}); err != nil {
+			logger.Fatal(err.Error())
+		}
+	}
+
+	if len(config.MigrateFrom) != 0 {
+		logger.Sugar().Infof("Try to migrate IP allocations from %s", config.MigrateFrom)
+
+		ranges, err := ipammigration.ReadWhereabouts(ctx, client)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		changes, err := ipammigration.Migrate(ctx, client, ranges, ipammigration.Options{CreateSubnets: config.MigrateCreateSubnets})
+		for _, change := range changes {
+			logger.Sugar().Infof("Migration %s %s/%s %s", change.Action, change.Kind, change.Name, change.Reason)
+		}
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+	}
+
 	logger.Info("Finish init")
 
 	// Wait for helm --wait.
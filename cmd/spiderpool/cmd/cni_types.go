@@ -6,13 +6,13 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"net"
 	"os"
 	"path/filepath"
 
 	"github.com/containernetworking/cni/pkg/types"
 	"github.com/spidernet-io/spiderpool/pkg/constant"
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
 )
 
 var BinNamePlugin = filepath.Base(os.Args[0])
@@ -21,6 +21,7 @@ var (
 	ErrAgentHealthCheck = fmt.Errorf("unhealthy spiderpool-agent backend")
 	ErrPostIPAM         = fmt.Errorf("spiderpool IP allocation error")
 	ErrDeleteIPAM       = fmt.Errorf("spiderpool IP release error")
+	ErrGCIPAM           = fmt.Errorf("spiderpool IP garbage collection error")
 )
 
 const (
@@ -28,21 +29,38 @@ const (
 	CniVersion031 = "0.3.1"
 	CniVersion040 = "0.4.0"
 	CniVersion100 = "1.0.0"
+	CniVersion110 = "1.1.0"
 )
 
 // SupportCNIVersions indicate the CNI version that spiderpool support.
-var SupportCNIVersions = []string{CniVersion030, CniVersion031, CniVersion040, CniVersion100}
+var SupportCNIVersions = []string{CniVersion030, CniVersion031, CniVersion040, CniVersion100, CniVersion110}
+
+// The CNI verbs introduced by CNI 1.1.0, which are not dispatched by the
+// vendored CNI library.
+const (
+	CommandGC     = "GC"
+	CommandStatus = "STATUS"
+)
+
+// ErrPluginNotAvailable is the CNI error code of STATUS, which means the
+// plugin cannot serve ADD requests.
+const ErrPluginNotAvailable uint = 50
 
 const DefaultLogLevelStr = logutils.LogInfoLevelStr
 
 // K8sArgs is the valid CNI_ARGS used for Kubernetes.
 type K8sArgs struct {
 	types.CommonArgs
-	IP                         net.IP
+	// IP is the comma-separated static IP addresses, at most one per IP
+	// version, with optional prefix lengths.
+	IP                         types.UnmarshallableString
 	K8S_POD_NAME               types.UnmarshallableString //revive:disable-line
 	K8S_POD_NAMESPACE          types.UnmarshallableString //revive:disable-line
 	K8S_POD_INFRA_CONTAINER_ID types.UnmarshallableString //revive:disable-line
 	K8S_POD_UID                types.UnmarshallableString //revive:disable-line
+	// SPIDERPOOL_OWNER is the owner of the standalone container, which is
+	// started without Kubernetes and has no K8S_POD_NAME.
+	SPIDERPOOL_OWNER types.UnmarshallableString //revive:disable-line
 }
 
 // NetConf is the structure of CNI network configuration.
@@ -50,6 +68,27 @@ type NetConf struct {
 	Name       string     `json:"name"`
 	CNIVersion string     `json:"cniVersion"`
 	IPAM       IPAMConfig `json:"ipam"`
+
+	// RuntimeConfig is injected by the container runtime for the
+	// capabilities of the plugin.
+	RuntimeConfig RuntimeConfig `json:"runtimeConfig,omitempty"`
+
+	// ValidAttachments is injected by the container runtime for GC.
+	ValidAttachments []Attachment `json:"cni.dev/valid-attachments,omitempty"`
+}
+
+// Attachment is an attachment of a container to the network.
+type Attachment struct {
+	ContainerID string `json:"containerID"`
+	IfName      string `json:"ifname"`
+}
+
+// RuntimeConfig holds the supported capability arguments.
+// Reference: https://github.com/containernetworking/cni/blob/main/CONVENTIONS.md
+type RuntimeConfig struct {
+	// IPs is the static IP addresses of the "ips" capability, with optional
+	// prefix lengths.
+	IPs []string `json:"ips,omitempty"`
 }
 
 // IPAMConfig is a custom IPAM struct.
@@ -68,6 +107,11 @@ type IPAMConfig struct {
 	CleanGateway      bool     `json:"clean_gateway,omitempty"`
 
 	IPAMUnixSocketPath string `json:"ipam_unix_socket_path,omitempty"`
+
+	TraceExporter string `json:"trace_exporter,omitempty"`
+	TraceEndpoint string `json:"trace_endpoint,omitempty"`
+	TraceInsecure bool   `json:"trace_insecure,omitempty"`
+	TraceFilePath string `json:"trace_file_path,omitempty"`
 }
 
 // LoadNetConf converts input (i.e. stdin) to NetConf.
@@ -87,6 +131,11 @@ func LoadNetConf(argsStdin []byte) (*NetConf, error) {
 		netConf.IPAM.IPAMUnixSocketPath = constant.DefaultIPAMUnixSocketPath
 	}
 
+	// The stdout of the CNI plugin is reserved for the CNI result.
+	if netConf.IPAM.TraceExporter == tracing.ExporterStdout {
+		return nil, fmt.Errorf("unsupported trace exporter %s for CNI plugin, expect '%s' or '%s'", netConf.IPAM.TraceExporter, tracing.ExporterOTLP, tracing.ExporterFile)
+	}
+
 	for _, vers := range SupportCNIVersions {
 		if netConf.CNIVersion == vers {
 			return netConf, nil
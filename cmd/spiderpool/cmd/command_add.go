@@ -14,13 +14,16 @@ import (
 	"github.com/containernetworking/cni/pkg/types"
 	current "github.com/containernetworking/cni/pkg/types/100"
 	"github.com/go-openapi/strfmt"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
 	"github.com/spidernet-io/spiderpool/api/v1/agent/client/connectivity"
 	"github.com/spidernet-io/spiderpool/api/v1/agent/client/daemonset"
 	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
 	"github.com/spidernet-io/spiderpool/cmd/spiderpool-agent/cmd"
+	"github.com/spidernet-io/spiderpool/pkg/constant"
 	spiderpoolip "github.com/spidernet-io/spiderpool/pkg/ip"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
 )
 
 // CmdAdd follows CNI SPEC cmdAdd.
@@ -78,6 +81,13 @@ func CmdAdd(args *skel.CmdArgs) (err error) {
 	)
 	logger.Sugar().Debugf("CNI ENV args: %+v", k8sArgs)
 
+	ips, err := staticIPs(conf, &k8sArgs)
+	if nil != err {
+		err := fmt.Errorf("failed to parse static IPs: %w", err)
+		logger.Error(err.Error())
+		return err
+	}
+
 	spiderpoolAgentAPI, err := cmd.NewAgentOpenAPIUnixClient(conf.IPAM.IPAMUnixSocketPath)
 	if nil != err {
 		err := fmt.Errorf("failed to create spiderpool-agent client: %w", err)
@@ -93,9 +103,26 @@ func CmdAdd(args *skel.CmdArgs) (err error) {
 		return err
 	}
 
+	shutdownTracing := setupTracing(context.Background(), conf, logger)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracing.CNIShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Sugar().Warnf("failed to flush spans: %v", err)
+		}
+	}()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
 	defer cancel()
 
+	ctx, span := tracing.Start(ctx, "cni ADD",
+		attribute.String("k8s.pod.namespace", string(k8sArgs.K8S_POD_NAMESPACE)),
+		attribute.String("k8s.pod.name", string(k8sArgs.K8S_POD_NAME)),
+		attribute.String("container.id", args.ContainerID),
+		attribute.String("cni.ifname", args.IfName),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	params := daemonset.NewPostIpamIPParams().
 		WithContext(ctx).
 		WithIpamAddArgs(&models.IpamAddArgs{
@@ -108,6 +135,10 @@ func CmdAdd(args *skel.CmdArgs) (err error) {
 			DefaultIPV4IPPool: conf.IPAM.DefaultIPv4IPPool,
 			DefaultIPV6IPPool: conf.IPAM.DefaultIPv6IPPool,
 			CleanGateway:      conf.IPAM.CleanGateway,
+			Ips:               ips,
+			Mac:               interfaceMAC(args.Netns, args.IfName),
+			Network:           conf.Name,
+			Owner:             string(k8sArgs.SPIDERPOOL_OWNER),
 		})
 
 	logger.Debug("Send IPAM request")
@@ -133,6 +164,14 @@ func CmdAdd(args *skel.CmdArgs) (err error) {
 		return err
 	}
 
+	// Set the MAC address allocated along with the IP addresses, in case
+	// that the main CNI has created the interface.
+	if len(result.Interfaces) != 0 {
+		if err := setInterfaceMAC(args.Netns, args.IfName, result.Interfaces[0].Mac); err != nil {
+			logger.Sugar().Warnf("failed to set MAC address %s of interface %s: %v", result.Interfaces[0].Mac, args.IfName, err)
+		}
+	}
+
 	logger.Sugar().Infof("IPAM allocation result: %+v", *result)
 	return types.PrintResult(result, conf.CNIVersion)
 }
@@ -169,6 +208,7 @@ func assembleResult(cniVersion, IfName string, ipamResponse *daemonset.PostIpamI
 	}
 	result.Routes = routes
 
+	var mac string
 	for _, ip := range ipamResponse.Payload.Ips {
 		if *ip.Nic == IfName {
 			address, err := spiderpoolip.ParseIP(*ip.Version, *ip.Address, true)
@@ -179,6 +219,19 @@ func assembleResult(cniVersion, IfName string, ipamResponse *daemonset.PostIpamI
 				Address: *address,
 				Gateway: net.ParseIP(ip.Gateway),
 			})
+
+			// The MAC address allocated with the IPv4 address takes
+			// precedence in dual-stack.
+			if ip.Mac != "" && (mac == "" || *ip.Version == constant.IPv4) {
+				mac = ip.Mac
+			}
+		}
+	}
+
+	if mac != "" {
+		result.Interfaces = []*current.Interface{{Name: IfName, Mac: mac}}
+		for _, ip := range result.IPs {
+			ip.Interface = current.Int(0)
 		}
 	}
 
@@ -11,12 +11,14 @@ import (
 
 	"github.com/containernetworking/cni/pkg/skel"
 	"github.com/containernetworking/cni/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
 	"github.com/spidernet-io/spiderpool/api/v1/agent/client/connectivity"
 	"github.com/spidernet-io/spiderpool/api/v1/agent/client/daemonset"
 	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
 	"github.com/spidernet-io/spiderpool/cmd/spiderpool-agent/cmd"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
 )
 
 // CmdDel follows CNI SPEC cmdDel.
@@ -89,9 +91,26 @@ func CmdDel(args *skel.CmdArgs) (err error) {
 		return err
 	}
 
+	shutdownTracing := setupTracing(context.Background(), conf, logger)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracing.CNIShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Sugar().Warnf("failed to flush spans: %v", err)
+		}
+	}()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	ctx, span := tracing.Start(ctx, "cni DEL",
+		attribute.String("k8s.pod.namespace", string(k8sArgs.K8S_POD_NAMESPACE)),
+		attribute.String("k8s.pod.name", string(k8sArgs.K8S_POD_NAME)),
+		attribute.String("container.id", args.ContainerID),
+		attribute.String("cni.ifname", args.IfName),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	params := daemonset.NewDeleteIpamIPParams().
 		WithContext(ctx).
 		WithIpamDelArgs(&models.IpamDelArgs{
@@ -0,0 +1,100 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	"github.com/spidernet-io/spiderpool/api/v1/agent/client/connectivity"
+	"github.com/spidernet-io/spiderpool/api/v1/agent/client/daemonset"
+	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
+	"github.com/spidernet-io/spiderpool/cmd/spiderpool-agent/cmd"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
+)
+
+// CmdGC follows CNI SPEC cmdGC, it forwards the valid attachments supplied by
+// the container runtime to spiderpool-agent, which releases the IP
+// allocations of the Pod sandboxes not in them.
+func CmdGC(stdinData []byte) (err error) {
+	conf, err := LoadNetConf(stdinData)
+	if nil != err {
+		return fmt.Errorf("failed to load CNI network configuration: %v", err)
+	}
+
+	logger, err := setupFileLogging(conf)
+	if nil != err {
+		return fmt.Errorf("failed to setup file logging: %v", err)
+	}
+
+	logger = logger.Named(BinNamePlugin).With(
+		zap.String("Action", CommandGC),
+		zap.String("Network", conf.Name),
+	)
+	logger.Debug("Processing CNI GC request")
+	logger.Sugar().Debugf("CNI network configuration: %+v", *conf)
+
+	spiderpoolAgentAPI, err := cmd.NewAgentOpenAPIUnixClient(conf.IPAM.IPAMUnixSocketPath)
+	if nil != err {
+		err := fmt.Errorf("failed to create spiderpool-agent client: %w", err)
+		logger.Error(err.Error())
+		return err
+	}
+
+	logger.Debug("Send health check request to spiderpool-agent backend")
+	_, err = spiderpoolAgentAPI.Connectivity.GetIpamHealthy(connectivity.NewGetIpamHealthyParams())
+	if nil != err {
+		err := fmt.Errorf("%w, failed to check: %v", ErrAgentHealthCheck, err)
+		logger.Error(err.Error())
+		return err
+	}
+
+	shutdownTracing := setupTracing(context.Background(), conf, logger)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracing.CNIShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Sugar().Warnf("failed to flush spans: %v", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+	defer cancel()
+
+	ctx, span := tracing.Start(ctx, "cni GC",
+		attribute.String("cni.network", conf.Name),
+		attribute.Int("cni.valid_attachments", len(conf.ValidAttachments)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	attachments := make([]*models.IpamAttachment, 0, len(conf.ValidAttachments))
+	for j := range conf.ValidAttachments {
+		attachments = append(attachments, &models.IpamAttachment{
+			ContainerID: &conf.ValidAttachments[j].ContainerID,
+			IfName:      &conf.ValidAttachments[j].IfName,
+		})
+	}
+
+	params := daemonset.NewPostIpamGcParams().
+		WithContext(ctx).
+		WithIpamGcArgs(&models.IpamGCArgs{
+			Network:          &conf.Name,
+			ValidAttachments: attachments,
+		})
+
+	logger.Sugar().Debugf("Send IPAM GC request with %d valid attachments", len(attachments))
+	_, err = spiderpoolAgentAPI.Daemonset.PostIpamGc(params)
+	if nil != err {
+		err := fmt.Errorf("%w: %v", ErrGCIPAM, err)
+		logger.Error(err.Error())
+		return err
+	}
+
+	logger.Info("IPAM garbage collection successfully")
+	return nil
+}
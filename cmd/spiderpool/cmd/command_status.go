@@ -0,0 +1,51 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/containernetworking/cni/pkg/types"
+	"go.uber.org/zap"
+
+	"github.com/spidernet-io/spiderpool/api/v1/agent/client/connectivity"
+	"github.com/spidernet-io/spiderpool/cmd/spiderpool-agent/cmd"
+)
+
+// CmdStatus follows CNI SPEC cmdStatus, it reports the plugin is not
+// available until spiderpool-agent is healthy, so that the container runtime
+// holds the creation of Pods.
+func CmdStatus(stdinData []byte) error {
+	conf, err := LoadNetConf(stdinData)
+	if nil != err {
+		return fmt.Errorf("failed to load CNI network configuration: %v", err)
+	}
+
+	logger, err := setupFileLogging(conf)
+	if nil != err {
+		return fmt.Errorf("failed to setup file logging: %v", err)
+	}
+
+	logger = logger.Named(BinNamePlugin).With(
+		zap.String("Action", CommandStatus),
+		zap.String("Network", conf.Name),
+	)
+	logger.Debug("Processing CNI STATUS request")
+
+	spiderpoolAgentAPI, err := cmd.NewAgentOpenAPIUnixClient(conf.IPAM.IPAMUnixSocketPath)
+	if nil != err {
+		err := fmt.Errorf("failed to create spiderpool-agent client: %w", err)
+		logger.Error(err.Error())
+		return err
+	}
+
+	logger.Debug("Send health check request to spiderpool-agent backend")
+	_, err = spiderpoolAgentAPI.Connectivity.GetIpamHealthy(connectivity.NewGetIpamHealthyParams())
+	if nil != err {
+		logger.Sugar().Warnf("%v: %v", ErrAgentHealthCheck, err)
+		return types.NewError(ErrPluginNotAvailable, ErrAgentHealthCheck.Error(), err.Error())
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ package cmd_test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -40,6 +41,7 @@ const CNILogFilePath = "/tmp/spiderpool.log"
 const (
 	healthCheckRoute = "/v1/ipam/healthy"
 	ipamReqRoute     = "/v1/ipam/ip"
+	ipamGCRoute      = "/v1/ipam/gc"
 )
 
 const CNIVersion010 = "0.1.0"
@@ -323,6 +325,60 @@ var _ = Describe("spiderpool plugin", Label("unitest", "ipam_plugin_test"), func
 				expectResult.Interfaces = []*current.Interface{{Name: ifName}}
 				return expectResult
 			}),
+			Entry("returns the MAC address allocated with the IPv4 address with ADD", ConfigWorkableSets{isPreConfigGood: true, isHealthy: true, isPostIPAM: true}, func() *skel.CmdArgs {
+				netConfBytes, err := json.Marshal(netConf)
+				Expect(err).NotTo(HaveOccurred())
+				args.StdinData = netConfBytes
+				return args
+			}, func() *models.IpamAddResponse {
+				ipamAddResp := &models.IpamAddResponse{
+					DNS: &models.DNS{},
+					Ips: []*models.IPConfig{
+						{
+							Address: pointer.String("fc00:f853:ccd:e793:f::fc/64"),
+							Mac:     "0a:1c:00:00:00:fc",
+							Nic:     pointer.String(ifName),
+							Version: pointer.Int64(constant.IPv6),
+						},
+						{
+							Address: pointer.String("10.1.0.8/24"),
+							Mac:     "0a:1b:0a:01:00:08",
+							Nic:     pointer.String(ifName),
+							Version: pointer.Int64(constant.IPv4),
+						},
+					},
+				}
+
+				return ipamAddResp
+			}, func() *current.Result {
+				expectResult := new(current.Result)
+				// CNIVersion
+				expectResult.CNIVersion = cniVersion
+				// DNS
+				expectResult.DNS = types.DNS{}
+				// IPs
+				expectResult.IPs = []*current.IPConfig{
+					{Interface: current.Int(0), Address: net.IPNet{IP: net.ParseIP("fc00:f853:ccd:e793:f::fc"), Mask: net.CIDRMask(64, 128)}},
+					{Interface: current.Int(0), Address: net.IPNet{IP: net.ParseIP("10.1.0.8"), Mask: net.CIDRMask(24, 32)}},
+				}
+				//Interfaces
+				expectResult.Interfaces = []*current.Interface{{Name: ifName, Mac: "0a:1b:0a:01:00:08"}}
+				return expectResult
+			}),
+			Entry("returning an error on invalid static IP in runtimeConfig with ADD", ConfigWorkableSets{isPreConfigGood: false, isHealthy: true, isPostIPAM: true}, func() *skel.CmdArgs {
+				netConf.RuntimeConfig.IPs = []string{"10.1.0.256/24"}
+				netConfBytes, err := json.Marshal(netConf)
+				Expect(err).NotTo(HaveOccurred())
+				args.StdinData = netConfBytes
+				return args
+			}, nil, nil),
+			Entry("returning an error on static IPs of the same IP version in CNI_ARGS with ADD", ConfigWorkableSets{isPreConfigGood: false, isHealthy: true, isPostIPAM: true}, func() *skel.CmdArgs {
+				netConfBytes, err := json.Marshal(netConf)
+				Expect(err).NotTo(HaveOccurred())
+				args.StdinData = netConfBytes
+				args.Args = "IP=10.1.0.7,10.1.0.8"
+				return args
+			}, nil, nil),
 			Entry(fmt.Sprintf("support CNI version '%s'", CNIVersion010), ConfigWorkableSets{isPreConfigGood: false, isHealthy: true, isPostIPAM: true}, func() *skel.CmdArgs {
 				netConf.CNIVersion = CNIVersion010
 				netConfBytes, err := json.Marshal(netConf)
@@ -402,6 +458,65 @@ var _ = Describe("spiderpool plugin", Label("unitest", "ipam_plugin_test"), func
 				return args
 			}),
 		)
+
+		It("collects garbage with the valid attachments by GC", func() {
+			netConf.Name = "macvlan-ens1"
+			server.RouteToHandler(http.MethodGet, healthCheckRoute, ghttp.CombineHandlers(getHealthHandleFunc(true)))
+			server.RouteToHandler(http.MethodPost, ipamGCRoute, ghttp.CombineHandlers(
+				ghttp.VerifyJSONRepresenting(models.IpamGCArgs{
+					Network: pointer.String(netConf.Name),
+					ValidAttachments: []*models.IpamAttachment{{
+						ContainerID: pointer.String(containerID),
+						IfName:      pointer.String(ifName),
+					}},
+				}),
+				ghttp.RespondWith(daemonset.PostIpamGcOKCode, nil),
+			))
+
+			netConf.CNIVersion = cmd.CniVersion110
+			netConf.ValidAttachments = []cmd.Attachment{{ContainerID: containerID, IfName: ifName}}
+			netConfBytes, err := json.Marshal(netConf)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = cmd.CmdGC(netConfBytes)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns an error on bad spiderpool agent response with GC", func() {
+			server.RouteToHandler(http.MethodGet, healthCheckRoute, ghttp.CombineHandlers(getHealthHandleFunc(true)))
+			server.RouteToHandler(http.MethodPost, ipamGCRoute, ghttp.CombineHandlers(ghttp.RespondWith(daemonset.PostIpamGcFailureCode, nil)))
+
+			netConf.CNIVersion = cmd.CniVersion110
+			netConfBytes, err := json.Marshal(netConf)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = cmd.CmdGC(netConfBytes)
+			Expect(err).To(MatchError(cmd.ErrGCIPAM))
+		})
+
+		It("reports the plugin is available by STATUS", func() {
+			server.RouteToHandler(http.MethodGet, healthCheckRoute, ghttp.CombineHandlers(getHealthHandleFunc(true)))
+
+			netConf.CNIVersion = cmd.CniVersion110
+			netConfBytes, err := json.Marshal(netConf)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = cmd.CmdStatus(netConfBytes)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports the plugin is not available by STATUS on bad health check", func() {
+			server.RouteToHandler(http.MethodGet, healthCheckRoute, ghttp.CombineHandlers(getHealthHandleFunc(false)))
+
+			netConf.CNIVersion = cmd.CniVersion110
+			netConfBytes, err := json.Marshal(netConf)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = cmd.CmdStatus(netConfBytes)
+			var cniErr *types.Error
+			Expect(errors.As(err, &cniErr)).To(BeTrue())
+			Expect(cniErr.Code).To(Equal(cmd.ErrPluginNotAvailable))
+		})
 	})
 
 	Describe("test ipam plugin configuration ", func() {
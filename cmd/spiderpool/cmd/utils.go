@@ -4,11 +4,21 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
 
+	"github.com/containernetworking/cni/pkg/types"
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/vishvananda/netlink"
 	"go.uber.org/zap"
 
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
 )
 
 // Set up file logging for spiderpool bin.
@@ -26,3 +36,127 @@ func setupFileLogging(conf *NetConf) (*zap.Logger, error) {
 		conf.IPAM.LogFileMaxCount,
 	)
 }
+
+// Set up tracing for spiderpool bin, every CNI call is sampled and the trace
+// context is propagated to spiderpool-agent. Tracing is best-effort, if it
+// fails to initialize, the CNI call goes on without exporting spans.
+func setupTracing(ctx context.Context, conf *NetConf, logger *zap.Logger) func(context.Context) error {
+	shutdown, err := tracing.InitTracing(ctx, BinNamePlugin, tracing.Config{
+		Exporter:    conf.IPAM.TraceExporter,
+		Endpoint:    conf.IPAM.TraceEndpoint,
+		Insecure:    conf.IPAM.TraceInsecure,
+		FilePath:    conf.IPAM.TraceFilePath,
+		SampleRatio: 1,
+	})
+	if err != nil {
+		logger.Warn("failed to setup tracing, continue without it", zap.Error(err))
+		return func(context.Context) error { return nil }
+	}
+
+	return shutdown
+}
+
+// Get the MAC address of the interface in the network namespace, which is
+// recorded in the audit log of spiderpool-agent and derives the EUI-64 IPv6
+// address. It is best-effort, because the main CNI may not have created the
+// interface yet.
+func interfaceMAC(netns, ifName string) string {
+	var mac string
+	_ = ns.WithNetNSPath(netns, func(_ ns.NetNS) error {
+		link, err := netlink.LinkByName(ifName)
+		if err != nil {
+			return err
+		}
+		mac = link.Attrs().HardwareAddr.String()
+		return nil
+	})
+
+	return mac
+}
+
+// Set the MAC address of the interface in the network namespace to the one
+// allocated along with the IP addresses. It is skipped if the main CNI has not
+// created the interface yet, which is expected to apply the MAC address in the
+// CNI result instead.
+func setInterfaceMAC(netns, ifName, mac string) error {
+	hwAddr, err := net.ParseMAC(mac)
+	if err != nil {
+		return err
+	}
+
+	return ns.WithNetNSPath(netns, func(_ ns.NetNS) error {
+		link, err := netlink.LinkByName(ifName)
+		if err != nil {
+			if _, ok := err.(netlink.LinkNotFoundError); ok {
+				return nil
+			}
+			return err
+		}
+		if link.Attrs().HardwareAddr.String() == hwAddr.String() {
+			return nil
+		}
+
+		return netlink.LinkSetHardwareAddr(link, hwAddr)
+	})
+}
+
+// Get the static IP addresses requested for the interface. The "ips"
+// capability of the runtime takes precedence over the CNI_ARGS IP. The prefix
+// lengths are ignored, because they are determined by the IPPools.
+func staticIPs(conf *NetConf, k8sArgs *K8sArgs) ([]string, error) {
+	ips := conf.RuntimeConfig.IPs
+	if len(ips) == 0 && k8sArgs.IP != "" {
+		ips = strings.Split(string(k8sArgs.IP), ",")
+	}
+
+	var v4, v6 int
+	result := make([]string, 0, len(ips))
+	for _, s := range ips {
+		s = strings.TrimSpace(s)
+		addr, _, _ := strings.Cut(s, "/")
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid static IP '%s'", s)
+		}
+
+		if ip.To4() != nil {
+			v4++
+		} else {
+			v6++
+		}
+		if v4 > 1 || v6 > 1 {
+			return nil, fmt.Errorf("more than one static IP of the same IP version in %v", ips)
+		}
+		result = append(result, ip.String())
+	}
+
+	return result, nil
+}
+
+// PluginMainWithStdin serves the CNI verbs which have no attachment, i.e. GC
+// and STATUS, with the network configuration from stdin. The error is printed
+// as JSON to stdout with a nonzero exit code, like skel.PluginMain.
+func PluginMainWithStdin(cmdFunc func(stdinData []byte) error) {
+	if e := pluginMainWithStdin(os.Stdin, cmdFunc); e != nil {
+		if err := e.Print(); err != nil {
+			log.Print("Error writing error JSON to stdout: ", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func pluginMainWithStdin(stdin io.Reader, cmdFunc func(stdinData []byte) error) *types.Error {
+	stdinData, err := io.ReadAll(stdin)
+	if err != nil {
+		return types.NewError(types.ErrIOFailure, fmt.Sprintf("error reading from stdin: %v", err), "")
+	}
+
+	if err := cmdFunc(stdinData); err != nil {
+		if e, ok := err.(*types.Error); ok {
+			return e
+		}
+		return types.NewError(types.ErrInternal, err.Error(), "")
+	}
+
+	return nil
+}
@@ -4,6 +4,8 @@
 package main
 
 import (
+	"os"
+
 	"github.com/containernetworking/cni/pkg/skel"
 	cniSpecVersion "github.com/containernetworking/cni/pkg/version"
 	"github.com/spidernet-io/spiderpool/cmd/spiderpool/cmd"
@@ -13,6 +15,16 @@ import (
 var version string
 
 func main() {
+	// The CNI library dispatches the verbs up to CNI 1.0.0 only.
+	switch os.Getenv("CNI_COMMAND") {
+	case cmd.CommandGC:
+		cmd.PluginMainWithStdin(cmd.CmdGC)
+		return
+	case cmd.CommandStatus:
+		cmd.PluginMainWithStdin(cmd.CmdStatus)
+		return
+	}
+
 	skel.PluginMain(cmd.CmdAdd, cmdCheck, cmd.CmdDel,
 		cniSpecVersion.PluginSupports(cmd.SupportCNIVersions...),
 		"Spiderpool IPAM "+version)
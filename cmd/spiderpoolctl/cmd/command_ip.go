@@ -4,7 +4,19 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/spidernet-io/spiderpool/pkg/audit"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
 )
 
 // ipCmd represents the base command.
@@ -18,12 +30,122 @@ var ipCmd = &cobra.Command{
 var ipShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "show ip related data",
-	Long:  `show pod who is taking this ip`,
-	Run: func(cmd *cobra.Command, args []string) {
-		logger.Info("This is spiderpool ctl ip show...")
+	Long:  `show pod who is taking this ip, with the interface and the mac address allocated along with the ip. All allocated ips are shown if --ip is not specified`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ip, err := cmd.Flags().GetString("ip")
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		c, err := newClient()
+		if err != nil {
+			return fmt.Errorf("failed to create k8s client: %v", err)
+		}
+
+		allocations, err := listIPAllocations(cmd.Context(), c, ip)
+		if err != nil {
+			return err
+		}
+
+		switch output {
+		case "json":
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			if allocations == nil {
+				allocations = []ipAllocation{}
+			}
+			return encoder.Encode(allocations)
+		case "table":
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "IP\tIPPOOL\tPOD\tPOD UID\tNIC\tMAC")
+			for _, a := range allocations {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", a.IP, a.IPPool, a.Pod, a.PodUID, a.NIC, a.MAC)
+			}
+			return w.Flush()
+		default:
+			return fmt.Errorf("unknown output format '%s', expect 'table' or 'json'", output)
+		}
 	},
 }
 
+// ipAllocation is an allocated IP address of a SpiderIPPool.
+type ipAllocation struct {
+	IP     string `json:"ip"`
+	IPPool string `json:"ippool"`
+	Pod    string `json:"pod"`
+	PodUID string `json:"podUid"`
+	NIC    string `json:"interface"`
+	MAC    string `json:"mac,omitempty"`
+}
+
+// listIPAllocations lists the allocated IP addresses of all SpiderIPPools,
+// which are recorded in their status or SpiderIPClaims, or only the specified
+// one if ip is not empty.
+func listIPAllocations(ctx context.Context, c client.Client, ip string) ([]ipAllocation, error) {
+	var ipPoolList spiderpoolv2beta1.SpiderIPPoolList
+	if err := c.List(ctx, &ipPoolList); err != nil {
+		return nil, fmt.Errorf("failed to list IPPools: %v", err)
+	}
+	var claimList spiderpoolv2beta1.SpiderIPClaimList
+	if err := c.List(ctx, &claimList); err != nil {
+		return nil, fmt.Errorf("failed to list IPClaims: %v", err)
+	}
+
+	poolToRecords := map[string]spiderpoolv2beta1.PoolIPAllocations{}
+	for _, pool := range ipPoolList.Items {
+		records, err := convert.UnmarshalIPPoolAllocatedIPs(pool.Status.AllocatedIPs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the allocated IPs of IPPool %s: %v", pool.Name, err)
+		}
+		if records == nil {
+			records = spiderpoolv2beta1.PoolIPAllocations{}
+		}
+		poolToRecords[pool.Name] = records
+	}
+	for _, claim := range claimList.Items {
+		records, ok := poolToRecords[claim.Spec.IPPool]
+		if !ok || claim.DeletionTimestamp != nil {
+			continue
+		}
+		records[claim.Spec.IP] = spiderpoolv2beta1.PoolIPAllocation{
+			NIC:            claim.Spec.NIC,
+			NamespacedName: claim.Spec.NamespacedName,
+			PodUID:         claim.Spec.PodUID,
+			MAC:            claim.Spec.MAC,
+		}
+	}
+
+	var allocations []ipAllocation
+	for poolName, records := range poolToRecords {
+		for recordIP, record := range records {
+			if ip != "" && recordIP != ip {
+				continue
+			}
+			allocations = append(allocations, ipAllocation{
+				IP:     recordIP,
+				IPPool: poolName,
+				Pod:    record.NamespacedName,
+				PodUID: record.PodUID,
+				NIC:    record.NIC,
+				MAC:    record.MAC,
+			})
+		}
+	}
+
+	sort.Slice(allocations, func(i, j int) bool {
+		if allocations[i].IPPool != allocations[j].IPPool {
+			return allocations[i].IPPool < allocations[j].IPPool
+		}
+		return allocations[i].IP < allocations[j].IP
+	})
+
+	return allocations, nil
+}
+
 // ipReleaseCmd represents the release command.
 var ipReleaseCmd = &cobra.Command{
 	Use:   "release",
@@ -44,9 +166,93 @@ var ipSetCmd = &cobra.Command{
 	},
 }
 
+// ipHistoryCmd represents the history command.
+var ipHistoryCmd = &cobra.Command{
+	Use:   "history <ip>",
+	Short: "show the allocation history of ip",
+	Long: `show the allocate, release and GC records of ip in the audit log of spiderpool-agent and spiderpool-controller, which answers which pod held the ip at a given time.
+Run it on a node where the audit log directory is mounted, or collect the audit logs of all nodes into one directory`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cmd.Flags().GetString("dir")
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		filter := audit.Filter{IP: args[0]}
+		if filter.Since, err = parseTimeFlag(cmd, "since"); err != nil {
+			return err
+		}
+		if filter.Until, err = parseTimeFlag(cmd, "until"); err != nil {
+			return err
+		}
+		at, err := parseTimeFlag(cmd, "at")
+		if err != nil {
+			return err
+		}
+
+		if !at.IsZero() {
+			// the allocation may happen long before, so look up the whole history
+			filter.Since, filter.Until = time.Time{}, time.Time{}
+		}
+
+		records, err := audit.ReadRecords(dir, filter)
+		if err != nil {
+			return fmt.Errorf("failed to read audit log: %v", err)
+		}
+
+		if !at.IsZero() {
+			holder := audit.HolderAt(records, at)
+			records = nil
+			if holder != nil {
+				records = append(records, *holder)
+			}
+		}
+
+		switch output {
+		case "json":
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			if records == nil {
+				records = []audit.Record{}
+			}
+			return encoder.Encode(records)
+		case "table":
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TIME\tACTION\tIPPOOL\tPOD\tPOD UID\tNODE\tNIC\tMAC\tCOMPONENT")
+			for _, r := range records {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s/%s\t%s\t%s\t%s\t%s\t%s\n",
+					r.Time.Format(time.RFC3339), r.Action, r.IPPool, r.Namespace, r.Pod, r.PodUID, r.Node, r.NIC, r.MAC, r.Component)
+			}
+			return w.Flush()
+		default:
+			return fmt.Errorf("unknown output format '%s', expect 'table' or 'json'", output)
+		}
+	},
+}
+
+func parseTimeFlag(cmd *cobra.Command, name string) (time.Time, error) {
+	value, err := cmd.Flags().GetString(name)
+	if err != nil || value == "" {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid flag --%s '%s', expect RFC3339 format such as 2023-01-01T00:00:00Z", name, value)
+	}
+
+	return t, nil
+}
+
 func init() {
 	// show flags
 	ipShowCmd.PersistentFlags().String("ip", "", "[optional] ip")
+	ipShowCmd.PersistentFlags().StringP("output", "o", "table", "[optional] output format, 'table' or 'json'")
 
 	// release flags
 	ipReleaseCmd.PersistentFlags().String("ip", "", "[required] ip")
@@ -89,8 +295,16 @@ func init() {
 		logger.Error(err.Error())
 	}
 
+	// history flags
+	ipHistoryCmd.PersistentFlags().String("dir", audit.DefaultDir, "[optional] directory of the audit log")
+	ipHistoryCmd.PersistentFlags().String("since", "", "[optional] only show the records since the time, in RFC3339 format")
+	ipHistoryCmd.PersistentFlags().String("until", "", "[optional] only show the records until the time, in RFC3339 format")
+	ipHistoryCmd.PersistentFlags().String("at", "", "[optional] only show the allocation of the pod holding the ip at the time, in RFC3339 format, --since and --until are ignored")
+	ipHistoryCmd.PersistentFlags().StringP("output", "o", "table", "[optional] output format, 'table' or 'json'")
+
 	rootCmd.AddCommand(ipCmd)
 	ipCmd.AddCommand(ipShowCmd)
 	ipCmd.AddCommand(ipReleaseCmd)
 	ipCmd.AddCommand(ipSetCmd)
+	ipCmd.AddCommand(ipHistoryCmd)
 }
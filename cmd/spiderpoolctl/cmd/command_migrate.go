@@ -0,0 +1,109 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/spidernet-io/spiderpool/pkg/ipammigration"
+)
+
+// migrateCmd represents the migrate command.
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "migrate IP allocations from Whereabouts or host-local",
+	Long: `create a SpiderIPPool for each IP range of Whereabouts or host-local, and record the IP addresses in use by running Pods in the SpiderIPPools and SpiderEndpoints, so that the Pods keep their IP addresses when handed over to Spiderpool.
+Whereabouts is read from NetworkAttachmentDefinitions and Whereabouts CRs. host-local is read from the CNI configuration files and the data directory of a node, so run it on each node`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source, err := cmd.Flags().GetString("source")
+		if err != nil {
+			return err
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+		createSubnets, err := cmd.Flags().GetBool("create-subnets")
+		if err != nil {
+			return err
+		}
+
+		c, err := newClient()
+		if err != nil {
+			return fmt.Errorf("failed to create k8s client: %v", err)
+		}
+
+		var ranges []ipammigration.Range
+		switch source {
+		case ipammigration.SourceWhereabouts:
+			if ranges, err = ipammigration.ReadWhereabouts(cmd.Context(), c); err != nil {
+				return err
+			}
+		case ipammigration.SourceHostLocal:
+			opts := ipammigration.HostLocalOptions{}
+			if opts.ConfDir, err = cmd.Flags().GetString("cni-conf-dir"); err != nil {
+				return err
+			}
+			if opts.DataDir, err = cmd.Flags().GetString("host-local-data-dir"); err != nil {
+				return err
+			}
+			if opts.Node, err = cmd.Flags().GetString("node"); err != nil {
+				return err
+			}
+			if opts.Node == "" {
+				return fmt.Errorf("flag --node is required for source %s", source)
+			}
+
+			var node corev1.Node
+			if err := c.Get(cmd.Context(), apitypes.NamespacedName{Name: opts.Node}, &node); err != nil {
+				return fmt.Errorf("failed to get node %s: %v", opts.Node, err)
+			}
+			opts.PodCIDRs = node.Spec.PodCIDRs
+
+			if ranges, err = ipammigration.ReadHostLocal(opts); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown source '%s', expect '%s' or '%s'", source, ipammigration.SourceWhereabouts, ipammigration.SourceHostLocal)
+		}
+
+		if len(ranges) == 0 {
+			logger.Sugar().Infof("No IP range of %s found", source)
+			return nil
+		}
+
+		changes, err := ipammigration.Migrate(cmd.Context(), c, ranges, ipammigration.Options{
+			DryRun:        dryRun,
+			CreateSubnets: createSubnets,
+		})
+		for _, change := range changes {
+			fmt.Fprintf(cmd.OutOrStdout(), "%-9s %s/%s", change.Action, change.Kind, change.Name)
+			if change.Reason != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), " (%s)", change.Reason)
+			}
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+
+		return err
+	},
+}
+
+func init() {
+	migrateCmd.PersistentFlags().String("source", "", "[required] IPAM plugin to migrate from, 'whereabouts' or 'host-local'")
+	migrateCmd.PersistentFlags().Bool("dry-run", false, "[optional] only show the changes")
+	migrateCmd.PersistentFlags().Bool("create-subnets", false, "[optional] create a SpiderSubnet for each IP range as well, which requires the SpiderSubnet feature")
+	migrateCmd.PersistentFlags().String("node", "", "[optional] node to migrate host-local on, required for host-local")
+	migrateCmd.PersistentFlags().String("cni-conf-dir", ipammigration.DefaultCNIConfDir, "[optional] directory of CNI configuration files for host-local")
+	migrateCmd.PersistentFlags().String("host-local-data-dir", "", "[optional] override the data directory of host-local, default to the 'dataDir' of its configuration or "+ipammigration.DefaultHostLocalDataDir)
+
+	if err := migrateCmd.MarkPersistentFlagRequired("source"); err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/spidernet-io/spiderpool/pkg/ipamstate"
+)
+
+// stateCmd represents the state command.
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "backup and restore Spiderpool IPAM state",
+	Long:  `export or import SpiderSubnets, SpiderIPPools, SpiderIPClaims, SpiderReservedIPs and SpiderEndpoints, including their status`,
+}
+
+// stateExportCmd represents the export command.
+var stateExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export Spiderpool IPAM state to an archive",
+	Long:  `export all Spiderpool IPAM resources to a versioned archive, which is gzip-compressed if the output file ends with .gz`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		c, err := newClient()
+		if err != nil {
+			return fmt.Errorf("failed to create k8s client: %v", err)
+		}
+
+		archive, err := ipamstate.Export(cmd.Context(), c)
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = cmd.OutOrStdout()
+		if output != "-" {
+			f, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+
+		if err := ipamstate.WriteArchive(w, archive, strings.HasSuffix(output, ".gz")); err != nil {
+			return fmt.Errorf("failed to write archive: %v", err)
+		}
+
+		logger.Sugar().Infof("Exported %d Subnets, %d IPPools, %d IPClaims, %d ReservedIPs and %d Endpoints",
+			len(archive.Subnets), len(archive.IPPools), len(archive.IPClaims), len(archive.ReservedIPs), len(archive.Endpoints))
+
+		return nil
+	},
+}
+
+// stateImportCmd represents the import command.
+var stateImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "import Spiderpool IPAM state from an archive",
+	Long:  `validate the archive, create the archived resources absent from the cluster and restore their status. The existing resources are never overwritten, the different ones are reported as conflicts`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input, err := cmd.Flags().GetString("input")
+		if err != nil {
+			return err
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		var r io.Reader = cmd.InOrStdin()
+		if input != "-" {
+			f, err := os.Open(input)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+
+		archive, err := ipamstate.ReadArchive(r)
+		if err != nil {
+			return err
+		}
+
+		c, err := newClient()
+		if err != nil {
+			return fmt.Errorf("failed to create k8s client: %v", err)
+		}
+
+		changes, err := ipamstate.Import(cmd.Context(), c, archive, ipamstate.ImportOptions{DryRun: dryRun})
+		for _, change := range changes {
+			fmt.Fprintf(cmd.OutOrStdout(), "%-9s %s/%s\n", change.Action, change.Kind, change.Name)
+			if change.Diff != "" {
+				fmt.Fprint(cmd.OutOrStdout(), change.Diff)
+			}
+		}
+
+		return err
+	},
+}
+
+func init() {
+	stateExportCmd.PersistentFlags().StringP("output", "o", "-", "[optional] archive file, default to stdout")
+
+	stateImportCmd.PersistentFlags().StringP("input", "f", "-", "[optional] archive file, default to stdin")
+	stateImportCmd.PersistentFlags().Bool("dry-run", false, "[optional] only show the difference between the archive and the cluster")
+
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateExportCmd)
+	stateCmd.AddCommand(stateImportCmd)
+}
@@ -0,0 +1,200 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/lock"
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+)
+
+type Action string
+
+const (
+	// ActionAllocate is recorded when an IP address is allocated to a Pod.
+	ActionAllocate Action = "allocate"
+	// ActionRelease is recorded when an IP address is released by CNI DEL.
+	ActionRelease Action = "release"
+	// ActionGC is recorded when an IP address is reclaimed by the garbage
+	// collection of spiderpool-controller.
+	ActionGC Action = "gc"
+)
+
+// DefaultDir is where the audit records are appended to by default.
+const DefaultDir = "/var/log/spidernet/audit"
+
+// Record is an audit record of an IP address allocation or release.
+type Record struct {
+	Time        time.Time `json:"time"`
+	Action      Action    `json:"action"`
+	Component   string    `json:"component"`
+	IP          string    `json:"ip"`
+	IPPool      string    `json:"ippool,omitempty"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Pod         string    `json:"pod,omitempty"`
+	PodUID      string    `json:"podUID,omitempty"`
+	Node        string    `json:"node,omitempty"`
+	NIC         string    `json:"nic,omitempty"`
+	MAC         string    `json:"mac,omitempty"`
+	ContainerID string    `json:"containerID,omitempty"`
+}
+
+// Sink is where the audit records are exported to.
+type Sink interface {
+	Write(r Record) error
+	Close() error
+}
+
+type Config struct {
+	// Component is spiderpool-agent or spiderpool-controller, which names
+	// the file the records are appended to.
+	Component string
+	// Node is recorded when the record does not specify it.
+	Node string
+
+	// Dir is the directory of the JSON lines file, the file sink is
+	// disabled if it is empty.
+	Dir string
+	// MaxSize is the maximum size in megabytes of the file before rotated.
+	MaxSize int
+	// MaxAge is the maximum days to retain the rotated files.
+	MaxAge int
+	// MaxBackups is the maximum number of the rotated files to retain.
+	MaxBackups int
+
+	// WebhookURL is optional, the records are posted to it in JSON.
+	WebhookURL string
+	// SyslogAddress is optional, such as udp://10.6.0.1:514 or
+	// unix:///dev/log.
+	SyslogAddress string
+}
+
+// Recorder writes the audit records to all sinks. The zero value records
+// nothing.
+type Recorder struct {
+	component string
+	node      string
+	sinks     []Sink
+	logger    *zap.Logger
+}
+
+var (
+	// DefaultRecorder is Singleton, it records nothing until Init is called.
+	DefaultRecorder = &Recorder{}
+	recorderLock    lock.RWMutex
+)
+
+// NewRecorder creates the sinks of the config.
+func NewRecorder(config Config) (*Recorder, error) {
+	if config.Component == "" {
+		return nil, fmt.Errorf("component %w", constant.ErrMissingRequiredParam)
+	}
+
+	r := &Recorder{
+		component: config.Component,
+		node:      config.Node,
+		logger:    logutils.Logger.Named("Audit"),
+	}
+
+	if config.Dir != "" {
+		r.sinks = append(r.sinks, NewFileSink(filepath.Join(config.Dir, config.Component+".log"), config.MaxSize, config.MaxAge, config.MaxBackups))
+	}
+
+	if config.WebhookURL != "" {
+		sink, err := NewWebhookSink(config.WebhookURL)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		r.sinks = append(r.sinks, sink)
+	}
+
+	if config.SyslogAddress != "" {
+		sink, err := NewSyslogSink(config.SyslogAddress, config.Component)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		r.sinks = append(r.sinks, sink)
+	}
+
+	return r, nil
+}
+
+// Init replaces the DefaultRecorder with the one of the config.
+func Init(config Config) error {
+	r, err := NewRecorder(config)
+	if err != nil {
+		return err
+	}
+
+	recorderLock.Lock()
+	DefaultRecorder = r
+	recorderLock.Unlock()
+
+	return nil
+}
+
+// Close flushes and closes the sinks of the DefaultRecorder.
+func Close() {
+	recorderLock.RLock()
+	recorder := DefaultRecorder
+	recorderLock.RUnlock()
+
+	recorder.Close()
+}
+
+// Emit writes the record with the DefaultRecorder.
+func Emit(ctx context.Context, r Record) {
+	recorderLock.RLock()
+	recorder := DefaultRecorder
+	recorderLock.RUnlock()
+
+	recorder.Record(ctx, r)
+}
+
+// Record fills the time, component and node of the record if absent, and
+// writes it to all sinks. A failure of a sink is logged, it never fails the
+// IPAM operation.
+func (r *Recorder) Record(ctx context.Context, record Record) {
+	if r == nil || len(r.sinks) == 0 {
+		return
+	}
+
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+	if record.Component == "" {
+		record.Component = r.component
+	}
+	if record.Node == "" {
+		record.Node = r.node
+	}
+
+	for _, sink := range r.sinks {
+		if err := sink.Write(record); err != nil {
+			logutils.FromContext(ctx).Sugar().Warnf("Failed to write audit record %+v: %v", record, err)
+		}
+	}
+}
+
+// Close flushes and closes all sinks.
+func (r *Recorder) Close() {
+	if r == nil {
+		return
+	}
+
+	for _, sink := range r.sinks {
+		if err := sink.Close(); err != nil && r.logger != nil {
+			r.logger.Sugar().Warnf("Failed to close audit sink: %v", err)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+)
+
+var _ = Describe("Audit", Label("audit_test"), func() {
+	var dir string
+	var now time.Time
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	Describe("Recorder", func() {
+		It("requires the component", func() {
+			_, err := NewRecorder(Config{Dir: dir})
+			Expect(err).To(MatchError(constant.ErrMissingRequiredParam))
+		})
+
+		It("rejects an invalid webhook URL", func() {
+			_, err := NewRecorder(Config{Component: "spiderpool-agent", WebhookURL: "ftp://10.6.0.1"})
+			Expect(err).To(MatchError(constant.ErrWrongInput))
+		})
+
+		It("rejects an invalid syslog address", func() {
+			_, err := NewRecorder(Config{Component: "spiderpool-agent", SyslogAddress: "http://10.6.0.1:514"})
+			Expect(err).To(MatchError(constant.ErrWrongInput))
+		})
+
+		It("records nothing by default", func() {
+			Expect(func() { Emit(context.TODO(), Record{IP: "10.6.0.1"}) }).NotTo(Panic())
+		})
+
+		It("appends the records to the file of the component", func() {
+			r, err := NewRecorder(Config{Component: "spiderpool-agent", Node: "node1", Dir: dir})
+			Expect(err).NotTo(HaveOccurred())
+
+			r.Record(context.TODO(), Record{Action: ActionAllocate, IP: "10.6.0.1", Pod: "pod1"})
+			r.Record(context.TODO(), Record{Action: ActionRelease, IP: "10.6.0.1", Pod: "pod1", Node: "node2"})
+			r.Close()
+
+			records, err := ReadRecords(dir, Filter{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(2))
+			Expect(records[0].Action).To(Equal(ActionAllocate))
+			Expect(records[0].Component).To(Equal("spiderpool-agent"))
+			Expect(records[0].Node).To(Equal("node1"))
+			Expect(records[0].Time.IsZero()).To(BeFalse())
+			Expect(records[1].Node).To(Equal("node2"))
+
+			_, err = os.Stat(filepath.Join(dir, "spiderpool-agent.log"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("posts the records to the webhook", func() {
+			received := make(chan Record, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+				var r Record
+				Expect(json.NewDecoder(req.Body).Decode(&r)).To(Succeed())
+				received <- r
+			}))
+			defer server.Close()
+
+			r, err := NewRecorder(Config{Component: "spiderpool-controller", WebhookURL: server.URL})
+			Expect(err).NotTo(HaveOccurred())
+
+			r.Record(context.TODO(), Record{Action: ActionGC, IP: "10.6.0.1"})
+			var r1 Record
+			Eventually(received).Should(Receive(&r1))
+			Expect(r1.Action).To(Equal(ActionGC))
+			Expect(r1.Component).To(Equal("spiderpool-controller"))
+			r.Close()
+		})
+	})
+
+	Describe("ReadRecords", func() {
+		writeFile := func(name string, compress bool, records ...Record) {
+			f, err := os.Create(filepath.Join(dir, name))
+			Expect(err).NotTo(HaveOccurred())
+			defer f.Close()
+
+			var encoder *json.Encoder
+			if compress {
+				gz := gzip.NewWriter(f)
+				defer gz.Close()
+				encoder = json.NewEncoder(gz)
+			} else {
+				encoder = json.NewEncoder(f)
+			}
+			for _, r := range records {
+				Expect(encoder.Encode(r)).To(Succeed())
+			}
+		}
+
+		It("rejects an invalid IP address", func() {
+			_, err := ReadRecords(dir, Filter{IP: "10.6.0"})
+			Expect(err).To(MatchError(constant.ErrWrongInput))
+		})
+
+		It("fails if the directory does not exist", func() {
+			_, err := ReadRecords(filepath.Join(dir, "none"), Filter{})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("reads the rotated files and filters the records", func() {
+			writeFile("spiderpool-agent-2023-01-01T00-00-00.000.log.gz", true,
+				Record{Time: now, Action: ActionAllocate, IP: "10.6.0.1", PodUID: "1"},
+				Record{Time: now.Add(time.Minute), Action: ActionAllocate, IP: "10.6.0.2", PodUID: "2"},
+			)
+			writeFile("spiderpool-agent.log", false,
+				Record{Time: now.Add(3 * time.Minute), Action: ActionAllocate, IP: "10.6.0.1", PodUID: "3"},
+			)
+			writeFile("spiderpool-controller.log", false,
+				Record{Time: now.Add(2 * time.Minute), Action: ActionGC, IP: "10.6.0.1", PodUID: "1"},
+			)
+			writeFile("other.txt", false, Record{Time: now, IP: "10.6.0.1"})
+			f, err := os.OpenFile(filepath.Join(dir, "spiderpool-agent.log"), os.O_APPEND|os.O_WRONLY, 0)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = f.WriteString("{\"ip\":")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Close()).To(Succeed())
+
+			records, err := ReadRecords(dir, Filter{IP: "10.6.0.1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(3))
+			Expect(records[0].PodUID).To(Equal("1"))
+			Expect(records[1].Action).To(Equal(ActionGC))
+			Expect(records[2].PodUID).To(Equal("3"))
+
+			records, err = ReadRecords(dir, Filter{Since: now.Add(time.Minute), Until: now.Add(2 * time.Minute)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(2))
+			Expect(records[0].IP).To(Equal("10.6.0.2"))
+		})
+	})
+
+	Describe("HolderAt", func() {
+		records := func() []Record {
+			return []Record{
+				{Time: now, Action: ActionAllocate, IP: "10.6.0.1", PodUID: "1"},
+				{Time: now.Add(time.Minute), Action: ActionRelease, IP: "10.6.0.1", PodUID: "1"},
+				{Time: now.Add(2 * time.Minute), Action: ActionAllocate, IP: "10.6.0.1", PodUID: "2"},
+				{Time: now.Add(3 * time.Minute), Action: ActionGC, IP: "10.6.0.1", PodUID: "3"},
+			}
+		}
+
+		It("returns the Pod holding the IP address", func() {
+			Expect(HolderAt(records(), now.Add(-time.Second))).To(BeNil())
+			Expect(HolderAt(records(), now.Add(30*time.Second)).PodUID).To(Equal("1"))
+			Expect(HolderAt(records(), now.Add(90*time.Second))).To(BeNil())
+			Expect(HolderAt(records(), now.Add(4*time.Minute)).PodUID).To(Equal("2"))
+		})
+	})
+})
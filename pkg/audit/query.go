@@ -0,0 +1,143 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+)
+
+// Filter selects the records, the zero value of a field matches all.
+type Filter struct {
+	IP    string
+	Since time.Time
+	Until time.Time
+}
+
+func (f Filter) match(r Record) bool {
+	if f.IP != "" && r.IP != f.IP {
+		return false
+	}
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && r.Time.After(f.Until) {
+		return false
+	}
+
+	return true
+}
+
+// ReadRecords reads the records matching the filter from all the audit files
+// in the directory, including the rotated and compressed ones, sorted by
+// time.
+func ReadRecords(dir string, filter Filter) ([]Record, error) {
+	if filter.IP != "" {
+		ip, err := netip.ParseAddr(filter.IP)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid IP address '%s'", constant.ErrWrongInput, filter.IP)
+		}
+		filter.IP = ip.String()
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.Contains(entry.Name(), ".log") {
+			continue
+		}
+
+		rs, err := readFile(filepath.Join(dir, entry.Name()), filter)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rs...)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+
+	return records, nil
+}
+
+func readFile(path string, filter Filter) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var reader io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	var records []Record
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var r Record
+		if err := json.Unmarshal(line, &r); err != nil {
+			// the last line may be truncated by a crash
+			continue
+		}
+		if filter.match(r) {
+			records = append(records, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return records, nil
+}
+
+// HolderAt returns the allocate record of the Pod holding the IP address at
+// the time, the records must be of the IP address and sorted by time. It
+// returns nil if the IP address is free or its allocation is not recorded.
+func HolderAt(records []Record, at time.Time) *Record {
+	var holder *Record
+	for i := range records {
+		r := records[i]
+		if r.Time.After(at) {
+			break
+		}
+
+		switch r.Action {
+		case ActionAllocate:
+			holder = &records[i]
+		case ActionRelease, ActionGC:
+			if holder != nil && (r.PodUID == "" || r.PodUID == holder.PodUID) {
+				holder = nil
+			}
+		}
+	}
+
+	return holder
+}
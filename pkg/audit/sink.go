@@ -0,0 +1,193 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/lock"
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+)
+
+// fileSink appends the records to a JSON lines file with rotation.
+type fileSink struct {
+	lock   lock.Mutex
+	writer *lumberjack.Logger
+}
+
+func NewFileSink(path string, maxSize, maxAge, maxBackups int) Sink {
+	return &fileSink{
+		writer: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    maxSize,
+			MaxAge:     maxAge,
+			MaxBackups: maxBackups,
+			LocalTime:  true,
+		},
+	}
+}
+
+func (s *fileSink) Write(r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, err = s.writer.Write(line)
+	return err
+}
+
+func (s *fileSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.writer.Close()
+}
+
+const (
+	webhookBufferSize = 1024
+	webhookTimeout    = 5 * time.Second
+)
+
+// webhookSink posts the records to the URL in background, so that a slow
+// receiver never blocks the IPAM. The records are dropped once the buffer
+// is full.
+type webhookSink struct {
+	url    string
+	client *http.Client
+	queue  chan Record
+	done   chan struct{}
+}
+
+func NewWebhookSink(rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid audit webhook URL '%s': %v", constant.ErrWrongInput, rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%w: invalid audit webhook URL '%s', expect http or https scheme", constant.ErrWrongInput, rawURL)
+	}
+
+	s := &webhookSink{
+		url:    rawURL,
+		client: &http.Client{Timeout: webhookTimeout},
+		queue:  make(chan Record, webhookBufferSize),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+
+	return s, nil
+}
+
+func (s *webhookSink) Write(r Record) error {
+	select {
+	case s.queue <- r:
+		return nil
+	default:
+		return fmt.Errorf("audit webhook buffer is full, drop the record")
+	}
+}
+
+func (s *webhookSink) run() {
+	defer close(s.done)
+
+	logger := logutils.Logger.Named("Audit")
+	for r := range s.queue {
+		if err := s.post(r); err != nil {
+			logger.Sugar().Warnf("Failed to post audit record %+v to %s: %v", r, s.url, err)
+		}
+	}
+}
+
+func (s *webhookSink) post(r Record) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Close posts the buffered records before returning.
+func (s *webhookSink) Close() error {
+	close(s.queue)
+	<-s.done
+
+	return nil
+}
+
+// syslogSink sends the records in JSON to a syslog server.
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+func NewSyslogSink(address, tag string) (Sink, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid audit syslog address '%s': %v", constant.ErrWrongInput, address, err)
+	}
+
+	var raddr string
+	switch u.Scheme {
+	case "udp", "tcp":
+		raddr = u.Host
+	case "unix", "unixgram":
+		raddr = u.Path
+	default:
+		return nil, fmt.Errorf("%w: invalid audit syslog address '%s', expect udp, tcp, unix or unixgram scheme", constant.ErrWrongInput, address)
+	}
+
+	writer, err := syslog.Dial(u.Scheme, raddr, syslog.LOG_INFO|syslog.LOG_AUTH, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog %s: %w", address, err)
+	}
+
+	return &syslogSink{writer: writer}, nil
+}
+
+func (s *syslogSink) Write(r Record) error {
+	msg, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return s.writer.Info(string(msg))
+}
+
+func (s *syslogSink) Close() error {
+	return s.writer.Close()
+}
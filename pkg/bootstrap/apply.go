@@ -0,0 +1,347 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
+)
+
+type Action string
+
+const (
+	// ActionCreate means the object is created.
+	ActionCreate Action = "create"
+	// ActionUpdate means the object created by the bootstrap earlier is
+	// updated to the manifest.
+	ActionUpdate Action = "update"
+	// ActionUnchanged means the object is the same as the manifest.
+	ActionUnchanged Action = "unchanged"
+	// ActionSkip means the object is left untouched, because it is not
+	// created by the bootstrap, or it is still in use when pruning.
+	ActionSkip Action = "skip"
+	// ActionPrune means the object created by the bootstrap earlier is
+	// deleted, because it is no longer in the manifest.
+	ActionPrune Action = "prune"
+)
+
+// Change describes what the bootstrap does to an object.
+type Change struct {
+	Kind   string
+	Name   string
+	Action Action
+	Reason string
+}
+
+type Options struct {
+	// Prune deletes the objects created by the bootstrap earlier but no
+	// longer in the manifest.
+	Prune bool
+}
+
+// kindHandler lists the objects of a kind created by the bootstrap, and
+// tells why one of them can not be pruned.
+type kindHandler struct {
+	kind    string
+	newList func() client.ObjectList
+	items   func(client.ObjectList) []client.Object
+	inUse   func(context.Context, client.Client, client.Object) (string, error)
+}
+
+// kindHandlers are in the order the objects are applied, the objects are
+// pruned in the reverse order.
+var kindHandlers = []kindHandler{
+	{
+		kind:    constant.KindSpiderCoordinator,
+		newList: func() client.ObjectList { return &spiderpoolv2beta1.SpiderCoordinatorList{} },
+		items: func(list client.ObjectList) []client.Object {
+			var objs []client.Object
+			for i := range list.(*spiderpoolv2beta1.SpiderCoordinatorList).Items {
+				objs = append(objs, &list.(*spiderpoolv2beta1.SpiderCoordinatorList).Items[i])
+			}
+			return objs
+		},
+	},
+	{
+		kind:    constant.KindSpiderSubnet,
+		newList: func() client.ObjectList { return &spiderpoolv2beta1.SpiderSubnetList{} },
+		items: func(list client.ObjectList) []client.Object {
+			var objs []client.Object
+			for i := range list.(*spiderpoolv2beta1.SpiderSubnetList).Items {
+				objs = append(objs, &list.(*spiderpoolv2beta1.SpiderSubnetList).Items[i])
+			}
+			return objs
+		},
+		inUse: func(_ context.Context, _ client.Client, obj client.Object) (string, error) {
+			subnet := obj.(*spiderpoolv2beta1.SpiderSubnet)
+			if subnet.Status.AllocatedIPCount != nil && *subnet.Status.AllocatedIPCount > 0 {
+				return fmt.Sprintf("%d IP addresses allocated to IPPools", *subnet.Status.AllocatedIPCount), nil
+			}
+			return "", nil
+		},
+	},
+	{
+		kind:    constant.KindSpiderReservedIP,
+		newList: func() client.ObjectList { return &spiderpoolv2beta1.SpiderReservedIPList{} },
+		items: func(list client.ObjectList) []client.Object {
+			var objs []client.Object
+			for i := range list.(*spiderpoolv2beta1.SpiderReservedIPList).Items {
+				objs = append(objs, &list.(*spiderpoolv2beta1.SpiderReservedIPList).Items[i])
+			}
+			return objs
+		},
+	},
+	{
+		kind:    constant.KindSpiderIPPool,
+		newList: func() client.ObjectList { return &spiderpoolv2beta1.SpiderIPPoolList{} },
+		items: func(list client.ObjectList) []client.Object {
+			var objs []client.Object
+			for i := range list.(*spiderpoolv2beta1.SpiderIPPoolList).Items {
+				objs = append(objs, &list.(*spiderpoolv2beta1.SpiderIPPoolList).Items[i])
+			}
+			return objs
+		},
+		inUse: ipPoolInUse,
+	},
+	{
+		kind:    constant.KindSpiderMultusConfig,
+		newList: func() client.ObjectList { return &spiderpoolv2beta1.SpiderMultusConfigList{} },
+		items: func(list client.ObjectList) []client.Object {
+			var objs []client.Object
+			for i := range list.(*spiderpoolv2beta1.SpiderMultusConfigList).Items {
+				objs = append(objs, &list.(*spiderpoolv2beta1.SpiderMultusConfigList).Items[i])
+			}
+			return objs
+		},
+	},
+}
+
+// Apply creates the objects of the manifest in the order of Coordinators,
+// Subnets, ReservedIPs, IPPools and MultusConfigs, the created objects are
+// labeled with constant.LabelBootstrapManaged. The existing objects with the
+// label are updated to the manifest, the ones without it are left untouched.
+// With Options.Prune, the labeled objects no longer in the manifest are
+// deleted in the reverse order, unless their IP addresses are still
+// allocated. It is safe to run again.
+func Apply(ctx context.Context, c client.Client, manifest *Manifest, opts Options) ([]Change, error) {
+	a := &applier{client: c}
+
+	objs := map[string][]client.Object{}
+	for i := range manifest.Coordinators {
+		objs[constant.KindSpiderCoordinator] = append(objs[constant.KindSpiderCoordinator], &manifest.Coordinators[i])
+	}
+	for i := range manifest.Subnets {
+		objs[constant.KindSpiderSubnet] = append(objs[constant.KindSpiderSubnet], &manifest.Subnets[i])
+	}
+	for i := range manifest.ReservedIPs {
+		objs[constant.KindSpiderReservedIP] = append(objs[constant.KindSpiderReservedIP], &manifest.ReservedIPs[i])
+	}
+	for i := range manifest.IPPools {
+		objs[constant.KindSpiderIPPool] = append(objs[constant.KindSpiderIPPool], &manifest.IPPools[i])
+	}
+	for i := range manifest.MultusConfigs {
+		objs[constant.KindSpiderMultusConfig] = append(objs[constant.KindSpiderMultusConfig], &manifest.MultusConfigs[i])
+	}
+
+	for _, h := range kindHandlers {
+		for _, obj := range objs[h.kind] {
+			if err := a.apply(ctx, h.kind, obj); err != nil {
+				return a.changes, fmt.Errorf("failed to apply %s %s: %w", h.kind, objectName(obj), err)
+			}
+		}
+	}
+
+	if !opts.Prune {
+		return a.changes, nil
+	}
+
+	for i := len(kindHandlers) - 1; i >= 0; i-- {
+		h := kindHandlers[i]
+		listed := map[string]struct{}{}
+		for _, obj := range objs[h.kind] {
+			listed[objectName(obj)] = struct{}{}
+		}
+		if err := a.prune(ctx, h, listed); err != nil {
+			return a.changes, fmt.Errorf("failed to prune %s: %w", h.kind, err)
+		}
+	}
+
+	return a.changes, nil
+}
+
+type applier struct {
+	client  client.Client
+	changes []Change
+}
+
+func (a *applier) record(change Change) {
+	a.changes = append(a.changes, change)
+}
+
+func (a *applier) apply(ctx context.Context, kind string, obj client.Object) error {
+	name := objectName(obj)
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[constant.LabelBootstrapManaged] = constant.True
+	obj.SetLabels(labels)
+
+	live := obj.DeepCopyObject().(client.Object)
+	err := a.client.Get(ctx, client.ObjectKeyFromObject(obj), live)
+	if apierrors.IsNotFound(err) {
+		if err := a.client.Create(ctx, obj); err != nil {
+			return err
+		}
+		a.record(Change{Kind: kind, Name: name, Action: ActionCreate})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if live.GetLabels()[constant.LabelBootstrapManaged] != constant.True {
+		a.record(Change{Kind: kind, Name: name, Action: ActionSkip, Reason: "not created by bootstrap"})
+		return nil
+	}
+
+	desired, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	current, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+	if err != nil {
+		return err
+	}
+
+	// Only the fields in the manifest are compared and updated, so that the
+	// fields defaulted by the webhook are kept.
+	merged := map[string]interface{}{}
+	for _, field := range []string{"spec", "metadata"} {
+		desiredField, _ := desired[field].(map[string]interface{})
+		currentField, _ := current[field].(map[string]interface{})
+		if field == "metadata" {
+			desiredField = map[string]interface{}{
+				"labels":      desiredField["labels"],
+				"annotations": desiredField["annotations"],
+			}
+		}
+		merged[field] = merge(currentField, desiredField)
+	}
+	if reflect.DeepEqual(merged["spec"], current["spec"]) && reflect.DeepEqual(merged["metadata"], current["metadata"]) {
+		a.record(Change{Kind: kind, Name: name, Action: ActionUnchanged})
+		return nil
+	}
+
+	current["spec"] = merged["spec"]
+	current["metadata"] = merged["metadata"]
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(current, live); err != nil {
+		return err
+	}
+	if err := a.client.Update(ctx, live); err != nil {
+		return err
+	}
+	a.record(Change{Kind: kind, Name: name, Action: ActionUpdate})
+
+	return nil
+}
+
+// merge returns a copy of current overwritten with the non-nil values of
+// desired, the nested maps are merged recursively.
+func merge(current, desired map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(current))
+	for k, v := range current {
+		merged[k] = v
+	}
+
+	for k, v := range desired {
+		if v == nil {
+			continue
+		}
+		desiredMap, ok := v.(map[string]interface{})
+		if !ok {
+			merged[k] = v
+			continue
+		}
+		currentMap, _ := merged[k].(map[string]interface{})
+		merged[k] = merge(currentMap, desiredMap)
+	}
+
+	return merged
+}
+
+func (a *applier) prune(ctx context.Context, h kindHandler, listed map[string]struct{}) error {
+	list := h.newList()
+	if err := a.client.List(ctx, list, client.MatchingLabels{constant.LabelBootstrapManaged: constant.True}); err != nil {
+		return err
+	}
+
+	for _, obj := range h.items(list) {
+		name := objectName(obj)
+		if _, ok := listed[name]; ok || obj.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		if h.inUse != nil {
+			reason, err := h.inUse(ctx, a.client, obj)
+			if err != nil {
+				return fmt.Errorf("failed to check whether %s %s is in use: %w", h.kind, name, err)
+			}
+			if reason != "" {
+				a.record(Change{Kind: h.kind, Name: name, Action: ActionSkip, Reason: reason})
+				continue
+			}
+		}
+
+		if err := a.client.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		a.record(Change{Kind: h.kind, Name: name, Action: ActionPrune})
+	}
+
+	return nil
+}
+
+// ipPoolInUse checks the IP allocation records of the IPPool instead of
+// 'status.allocatedIPCount', which lags behind the SpiderIPClaims of the
+// IPPool whose allocation storage is 'ipclaim'.
+func ipPoolInUse(ctx context.Context, c client.Client, obj client.Object) (string, error) {
+	ipPool := obj.(*spiderpoolv2beta1.SpiderIPPool)
+
+	records, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
+	if err != nil {
+		return "", err
+	}
+	count := len(records)
+
+	// The SpiderIPClaims are listed without the field index, which is not
+	// available on the client of spiderpool-init.
+	var claimList spiderpoolv2beta1.SpiderIPClaimList
+	if err := c.List(ctx, &claimList); err != nil {
+		return "", err
+	}
+	for _, claim := range claimList.Items {
+		if claim.Spec.IPPool != ipPool.Name || claim.DeletionTimestamp != nil {
+			continue
+		}
+		if _, ok := records[claim.Spec.IP]; !ok {
+			count++
+		}
+	}
+
+	if count > 0 {
+		return fmt.Sprintf("%d IP addresses allocated to Pods", count), nil
+	}
+
+	return "", nil
+}
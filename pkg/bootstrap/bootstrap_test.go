@@ -0,0 +1,236 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/spidernet-io/spiderpool/pkg/bootstrap"
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+)
+
+const manifestT = `
+apiVersion: spiderpool.spidernet.io/v2beta1
+kind: SpiderMultusConfig
+metadata:
+  name: macvlan-vlan100
+spec:
+  cniType: macvlan
+---
+apiVersion: spiderpool.spidernet.io/v2beta1
+kind: SpiderIPPool
+metadata:
+  name: vlan100-v4
+spec:
+  subnet: 172.100.0.0/16
+  ips:
+  - 172.100.0.10-172.100.0.200
+---
+{"apiVersion": "v1", "kind": "List", "items": [
+  {"apiVersion": "spiderpool.spidernet.io/v2beta1", "kind": "SpiderSubnet", "metadata": {"name": "vlan100-v4"}, "spec": {"subnet": "172.100.0.0/16", "ips": ["172.100.0.1-172.100.0.254"]}},
+  {"apiVersion": "spiderpool.spidernet.io/v2beta1", "kind": "SpiderReservedIP", "metadata": {"name": "vlan100-vip"}, "spec": {"ips": ["172.100.0.100"]}}
+]}
+`
+
+var _ = Describe("Bootstrap", Label("bootstrap_test"), func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+	})
+
+	Describe("ReadManifest", func() {
+		It("reads YAML documents and JSON lists", func() {
+			manifest, err := bootstrap.ReadManifest(strings.NewReader(manifestT), "kube-system")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest.Len()).To(Equal(4))
+
+			Expect(manifest.Subnets).To(HaveLen(1))
+			Expect(manifest.Subnets[0].Spec.IPs).To(Equal([]string{"172.100.0.1-172.100.0.254"}))
+			Expect(manifest.ReservedIPs).To(HaveLen(1))
+			Expect(manifest.IPPools).To(HaveLen(1))
+			Expect(manifest.IPPools[0].Spec.Subnet).To(Equal("172.100.0.0/16"))
+			Expect(manifest.MultusConfigs).To(HaveLen(1))
+			Expect(manifest.MultusConfigs[0].Namespace).To(Equal("kube-system"))
+		})
+
+		It("rejects the unsupported objects", func() {
+			_, err := bootstrap.ReadManifest(strings.NewReader("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n"), "kube-system")
+			Expect(err).To(MatchError(constant.ErrWrongInput))
+
+			_, err = bootstrap.ReadManifest(strings.NewReader("apiVersion: spiderpool.spidernet.io/v2beta1\nkind: SpiderEndpoint\nmetadata:\n  name: ep\n"), "kube-system")
+			Expect(err).To(MatchError(constant.ErrWrongInput))
+		})
+
+		It("rejects the duplicate objects", func() {
+			pool := "apiVersion: spiderpool.spidernet.io/v2beta1\nkind: SpiderIPPool\nmetadata:\n  name: pool\n"
+			_, err := bootstrap.ReadManifest(strings.NewReader(pool+"---\n"+pool), "kube-system")
+			Expect(err).To(MatchError(constant.ErrWrongInput))
+		})
+
+		It("rejects the cluster-scoped objects with namespace", func() {
+			_, err := bootstrap.ReadManifest(strings.NewReader("apiVersion: spiderpool.spidernet.io/v2beta1\nkind: SpiderIPPool\nmetadata:\n  name: pool\n  namespace: default\n"), "kube-system")
+			Expect(err).To(MatchError(constant.ErrWrongInput))
+		})
+
+		It("rejects the unknown fields", func() {
+			_, err := bootstrap.ReadManifest(strings.NewReader("apiVersion: spiderpool.spidernet.io/v2beta1\nkind: SpiderIPPool\nmetadata:\n  name: pool\nspec:\n  subnets: 172.100.0.0/16\n"), "kube-system")
+			Expect(err).To(MatchError(constant.ErrWrongInput))
+		})
+	})
+
+	Describe("Apply", func() {
+		var manifest *bootstrap.Manifest
+
+		BeforeEach(func() {
+			var err error
+			manifest, err = bootstrap.ReadManifest(strings.NewReader(manifestT), "kube-system")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("creates the objects in dependency order", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			changes, err := bootstrap.Apply(ctx, c, manifest, bootstrap.Options{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(Equal([]bootstrap.Change{
+				{Kind: constant.KindSpiderSubnet, Name: "vlan100-v4", Action: bootstrap.ActionCreate},
+				{Kind: constant.KindSpiderReservedIP, Name: "vlan100-vip", Action: bootstrap.ActionCreate},
+				{Kind: constant.KindSpiderIPPool, Name: "vlan100-v4", Action: bootstrap.ActionCreate},
+				{Kind: constant.KindSpiderMultusConfig, Name: "kube-system/macvlan-vlan100", Action: bootstrap.ActionCreate},
+			}))
+
+			var ipPool spiderpoolv2beta1.SpiderIPPool
+			Expect(c.Get(ctx, types.NamespacedName{Name: "vlan100-v4"}, &ipPool)).To(Succeed())
+			Expect(ipPool.Labels).To(HaveKeyWithValue(constant.LabelBootstrapManaged, constant.True))
+		})
+
+		It("is idempotent and keeps the defaulted fields", func() {
+			c := fake.NewClientBuilder().WithScheme(scheme).Build()
+			_, err := bootstrap.Apply(ctx, c, manifest, bootstrap.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Defaulted by the webhook.
+			var ipPool spiderpoolv2beta1.SpiderIPPool
+			Expect(c.Get(ctx, types.NamespacedName{Name: "vlan100-v4"}, &ipPool)).To(Succeed())
+			ipPool.Spec.IPVersion = pointer.Int64(constant.IPv4)
+			Expect(c.Update(ctx, &ipPool)).To(Succeed())
+
+			manifest, err = bootstrap.ReadManifest(strings.NewReader(manifestT), "kube-system")
+			Expect(err).NotTo(HaveOccurred())
+			changes, err := bootstrap.Apply(ctx, c, manifest, bootstrap.Options{})
+			Expect(err).NotTo(HaveOccurred())
+			for _, change := range changes {
+				Expect(change.Action).To(Equal(bootstrap.ActionUnchanged), "%+v", change)
+			}
+		})
+
+		It("updates the objects created by bootstrap only", func() {
+			unmanaged := &spiderpoolv2beta1.SpiderSubnet{
+				ObjectMeta: metav1.ObjectMeta{Name: "vlan100-v4"},
+				Spec: spiderpoolv2beta1.SubnetSpec{
+					Subnet: "172.100.0.0/16",
+					IPs:    []string{"172.100.0.1-172.100.0.10"},
+				},
+			}
+			managed := &spiderpoolv2beta1.SpiderIPPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "vlan100-v4",
+					Labels: map[string]string{constant.LabelBootstrapManaged: constant.True},
+				},
+				Spec: spiderpoolv2beta1.IPPoolSpec{
+					IPVersion: pointer.Int64(constant.IPv4),
+					Subnet:    "172.100.0.0/16",
+					IPs:       []string{"172.100.0.10-172.100.0.20"},
+				},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(unmanaged, managed).Build()
+
+			changes, err := bootstrap.Apply(ctx, c, manifest, bootstrap.Options{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(ContainElements(
+				bootstrap.Change{Kind: constant.KindSpiderSubnet, Name: "vlan100-v4", Action: bootstrap.ActionSkip, Reason: "not created by bootstrap"},
+				bootstrap.Change{Kind: constant.KindSpiderIPPool, Name: "vlan100-v4", Action: bootstrap.ActionUpdate},
+			))
+
+			var subnet spiderpoolv2beta1.SpiderSubnet
+			Expect(c.Get(ctx, types.NamespacedName{Name: "vlan100-v4"}, &subnet)).To(Succeed())
+			Expect(subnet.Spec.IPs).To(Equal([]string{"172.100.0.1-172.100.0.10"}))
+
+			var ipPool spiderpoolv2beta1.SpiderIPPool
+			Expect(c.Get(ctx, types.NamespacedName{Name: "vlan100-v4"}, &ipPool)).To(Succeed())
+			Expect(ipPool.Spec.IPs).To(Equal([]string{"172.100.0.10-172.100.0.200"}))
+			Expect(ipPool.Spec.IPVersion).To(Equal(pointer.Int64(constant.IPv4)))
+		})
+
+		It("prunes the objects no longer in the manifest unless in use", func() {
+			stale := &spiderpoolv2beta1.SpiderIPPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "stale",
+					Labels: map[string]string{constant.LabelBootstrapManaged: constant.True},
+				},
+			}
+			inUse := &spiderpoolv2beta1.SpiderIPPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "in-use",
+					Labels: map[string]string{constant.LabelBootstrapManaged: constant.True},
+				},
+				Status: spiderpoolv2beta1.IPPoolStatus{
+					AllocatedIPs:     pointer.String(`{"172.100.0.10":{"interface":"eth0","pod":"default/pod","podUid":"uid"}}`),
+					AllocatedIPCount: pointer.Int64(1),
+				},
+			}
+			// the allocated IP count in status lags behind the SpiderIPClaims
+			claimed := &spiderpoolv2beta1.SpiderIPPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "claimed",
+					Labels: map[string]string{constant.LabelBootstrapManaged: constant.True},
+				},
+				Spec: spiderpoolv2beta1.IPPoolSpec{
+					AllocationStorage: pointer.String(constant.IPPoolAllocationStorageIPClaim),
+				},
+				Status: spiderpoolv2beta1.IPPoolStatus{
+					AllocatedIPCount: pointer.Int64(0),
+				},
+			}
+			claim := &spiderpoolv2beta1.SpiderIPClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "claimed-172.100.0.11"},
+				Spec: spiderpoolv2beta1.IPClaimSpec{
+					IPPool: "claimed",
+					IP:     "172.100.0.11",
+				},
+			}
+			unmanaged := &spiderpoolv2beta1.SpiderIPPool{
+				ObjectMeta: metav1.ObjectMeta{Name: "unmanaged"},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stale, inUse, claimed, claim, unmanaged).Build()
+
+			changes, err := bootstrap.Apply(ctx, c, manifest, bootstrap.Options{Prune: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(ContainElements(
+				bootstrap.Change{Kind: constant.KindSpiderIPPool, Name: "stale", Action: bootstrap.ActionPrune},
+				bootstrap.Change{Kind: constant.KindSpiderIPPool, Name: "in-use", Action: bootstrap.ActionSkip, Reason: "1 IP addresses allocated to Pods"},
+				bootstrap.Change{Kind: constant.KindSpiderIPPool, Name: "claimed", Action: bootstrap.ActionSkip, Reason: "1 IP addresses allocated to Pods"},
+			))
+
+			err = c.Get(ctx, client.ObjectKeyFromObject(stale), &spiderpoolv2beta1.SpiderIPPool{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(inUse), &spiderpoolv2beta1.SpiderIPPool{})).To(Succeed())
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(claimed), &spiderpoolv2beta1.SpiderIPPool{})).To(Succeed())
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(unmanaged), &spiderpoolv2beta1.SpiderIPPool{})).To(Succeed())
+			Expect(c.Get(ctx, types.NamespacedName{Name: "vlan100-v4"}, &spiderpoolv2beta1.SpiderIPPool{})).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,149 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+)
+
+// Manifest is the set of Spiderpool objects to bootstrap a cluster with,
+// grouped by kind in the order they are applied.
+type Manifest struct {
+	Coordinators  []spiderpoolv2beta1.SpiderCoordinator
+	Subnets       []spiderpoolv2beta1.SpiderSubnet
+	ReservedIPs   []spiderpoolv2beta1.SpiderReservedIP
+	IPPools       []spiderpoolv2beta1.SpiderIPPool
+	MultusConfigs []spiderpoolv2beta1.SpiderMultusConfig
+}
+
+// ReadManifest decodes a stream of YAML documents or JSON objects, each of
+// which is a Spiderpool object or a List of them. The SpiderMultusConfigs
+// without namespace are put in the namespace.
+func ReadManifest(r io.Reader, namespace string) (*Manifest, error) {
+	manifest := &Manifest{}
+	seen := map[string]struct{}{}
+
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		var obj unstructured.Unstructured
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("%w, failed to decode manifest: %v", constant.ErrWrongInput, err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		objs := []unstructured.Unstructured{obj}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("%w, failed to decode %s: %v", constant.ErrWrongInput, obj.GetKind(), err)
+			}
+			objs = list.Items
+		}
+
+		for i := range objs {
+			if err := manifest.add(&objs[i], namespace, seen); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return manifest, nil
+}
+
+func (m *Manifest) add(obj *unstructured.Unstructured, namespace string, seen map[string]struct{}) error {
+	kind := obj.GetKind()
+	if obj.GetAPIVersion() != spiderpoolv2beta1.GroupVersion.String() {
+		return fmt.Errorf("%w, unsupported apiVersion '%s' of %s %s, expect '%s'", constant.ErrWrongInput, obj.GetAPIVersion(), kind, obj.GetName(), spiderpoolv2beta1.GroupVersion)
+	}
+	if obj.GetName() == "" {
+		return fmt.Errorf("%w, %s without name", constant.ErrWrongInput, kind)
+	}
+
+	if kind == constant.KindSpiderMultusConfig {
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(namespace)
+		}
+	} else if obj.GetNamespace() != "" {
+		return fmt.Errorf("%w, cluster-scoped %s %s must not have namespace", constant.ErrWrongInput, kind, obj.GetName())
+	}
+
+	key := strings.Join([]string{kind, obj.GetNamespace(), obj.GetName()}, "/")
+	if _, ok := seen[key]; ok {
+		return fmt.Errorf("%w, duplicate %s %s", constant.ErrWrongInput, kind, objectName(obj))
+	}
+	seen[key] = struct{}{}
+
+	var err error
+	switch kind {
+	case constant.KindSpiderCoordinator:
+		var coord spiderpoolv2beta1.SpiderCoordinator
+		if err = fromUnstructured(obj, &coord); err == nil {
+			m.Coordinators = append(m.Coordinators, coord)
+		}
+	case constant.KindSpiderSubnet:
+		var subnet spiderpoolv2beta1.SpiderSubnet
+		if err = fromUnstructured(obj, &subnet); err == nil {
+			m.Subnets = append(m.Subnets, subnet)
+		}
+	case constant.KindSpiderReservedIP:
+		var rIP spiderpoolv2beta1.SpiderReservedIP
+		if err = fromUnstructured(obj, &rIP); err == nil {
+			m.ReservedIPs = append(m.ReservedIPs, rIP)
+		}
+	case constant.KindSpiderIPPool:
+		var ipPool spiderpoolv2beta1.SpiderIPPool
+		if err = fromUnstructured(obj, &ipPool); err == nil {
+			m.IPPools = append(m.IPPools, ipPool)
+		}
+	case constant.KindSpiderMultusConfig:
+		var multusConfig spiderpoolv2beta1.SpiderMultusConfig
+		if err = fromUnstructured(obj, &multusConfig); err == nil {
+			m.MultusConfigs = append(m.MultusConfigs, multusConfig)
+		}
+	default:
+		return fmt.Errorf("%w, unsupported kind '%s' of %s", constant.ErrWrongInput, kind, obj.GetName())
+	}
+	if err != nil {
+		return fmt.Errorf("%w, failed to decode %s %s: %v", constant.ErrWrongInput, kind, objectName(obj), err)
+	}
+
+	return nil
+}
+
+// Len returns the number of objects in the manifest.
+func (m *Manifest) Len() int {
+	return len(m.Coordinators) + len(m.Subnets) + len(m.ReservedIPs) + len(m.IPPools) + len(m.MultusConfigs)
+}
+
+func fromUnstructured(obj *unstructured.Unstructured, out interface{}) error {
+	return runtime.DefaultUnstructuredConverter.FromUnstructuredWithValidation(obj.Object, out, true)
+}
+
+type namedObject interface {
+	GetNamespace() string
+	GetName() string
+}
+
+func objectName(obj namedObject) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
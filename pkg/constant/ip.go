@@ -19,4 +19,11 @@ const (
 	InvalidGateway   = "invalid routing gateway"
 )
 
+// Scopes of the custom routes
+const (
+	RouteScopeUniverse = "universe"
+	RouteScopeLink     = "link"
+	RouteScopeHost     = "host"
+)
+
 var InvalidIPRanges = []string{InvalidIPRange}
@@ -31,6 +31,11 @@ const (
 	KindReplicaSet  = "ReplicaSet"
 	KindJob         = "Job"
 	KindCronJob     = "CronJob"
+	KindNode        = "Node"
+
+	// KindStandalone is the owner controller type of the Endpoints of the
+	// containers started by the container runtimes without Kubernetes.
+	KindStandalone = "Standalone"
 )
 
 var K8sKinds = []string{KindPod, KindDeployment, KindReplicaSet, KindDaemonSet, KindStatefulSet, KindJob, KindCronJob}
@@ -52,8 +57,10 @@ const (
 
 	AnnoPodIPPool       = AnnotationPre + "/ippool"
 	AnnoPodIPPools      = AnnotationPre + "/ippools"
+	AnnoPodIPPoolGroup  = AnnotationPre + "/ippool-group"
 	AnnoPodRoutes       = AnnotationPre + "/routes"
 	AnnoPodDNS          = AnnotationPre + "/dns"
+	AnnoPodStaticIPs    = AnnotationPre + "/static-ips"
 	AnnoNSDefautlV4Pool = AnnotationPre + "/default-ipv4-ippool"
 	AnnoNSDefautlV6Pool = AnnotationPre + "/default-ipv6-ippool"
 
@@ -78,6 +85,13 @@ const (
 	LabelSubnetCIDR = AnnotationPre + "/subnet-cidr"
 	LabelIPPoolCIDR = AnnotationPre + "/ippool-cidr"
 
+	// objects created by the bootstrap manifest of spiderpool-init
+	LabelBootstrapManaged = AnnotationPre + "/bootstrap-managed"
+
+	// Endpoints of the standalone containers
+	LabelStandaloneOwner = AnnotationPre + "/standalone-owner"
+	AnnoStandaloneNetns  = AnnotationPre + "/standalone-netns"
+
 	// auto pool special pod affinity matchLabels key
 	AutoPoolPodAffinityAppPrefix     = AnnotationPre
 	AutoPoolPodAffinityAppAPIGroup   = AutoPoolPodAffinityAppPrefix + "/app-api-group"
@@ -93,6 +107,8 @@ const (
 
 	// Coordinator
 	AnnoDefaultRouteInterface = AnnotationPre + "/default-route-nic"
+	AnnoPodCoordinator        = AnnotationPre + "/coordinator"
+	AnnoPodBandwidth          = AnnotationPre + "/bandwidth"
 )
 
 const (
@@ -111,6 +127,26 @@ const (
 	KindSpiderReservedIP   = "SpiderReservedIP"
 	KindSpiderCoordinator  = "SpiderCoordinator"
 	KindSpiderMultusConfig = "SpiderMultusConfig"
+	KindSpiderIPClaim      = "SpiderIPClaim"
+	KindSpiderNodeNetwork  = "SpiderNodeNetwork"
+)
+
+// Storage backends of the IP allocation records of SpiderIPPool
+const (
+	IPPoolAllocationStorageStatus  = "status"
+	IPPoolAllocationStorageIPClaim = "ipclaim"
+)
+
+// Ways to derive the IPv6 addresses of SpiderIPPool
+const (
+	IPv6DerivationEUI64 = "eui64"
+	IPv6DerivationHash  = "hash"
+)
+
+// Sources of the MAC addresses allocated by SpiderIPPool
+const (
+	MACSourceIP  = "ip"
+	MACSourcePod = "pod"
 )
 
 const (
@@ -132,6 +168,16 @@ const (
 	EventReasonScaleIPPool  = "ScaleIPPool"
 	EventReasonDeleteIPPool = "DeleteIPPool"
 	EventReasonResyncSubnet = "ResyncSubnet"
+
+	EventReasonIPConflict        = "IPConflict"
+	EventReasonIPConflictCleared = "IPConflictCleared"
+
+	EventReasonIPUtilizationHigh = "IPUtilizationHigh"
+
+	EventReasonGatewayUnreachable = "GatewayUnreachable"
+	EventReasonGatewayReachable   = "GatewayReachable"
+
+	EventReasonNodeInterfaceMissing = "NodeInterfaceMissing"
 )
 
 const ClusterDefaultInterfaceName = "eth0"
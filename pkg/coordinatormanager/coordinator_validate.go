@@ -8,10 +8,13 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/networking/gwconnection"
+	"github.com/spidernet-io/spiderpool/pkg/networking/networking"
 )
 
 var (
@@ -19,6 +22,12 @@ var (
 	extraCIDRField    *field.Path = field.NewPath("spec").Child("extraCIDR")
 	podMACPrefixField *field.Path = field.NewPath("spec").Child("podMACPrefix")
 	hostRPFilterField *field.Path = field.NewPath("spec").Child("hostRPFilter")
+
+	gratuitousNeighborField   *field.Path = field.NewPath("spec").Child("gratuitousNeighbor")
+	detectGatewayOptionsField *field.Path = field.NewPath("spec").Child("detectGatewayOptions")
+	vrfField                  *field.Path = field.NewPath("spec").Child("vrf")
+	mtuField                  *field.Path = field.NewPath("spec").Child("mtu")
+	bandwidthField            *field.Path = field.NewPath("spec").Child("bandwidth")
 )
 
 func validateCreateCoordinator(coord *spiderpoolv2beta1.SpiderCoordinator) field.ErrorList {
@@ -26,6 +35,13 @@ func validateCreateCoordinator(coord *spiderpoolv2beta1.SpiderCoordinator) field
 	if err := ValidateCoordinatorSpec(coord.Spec.DeepCopy()); err != nil {
 		errs = append(errs, err)
 	}
+	// the VRF and the bandwidth are specific to an interface of the Pod
+	if coord.Spec.VRF != nil {
+		errs = append(errs, field.Forbidden(vrfField, "only supported in SpiderMultusConfig"))
+	}
+	if coord.Spec.Bandwidth != nil {
+		errs = append(errs, field.Forbidden(bandwidthField, "only supported in SpiderMultusConfig"))
+	}
 
 	if len(errs) == 0 {
 		return nil
@@ -39,6 +55,13 @@ func validateUpdateCoordinator(oldCoord, newCoord *spiderpoolv2beta1.SpiderCoord
 	if err := ValidateCoordinatorSpec(newCoord.Spec.DeepCopy()); err != nil {
 		errs = append(errs, err)
 	}
+	// the VRF and the bandwidth are specific to an interface of the Pod
+	if newCoord.Spec.VRF != nil {
+		errs = append(errs, field.Forbidden(vrfField, "only supported in SpiderMultusConfig"))
+	}
+	if newCoord.Spec.Bandwidth != nil {
+		errs = append(errs, field.Forbidden(bandwidthField, "only supported in SpiderMultusConfig"))
+	}
 
 	if len(errs) == 0 {
 		return nil
@@ -58,6 +81,26 @@ func ValidateCoordinatorSpec(spec *spiderpoolv2beta1.CoordinatorSpec) *field.Err
 		return err
 	}
 
+	if err := validateCoordinatorGratuitousNeighbor(spec.GratuitousNeighbor); err != nil {
+		return err
+	}
+
+	if err := validateCoordinatorDetectGatewayOptions(spec.DetectGatewayOptions); err != nil {
+		return err
+	}
+
+	if err := validateCoordinatorVRF(spec.VRF); err != nil {
+		return err
+	}
+
+	if err := validateCoordinatorMTU(spec.MTU); err != nil {
+		return err
+	}
+
+	if err := validateCoordinatorBandwidth(spec.Bandwidth); err != nil {
+		return err
+	}
+
 	return validateCoordinatorhostRPFilter(spec.HostRPFilter)
 }
 
@@ -139,3 +182,123 @@ func validateCoordinatorhostRPFilter(f *int) *field.Error {
 
 	return nil
 }
+
+func validateCoordinatorGratuitousNeighbor(gn *spiderpoolv2beta1.GratuitousNeighbor) *field.Error {
+	if gn == nil {
+		return nil
+	}
+
+	if gn.Count != nil && *gn.Count <= 0 {
+		return field.Invalid(
+			gratuitousNeighborField.Child("count"),
+			*gn.Count,
+			"must be greater than 0",
+		)
+	}
+
+	if gn.Interval != nil {
+		if _, err := time.ParseDuration(*gn.Interval); err != nil {
+			return field.Invalid(
+				gratuitousNeighborField.Child("interval"),
+				*gn.Interval,
+				err.Error(),
+			)
+		}
+	}
+
+	return nil
+}
+
+func validateCoordinatorDetectGatewayOptions(opts *spiderpoolv2beta1.DetectGatewayOptions) *field.Error {
+	if opts == nil {
+		return nil
+	}
+
+	if opts.Method != nil {
+		switch *opts.Method {
+		case gwconnection.MethodICMP, gwconnection.MethodARP, gwconnection.MethodNDP, gwconnection.MethodAny:
+		default:
+			return field.NotSupported(
+				detectGatewayOptionsField.Child("method"),
+				*opts.Method,
+				[]string{gwconnection.MethodICMP, gwconnection.MethodARP, gwconnection.MethodNDP, gwconnection.MethodAny},
+			)
+		}
+	}
+
+	if opts.LossTolerance != nil && (*opts.LossTolerance < 0 || *opts.LossTolerance > 99) {
+		return field.Invalid(
+			detectGatewayOptionsField.Child("lossTolerance"),
+			*opts.LossTolerance,
+			"must be in the range of 0 to 99",
+		)
+	}
+
+	return nil
+}
+
+func validateCoordinatorVRF(vrf *spiderpoolv2beta1.VRF) *field.Error {
+	if vrf == nil {
+		return nil
+	}
+
+	if vrf.Name == "" || len(vrf.Name) > 15 || strings.ContainsAny(vrf.Name, "/: \t\n") {
+		return field.Invalid(
+			vrfField.Child("name"),
+			vrf.Name,
+			"must be a valid interface name of 1 to 15 characters",
+		)
+	}
+
+	if vrf.Table != nil {
+		switch t := *vrf.Table; {
+		case t <= 0:
+			return field.Invalid(vrfField.Child("table"), t, "must be greater than 0")
+		case t >= 253 && t <= 255:
+			return field.Invalid(vrfField.Child("table"), t, "must not be the reserved table default(253), main(254) or local(255)")
+		}
+	}
+
+	return nil
+}
+
+func validateCoordinatorMTU(mtu *string) *field.Error {
+	if mtu == nil || *mtu == "auto" {
+		return nil
+	}
+
+	v, err := strconv.Atoi(*mtu)
+	if err != nil || v < 68 || v > 65535 {
+		return field.Invalid(
+			mtuField,
+			*mtu,
+			"must be auto or in the range of 68 to 65535",
+		)
+	}
+
+	return nil
+}
+
+func validateCoordinatorBandwidth(bw *spiderpoolv2beta1.Bandwidth) *field.Error {
+	if bw == nil {
+		return nil
+	}
+
+	if bw.IngressRate != nil {
+		if _, err := networking.ParseBandwidthRate(*bw.IngressRate); err != nil {
+			return field.Invalid(bandwidthField.Child("ingressRate"), *bw.IngressRate, err.Error())
+		}
+	}
+
+	if bw.EgressRate != nil {
+		if _, err := networking.ParseBandwidthRate(*bw.EgressRate); err != nil {
+			return field.Invalid(bandwidthField.Child("egressRate"), *bw.EgressRate, err.Error())
+		}
+	}
+
+	if bw.DSCP != nil && (*bw.DSCP < 0 || *bw.DSCP > 63) {
+		return field.Invalid(bandwidthField.Child("dscp"), *bw.DSCP, "must be in the range of 0 to 63")
+	}
+
+	return nil
+}
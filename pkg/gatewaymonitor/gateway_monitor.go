@@ -0,0 +1,426 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package gatewaymonitor
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/event"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/lock"
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+	"github.com/spidernet-io/spiderpool/pkg/metric"
+)
+
+const (
+	// MethodICMP probes the gateways with ICMP echo.
+	MethodICMP = "icmp"
+	// MethodNeighbor probes the gateways with ARP for IPv4 and NDP for IPv6,
+	// for the gateways which drop or rate-limit ICMP.
+	MethodNeighbor = "neighbor"
+)
+
+// DefaultNetnsDir is where containerd and CRI-O bind mount the network
+// namespaces of Pods.
+const DefaultNetnsDir = "/var/run/netns"
+
+type Config struct {
+	NodeName string
+	// Interval is the period to probe the gateways of all local Pods.
+	Interval time.Duration
+	// Timeout bounds every single probe.
+	Timeout time.Duration
+	Method  string
+	// FailureThreshold is the consecutive failed probes after which the
+	// gateway is considered unreachable.
+	FailureThreshold int
+	// NetnsDir is the directory the network namespaces of Pods are found in.
+	NetnsDir string
+	// MarkPoolUnhealthy makes the IPAM on the node prefer other IPPools once
+	// the gateway of an IPPool is unreachable from all its local Pods.
+	MarkPoolUnhealthy bool
+	// Workers bounds the gateways probed concurrently.
+	Workers int
+}
+
+func setDefaultsForConfig(config Config) Config {
+	if config.Interval <= 0 {
+		config.Interval = 30 * time.Second
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = time.Second
+	}
+	if config.Method == "" {
+		config.Method = MethodICMP
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 3
+	}
+	if config.NetnsDir == "" {
+		config.NetnsDir = DefaultNetnsDir
+	}
+	if config.Workers <= 0 {
+		config.Workers = 8
+	}
+
+	return config
+}
+
+// location is where a Pod IP address is configured on the node.
+type location struct {
+	netns string
+	iface string
+	mac   string
+}
+
+// netnsAddr keys the locations, the same IP address may be configured in
+// the network namespaces of several Pods, for example of different underlay
+// networks.
+type netnsAddr struct {
+	netns string
+	ip    netip.Addr
+}
+
+// target is a gateway of a Pod interface to probe.
+type target struct {
+	metric.GatewayTarget
+	podUID   string
+	gateway  netip.Addr
+	location location
+}
+
+type targetState struct {
+	failures    int
+	unreachable bool
+}
+
+// Monitor periodically probes the gateways of the Pods on the node from their
+// network namespaces, reports the reachability with metrics and Events, and
+// tracks the IPPools whose gateway is unreachable on the node.
+type Monitor struct {
+	config Config
+	client client.Reader
+
+	// locate and probe are replaced in unit tests.
+	locate func() (map[netnsAddr]location, error)
+	probe  func(t target) error
+
+	lock           lock.RWMutex
+	states         map[metric.GatewayTarget]*targetState
+	unhealthyPools map[string]struct{}
+}
+
+func NewMonitor(config Config, client client.Reader) (*Monitor, error) {
+	if client == nil {
+		return nil, fmt.Errorf("k8s client %w", constant.ErrMissingRequiredParam)
+	}
+	if config.NodeName == "" {
+		return nil, fmt.Errorf("node name %w", constant.ErrMissingRequiredParam)
+	}
+
+	config = setDefaultsForConfig(config)
+	if config.Method != MethodICMP && config.Method != MethodNeighbor {
+		return nil, fmt.Errorf("%w: unknown gateway probe method '%s', expect '%s' or '%s'", constant.ErrWrongInput, config.Method, MethodICMP, MethodNeighbor)
+	}
+
+	m := &Monitor{
+		config:         config,
+		client:         client,
+		states:         map[metric.GatewayTarget]*targetState{},
+		unhealthyPools: map[string]struct{}{},
+	}
+	m.locate = func() (map[netnsAddr]location, error) {
+		return locateNetns(m.config.NetnsDir)
+	}
+	m.probe = m.probeGateway
+
+	return m, nil
+}
+
+// Start probes the gateways every interval until the context is done.
+func (m *Monitor) Start(ctx context.Context) error {
+	logger := logutils.Logger.Named("Gateway-Monitor")
+	logger.Sugar().Infof("Start to probe the gateways of the Pods on Node %s by %s every %s", m.config.NodeName, m.config.Method, m.config.Interval)
+
+	ctx = logutils.IntoContext(ctx, logger)
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := m.probeAll(ctx); err != nil {
+				logger.Sugar().Warnf("Failed to probe the gateways: %v", err)
+			}
+		}
+	}
+}
+
+// IsUnhealthy returns true if the gateway of the IPPool is unreachable from
+// all the local Pods probed, and the IPPool is expected to be avoided on the
+// node.
+func (m *Monitor) IsUnhealthy(pool string) bool {
+	if !m.config.MarkPoolUnhealthy {
+		return false
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	_, ok := m.unhealthyPools[pool]
+	return ok
+}
+
+func (m *Monitor) probeAll(ctx context.Context) error {
+	targets, err := m.listTargets(ctx)
+	if err != nil {
+		return err
+	}
+
+	results := make([]error, len(targets))
+	var g errgroup.Group
+	g.SetLimit(m.config.Workers)
+	for i := range targets {
+		i := i
+		g.Go(func() error {
+			results[i] = m.probe(targets[i])
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	m.update(ctx, targets, results)
+
+	return nil
+}
+
+// listTargets returns the gateways of the Spiderpool-managed interfaces of
+// the Pods on the node, whose network namespaces are found.
+func (m *Monitor) listTargets(ctx context.Context) ([]target, error) {
+	logger := logutils.FromContext(ctx)
+
+	var endpointList spiderpoolv2beta1.SpiderEndpointList
+	if err := m.client.List(ctx, &endpointList); err != nil {
+		return nil, fmt.Errorf("failed to list SpiderEndpoints: %w", err)
+	}
+
+	locations, err := m.locate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the network namespaces of Pods: %w", err)
+	}
+
+	var targets []target
+	for _, endpoint := range endpointList.Items {
+		if endpoint.DeletionTimestamp != nil || endpoint.Status.Current.Node != m.config.NodeName {
+			continue
+		}
+
+		netns, err := findNetns(endpoint.Status.Current.IPs, locations)
+		if err != nil {
+			logger.Sugar().Debugf("No network namespace found for Pod %s/%s: %v", endpoint.Namespace, endpoint.Name, err)
+			continue
+		}
+
+		for _, detail := range endpoint.Status.Current.IPs {
+			families := []struct {
+				ip, gateway, pool *string
+			}{
+				{detail.IPv4, detail.IPv4Gateway, detail.IPv4Pool},
+				{detail.IPv6, detail.IPv6Gateway, detail.IPv6Pool},
+			}
+
+			for _, f := range families {
+				if f.ip == nil || f.gateway == nil || *f.gateway == "" {
+					continue
+				}
+
+				prefix, err := netip.ParsePrefix(*f.ip)
+				if err != nil {
+					logger.Sugar().Debugf("Invalid IP %s of SpiderEndpoint %s/%s: %v", *f.ip, endpoint.Namespace, endpoint.Name, err)
+					continue
+				}
+				gateway, err := netip.ParseAddr(*f.gateway)
+				if err != nil {
+					logger.Sugar().Debugf("Invalid gateway %s of SpiderEndpoint %s/%s: %v", *f.gateway, endpoint.Namespace, endpoint.Name, err)
+					continue
+				}
+
+				loc, ok := locations[netnsAddr{netns: netns, ip: prefix.Addr()}]
+				if !ok {
+					logger.Sugar().Debugf("No IP %s of Pod %s/%s found in network namespace %s", prefix.Addr(), endpoint.Namespace, endpoint.Name, netns)
+					continue
+				}
+
+				var pool string
+				if f.pool != nil {
+					pool = *f.pool
+				}
+
+				targets = append(targets, target{
+					GatewayTarget: metric.GatewayTarget{
+						Namespace: endpoint.Namespace,
+						Pod:       endpoint.Name,
+						Interface: detail.NIC,
+						Gateway:   gateway.String(),
+						IPPool:    pool,
+					},
+					podUID:   endpoint.Status.Current.UID,
+					gateway:  gateway,
+					location: loc,
+				})
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+// findNetns returns the network namespace holding all the IP addresses of
+// the Pod on the recorded interfaces, with the recorded MAC addresses if any.
+// It fails if none or several network namespaces match, the Pod is not
+// probed rather than probed from the network namespace of another Pod.
+func findNetns(details []spiderpoolv2beta1.IPAllocationDetail, locations map[netnsAddr]location) (string, error) {
+	var candidates map[string]struct{}
+	for _, detail := range details {
+		for _, ip := range []*string{detail.IPv4, detail.IPv6} {
+			if ip == nil {
+				continue
+			}
+			prefix, err := netip.ParsePrefix(*ip)
+			if err != nil {
+				continue
+			}
+
+			matched := map[string]struct{}{}
+			for key, loc := range locations {
+				if key.ip != prefix.Addr() || loc.iface != detail.NIC {
+					continue
+				}
+				if detail.MAC != nil && *detail.MAC != "" && loc.mac != "" && !strings.EqualFold(loc.mac, *detail.MAC) {
+					continue
+				}
+				if _, ok := candidates[key.netns]; candidates == nil || ok {
+					matched[key.netns] = struct{}{}
+				}
+			}
+			candidates = matched
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("no network namespace holds all the IP addresses")
+	case 1:
+		for netns := range candidates {
+			return netns, nil
+		}
+	}
+
+	return "", fmt.Errorf("%d network namespaces hold the same IP addresses", len(candidates))
+}
+
+// update counts the consecutive failures of every gateway, emits Events on
+// the Pods when their gateways become unreachable or recover, and refreshes
+// the metrics and the unhealthy IPPools. The gateways not probed this time
+// are forgotten.
+func (m *Monitor) update(ctx context.Context, targets []target, results []error) {
+	logger := logutils.FromContext(ctx)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	states := make(map[metric.GatewayTarget]*targetState, len(targets))
+	reachability := make(map[metric.GatewayTarget]bool, len(targets))
+	reachablePools := map[string]struct{}{}
+	unreachablePools := map[string]struct{}{}
+
+	for i, t := range targets {
+		s, ok := m.states[t.GatewayTarget]
+		if !ok {
+			s = &targetState{}
+		}
+		states[t.GatewayTarget] = s
+
+		if err := results[i]; err != nil {
+			s.failures++
+			metric.GatewayProbeFailureCounts.Add(ctx, 1, t.Attributes()...)
+			logger.Sugar().Debugf("Failed to probe gateway %s of interface %s of Pod %s/%s (%d/%d): %v",
+				t.Gateway, t.Interface, t.Namespace, t.Pod, s.failures, m.config.FailureThreshold, err)
+
+			if !s.unreachable && s.failures >= m.config.FailureThreshold {
+				s.unreachable = true
+				logger.Sugar().Warnf("Gateway %s of interface %s of Pod %s/%s is unreachable: %v", t.Gateway, t.Interface, t.Namespace, t.Pod, err)
+				event.EventRecorder.Eventf(podRef(t), corev1.EventTypeWarning, constant.EventReasonGatewayUnreachable,
+					"gateway %s of interface %s is unreachable after %d probes by %s: %v", t.Gateway, t.Interface, s.failures, m.config.Method, err)
+			}
+		} else {
+			if s.unreachable {
+				logger.Sugar().Infof("Gateway %s of interface %s of Pod %s/%s is reachable again", t.Gateway, t.Interface, t.Namespace, t.Pod)
+				event.EventRecorder.Eventf(podRef(t), corev1.EventTypeNormal, constant.EventReasonGatewayReachable,
+					"gateway %s of interface %s is reachable again", t.Gateway, t.Interface)
+			}
+			s.failures = 0
+			s.unreachable = false
+		}
+
+		reachability[t.GatewayTarget] = !s.unreachable
+		if t.IPPool == "" {
+			continue
+		}
+		if s.unreachable {
+			unreachablePools[t.IPPool] = struct{}{}
+		} else {
+			reachablePools[t.IPPool] = struct{}{}
+		}
+	}
+
+	unhealthyPools := map[string]struct{}{}
+	for pool := range unreachablePools {
+		if _, ok := reachablePools[pool]; !ok {
+			unhealthyPools[pool] = struct{}{}
+		}
+	}
+	for pool := range unhealthyPools {
+		if _, ok := m.unhealthyPools[pool]; !ok {
+			logger.Sugar().Warnf("Gateway of IPPool %s is unreachable from all Pods on the Node", pool)
+		}
+	}
+	for pool := range m.unhealthyPools {
+		if _, ok := unhealthyPools[pool]; !ok {
+			logger.Sugar().Infof("Gateway of IPPool %s is reachable on the Node again", pool)
+		}
+	}
+
+	m.states = states
+	m.unhealthyPools = unhealthyPools
+	metric.GatewayHealth.Record(reachability)
+}
+
+// podRef is the Pod the Events are emitted on.
+func podRef(t target) *corev1.Pod {
+	return &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       constant.KindPod,
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: t.Namespace,
+			Name:      t.Pod,
+			UID:       apitypes.UID(t.podUID),
+		},
+	}
+}
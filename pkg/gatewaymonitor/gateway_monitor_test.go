@@ -0,0 +1,239 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package gatewaymonitor
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/event"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/metric"
+)
+
+var _ = Describe("GatewayMonitor", Label("gateway_monitor_test"), func() {
+	var fakeClient client.Client
+	var recorder *record.FakeRecorder
+	var unreachable sync.Map
+
+	newEndpoint := func(name, node, ip, pool string) *spiderpoolv2beta1.SpiderEndpoint {
+		return &spiderpoolv2beta1.SpiderEndpoint{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+			},
+			Status: spiderpoolv2beta1.WorkloadEndpointStatus{
+				Current: spiderpoolv2beta1.PodIPAllocation{
+					UID:  "uid-" + name,
+					Node: node,
+					IPs: []spiderpoolv2beta1.IPAllocationDetail{{
+						NIC:         "net1",
+						IPv4:        pointer.String(ip),
+						IPv4Pool:    pointer.String(pool),
+						IPv4Gateway: pointer.String("10.6.0.1"),
+					}},
+				},
+			},
+		}
+	}
+
+	// newLocations takes the pairs of location and IP address.
+	newLocations := func(pairs ...interface{}) map[netnsAddr]location {
+		locations := map[netnsAddr]location{}
+		for i := 0; i < len(pairs); i += 2 {
+			loc := pairs[i].(location)
+			locations[netnsAddr{netns: loc.netns, ip: netip.MustParseAddr(pairs[i+1].(string))}] = loc
+		}
+		return locations
+	}
+
+	newMonitor := func(config Config) *Monitor {
+		m, err := NewMonitor(config, fakeClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		m.locate = func() (map[netnsAddr]location, error) {
+			return newLocations(
+				location{netns: "/var/run/netns/cni-1", iface: "net1"}, "10.6.0.10",
+				location{netns: "/var/run/netns/cni-2", iface: "net1"}, "10.6.0.11",
+				location{netns: "/var/run/netns/cni-3", iface: "net1"}, "10.7.0.10",
+			), nil
+		}
+		m.probe = func(t target) error {
+			if _, ok := unreachable.Load(t.Pod); ok {
+				return errors.New("timeout")
+			}
+			return nil
+		}
+
+		return m
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(spiderpoolv2beta1.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				newEndpoint("pod-1", "node1", "10.6.0.10/16", "pool-a"),
+				newEndpoint("pod-2", "node1", "10.6.0.11/16", "pool-a"),
+				newEndpoint("pod-3", "node1", "10.7.0.10/16", "pool-b"),
+				newEndpoint("pod-4", "node2", "10.6.0.12/16", "pool-a"),
+			).
+			Build()
+
+		unreachable = sync.Map{}
+		recorder = record.NewFakeRecorder(10)
+		event.EventRecorder = recorder
+		DeferCleanup(func() {
+			event.EventRecorder = record.NewFakeRecorder(event.FakeRecorderBufferSize)
+		})
+	})
+
+	It("validates the config", func() {
+		_, err := NewMonitor(Config{NodeName: "node1"}, nil)
+		Expect(err).To(MatchError(constant.ErrMissingRequiredParam))
+
+		_, err = NewMonitor(Config{}, fakeClient)
+		Expect(err).To(MatchError(constant.ErrMissingRequiredParam))
+
+		_, err = NewMonitor(Config{NodeName: "node1", Method: "tcp"}, fakeClient)
+		Expect(err).To(MatchError(constant.ErrWrongInput))
+
+		m, err := NewMonitor(Config{NodeName: "node1"}, fakeClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.config.Method).To(Equal(MethodICMP))
+		Expect(m.config.NetnsDir).To(Equal(DefaultNetnsDir))
+	})
+
+	It("only probes the gateways of the Pods on the node", func() {
+		m := newMonitor(Config{NodeName: "node1"})
+
+		targets, err := m.listTargets(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(HaveLen(3))
+		for _, t := range targets {
+			Expect(t.Pod).NotTo(Equal("pod-4"))
+			Expect(t.Gateway).To(Equal("10.6.0.1"))
+			Expect(t.location.iface).To(Equal("net1"))
+		}
+	})
+
+	It("distinguishes the Pods with the same IP in different network namespaces", func() {
+		pod5 := newEndpoint("pod-5", "node1", "10.6.0.10/16", "pool-c")
+		pod5.Status.Current.IPs[0].MAC = pointer.String("0a:00:00:00:00:05")
+		Expect(fakeClient.Create(context.TODO(), pod5)).To(Succeed())
+
+		m := newMonitor(Config{NodeName: "node1"})
+		m.locate = func() (map[netnsAddr]location, error) {
+			return newLocations(
+				location{netns: "/var/run/netns/cni-1", iface: "net1", mac: "0a:00:00:00:00:01"}, "10.6.0.10",
+				location{netns: "/var/run/netns/cni-5", iface: "net1", mac: "0a:00:00:00:00:05"}, "10.6.0.10",
+			), nil
+		}
+
+		targets, err := m.listTargets(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		// pod-1 without MAC recorded is ambiguous, and not probed at all
+		Expect(targets).To(HaveLen(1))
+		Expect(targets[0].Pod).To(Equal("pod-5"))
+		Expect(targets[0].location.netns).To(Equal("/var/run/netns/cni-5"))
+	})
+
+	It("locates the Pod by all its IP addresses", func() {
+		endpoint := newEndpoint("pod-6", "node1", "10.6.0.10/16", "pool-a")
+		endpoint.Status.Current.IPs = append(endpoint.Status.Current.IPs, spiderpoolv2beta1.IPAllocationDetail{
+			NIC:  "net2",
+			IPv4: pointer.String("10.8.0.10/16"),
+		})
+
+		locations := newLocations(
+			location{netns: "/var/run/netns/cni-1", iface: "net1"}, "10.6.0.10",
+			location{netns: "/var/run/netns/cni-6", iface: "net1"}, "10.6.0.10",
+			location{netns: "/var/run/netns/cni-6", iface: "net2"}, "10.8.0.10",
+		)
+		netns, err := findNetns(endpoint.Status.Current.IPs, locations)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(netns).To(Equal("/var/run/netns/cni-6"))
+
+		_, err = findNetns(endpoint.Status.Current.IPs[:1], locations)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reports the gateway unreachable after the failure threshold", func() {
+		m := newMonitor(Config{NodeName: "node1", FailureThreshold: 2, MarkPoolUnhealthy: true})
+		pod1 := metric.GatewayTarget{Namespace: "default", Pod: "pod-1", Interface: "net1", Gateway: "10.6.0.1", IPPool: "pool-a"}
+
+		unreachable.Store("pod-1", true)
+		Expect(m.probeAll(context.TODO())).To(Succeed())
+		reachable, ok := metric.GatewayHealth.Get(pod1)
+		Expect(ok).To(BeTrue())
+		Expect(reachable).To(BeTrue())
+		Expect(recorder.Events).NotTo(Receive())
+
+		Expect(m.probeAll(context.TODO())).To(Succeed())
+		reachable, _ = metric.GatewayHealth.Get(pod1)
+		Expect(reachable).To(BeFalse())
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning GatewayUnreachable")))
+		Expect(m.IsUnhealthy("pool-a")).To(BeFalse())
+
+		Expect(m.probeAll(context.TODO())).To(Succeed())
+		Expect(recorder.Events).NotTo(Receive())
+
+		unreachable.Delete("pod-1")
+		Expect(m.probeAll(context.TODO())).To(Succeed())
+		reachable, _ = metric.GatewayHealth.Get(pod1)
+		Expect(reachable).To(BeTrue())
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal GatewayReachable")))
+	})
+
+	It("marks the IPPool unhealthy if its gateway is unreachable from all local Pods", func() {
+		m := newMonitor(Config{NodeName: "node1", FailureThreshold: 1, MarkPoolUnhealthy: true})
+
+		unreachable.Store("pod-1", true)
+		unreachable.Store("pod-2", true)
+		Expect(m.probeAll(context.TODO())).To(Succeed())
+		Expect(m.IsUnhealthy("pool-a")).To(BeTrue())
+		Expect(m.IsUnhealthy("pool-b")).To(BeFalse())
+
+		unreachable.Delete("pod-2")
+		Expect(m.probeAll(context.TODO())).To(Succeed())
+		Expect(m.IsUnhealthy("pool-a")).To(BeFalse())
+	})
+
+	It("does not mark the IPPool unhealthy if it is not asked to", func() {
+		m := newMonitor(Config{NodeName: "node1", FailureThreshold: 1})
+
+		unreachable.Store("pod-3", true)
+		Expect(m.probeAll(context.TODO())).To(Succeed())
+		Expect(m.IsUnhealthy("pool-b")).To(BeFalse())
+	})
+
+	It("forgets the Pods deleted", func() {
+		m := newMonitor(Config{NodeName: "node1", FailureThreshold: 1})
+		pod3 := metric.GatewayTarget{Namespace: "default", Pod: "pod-3", Interface: "net1", Gateway: "10.6.0.1", IPPool: "pool-b"}
+
+		Expect(m.probeAll(context.TODO())).To(Succeed())
+		_, ok := metric.GatewayHealth.Get(pod3)
+		Expect(ok).To(BeTrue())
+
+		Expect(fakeClient.Delete(context.TODO(), newEndpoint("pod-3", "node1", "10.7.0.10/16", "pool-b"))).To(Succeed())
+		Expect(m.probeAll(context.TODO())).To(Succeed())
+		_, ok = metric.GatewayHealth.Get(pod3)
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,99 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package gatewaymonitor
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/vishvananda/netlink"
+
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+	"github.com/spidernet-io/spiderpool/pkg/networking/gwconnection"
+	"github.com/spidernet-io/spiderpool/pkg/networking/ipchecking"
+)
+
+// locateNetns enters every network namespace in the directory, and returns
+// where each IP address is configured.
+func locateNetns(dir string) (map[netnsAddr]location, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[netnsAddr]location{}, nil
+		}
+		return nil, err
+	}
+
+	locations := map[netnsAddr]location{}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		netns, err := ns.GetNS(path)
+		if err != nil {
+			// not a network namespace, or released just now
+			continue
+		}
+
+		_ = netns.Do(func(_ ns.NetNS) error {
+			links, err := netlink.LinkList()
+			if err != nil {
+				return err
+			}
+
+			for _, link := range links {
+				if link.Attrs().Flags&net.FlagLoopback != 0 {
+					continue
+				}
+
+				addrs, err := netlink.AddrList(link, netlink.FAMILY_ALL)
+				if err != nil {
+					continue
+				}
+				for _, addr := range addrs {
+					ip, ok := netip.AddrFromSlice(addr.IP)
+					if !ok {
+						continue
+					}
+					locations[netnsAddr{netns: path, ip: ip.Unmap()}] = location{
+						netns: path,
+						iface: link.Attrs().Name,
+						mac:   link.Attrs().HardwareAddr.String(),
+					}
+				}
+			}
+
+			return nil
+		})
+		netns.Close()
+	}
+
+	return locations, nil
+}
+
+// probeGateway probes the gateway from the network namespace of the Pod.
+func (m *Monitor) probeGateway(t target) error {
+	netns, err := ns.GetNS(t.location.netns)
+	if err != nil {
+		return fmt.Errorf("failed to get netns %s: %w", t.location.netns, err)
+	}
+	defer netns.Close()
+
+	return netns.Do(func(_ ns.NetNS) error {
+		if m.config.Method == MethodNeighbor {
+			_, err := ipchecking.ResolveNeighbor(t.location.iface, t.gateway, m.config.Timeout, logutils.Logger.Named("Gateway-Monitor"))
+			return err
+		}
+
+		p, err := gwconnection.NewPinger(1, m.config.Timeout.String(), m.config.Timeout.String(), t.gateway.String(), logutils.Logger.Named("Gateway-Monitor"))
+		if err != nil {
+			return err
+		}
+
+		return p.DetectGateway()
+	})
+}
@@ -0,0 +1,29 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package gcmanager
+
+import (
+	"context"
+
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/spidernet-io/spiderpool/pkg/audit"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+)
+
+// auditGC records the IP address reclaimed from the IPPool in the audit log.
+func auditGC(ctx context.Context, poolName, poolIP, nodeName string, poolIPAllocation spiderpoolv2beta1.PoolIPAllocation) {
+	podNS, podName, _ := cache.SplitMetaNamespaceKey(poolIPAllocation.NamespacedName)
+
+	audit.Emit(ctx, audit.Record{
+		Action:    audit.ActionGC,
+		IP:        poolIP,
+		IPPool:    poolName,
+		Namespace: podNS,
+		Pod:       podName,
+		PodUID:    poolIPAllocation.PodUID,
+		Node:      nodeName,
+		NIC:       poolIPAllocation.NIC,
+	})
+}
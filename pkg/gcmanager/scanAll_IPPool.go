@@ -19,7 +19,6 @@ import (
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
 	"github.com/spidernet-io/spiderpool/pkg/metric"
 	"github.com/spidernet-io/spiderpool/pkg/types"
-	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
 )
 
 // monitorGCSignal will monitor signal from CLI, DefaultGCInterval
@@ -100,9 +99,9 @@ func (s *SpiderGC) executeScanAll(ctx context.Context) {
 	fnScanAll := func(pools []spiderpoolv2beta1.SpiderIPPool) {
 		for _, pool := range pools {
 			logger.Sugar().Debugf("checking IPPool '%s'", pool.Name)
-			poolAllocatedIPs, err := convert.UnmarshalIPPoolAllocatedIPs(pool.Status.AllocatedIPs)
+			poolAllocatedIPs, err := s.ippoolMgr.ListAllocatedIPs(ctx, &pool)
 			if nil != err {
-				logger.Sugar().Errorf("failed to parse IPPool '%v' status AllocatedIPs, error: %v", pool, err)
+				logger.Sugar().Errorf("failed to list IPPool '%s' allocated IPs, error: %v", pool.Name, err)
 				continue
 			}
 
@@ -132,6 +131,12 @@ func (s *SpiderGC) executeScanAll(ctx context.Context) {
 								continue
 							}
 						} else {
+							// The standalone containers have no Pod, their IPs are reclaimed
+							// by CNI DEL and GC of spiderpool-agent.
+							if endpoint.Status.OwnerControllerType == constant.KindStandalone && endpoint.Status.Current.UID == poolIPAllocation.PodUID {
+								scanAllLogger.Sugar().Debugf("no need to release IP '%s' for standalone container", poolIP)
+								continue
+							}
 							if s.gcConfig.EnableStatefulSet && endpoint.Status.OwnerControllerType == constant.KindStatefulSet {
 								isValidStsPod, err := s.stsMgr.IsValidStatefulSetPod(ctx, podNS, podName, constant.KindStatefulSet)
 								if nil != err {
@@ -232,6 +237,7 @@ func (s *SpiderGC) executeScanAll(ctx context.Context) {
 									wrappedLog.Sugar().Errorf("failed to release ip '%s', error: '%v'", poolIP, err)
 									continue
 								}
+								auditGC(ctx, pool.Name, poolIP, endpoint.Status.Current.Node, poolIPAllocation)
 								wrappedLog.Sugar().Infof("release ip '%s' successfully!", poolIP)
 							}
 						}
@@ -276,6 +282,7 @@ func (s *SpiderGC) releaseSingleIPAndRemoveWEPFinalizer(ctx context.Context, poo
 	}
 
 	metric.IPGCTotalCounts.Add(ctx, 1)
+	auditGC(ctx, poolName, poolIP, "", poolIPAllocation)
 	log.Sugar().Infof("release ip '%s' successfully", poolIP)
 
 	podNS, podName, err := cache.SplitMetaNamespaceKey(poolIPAllocation.NamespacedName)
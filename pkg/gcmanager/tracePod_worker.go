@@ -6,6 +6,7 @@ package gcmanager
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
 	"github.com/spidernet-io/spiderpool/pkg/metric"
 	"github.com/spidernet-io/spiderpool/pkg/types"
 	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
@@ -91,6 +93,15 @@ func (s *SpiderGC) releaseIPPoolIPExecutor(ctx context.Context, workerIndex int)
 
 				// we need to gather the pod corresponding SpiderEndpoint allocation data to get the used history IPs.
 				podUsedIPs := convert.GroupIPAllocationDetails(endpoint.Status.Current.UID, endpoint.Status.Current.IPs)
+				nics := map[string]string{}
+				for _, d := range endpoint.Status.Current.IPs {
+					if d.IPv4 != nil {
+						nics[strings.Split(*d.IPv4, "/")[0]] = d.NIC
+					}
+					if d.IPv6 != nil {
+						nics[strings.Split(*d.IPv6, "/")[0]] = d.NIC
+					}
+				}
 				tickets := podUsedIPs.Pools()
 				err = s.gcLimiter.AcquireTicket(ctx, tickets...)
 				if nil != err {
@@ -115,6 +126,14 @@ func (s *SpiderGC) releaseIPPoolIPExecutor(ctx context.Context, workerIndex int)
 							metric.IPGCFailureCounts.Add(ctx, 1)
 							log.Sugar().Errorf("failed to release pool '%s' IPs '%+v' in SpiderEndpoint '%s/%s', error: %v",
 								poolName, ips, podCache.Namespace, podCache.PodName, err)
+						} else if err == nil {
+							for _, ip := range ips {
+								auditGC(ctx, poolName, ip.IP, podCache.NodeName, spiderpoolv2beta1.PoolIPAllocation{
+									NIC:            nics[ip.IP],
+									NamespacedName: podCache.Namespace + "/" + podCache.PodName,
+									PodUID:         ip.UID,
+								})
+							}
 						}
 						metric.IPGCTotalCounts.Add(ctx, 1)
 					}(tmpPoolName, tmpIPs)
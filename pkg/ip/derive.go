@@ -0,0 +1,85 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ip
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+)
+
+// DerivationPrefixLength is the prefix length of the subnet whose IPv6
+// addresses are derived, the remaining 64 bits are the interface identifier.
+const DerivationPrefixLength = 64
+
+// IsDerivationCIDR reports whether the subnet is an IPv6 /64 subnet, whose
+// IPv6 addresses can be derived.
+func IsDerivationCIDR(subnet string) error {
+	ipNet, err := ParseCIDR(constant.IPv6, subnet)
+	if err != nil {
+		return err
+	}
+
+	if ones, _ := ipNet.Mask.Size(); ones != DerivationPrefixLength {
+		return fmt.Errorf("%w '%s', the prefix length must be %d to derive IPv6 addresses", ErrInvalidCIDRFormat, subnet, DerivationPrefixLength)
+	}
+
+	return nil
+}
+
+// DeriveEUI64IP returns the IPv6 address of the /64 subnet, whose interface
+// identifier is the modified EUI-64 format of the 48-bit MAC address, see
+// RFC 4291 Appendix A.
+func DeriveEUI64IP(subnet string, mac net.HardwareAddr) (net.IP, error) {
+	if len(mac) != 6 {
+		return nil, fmt.Errorf("invalid MAC address '%s', only the 48-bit MAC address can be converted to EUI-64", mac)
+	}
+
+	iid := []byte{mac[0] ^ 0x02, mac[1], mac[2], 0xff, 0xfe, mac[3], mac[4], mac[5]}
+
+	return withInterfaceID(subnet, iid)
+}
+
+// DeriveHashIP returns the IPv6 address of the /64 subnet, whose interface
+// identifier is hashed from the key. The same key and attempt always result
+// in the same IPv6 address, a different attempt results in another one.
+func DeriveHashIP(subnet, key string, attempt int) (net.IP, error) {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", key, attempt)))
+	iid := sum[:8]
+	// Clear the universal/local bit, the interface identifier is not
+	// derived from a universal MAC address.
+	iid[0] &^= 0x02
+
+	return withInterfaceID(subnet, iid)
+}
+
+// IsReservedInterfaceID reports whether the interface identifier of the
+// IPv6 address is the Subnet-Router anycast one (all zeros), or one of the
+// reserved subnet anycast ones of RFC 2526.
+func IsReservedInterfaceID(ip net.IP) bool {
+	ip = ip.To16()
+	if ip == nil || ip.To4() != nil {
+		return false
+	}
+
+	iid := binary.BigEndian.Uint64(ip[8:])
+
+	return iid == 0 || iid >= 0xfdffffffffffff80
+}
+
+func withInterfaceID(subnet string, iid []byte) (net.IP, error) {
+	if err := IsDerivationCIDR(subnet); err != nil {
+		return nil, err
+	}
+
+	_, ipNet, _ := net.ParseCIDR(subnet)
+	ip := make(net.IP, net.IPv6len)
+	copy(ip[:8], ipNet.IP.To16()[:8])
+	copy(ip[8:], iid)
+
+	return ip, nil
+}
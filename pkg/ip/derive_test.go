@@ -0,0 +1,110 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ip_test
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolip "github.com/spidernet-io/spiderpool/pkg/ip"
+)
+
+var _ = Describe("IP derive", Label("ip_derive_test"), func() {
+	Describe("Test IsDerivationCIDR", func() {
+		It("inputs invalid CIDR address", func() {
+			err := spiderpoolip.IsDerivationCIDR(constant.InvalidCIDR)
+			Expect(err).To(MatchError(spiderpoolip.ErrInvalidCIDRFormat))
+		})
+
+		It("inputs IPv4 CIDR address", func() {
+			err := spiderpoolip.IsDerivationCIDR("172.18.40.0/24")
+			Expect(err).To(MatchError(spiderpoolip.ErrInvalidCIDRFormat))
+		})
+
+		It("inputs IPv6 CIDR address which is not /64", func() {
+			err := spiderpoolip.IsDerivationCIDR("abcd:1234::/120")
+			Expect(err).To(MatchError(spiderpoolip.ErrInvalidCIDRFormat))
+		})
+
+		It("inputs IPv6 /64 CIDR address", func() {
+			err := spiderpoolip.IsDerivationCIDR("abcd:1234::/64")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("Test DeriveEUI64IP", func() {
+		It("inputs invalid MAC address", func() {
+			mac, _ := net.ParseMAC("00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01")
+			ip, err := spiderpoolip.DeriveEUI64IP("abcd:1234::/64", mac)
+			Expect(err).To(HaveOccurred())
+			Expect(ip).To(BeNil())
+		})
+
+		It("inputs the subnet which is not /64", func() {
+			mac, _ := net.ParseMAC("00:1a:2b:3c:4d:5e")
+			ip, err := spiderpoolip.DeriveEUI64IP("abcd:1234::/120", mac)
+			Expect(err).To(MatchError(spiderpoolip.ErrInvalidCIDRFormat))
+			Expect(ip).To(BeNil())
+		})
+
+		It("derives the modified EUI-64 IPv6 address", func() {
+			mac, _ := net.ParseMAC("00:1a:2b:3c:4d:5e")
+			ip, err := spiderpoolip.DeriveEUI64IP("abcd:1234::/64", mac)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ip.String()).To(Equal("abcd:1234::21a:2bff:fe3c:4d5e"))
+		})
+	})
+
+	Describe("Test DeriveHashIP", func() {
+		It("inputs the subnet which is not /64", func() {
+			ip, err := spiderpoolip.DeriveHashIP("abcd:1234::/120", "default/sts-0/eth0", 0)
+			Expect(err).To(MatchError(spiderpoolip.ErrInvalidCIDRFormat))
+			Expect(ip).To(BeNil())
+		})
+
+		It("derives the same IPv6 address from the same key", func() {
+			ip1, err := spiderpoolip.DeriveHashIP("abcd:1234::/64", "default/sts-0/eth0", 0)
+			Expect(err).NotTo(HaveOccurred())
+			ip2, err := spiderpoolip.DeriveHashIP("abcd:1234::/64", "default/sts-0/eth0", 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ip1).To(Equal(ip2))
+			Expect(ip1.To16()[:8]).To(Equal(net.ParseIP("abcd:1234::").To16()[:8]))
+			Expect(ip1.To16()[8] & 0x02).To(BeZero())
+		})
+
+		It("derives different IPv6 addresses from different keys or attempts", func() {
+			ip1, err := spiderpoolip.DeriveHashIP("abcd:1234::/64", "default/sts-0/eth0", 0)
+			Expect(err).NotTo(HaveOccurred())
+			ip2, err := spiderpoolip.DeriveHashIP("abcd:1234::/64", "default/sts-1/eth0", 0)
+			Expect(err).NotTo(HaveOccurred())
+			ip3, err := spiderpoolip.DeriveHashIP("abcd:1234::/64", "default/sts-0/eth0", 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ip1).NotTo(Equal(ip2))
+			Expect(ip1).NotTo(Equal(ip3))
+		})
+	})
+
+	Describe("Test IsReservedInterfaceID", func() {
+		It("checks the Subnet-Router anycast address", func() {
+			Expect(spiderpoolip.IsReservedInterfaceID(net.ParseIP("abcd:1234::"))).To(BeTrue())
+		})
+
+		It("checks the reserved subnet anycast address", func() {
+			Expect(spiderpoolip.IsReservedInterfaceID(net.ParseIP("abcd:1234::fdff:ffff:ffff:ffff"))).To(BeTrue())
+		})
+
+		It("checks the unicast address", func() {
+			Expect(spiderpoolip.IsReservedInterfaceID(net.ParseIP("abcd:1234::1"))).To(BeFalse())
+		})
+
+		It("checks the IPv4 address", func() {
+			Expect(spiderpoolip.IsReservedInterfaceID(net.ParseIP("172.18.40.0"))).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,68 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ip
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// MACPrefixLength is the number of bytes of the MAC prefix, the remaining 4
+// bytes of the 48-bit MAC address are generated.
+const MACPrefixLength = 2
+
+// ParseMACPrefix parses the MAC prefix in the form of 'xx:xx', which must not
+// be a multicast one.
+func ParseMACPrefix(prefix string) ([]byte, error) {
+	parts := strings.Split(prefix, ":")
+	if len(parts) != MACPrefixLength {
+		return nil, fmt.Errorf("%w '%s', it must be %d bytes in the form of 'xx:xx'", ErrInvalidMACPrefix, prefix, MACPrefixLength)
+	}
+
+	b, err := hex.DecodeString(strings.Join(parts, ""))
+	if err != nil || len(b) != MACPrefixLength {
+		return nil, fmt.Errorf("%w '%s', it must be %d bytes in the form of 'xx:xx'", ErrInvalidMACPrefix, prefix, MACPrefixLength)
+	}
+
+	if b[0]&0x01 != 0 {
+		return nil, fmt.Errorf("%w '%s', the multicast bit of the first byte must be 0", ErrInvalidMACPrefix, prefix)
+	}
+
+	return b, nil
+}
+
+// GenMACFromIP returns the MAC address made up of the prefix and the last 4
+// bytes of the IP address.
+func GenMACFromIP(prefix string, ip net.IP) (net.HardwareAddr, error) {
+	if len(ip) == 0 {
+		return nil, fmt.Errorf("%w '%s'", ErrInvalidIPFormat, ip)
+	}
+
+	return withMACSuffix(prefix, ip[len(ip)-4:])
+}
+
+// GenMACFromHash returns the MAC address made up of the prefix and 4 bytes
+// hashed from the key. The same key and attempt always result in the same
+// MAC address, a different attempt results in another one.
+func GenMACFromHash(prefix, key string, attempt int) (net.HardwareAddr, error) {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", key, attempt)))
+
+	return withMACSuffix(prefix, sum[:4])
+}
+
+func withMACSuffix(prefix string, suffix []byte) (net.HardwareAddr, error) {
+	b, err := ParseMACPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := make(net.HardwareAddr, 0, 6)
+	mac = append(mac, b...)
+	mac = append(mac, suffix...)
+
+	return mac, nil
+}
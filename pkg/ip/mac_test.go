@@ -0,0 +1,85 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ip_test
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	spiderpoolip "github.com/spidernet-io/spiderpool/pkg/ip"
+)
+
+var _ = Describe("IP mac", Label("ip_mac_test"), func() {
+	Describe("Test ParseMACPrefix", func() {
+		It("inputs the prefix which is not 2 bytes", func() {
+			prefix, err := spiderpoolip.ParseMACPrefix("0a:1b:2c")
+			Expect(err).To(MatchError(spiderpoolip.ErrInvalidMACPrefix))
+			Expect(prefix).To(BeNil())
+		})
+
+		It("inputs the prefix which is not hexadecimal", func() {
+			prefix, err := spiderpoolip.ParseMACPrefix("0a:zz")
+			Expect(err).To(MatchError(spiderpoolip.ErrInvalidMACPrefix))
+			Expect(prefix).To(BeNil())
+		})
+
+		It("inputs the multicast prefix", func() {
+			prefix, err := spiderpoolip.ParseMACPrefix("01:00")
+			Expect(err).To(MatchError(spiderpoolip.ErrInvalidMACPrefix))
+			Expect(prefix).To(BeNil())
+		})
+
+		It("inputs the unicast prefix", func() {
+			prefix, err := spiderpoolip.ParseMACPrefix("0a:1b")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(prefix).To(Equal([]byte{0x0a, 0x1b}))
+		})
+	})
+
+	Describe("Test GenMACFromIP", func() {
+		It("generates the MAC address of the IPv4 address", func() {
+			mac, err := spiderpoolip.GenMACFromIP("0a:1b", net.ParseIP("172.18.40.10"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mac.String()).To(Equal("0a:1b:ac:12:28:0a"))
+		})
+
+		It("generates the MAC address of the IPv6 address", func() {
+			mac, err := spiderpoolip.GenMACFromIP("0a:1b", net.ParseIP("abcd:1234::a:b"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mac.String()).To(Equal("0a:1b:00:0a:00:0b"))
+		})
+
+		It("inputs the invalid prefix", func() {
+			mac, err := spiderpoolip.GenMACFromIP("01:1b", net.ParseIP("172.18.40.10"))
+			Expect(err).To(MatchError(spiderpoolip.ErrInvalidMACPrefix))
+			Expect(mac).To(BeNil())
+		})
+	})
+
+	Describe("Test GenMACFromHash", func() {
+		It("generates the same MAC address from the same key", func() {
+			mac1, err := spiderpoolip.GenMACFromHash("0a:1b", "default/sts-0/eth0", 0)
+			Expect(err).NotTo(HaveOccurred())
+			mac2, err := spiderpoolip.GenMACFromHash("0a:1b", "default/sts-0/eth0", 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(mac1).To(Equal(mac2))
+			Expect(mac1[:2]).To(Equal(net.HardwareAddr{0x0a, 0x1b}))
+		})
+
+		It("generates different MAC addresses from different keys or attempts", func() {
+			mac1, err := spiderpoolip.GenMACFromHash("0a:1b", "default/sts-0/eth0", 0)
+			Expect(err).NotTo(HaveOccurred())
+			mac2, err := spiderpoolip.GenMACFromHash("0a:1b", "default/sts-1/eth0", 0)
+			Expect(err).NotTo(HaveOccurred())
+			mac3, err := spiderpoolip.GenMACFromHash("0a:1b", "default/sts-0/eth0", 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(mac1).NotTo(Equal(mac2))
+			Expect(mac1).NotTo(Equal(mac3))
+		})
+	})
+})
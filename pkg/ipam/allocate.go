@@ -6,11 +6,14 @@ package ipam
 import (
 	"context"
 	"fmt"
+	"net"
 	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -23,15 +26,27 @@ import (
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
 	"github.com/spidernet-io/spiderpool/pkg/metric"
 	"github.com/spidernet-io/spiderpool/pkg/podmanager"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
 	"github.com/spidernet-io/spiderpool/pkg/types"
 	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
 	"github.com/spidernet-io/spiderpool/pkg/workloadendpointmanager"
 )
 
-func (i *ipam) Allocate(ctx context.Context, addArgs *models.IpamAddArgs) (*models.IpamAddResponse, error) {
+func (i *ipam) Allocate(ctx context.Context, addArgs *models.IpamAddArgs) (_ *models.IpamAddResponse, err error) {
 	logger := logutils.FromContext(ctx)
 	logger.Info("Start to allocate")
 
+	ctx, span := tracing.Start(ctx, "ipam Allocate",
+		attribute.String("pod", *addArgs.PodNamespace+"/"+*addArgs.PodName),
+		attribute.String("containerID", *addArgs.ContainerID),
+		attribute.String("nic", *addArgs.IfName),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	if isStandalone(*addArgs.PodName) {
+		return i.allocateStandalone(ctx, addArgs)
+	}
+
 	pod, err := i.podManager.GetPodByName(ctx, *addArgs.PodNamespace, *addArgs.PodName, constant.UseCache)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Pod %s/%s: %v", *addArgs.PodNamespace, *addArgs.PodName, err)
@@ -57,22 +72,46 @@ func (i *ipam) Allocate(ctx context.Context, addArgs *models.IpamAddArgs) (*mode
 		logger.Debug("No Endpoint")
 	}
 
+	nicToStaticIPs, err := getStaticIPs(addArgs, pod)
+	if err != nil {
+		return nil, err
+	}
+
 	if i.config.EnableStatefulSet && podTopController.APIVersion == appsv1.SchemeGroupVersion.String() && podTopController.Kind == constant.KindStatefulSet {
+		if _, ok := pod.Annotations[constant.AnnoPodIPPoolGroup]; ok {
+			endpoint, err = i.releaseCrossTopologyStsIPAllocation(ctx, pod, endpoint)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check the topology of the IP allocation of StatefulSet %s/%s: %w", podTopController.Namespace, podTopController.Name, err)
+			}
+		}
+
+		endpoint, err = i.releaseMismatchedStsIPAllocation(ctx, endpoint, nicToStaticIPs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check the static IP addresses of the IP allocation of StatefulSet %s/%s: %w", podTopController.Namespace, podTopController.Name, err)
+		}
+
 		logger.Info("Try to retrieve the IP allocation of StatefulSet")
 		addResp, err := i.retrieveStsIPAllocation(ctx, *addArgs.IfName, pod, endpoint)
 		if err != nil {
 			return nil, fmt.Errorf("failed to retrieve the IP allocation of StatefulSet %s/%s: %w", podTopController.Namespace, podTopController.Name, err)
 		}
 		if addResp != nil {
+			if err := i.endpointManager.UpdateAttachment(ctx, attachmentOf(addArgs), endpoint); err != nil {
+				return nil, fmt.Errorf("failed to update the attachment of Endpoint: %w", err)
+			}
+			auditAllocation(ctx, addArgs, pod, addResp.Ips)
 			return addResp, nil
 		}
 	} else {
 		logger.Debug("Try to retrieve the existing IP allocation")
-		addResp, err := i.retrieveExistingIPAllocation(ctx, string(pod.UID), *addArgs.IfName, endpoint)
+		addResp, err := i.retrieveExistingIPAllocation(ctx, string(pod.UID), *addArgs.IfName, endpoint, nicToStaticIPs)
 		if err != nil {
 			return nil, fmt.Errorf("failed to retrieve the existing IP allocation: %w", err)
 		}
 		if addResp != nil {
+			if err := i.endpointManager.UpdateAttachment(ctx, attachmentOf(addArgs), endpoint); err != nil {
+				return nil, fmt.Errorf("failed to update the attachment of Endpoint: %w", err)
+			}
 			return addResp, nil
 		}
 	}
@@ -116,6 +155,88 @@ func (i *ipam) retrieveStsIPAllocation(ctx context.Context, nic string, pod *cor
 	return addResp, nil
 }
 
+// releaseCrossTopologyStsIPAllocation releases the IP allocation kept for a
+// StatefulSet Pod which is rescheduled to a Node out of the topology served by
+// the IPPool group members it has IP addresses from, so that the Pod gets new
+// IP addresses from the members serving its new Node.
+func (i *ipam) releaseCrossTopologyStsIPAllocation(ctx context.Context, pod *corev1.Pod, endpoint *spiderpoolv2beta1.SpiderEndpoint) (*spiderpoolv2beta1.SpiderEndpoint, error) {
+	if endpoint == nil {
+		return nil, nil
+	}
+
+	node, err := i.nodeManager.GetNodeByName(ctx, pod.Spec.NodeName, constant.UseCache)
+	if err != nil {
+		return nil, err
+	}
+
+	crossTopology := false
+	for _, d := range endpoint.Status.Current.IPs {
+		for _, pool := range []*string{d.IPv4Pool, d.IPv6Pool} {
+			if pool == nil {
+				continue
+			}
+
+			ipPool, err := i.ipPoolManager.GetIPPoolByName(ctx, *pool, constant.UseCache)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+
+			if ipPool.Spec.PoolGroup != nil && !ippoolmanager.IsMatchPoolGroupTopology(ipPool, node.Labels) {
+				crossTopology = true
+			}
+		}
+	}
+
+	if !crossTopology {
+		return endpoint, nil
+	}
+
+	logger := logutils.FromContext(ctx)
+	logger.Sugar().Infof("StatefulSet Pod is rescheduled to Node %s out of the topology of its IPPools, release IP allocation details: %v", node.Name, endpoint.Status.Current.IPs)
+	if err := i.releaseStsIPAllocation(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// releaseMismatchedStsIPAllocation releases the IP allocation kept for a
+// StatefulSet Pod whose static IP addresses are changed, so that the Pod gets
+// the static IP addresses requested now instead of the ones kept.
+func (i *ipam) releaseMismatchedStsIPAllocation(ctx context.Context, endpoint *spiderpoolv2beta1.SpiderEndpoint, nicToStaticIPs map[string][]net.IP) (*spiderpoolv2beta1.SpiderEndpoint, error) {
+	if endpoint == nil || len(nicToStaticIPs) == 0 {
+		return endpoint, nil
+	}
+
+	err := checkRetrievedStaticIPs(endpoint.Status.Current.IPs, nicToStaticIPs)
+	if err == nil {
+		return endpoint, nil
+	}
+
+	logger := logutils.FromContext(ctx)
+	logger.Sugar().Infof("Static IP addresses of StatefulSet Pod are changed (%v), release IP allocation details: %v", err, endpoint.Status.Current.IPs)
+	if err := i.releaseStsIPAllocation(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (i *ipam) releaseStsIPAllocation(ctx context.Context, endpoint *spiderpoolv2beta1.SpiderEndpoint) error {
+	if err := i.release(ctx, endpoint, endpoint.Status.Current.UID, endpoint.Status.Current.IPs); err != nil {
+		return err
+	}
+
+	if err := i.endpointManager.RemoveFinalizer(ctx, endpoint); err != nil {
+		return err
+	}
+
+	return i.endpointManager.DeleteEndpoint(ctx, endpoint)
+}
+
 func (i *ipam) reallocateIPPoolIPRecords(ctx context.Context, uid string, endpoint *spiderpoolv2beta1.SpiderEndpoint) error {
 	logger := logutils.FromContext(ctx)
 
@@ -161,7 +282,7 @@ func (i *ipam) reallocateIPPoolIPRecords(ctx context.Context, uid string, endpoi
 	return nil
 }
 
-func (i *ipam) retrieveExistingIPAllocation(ctx context.Context, uid, nic string, endpoint *spiderpoolv2beta1.SpiderEndpoint) (*models.IpamAddResponse, error) {
+func (i *ipam) retrieveExistingIPAllocation(ctx context.Context, uid, nic string, endpoint *spiderpoolv2beta1.SpiderEndpoint, nicToStaticIPs map[string][]net.IP) (*models.IpamAddResponse, error) {
 	logger := logutils.FromContext(ctx)
 
 	// Create -> Delete -> Create a Pod with the same namespace and name in
@@ -177,6 +298,12 @@ func (i *ipam) retrieveExistingIPAllocation(ctx context.Context, uid, nic string
 		return nil, nil
 	}
 
+	// The IP allocation of the Pod is never changed during its lifetime, so
+	// fail rather than silently ignore the static IP addresses changed.
+	if err := checkRetrievedStaticIPs(allocation.IPs, nicToStaticIPs); err != nil {
+		return nil, err
+	}
+
 	ips, routes := convert.ConvertIPDetailsToIPConfigsAndAllRoutes(allocation.IPs)
 	addResp := &models.IpamAddResponse{
 		Ips:    ips,
@@ -225,7 +352,7 @@ func (i *ipam) allocateInStandardMode(ctx context.Context, addArgs *models.IpamA
 	}
 
 	logger.Debug("Patch IP allocation results to Endpoint")
-	if err = i.endpointManager.PatchIPAllocationResults(ctx, results, endpoint, pod, podController); err != nil {
+	if err = i.endpointManager.PatchIPAllocationResults(ctx, attachmentOf(addArgs), results, endpoint, pod, podController); err != nil {
 		return nil, fmt.Errorf("failed to patch IP allocation results to Endpoint: %v", err)
 	}
 
@@ -235,6 +362,7 @@ func (i *ipam) allocateInStandardMode(ctx context.Context, addArgs *models.IpamA
 		Routes: resRoutes,
 	}
 	logger.Sugar().Infof("Succeed to allocate: %+v", *addResp)
+	auditAllocation(ctx, addArgs, pod, addResp.Ips)
 
 	return addResp, nil
 }
@@ -268,6 +396,24 @@ func (i *ipam) genToBeAllocatedSet(ctx context.Context, addArgs *models.IpamAddA
 	}
 	logger.Sugar().Infof("Filtered IPPool candidates: %s", preliminary)
 
+	if err := i.applyStaticIPs(ctx, preliminary, addArgs, pod); err != nil {
+		return nil, err
+	}
+
+	if mac, err := net.ParseMAC(addArgs.Mac); err == nil {
+		for _, t := range preliminary {
+			if t.NIC == *addArgs.IfName {
+				t.MAC = mac
+			}
+		}
+	}
+
+	if i.config.PoolHealth != nil {
+		for _, t := range preliminary {
+			i.preferHealthyPools(ctx, t)
+		}
+	}
+
 	logger.Debug("Verify IPPool candidates")
 	if err := i.verifyPoolCandidates(preliminary); err != nil {
 		return nil, err
@@ -290,44 +436,63 @@ func (i *ipam) allocateIPsFromAllCandidates(ctx context.Context, tt ToBeAllocate
 	// Record the metric of queuing time for allocating.
 	metric.IPAMDurationConstruct.RecordIPAMAllocationLimitDuration(ctx, timeRecorder.SinceInSeconds())
 
-	n := len(tt.Candidates())
-	resultCh := make(chan *types.AllocationResult, n)
-	errCh := make(chan error, n)
-	wg := sync.WaitGroup{}
-	wg.Add(n)
+	var results []*types.AllocationResult
+	var errs []error
+	allocateCandidates := func(eui64 bool, nicMAC func(t *ToBeAllocated) net.HardwareAddr) {
+		n := len(tt.Candidates())
+		resultCh := make(chan *types.AllocationResult, n)
+		errCh := make(chan error, n)
+		wg := sync.WaitGroup{}
 
-	doAllocate := func(candidate *PoolCandidate, nic string, cleanGateway bool) {
-		defer wg.Done()
+		doAllocate := func(candidate *PoolCandidate, nic string, mac net.HardwareAddr, cleanGateway bool) {
+			defer wg.Done()
 
-		clogger := logger.With(zap.String("AllocateHash", fmt.Sprintf("%s-%d-%v", nic, candidate.IPVersion, candidate.Pools)))
-		clogger.Sugar().Debugf("Try to allocate IPv%d IP address to NIC %s from IPPools %v", candidate.IPVersion, nic, candidate.Pools)
-		result, err := i.allocateIPFromCandidate(logutils.IntoContext(ctx, clogger), candidate, nic, cleanGateway, pod)
-		if err != nil {
-			clogger.Warn(err.Error())
-			errCh <- err
-			return
-		}
+			clogger := logger.With(zap.String("AllocateHash", fmt.Sprintf("%s-%d-%v", nic, candidate.IPVersion, candidate.Pools)))
+			clogger.Sugar().Debugf("Try to allocate IPv%d IP address to NIC %s from IPPools %v", candidate.IPVersion, nic, candidate.Pools)
+			result, err := i.allocateIPFromCandidate(logutils.IntoContext(ctx, clogger), candidate, nic, mac, cleanGateway, pod)
+			if err != nil {
+				clogger.Warn(err.Error())
+				errCh <- err
+				return
+			}
 
-		resultCh <- result
-	}
+			resultCh <- result
+		}
 
-	for _, t := range tt {
-		for _, c := range t.PoolCandidates {
-			go doAllocate(c, t.NIC, t.CleanGateway)
+		for _, t := range tt {
+			for _, c := range t.PoolCandidates {
+				if isEUI64Candidate(c) != eui64 {
+					continue
+				}
+				wg.Add(1)
+				go doAllocate(c, t.NIC, nicMAC(t), t.CleanGateway)
+			}
 		}
-	}
-	wg.Wait()
-	close(resultCh)
-	close(errCh)
+		wg.Wait()
+		close(resultCh)
+		close(errCh)
 
-	var results []*types.AllocationResult
-	for res := range resultCh {
-		results = append(results, res)
+		for res := range resultCh {
+			results = append(results, res)
+		}
+		for err := range errCh {
+			errs = append(errs, err)
+		}
 	}
 
-	var errs []error
-	for err := range errCh {
-		errs = append(errs, err)
+	// The EUI-64 IPv6 addresses are derived after the other IP addresses of
+	// the NIC, from the MAC address allocated along with them if any, which
+	// replaces the current MAC address of the NIC.
+	allocateCandidates(false, func(t *ToBeAllocated) net.HardwareAddr {
+		return t.MAC
+	})
+	if len(errs) == 0 {
+		allocateCandidates(true, func(t *ToBeAllocated) net.HardwareAddr {
+			if mac := allocatedMAC(results, t.NIC); mac != nil {
+				return mac
+			}
+			return t.MAC
+		})
 	}
 
 	if len(errs) != 0 {
@@ -337,10 +502,22 @@ func (i *ipam) allocateIPsFromAllCandidates(ctx context.Context, tt ToBeAllocate
 	return results, nil
 }
 
-func (i *ipam) allocateIPFromCandidate(ctx context.Context, c *PoolCandidate, nic string, cleanGateway bool, pod *corev1.Pod) (*types.AllocationResult, error) {
+func (i *ipam) allocateIPFromCandidate(ctx context.Context, c *PoolCandidate, nic string, mac net.HardwareAddr, cleanGateway bool, pod *corev1.Pod) (_ *types.AllocationResult, err error) {
 	logger := logutils.FromContext(ctx)
 
+	ctx, span := tracing.Start(ctx, "ipam allocateIPFromCandidate",
+		attribute.Int("ipVersion", int(c.IPVersion)),
+		attribute.String("nic", nic),
+		attribute.StringSlice("ippools", c.Pools),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	for _, oldRes := range i.failure.getFailureIPs(string(pod.UID)) {
+		if c.IP != nil {
+			if ip, _, err := net.ParseCIDR(*oldRes.IP.Address); err != nil || !ip.Equal(c.IP) {
+				continue
+			}
+		}
 		for _, ipPool := range c.PToIPPool {
 			if oldRes.IP.IPPool == ipPool.Name && *oldRes.IP.Nic == nic {
 				logger.Sugar().Infof("Reuse allocated IPv%d IP %s for NIC %s from IPPool %s", c.IPVersion, *oldRes.IP.Address, nic, ipPool.Name)
@@ -354,7 +531,15 @@ func (i *ipam) allocateIPFromCandidate(ctx context.Context, c *PoolCandidate, ni
 	var errs []error
 	var result *types.AllocationResult
 	for _, pool := range c.Pools {
-		ip, err := i.ipPoolManager.AllocateIP(ctx, pool, nic, pod)
+		var ip *models.IPConfig
+		var err error
+		if c.IP != nil {
+			ip, err = i.ipPoolManager.AllocateStaticIP(ctx, pool, nic, c.IP, pod)
+		} else if ippoolmanager.IsDerivedIPPool(c.PToIPPool[pool]) {
+			ip, err = i.ipPoolManager.AllocateDerivedIP(ctx, pool, nic, mac, pod)
+		} else {
+			ip, err = i.ipPoolManager.AllocateIP(ctx, pool, nic, pod)
+		}
 		if err != nil {
 			logger.Sugar().Warnf("Failed to allocate IPv%d IP address to NIC %s from IPPool %s: %v", c.IPVersion, nic, pool, err)
 			errs = append(errs, err)
@@ -438,6 +623,81 @@ func (i *ipam) filterPoolCandidates(ctx context.Context, t *ToBeAllocated, pod *
 	return nil
 }
 
+// applyStaticIPs pins the IPPool candidates of the NICs to the static IP
+// addresses requested for them. It fails if no IPPool the Pod is allowed to
+// use contains the static IP address, rather than allocating another one.
+func (i *ipam) applyStaticIPs(ctx context.Context, tt ToBeAllocateds, addArgs *models.IpamAddArgs, pod *corev1.Pod) error {
+	logger := logutils.FromContext(ctx)
+
+	nicToIPs, err := getStaticIPs(addArgs, pod)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tt {
+		for _, ip := range nicToIPs[t.NIC] {
+			version := constant.IPv4
+			if ip.To4() == nil {
+				version = constant.IPv6
+			}
+
+			var candidate *PoolCandidate
+			for _, c := range t.PoolCandidates {
+				if c.IPVersion == version {
+					candidate = c
+					break
+				}
+			}
+			if candidate == nil {
+				return fmt.Errorf("%w, no IPv%d IPPool specified for NIC %s to allocate static IP %s", constant.ErrWrongInput, version, t.NIC, ip)
+			}
+			if candidate.IP != nil {
+				return fmt.Errorf("%w, more than one static IPv%d IP specified for NIC %s", constant.ErrWrongInput, version, t.NIC)
+			}
+
+			var pools []string
+			for _, pool := range candidate.Pools {
+				if ippoolmanager.IsIPInIPPool(candidate.PToIPPool[pool], ip) {
+					pools = append(pools, pool)
+				} else {
+					delete(candidate.PToIPPool, pool)
+				}
+			}
+			if len(pools) == 0 {
+				return fmt.Errorf("%w, static IP %s of NIC %s is not within any IPPool %v the Pod is allowed to use", constant.ErrNoAvailablePool, ip, t.NIC, candidate.Pools)
+			}
+
+			logger.Sugar().Infof("Allocate static IP %s to NIC %s from IPPools %v", ip, t.NIC, pools)
+			candidate.Pools = pools
+			candidate.IP = ip
+		}
+	}
+
+	return nil
+}
+
+// preferHealthyPools moves the IPPools unhealthy on the node to the end of
+// the candidates, they are still used if the others are exhausted.
+func (i *ipam) preferHealthyPools(ctx context.Context, t *ToBeAllocated) {
+	logger := logutils.FromContext(ctx)
+
+	for _, c := range t.PoolCandidates {
+		var healthy, unhealthy []string
+		for _, pool := range c.Pools {
+			if i.config.PoolHealth.IsUnhealthy(pool) {
+				unhealthy = append(unhealthy, pool)
+				continue
+			}
+			healthy = append(healthy, pool)
+		}
+
+		if len(unhealthy) != 0 {
+			logger.Sugar().Infof("IPv%d IPPools %v of %s are unhealthy on the Node, prefer the others", c.IPVersion, unhealthy, t.NIC)
+			c.Pools = append(healthy, unhealthy...)
+		}
+	}
+}
+
 func (i *ipam) selectByPod(ctx context.Context, version types.IPVersion, ipPool *spiderpoolv2beta1.SpiderIPPool, pod *corev1.Pod, podTopController types.PodTopController) error {
 	if ipPool.DeletionTimestamp != nil {
 		return fmt.Errorf("terminating IPPool %s", ipPool.Name)
@@ -470,11 +730,11 @@ func (i *ipam) selectByPod(ctx context.Context, version types.IPVersion, ipPool
 		if err != nil {
 			return err
 		}
-		selector, err := metav1.LabelSelectorAsSelector(ipPool.Spec.NamespaceAffinity)
+		matched, err := ippoolmanager.IsMatchNamespaceAffinity(ipPool, namespace.Labels)
 		if err != nil {
 			return err
 		}
-		if !selector.Matches(labels.Set(namespace.Labels)) {
+		if !matched {
 			return fmt.Errorf("unmatched Namespace affinity of IPPool %s", ipPool.Name)
 		}
 	}
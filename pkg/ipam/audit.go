@@ -0,0 +1,71 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipam
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
+	"github.com/spidernet-io/spiderpool/pkg/audit"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/types"
+)
+
+// auditAllocation records the IP addresses allocated to the Pod in the audit
+// log. The MAC address is only known for the NIC of the CNI call.
+func auditAllocation(ctx context.Context, addArgs *models.IpamAddArgs, pod *corev1.Pod, ips []*models.IPConfig) {
+	for _, ip := range ips {
+		if ip == nil || ip.Address == nil {
+			continue
+		}
+
+		r := audit.Record{
+			Action:      audit.ActionAllocate,
+			IP:          strings.Split(*ip.Address, "/")[0],
+			IPPool:      ip.IPPool,
+			Namespace:   pod.Namespace,
+			Pod:         pod.Name,
+			PodUID:      string(pod.UID),
+			Node:        pod.Spec.NodeName,
+			ContainerID: *addArgs.ContainerID,
+		}
+		if ip.Nic != nil {
+			r.NIC = *ip.Nic
+			if *ip.Nic == *addArgs.IfName {
+				r.MAC = addArgs.Mac
+			}
+		}
+		audit.Emit(ctx, r)
+	}
+}
+
+// auditRelease records the IP addresses released from the IPPool in the audit
+// log.
+func auditRelease(ctx context.Context, endpoint *spiderpoolv2beta1.SpiderEndpoint, poolName string, ipAndUIDs []types.IPAndUID, details []spiderpoolv2beta1.IPAllocationDetail) {
+	nics := map[string]string{}
+	for _, d := range details {
+		if d.IPv4 != nil {
+			nics[strings.Split(*d.IPv4, "/")[0]] = d.NIC
+		}
+		if d.IPv6 != nil {
+			nics[strings.Split(*d.IPv6, "/")[0]] = d.NIC
+		}
+	}
+
+	for _, iu := range ipAndUIDs {
+		audit.Emit(ctx, audit.Record{
+			Action:    audit.ActionRelease,
+			IP:        iu.IP,
+			IPPool:    poolName,
+			Namespace: endpoint.Namespace,
+			Pod:       endpoint.Name,
+			PodUID:    iu.UID,
+			Node:      endpoint.Status.Current.Node,
+			NIC:       nics[iu.IP],
+		})
+	}
+}
@@ -25,6 +25,25 @@ type IPAMConfig struct {
 
 	OperationRetries     int
 	OperationGapDuration time.Duration
+
+	NodeName                  string
+	IPConflictReprobeInterval time.Duration
+	IPConflictMaxAge          time.Duration
+
+	// EnableStandalone allows the containers without Pods, their Endpoints
+	// are recorded in StandaloneNamespace.
+	EnableStandalone    bool
+	StandaloneNamespace string
+
+	// PoolHealth is optional, the IPPools it reports unhealthy on the node
+	// are tried after the others.
+	PoolHealth PoolHealthChecker
+}
+
+// PoolHealthChecker reports whether an IPPool is unhealthy on the node, such
+// as its gateway is unreachable from the local Pods.
+type PoolHealthChecker interface {
+	IsUnhealthy(pool string) bool
 }
 
 func setDefaultsForIPAMConfig(config IPAMConfig) IPAMConfig {
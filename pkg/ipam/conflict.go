@@ -0,0 +1,203 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/event"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+	"github.com/spidernet-io/spiderpool/pkg/networking/ipchecking"
+	"github.com/spidernet-io/spiderpool/pkg/types"
+)
+
+const (
+	reprobeRetries  = 3
+	reprobeInterval = "1s"
+	reprobeTimeout  = "3s"
+)
+
+func (i *ipam) ReportIPConflict(ctx context.Context, conflictArgs *models.IpamConflictArgs) error {
+	logger := logutils.FromContext(ctx)
+	logger.Info("Start to handle IP conflict")
+
+	conflictIP := net.ParseIP(*conflictArgs.IP)
+	if conflictIP == nil {
+		return fmt.Errorf("%w, invalid IP address %s", constant.ErrWrongInput, *conflictArgs.IP)
+	}
+
+	endpoint, err := i.endpointManager.GetEndpointByName(ctx, *conflictArgs.PodNamespace, *conflictArgs.PodName, constant.IgnoreCache)
+	if err != nil {
+		return fmt.Errorf("failed to get Endpoint %s/%s: %v", *conflictArgs.PodNamespace, *conflictArgs.PodName, err)
+	}
+	// Some CRIs do not set K8S_POD_UID in CNI_ARGS, trust the current IP
+	// allocation of Endpoint in that case.
+	if *conflictArgs.PodUID != "" && endpoint.Status.Current.UID != *conflictArgs.PodUID {
+		return fmt.Errorf("%w, the current IP allocation of Endpoint %s/%s does not belong to Pod (UID: %s)", constant.ErrWrongInput, endpoint.Namespace, endpoint.Name, *conflictArgs.PodUID)
+	}
+
+	poolName := conflictIPPool(conflictIP, *conflictArgs.IfName, endpoint.Status.Current.IPs)
+	if poolName == "" {
+		return fmt.Errorf("%w, IP address %s is not allocated to NIC %s of Pod %s/%s", constant.ErrWrongInput, conflictIP, *conflictArgs.IfName, endpoint.Namespace, endpoint.Name)
+	}
+
+	logger.Sugar().Infof("Mark IP address %s of IPPool %s as conflicted with %s", conflictIP, poolName, *conflictArgs.Mac)
+	conflict := spiderpoolv2beta1.PoolIPConflict{
+		MAC:          *conflictArgs.Mac,
+		Node:         endpoint.Status.Current.Node,
+		DetectedTime: metav1.Now(),
+	}
+	if err := i.ipPoolManager.MarkIPConflicted(ctx, poolName, types.IPAndUID{IP: conflictIP.String(), UID: endpoint.Status.Current.UID}, conflict); err != nil {
+		return err
+	}
+
+	// Release the rest of the IP allocation of the NIC, then the Pod will be
+	// allocated with new IP addresses in the retries of CNI ADD.
+	var details []spiderpoolv2beta1.IPAllocationDetail
+	for _, d := range endpoint.Status.Current.IPs {
+		if d.NIC == *conflictArgs.IfName {
+			details = append(details, d)
+		}
+	}
+	if err := i.release(ctx, endpoint, endpoint.Status.Current.UID, details); err != nil {
+		return err
+	}
+	if err := i.endpointManager.RemoveNICIPAllocation(ctx, *conflictArgs.IfName, endpoint); err != nil {
+		return fmt.Errorf("failed to remove the IP allocation of NIC %s from Endpoint: %v", *conflictArgs.IfName, err)
+	}
+
+	pod, err := i.podManager.GetPodByName(ctx, *conflictArgs.PodNamespace, *conflictArgs.PodName, constant.UseCache)
+	if err == nil {
+		event.EventRecorder.Eventf(
+			pod,
+			corev1.EventTypeWarning,
+			constant.EventReasonIPConflict,
+			"IP address %s of interface %s conflicts with the host %s, which is released for re-allocation", conflictIP, *conflictArgs.IfName, *conflictArgs.Mac,
+		)
+	}
+
+	ipPool, err := i.ipPoolManager.GetIPPoolByName(ctx, poolName, constant.UseCache)
+	if err == nil {
+		event.EventRecorder.Eventf(
+			ipPool,
+			corev1.EventTypeWarning,
+			constant.EventReasonIPConflict,
+			"IP address %s conflicts with the host %s, exclude it from allocation", conflictIP, *conflictArgs.Mac,
+		)
+	}
+
+	logger.Info("Succeed to handle IP conflict")
+
+	return nil
+}
+
+func conflictIPPool(ip net.IP, nic string, details []spiderpoolv2beta1.IPAllocationDetail) string {
+	for _, d := range details {
+		if d.NIC != nic {
+			continue
+		}
+
+		if d.IPv4 != nil && d.IPv4Pool != nil {
+			if addr, _, err := net.ParseCIDR(*d.IPv4); err == nil && addr.Equal(ip) {
+				return *d.IPv4Pool
+			}
+		}
+		if d.IPv6 != nil && d.IPv6Pool != nil {
+			if addr, _, err := net.ParseCIDR(*d.IPv6); err == nil && addr.Equal(ip) {
+				return *d.IPv6Pool
+			}
+		}
+	}
+
+	return ""
+}
+
+// reprobeConflictIPs periodically probes the conflicting IP addresses detected
+// on this Node, and clears the conflict marks of those no longer answered. An
+// IP address which can not be probed from this Node is cleared once the mark
+// is older than IPConflictMaxAge.
+func (i *ipam) reprobeConflictIPs(ctx context.Context) {
+	logger := logutils.Logger.Named("IP-Conflict-Reprobe")
+	ctx = logutils.IntoContext(ctx, logger)
+
+	ticker := time.NewTicker(i.config.IPConflictReprobeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		ipPoolList, err := i.ipPoolManager.ListIPPools(ctx, constant.UseCache)
+		if err != nil {
+			logger.Sugar().Warnf("failed to list IPPools: %v", err)
+			continue
+		}
+
+		for _, ipPool := range ipPoolList.Items {
+			for ip, conflict := range ipPool.Status.ConflictIPs {
+				if conflict.Node != i.config.NodeName {
+					continue
+				}
+				i.reprobeConflictIP(ctx, ipPool.DeepCopy(), ip, conflict)
+			}
+		}
+	}
+}
+
+func (i *ipam) reprobeConflictIP(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool, ip string, conflict spiderpoolv2beta1.PoolIPConflict) {
+	logger := logutils.FromContext(ctx).With(
+		zap.String("IPPoolName", ipPool.Name),
+		zap.String("IP", ip),
+	)
+
+	target, err := netip.ParseAddr(ip)
+	if err != nil {
+		logger.Sugar().Warnf("invalid conflicting IP address: %v", err)
+		return
+	}
+
+	err = ipchecking.ProbeIPOnHost(target, reprobeRetries, reprobeInterval, reprobeTimeout, logger)
+	var conflictErr *ipchecking.IPConflictError
+	switch {
+	case errors.As(err, &conflictErr):
+		logger.Sugar().Debugf("IP address is still answered by %s", conflictErr.MAC)
+		return
+	case errors.Is(err, ipchecking.ErrNotOnLink):
+		if time.Since(conflict.DetectedTime.Time) < i.config.IPConflictMaxAge {
+			logger.Debug("IP address is not on-link of this Node, wait for the conflict mark to expire")
+			return
+		}
+	case err != nil:
+		logger.Sugar().Warnf("failed to probe IP address: %v", err)
+		return
+	}
+
+	if err := i.ipPoolManager.UnmarkIPConflicted(ctx, ipPool.Name, ip); err != nil {
+		logger.Sugar().Warnf("failed to clear the conflict mark: %v", err)
+		return
+	}
+
+	logger.Info("Clear the conflict mark of IP address")
+	event.EventRecorder.Eventf(
+		ipPool,
+		corev1.EventTypeNormal,
+		constant.EventReasonIPConflictCleared,
+		"IP address %s is no longer in conflict with the host %s", ip, conflict.MAC,
+	)
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
+	"github.com/spidernet-io/spiderpool/pkg/types"
+	"github.com/spidernet-io/spiderpool/pkg/workloadendpointmanager"
+)
+
+// GarbageCollect releases the IP allocations of the interfaces on this node
+// which are attached to the network being collected, but are not in the
+// valid attachments supplied by CNI GC. The other interfaces of the same
+// sandbox may be attached by other networks, so the network and interface of
+// each IP allocation are matched besides the container ID. It assumes the
+// container runtime does not run CNI GC concurrently with CNI ADD of the same
+// network.
+func (i *ipam) GarbageCollect(ctx context.Context, gcArgs *models.IpamGCArgs) (err error) {
+	logger := logutils.FromContext(ctx)
+	logger.Info("Start to collect garbage")
+
+	network := *gcArgs.Network
+	ctx, span := tracing.Start(ctx, "ipam GarbageCollect",
+		attribute.String("network", network),
+		attribute.Int("validAttachments", len(gcArgs.ValidAttachments)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	validAttachments := make(map[types.Attachment]struct{}, len(gcArgs.ValidAttachments))
+	for _, a := range gcArgs.ValidAttachments {
+		if a == nil || a.ContainerID == nil || a.IfName == nil {
+			continue
+		}
+		validAttachments[types.Attachment{ContainerID: *a.ContainerID, NIC: *a.IfName, Network: network}] = struct{}{}
+	}
+
+	endpointList, err := i.endpointManager.ListEndpoints(ctx, constant.UseCache)
+	if err != nil {
+		return fmt.Errorf("failed to list Endpoints: %v", err)
+	}
+
+	var errs []error
+	for j := range endpointList.Items {
+		endpoint := &endpointList.Items[j]
+		current := endpoint.Status.Current
+
+		// The Endpoints created by the previous versions do not record
+		// the container ID or the network, leave them to the GC of
+		// spiderpool-controller.
+		if current.Node != i.config.NodeName {
+			continue
+		}
+		details := workloadendpointmanager.RetrieveStaleIPAllocationDetails(network, validAttachments, endpoint)
+		if len(details) == 0 {
+			continue
+		}
+
+		eLogger := logger.With(
+			zap.String("Endpoint", endpoint.Namespace+"/"+endpoint.Name),
+			zap.String("ContainerID", current.ContainerID),
+		)
+		eLogger.Sugar().Infof("Attachments of Pod sandbox are not valid, release IP allocation details: %v", details)
+		if err := i.releaseStaleNICs(logutils.IntoContext(ctx, eLogger), endpoint, details); err != nil {
+			eLogger.Warn(err.Error())
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 0 {
+		return fmt.Errorf("failed to collect all garbage: %w", utilerrors.NewAggregate(errs))
+	}
+	logger.Info("Succeed to collect garbage")
+
+	return nil
+}
+
+// releaseStaleNICs releases the IP allocation details of the stale
+// attachments. The Pod keeps the IP allocation of its NICs attached to the
+// other networks, and releases the whole IP allocation as CNI DEL if none
+// is left.
+func (i *ipam) releaseStaleNICs(ctx context.Context, endpoint *spiderpoolv2beta1.SpiderEndpoint, details []spiderpoolv2beta1.IPAllocationDetail) error {
+	if endpoint.Status.OwnerControllerType == constant.KindStandalone {
+		for _, d := range details {
+			if err := i.releaseStandaloneNICs(ctx, d.NIC, endpoint); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	current := endpoint.Status.Current
+	if len(details) == len(current.IPs) {
+		if err := i.releaseForAllNICs(ctx, current.UID, current.IPs[0].NIC, endpoint); err != nil {
+			return err
+		}
+		i.failure.rmFailureIPs(current.UID)
+		return nil
+	}
+
+	// Keep the IP allocation of StatefulSet as CNI DEL does.
+	if i.config.EnableStatefulSet && endpoint.Status.OwnerControllerType == constant.KindStatefulSet {
+		valid, err := i.stsManager.IsValidStatefulSetPod(ctx, endpoint.Namespace, endpoint.Name, endpoint.Status.OwnerControllerType)
+		if err != nil {
+			return fmt.Errorf("failed to check pod %s/%s whether is a valid StatefulSet pod: %v", endpoint.Namespace, endpoint.Name, err)
+		}
+		if valid {
+			logutils.FromContext(ctx).Info("There is no need to release the IP allocation of StatefulSet")
+			return nil
+		}
+	}
+
+	if err := i.release(ctx, endpoint, current.UID, details); err != nil {
+		return err
+	}
+	for _, d := range details {
+		if err := i.endpointManager.RemoveNICIPAllocation(ctx, d.NIC, endpoint); err != nil {
+			return fmt.Errorf("failed to remove the IP allocation of NIC %s from Endpoint: %v", d.NIC, err)
+		}
+	}
+
+	return nil
+}
@@ -24,6 +24,8 @@ import (
 type IPAM interface {
 	Allocate(ctx context.Context, addArgs *models.IpamAddArgs) (*models.IpamAddResponse, error)
 	Release(ctx context.Context, delArgs *models.IpamDelArgs) error
+	ReportIPConflict(ctx context.Context, conflictArgs *models.IpamConflictArgs) error
+	GarbageCollect(ctx context.Context, gcArgs *models.IpamGCArgs) error
 	Start(ctx context.Context) error
 }
 
@@ -72,6 +74,9 @@ func NewIPAM(
 	if config.EnableSpiderSubnet && subnetManager == nil {
 		return nil, fmt.Errorf("subnet manager %w", constant.ErrMissingRequiredParam)
 	}
+	if config.EnableStandalone && config.StandaloneNamespace == "" {
+		return nil, fmt.Errorf("standalone namespace %w", constant.ErrMissingRequiredParam)
+	}
 
 	return &ipam{
 		config:          setDefaultsForIPAMConfig(config),
@@ -95,6 +100,10 @@ func (i *ipam) Start(ctx context.Context) error {
 		}
 	}()
 
+	if i.config.IPConflictReprobeInterval > 0 {
+		go i.reprobeConflictIPs(ctx)
+	}
+
 	select {
 	case <-ctx.Done():
 		return nil
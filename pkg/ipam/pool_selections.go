@@ -5,12 +5,13 @@ package ipam
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strconv"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/multierr"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -20,13 +21,20 @@ import (
 	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
 	"github.com/spidernet-io/spiderpool/pkg/applicationcontroller/applicationinformers"
 	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/ippoolmanager"
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
 	"github.com/spidernet-io/spiderpool/pkg/namespacemanager"
+	"github.com/spidernet-io/spiderpool/pkg/podmanager"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
 	"github.com/spidernet-io/spiderpool/pkg/types"
 )
 
 func (i *ipam) getPoolCandidates(ctx context.Context, addArgs *models.IpamAddArgs, pod *corev1.Pod, podController types.PodTopController) (ToBeAllocateds, error) {
+	if podController.Kind == constant.KindStandalone {
+		return i.getStandalonePoolCandidates(ctx, addArgs)
+	}
+
 	// If feature SpiderSubnet is enabled, select IPPool candidates through the
 	// Pod annotations "ipam.spidernet.io/subnet" or "ipam.spidernet.io/subnets".
 	if i.config.EnableSpiderSubnet {
@@ -53,6 +61,15 @@ func (i *ipam) getPoolCandidates(ctx context.Context, addArgs *models.IpamAddArg
 		return ToBeAllocateds{t}, nil
 	}
 
+	// Select IPPool candidates through the Pod annotation "ipam.spidernet.io/ippool-group".
+	if anno, ok := pod.Annotations[constant.AnnoPodIPPoolGroup]; ok {
+		t, err := i.getPoolFromPodAnnoPoolGroup(ctx, anno, pod.Spec.NodeName, *addArgs.IfName, addArgs.CleanGateway)
+		if err != nil {
+			return nil, err
+		}
+		return ToBeAllocateds{t}, nil
+	}
+
 	// Select IPPool candidates through the Namespace annotations
 	// "ipam.spidernet.io/defaultv4ippool" and "ipam.spidernet.io/defaultv6ippool".
 	t, err := i.getPoolFromNS(ctx, pod.Namespace, *addArgs.IfName, addArgs.CleanGateway)
@@ -203,9 +220,15 @@ func (i *ipam) getPoolFromSubnetAnno(ctx context.Context, pod *corev1.Pod, nic s
 }
 
 // findAppAutoPool only fetches kubernetes basic controller(like Deployment, StatefulSet etc...) corresponding auto-created IPPools.
-func (i *ipam) findAppAutoPool(ctx context.Context, subnetName, ifName, labelIPPoolIPVersionValue string, desiredIPNumber int, podController types.PodTopController) (*spiderpoolv2beta1.SpiderIPPool, error) {
+func (i *ipam) findAppAutoPool(ctx context.Context, subnetName, ifName, labelIPPoolIPVersionValue string, desiredIPNumber int, podController types.PodTopController) (_ *spiderpoolv2beta1.SpiderIPPool, err error) {
 	log := logutils.FromContext(ctx)
 
+	ctx, span := tracing.Start(ctx, "ipam findAppAutoPool",
+		attribute.String("subnet", subnetName),
+		attribute.String("nic", ifName),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	var pool *spiderpoolv2beta1.SpiderIPPool
 	matchLabels := client.MatchingLabels{
 		constant.LabelIPPoolOwnerSpiderSubnet:         subnetName,
@@ -226,6 +249,7 @@ func (i *ipam) findAppAutoPool(ctx context.Context, subnetName, ifName, labelIPP
 		if len(poolList.Items) == 0 {
 			log.Sugar().Warnf("fetch SubnetIPPool %d times: no IPPool retrieved from SpiderSubnet '%s' with matchLabel '%v', wait for a second and get a retry",
 				j, subnetName, matchLabels)
+			span.AddEvent("wait for IPPool created", trace.WithAttributes(attribute.Int("times", j)))
 			time.Sleep(i.config.OperationGapDuration)
 		} else if len(poolList.Items) == 1 {
 			pool = poolList.Items[0].DeepCopy()
@@ -234,6 +258,7 @@ func (i *ipam) findAppAutoPool(ctx context.Context, subnetName, ifName, labelIPP
 			// we fetched Auto-created IPPool but it doesn't have any IPs, just wait for a while and let the IPPool informer to allocate IPs for it
 			if !isPoolIPsDesired(pool, desiredIPNumber) {
 				log.Sugar().Warnf("fetch SubnetIPPool %d times: retrieved IPPool '%s' but doesn't have the desiredIPNumber IPs, wait for a second and get a retry", j, pool.Name)
+				span.AddEvent("wait for IPPool scaled", trace.WithAttributes(attribute.Int("times", j)))
 				time.Sleep(i.config.OperationGapDuration)
 				continue
 			}
@@ -252,9 +277,15 @@ func (i *ipam) findAppAutoPool(ctx context.Context, subnetName, ifName, labelIPP
 
 // applyThirdControllerAutoPool will fetch or reconcile third-party controller corresponding auto-created IPPools,
 // and the kubernetes basic controller like Deployment,StatefulSet etc... We'll reconcile their auto-created IPPools in spiderpool-controller component.
-func (i *ipam) applyThirdControllerAutoPool(ctx context.Context, subnetName string, podController types.PodTopController, autoPoolProperty types.AutoPoolProperty) (*spiderpoolv2beta1.SpiderIPPool, error) {
+func (i *ipam) applyThirdControllerAutoPool(ctx context.Context, subnetName string, podController types.PodTopController, autoPoolProperty types.AutoPoolProperty) (_ *spiderpoolv2beta1.SpiderIPPool, err error) {
 	log := logutils.FromContext(ctx)
 
+	ctx, span := tracing.Start(ctx, "ipam applyThirdControllerAutoPool",
+		attribute.String("subnet", subnetName),
+		attribute.String("nic", autoPoolProperty.IfName),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	var pool *spiderpoolv2beta1.SpiderIPPool
 	matchLabels := client.MatchingLabels{
 		constant.LabelIPPoolOwnerSpiderSubnet:         subnetName,
@@ -291,6 +322,7 @@ func (i *ipam) applyThirdControllerAutoPool(ctx context.Context, subnetName stri
 		if nil != err {
 			if apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err) {
 				log.Sugar().Warnf("fetch SubnetIPPool %d times: apply auto-created IPPool conflict: %v", j, err)
+				span.AddEvent("conflict", trace.WithAttributes(attribute.Int("times", j)))
 				time.Sleep(i.config.OperationGapDuration)
 				continue
 			}
@@ -308,28 +340,20 @@ func getPoolFromPodAnnoPools(ctx context.Context, anno, nic string) (ToBeAllocat
 	logger := logutils.FromContext(ctx)
 	logger.Sugar().Infof("Use IPPools from Pod annotation '%s'", constant.AnnoPodIPPools)
 
-	var annoPodIPPools types.AnnoPodIPPoolsValue
 	errPrefix := fmt.Errorf("%w, invalid format of Pod annotation '%s'", constant.ErrWrongInput, constant.AnnoPodIPPools)
-	err := json.Unmarshal([]byte(anno), &annoPodIPPools)
+	annoPodIPPools, err := podmanager.ParsePodIPPoolsAnnotation(anno)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", errPrefix, err)
 	}
-	if len(annoPodIPPools) == 0 {
-		return nil, fmt.Errorf("%w: value requires at least one item", errPrefix)
-	}
 
-	nicSet := map[string]struct{}{}
+	var found bool
 	for _, v := range annoPodIPPools {
-		if v.NIC == "" {
-			return nil, fmt.Errorf("%w: interface must be specified", errPrefix)
-		}
-		if _, ok := nicSet[v.NIC]; ok {
-			return nil, fmt.Errorf("%w: duplicate interface %s", errPrefix, v.NIC)
+		if v.NIC == nic {
+			found = true
+			break
 		}
-		nicSet[v.NIC] = struct{}{}
 	}
-
-	if _, ok := nicSet[nic]; !ok {
+	if !found {
 		return nil, fmt.Errorf("%w: interfaces do not contain that requested by runtime", errPrefix)
 	}
 
@@ -361,9 +385,9 @@ func getPoolFromPodAnnoPool(ctx context.Context, anno, nic string, cleanGateway
 	logger := logutils.FromContext(ctx)
 	logger.Sugar().Infof("Use IPPools from Pod annotation '%s'", constant.AnnoPodIPPool)
 
-	var annoPodIPPool types.AnnoPodIPPoolValue
 	errPrefix := fmt.Errorf("%w, invalid format of Pod annotation '%s'", constant.ErrWrongInput, constant.AnnoPodIPPool)
-	if err := json.Unmarshal([]byte(anno), &annoPodIPPool); err != nil {
+	annoPodIPPool, err := podmanager.ParsePodIPPoolAnnotation(anno)
+	if err != nil {
 		return nil, fmt.Errorf("%w: %v", errPrefix, err)
 	}
 
@@ -387,6 +411,76 @@ func getPoolFromPodAnnoPool(ctx context.Context, anno, nic string, cleanGateway
 	return t, nil
 }
 
+func (i *ipam) getPoolFromPodAnnoPoolGroup(ctx context.Context, anno, nodeName, nic string, cleanGateway bool) (*ToBeAllocated, error) {
+	logger := logutils.FromContext(ctx)
+	logger.Sugar().Infof("Use IPPools from Pod annotation '%s'", constant.AnnoPodIPPoolGroup)
+
+	errPrefix := fmt.Errorf("%w, invalid format of Pod annotation '%s'", constant.ErrWrongInput, constant.AnnoPodIPPoolGroup)
+	group, err := podmanager.ParsePodIPPoolGroupAnnotation(anno)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errPrefix, err)
+	}
+
+	node, err := i.nodeManager.GetNodeByName(ctx, nodeName, constant.UseCache)
+	if err != nil {
+		return nil, err
+	}
+
+	ipPoolList, err := i.ipPoolManager.ListIPPools(
+		ctx,
+		constant.UseCache,
+		client.MatchingFields{"spec.poolGroup.name": group},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &ToBeAllocated{
+		NIC:          nic,
+		CleanGateway: cleanGateway,
+	}
+
+	var v4Pools, v6Pools []string
+	v4PToIPPool := PoolNameToIPPool{}
+	v6PToIPPool := PoolNameToIPPool{}
+	for _, ipPool := range ipPoolList.Items {
+		if !ippoolmanager.IsMatchPoolGroupTopology(&ipPool, node.Labels) {
+			continue
+		}
+
+		p := ipPool
+		if *ipPool.Spec.IPVersion == constant.IPv4 {
+			v4Pools = append(v4Pools, ipPool.Name)
+			v4PToIPPool[ipPool.Name] = &p
+		} else {
+			v6Pools = append(v6Pools, ipPool.Name)
+			v6PToIPPool[ipPool.Name] = &p
+		}
+	}
+
+	if len(v4Pools) == 0 && len(v6Pools) == 0 {
+		return nil, fmt.Errorf("%w, no member of IPPool group %s serves the topology of Node %s", constant.ErrNoAvailablePool, group, nodeName)
+	}
+	logger.Sugar().Debugf("IPPool group %s members for Node %s: IPv4 %v, IPv6 %v", group, nodeName, v4Pools, v6Pools)
+
+	if len(v4Pools) != 0 {
+		t.PoolCandidates = append(t.PoolCandidates, &PoolCandidate{
+			IPVersion: constant.IPv4,
+			Pools:     v4Pools,
+			PToIPPool: v4PToIPPool,
+		})
+	}
+	if len(v6Pools) != 0 {
+		t.PoolCandidates = append(t.PoolCandidates, &PoolCandidate{
+			IPVersion: constant.IPv6,
+			Pools:     v6Pools,
+			PToIPPool: v6PToIPPool,
+		})
+	}
+
+	return t, nil
+}
+
 func (i *ipam) getPoolFromNS(ctx context.Context, namespace, nic string, cleanGateway bool) (*ToBeAllocated, error) {
 	ns, err := i.nsManager.GetNamespaceByName(ctx, namespace, constant.UseCache)
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -20,15 +21,27 @@ import (
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
 	"github.com/spidernet-io/spiderpool/pkg/metric"
 	"github.com/spidernet-io/spiderpool/pkg/podmanager"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
 	"github.com/spidernet-io/spiderpool/pkg/types"
 	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
 	"github.com/spidernet-io/spiderpool/pkg/workloadendpointmanager"
 )
 
-func (i *ipam) Release(ctx context.Context, delArgs *models.IpamDelArgs) error {
+func (i *ipam) Release(ctx context.Context, delArgs *models.IpamDelArgs) (err error) {
 	logger := logutils.FromContext(ctx)
 	logger.Info("Start to release")
 
+	ctx, span := tracing.Start(ctx, "ipam Release",
+		attribute.String("pod", *delArgs.PodNamespace+"/"+*delArgs.PodName),
+		attribute.String("containerID", *delArgs.ContainerID),
+		attribute.String("nic", *delArgs.IfName),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	if isStandalone(*delArgs.PodName) {
+		return i.releaseStandalone(ctx, delArgs)
+	}
+
 	pod, err := i.podManager.GetPodByName(ctx, *delArgs.PodNamespace, *delArgs.PodName, constant.IgnoreCache)
 	if client.IgnoreNotFound(err) != nil {
 		return fmt.Errorf("failed to get Pod %s/%s: %v", *delArgs.PodNamespace, *delArgs.PodName, err)
@@ -119,7 +132,7 @@ func (i *ipam) releaseForAllNICs(ctx context.Context, uid, nic string, endpoint
 	}
 
 	logger.Sugar().Infof("Release IP allocation details: %v", allocation.IPs)
-	if err := i.release(ctx, allocation.UID, allocation.IPs); err != nil {
+	if err := i.release(ctx, endpoint, allocation.UID, allocation.IPs); err != nil {
 		return err
 	}
 
@@ -131,7 +144,7 @@ func (i *ipam) releaseForAllNICs(ctx context.Context, uid, nic string, endpoint
 	return nil
 }
 
-func (i *ipam) release(ctx context.Context, uid string, details []spiderpoolv2beta1.IPAllocationDetail) error {
+func (i *ipam) release(ctx context.Context, endpoint *spiderpoolv2beta1.SpiderEndpoint, uid string, details []spiderpoolv2beta1.IPAllocationDetail) error {
 	logger := logutils.FromContext(ctx)
 
 	pius := convert.GroupIPAllocationDetails(uid, details)
@@ -159,6 +172,7 @@ func (i *ipam) release(ctx context.Context, uid string, details []spiderpoolv2be
 				return
 			}
 			logger.Sugar().Infof("Succeed to release IP addresses %+v from IPPool %s", ipAndUIDs, poolName)
+			auditRelease(ctx, endpoint, poolName, ipAndUIDs, details)
 		}(p, ius)
 	}
 	wg.Wait()
@@ -0,0 +1,185 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/ippoolmanager"
+	"github.com/spidernet-io/spiderpool/pkg/namespacemanager"
+	"github.com/spidernet-io/spiderpool/pkg/nodemanager"
+	"github.com/spidernet-io/spiderpool/pkg/podmanager"
+	"github.com/spidernet-io/spiderpool/pkg/statefulsetmanager"
+	"github.com/spidernet-io/spiderpool/pkg/subnetmanager"
+	"github.com/spidernet-io/spiderpool/pkg/workloadendpointmanager"
+)
+
+// UnlimitedHeadroom is the headroom of the Node for the Pod whose IP
+// addresses do not depend on the Node, such as the ones from Subnets.
+const UnlimitedHeadroom int64 = -1
+
+// NodeChecker checks whether a Pod to be scheduled can get IP addresses on a
+// Node, without allocating any.
+type NodeChecker interface {
+	// CheckNode resolves the IPPool candidates of the Pod as if it was
+	// scheduled to the Node, with the same pool selection rules as CNI ADD.
+	// It fails if all IPPool candidates of any NIC and IP version are
+	// filtered out by the Node, or exhausted. Otherwise, it returns the
+	// headroom of the Node, the least number of free IP addresses among
+	// the IPPool candidates of all NICs and IP versions, which is
+	// UnlimitedHeadroom if the IP addresses of the Pod do not depend on
+	// the Node or are unknown before CNI ADD.
+	CheckNode(ctx context.Context, pod *corev1.Pod, nodeName string) (int64, error)
+}
+
+// NewNodeChecker returns the NodeChecker used by the scheduler extender,
+// which takes the same managers as NewIPAM.
+func NewNodeChecker(
+	config IPAMConfig,
+	ipPoolManager ippoolmanager.IPPoolManager,
+	endpointManager workloadendpointmanager.WorkloadEndpointManager,
+	nodeManager nodemanager.NodeManager,
+	nsManager namespacemanager.NamespaceManager,
+	podManager podmanager.PodManager,
+	stsManager statefulsetmanager.StatefulSetManager,
+	subnetManager subnetmanager.SubnetManager,
+) (NodeChecker, error) {
+	i, err := NewIPAM(config, ipPoolManager, endpointManager, nodeManager, nsManager, podManager, stsManager, subnetManager)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.(*ipam), nil
+}
+
+func (i *ipam) CheckNode(ctx context.Context, pod *corev1.Pod, nodeName string) (int64, error) {
+	// The Pod in the host network namespace takes no IP address from IPPools.
+	if pod.Spec.HostNetwork {
+		return UnlimitedHeadroom, nil
+	}
+
+	// The IPPool candidates from Subnets are created or scaled for the
+	// application on demand, they are not known until CNI ADD.
+	if i.config.EnableSpiderSubnet {
+		_, ok1 := pod.Annotations[constant.AnnoSpiderSubnets]
+		_, ok2 := pod.Annotations[constant.AnnoSpiderSubnet]
+		if ok1 || ok2 {
+			return UnlimitedHeadroom, nil
+		}
+	}
+
+	podTopController, err := i.podManager.GetPodTopController(ctx, pod)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the top controller of the Pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+
+	// The Pod of StatefulSet keeps its IP addresses wherever it is, unless
+	// they are from the IPPool group serving another topology.
+	if i.config.EnableStatefulSet && podTopController.APIVersion == appsv1.SchemeGroupVersion.String() && podTopController.Kind == constant.KindStatefulSet {
+		if _, ok := pod.Annotations[constant.AnnoPodIPPoolGroup]; !ok {
+			endpoint, err := i.endpointManager.GetEndpointByName(ctx, pod.Namespace, pod.Name, constant.UseCache)
+			if client.IgnoreNotFound(err) != nil {
+				return 0, fmt.Errorf("failed to get Endpoint %s/%s: %v", pod.Namespace, pod.Name, err)
+			}
+			if endpoint != nil && endpoint.Status.Current.UID != "" {
+				return UnlimitedHeadroom, nil
+			}
+		}
+	}
+
+	// The pool selection rules and affinities refer to the Node of the Pod.
+	scheduled := pod.DeepCopy()
+	scheduled.Spec.NodeName = nodeName
+
+	// Only the NICs named in the annotation "ipam.spidernet.io/ippools" are
+	// known before CNI ADD, the other Pods are checked on the default NIC.
+	nics := []string{constant.ClusterDefaultInterfaceName}
+	if anno, ok := pod.Annotations[constant.AnnoPodIPPools]; ok {
+		if ipPools, err := podmanager.ParsePodIPPoolsAnnotation(anno); err == nil {
+			nics = nics[:0]
+			for _, item := range ipPools {
+				nics = append(nics, item.NIC)
+			}
+		}
+	}
+
+	var tt ToBeAllocateds
+	for _, nic := range nics {
+		// The default IPPools of the CNI network configuration are unknown
+		// here, as the configuration is only passed to CNI ADD.
+		addArgs := &models.IpamAddArgs{IfName: &nic}
+		candidates, err := i.getPoolCandidates(ctx, addArgs, scheduled, podTopController)
+		if err != nil {
+			// The Pod without pool selection rules may take the default
+			// IPPools of the CNI network configuration.
+			if errors.Is(err, constant.ErrNoAvailablePool) && !hasPoolAnnotation(pod) {
+				return UnlimitedHeadroom, nil
+			}
+			return 0, err
+		}
+		for _, t := range candidates {
+			if t.NIC == nic {
+				tt = append(tt, t)
+			}
+		}
+	}
+	if err := i.config.checkIPVersionEnable(ctx, tt); err != nil {
+		return 0, err
+	}
+
+	headroom := UnlimitedHeadroom
+	for _, t := range tt {
+		if err := i.precheckPoolCandidates(ctx, t); err != nil {
+			return 0, err
+		}
+		if err := i.filterPoolCandidates(ctx, t, scheduled, podTopController); err != nil {
+			return 0, err
+		}
+
+		for _, c := range t.PoolCandidates {
+			var free int64
+			for _, pool := range c.Pools {
+				n, err := ippoolmanager.FreeIPCount(c.PToIPPool[pool])
+				if err != nil {
+					return 0, err
+				}
+				free += n
+			}
+			if free == 0 {
+				return 0, fmt.Errorf("%w, all IPv%d IPPools %v of %s are exhausted", constant.ErrIPUsedOut, c.IPVersion, c.Pools, t.NIC)
+			}
+			if headroom == UnlimitedHeadroom || free < headroom {
+				headroom = free
+			}
+		}
+	}
+
+	return headroom, nil
+}
+
+// hasPoolAnnotation reports whether the Pod selects its IPPools or Subnets
+// through the Pod annotations.
+func hasPoolAnnotation(pod *corev1.Pod) bool {
+	for _, anno := range []string{
+		constant.AnnoPodIPPools,
+		constant.AnnoPodIPPool,
+		constant.AnnoPodIPPoolGroup,
+		constant.AnnoSpiderSubnets,
+		constant.AnnoSpiderSubnet,
+	} {
+		if _, ok := pod.Annotations[anno]; ok {
+			return true
+		}
+	}
+
+	return false
+}
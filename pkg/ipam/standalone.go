@@ -0,0 +1,201 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+	"github.com/spidernet-io/spiderpool/pkg/types"
+)
+
+// StandaloneEndpointPrefix is the name prefix of the Endpoints of the
+// standalone containers, which are started by the container runtimes
+// without Kubernetes, such as nerdctl and Podman.
+const StandaloneEndpointPrefix = "standalone-"
+
+// isStandalone reports whether the CNI request comes from a standalone
+// container, whose CNI_ARGS carry no Pod.
+func isStandalone(podName string) bool {
+	return podName == ""
+}
+
+// standalonePod makes up a Pod for the standalone container, so that it goes
+// through the same pool selection, IP allocation and Endpoint recording as a
+// Pod. The container ID acts as the UID of the Pod.
+func (i *ipam) standalonePod(containerID, netns, owner string) (*corev1.Pod, error) {
+	name := StandaloneEndpointPrefix + containerID
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+		return nil, fmt.Errorf("%w, invalid container ID %s: %v", constant.ErrWrongInput, containerID, strings.Join(errs, ", "))
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   i.config.StandaloneNamespace,
+			UID:         apitypes.UID(containerID),
+			Annotations: map[string]string{constant.AnnoStandaloneNetns: netns},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: i.config.NodeName,
+		},
+	}
+
+	if owner != "" {
+		if errs := validation.IsValidLabelValue(owner); len(errs) != 0 {
+			return nil, fmt.Errorf("%w, invalid owner %s: %v", constant.ErrWrongInput, owner, strings.Join(errs, ", "))
+		}
+		pod.Labels = map[string]string{constant.LabelStandaloneOwner: owner}
+	}
+
+	return pod, nil
+}
+
+func (i *ipam) allocateStandalone(ctx context.Context, addArgs *models.IpamAddArgs) (*models.IpamAddResponse, error) {
+	logger := logutils.FromContext(ctx)
+
+	if !i.config.EnableStandalone {
+		return nil, fmt.Errorf("%w, no Pod specified in CNI_ARGS, but standalone IPAM is disabled", constant.ErrWrongInput)
+	}
+
+	pod, err := i.standalonePod(*addArgs.ContainerID, *addArgs.NetNamespace, addArgs.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := i.endpointManager.GetEndpointByName(ctx, pod.Namespace, pod.Name, constant.UseCache)
+	if client.IgnoreNotFound(err) != nil {
+		return nil, fmt.Errorf("failed to get Endpoint %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+
+	nicToStaticIPs, err := getStaticIPs(addArgs, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Try to retrieve the existing IP allocation of standalone container")
+	addResp, err := i.retrieveExistingIPAllocation(ctx, string(pod.UID), *addArgs.IfName, endpoint, nicToStaticIPs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the existing IP allocation: %w", err)
+	}
+	if addResp != nil {
+		return addResp, nil
+	}
+
+	podController := types.PodTopController{
+		AppNamespacedName: types.AppNamespacedName{
+			Kind:      constant.KindStandalone,
+			Namespace: pod.Namespace,
+			Name:      addArgs.Owner,
+		},
+		UID: pod.UID,
+	}
+
+	logger.Info("Allocate IP addresses to standalone container")
+	addResp, err = i.allocateInStandardMode(ctx, addArgs, pod, endpoint, podController)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate IP addresses to standalone container: %w", err)
+	}
+
+	return addResp, nil
+}
+
+// getStandalonePoolCandidates selects the IPPool candidates of the standalone
+// container, which has no Pod or Namespace annotations, through the CNI
+// network configuration and then the cluster default IPPools.
+func (i *ipam) getStandalonePoolCandidates(ctx context.Context, addArgs *models.IpamAddArgs) (ToBeAllocateds, error) {
+	if t := getPoolFromNetConf(ctx, *addArgs.IfName, addArgs.DefaultIPV4IPPool, addArgs.DefaultIPV6IPPool, addArgs.CleanGateway); t != nil {
+		return ToBeAllocateds{t}, nil
+	}
+
+	t, err := i.getClusterDefaultPools(ctx, *addArgs.IfName, addArgs.CleanGateway)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToBeAllocateds{t}, nil
+}
+
+func (i *ipam) releaseStandalone(ctx context.Context, delArgs *models.IpamDelArgs) error {
+	logger := logutils.FromContext(ctx)
+
+	// CNI DEL should succeed if there is nothing to release.
+	if !i.config.EnableStandalone {
+		logger.Info("No Pod specified in CNI_ARGS and standalone IPAM is disabled, ignore release")
+		return nil
+	}
+
+	name := StandaloneEndpointPrefix + *delArgs.ContainerID
+	endpoint, err := i.endpointManager.GetEndpointByName(ctx, i.config.StandaloneNamespace, name, constant.IgnoreCache)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Endpoint of standalone container does not exist, ignore release")
+			return nil
+		}
+		return fmt.Errorf("failed to get Endpoint %s/%s: %v", i.config.StandaloneNamespace, name, err)
+	}
+
+	if err := i.releaseStandaloneNICs(ctx, *delArgs.IfName, endpoint); err != nil {
+		return err
+	}
+	logger.Info("Succeed to release")
+
+	return nil
+}
+
+// releaseStandaloneNICs releases the IP allocation of the NIC of the
+// standalone container, or of all its NICs if nic is empty. Unlike the Pods,
+// a standalone container may be detached from one network and keep the
+// others. The Endpoint is deleted once nothing is left, because no Pod owns
+// it.
+func (i *ipam) releaseStandaloneNICs(ctx context.Context, nic string, endpoint *spiderpoolv2beta1.SpiderEndpoint) error {
+	logger := logutils.FromContext(ctx)
+
+	var details []spiderpoolv2beta1.IPAllocationDetail
+	remaining := 0
+	for _, d := range endpoint.Status.Current.IPs {
+		if nic == "" || d.NIC == nic {
+			details = append(details, d)
+			continue
+		}
+		remaining++
+	}
+
+	if len(details) != 0 {
+		logger.Sugar().Infof("Release IP allocation details of standalone container: %v", details)
+		if err := i.release(ctx, endpoint, endpoint.Status.Current.UID, details); err != nil {
+			return err
+		}
+	}
+
+	if remaining != 0 {
+		if err := i.endpointManager.RemoveNICIPAllocation(ctx, nic, endpoint); err != nil {
+			return fmt.Errorf("failed to remove the IP allocation of NIC %s from Endpoint: %v", nic, err)
+		}
+		return nil
+	}
+
+	i.failure.rmFailureIPs(endpoint.Status.Current.UID)
+	logger.Info("Delete Endpoint of standalone container")
+	if err := i.endpointManager.RemoveFinalizer(ctx, endpoint); err != nil {
+		return fmt.Errorf("failed to clean Endpoint: %v", err)
+	}
+	if err := i.endpointManager.DeleteEndpoint(ctx, endpoint); err != nil {
+		return fmt.Errorf("failed to delete Endpoint: %v", err)
+	}
+
+	return nil
+}
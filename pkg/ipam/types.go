@@ -5,6 +5,7 @@ package ipam
 
 import (
 	"fmt"
+	"net"
 
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
 	"github.com/spidernet-io/spiderpool/pkg/types"
@@ -31,8 +32,11 @@ func (tt *ToBeAllocateds) Candidates() []*PoolCandidate {
 }
 
 type ToBeAllocated struct {
-	NIC            string
-	CleanGateway   bool
+	NIC          string
+	CleanGateway bool
+	// MAC is the MAC address of the NIC, which is only known for the NIC
+	// of the CNI request.
+	MAC            net.HardwareAddr
 	PoolCandidates []*PoolCandidate
 }
 
@@ -53,6 +57,9 @@ type PoolCandidate struct {
 	IPVersion types.IPVersion
 	Pools     []string
 	PToIPPool PoolNameToIPPool
+	// IP is the static IP address to allocate, the Pools are narrowed down
+	// to the ones containing it.
+	IP net.IP
 }
 
 func (c *PoolCandidate) String() string {
@@ -5,7 +5,6 @@ package ipam
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net"
 
@@ -18,8 +17,10 @@ import (
 	subnetmanagercontrollers "github.com/spidernet-io/spiderpool/pkg/applicationcontroller/applicationinformers"
 	"github.com/spidernet-io/spiderpool/pkg/constant"
 	spiderpoolip "github.com/spidernet-io/spiderpool/pkg/ip"
+	"github.com/spidernet-io/spiderpool/pkg/ippoolmanager"
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
+	"github.com/spidernet-io/spiderpool/pkg/podmanager"
 	"github.com/spidernet-io/spiderpool/pkg/types"
 	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
 )
@@ -30,20 +31,127 @@ func getCustomRoutes(pod *corev1.Pod) ([]*models.Route, error) {
 		return nil, nil
 	}
 
-	var annoPodRoutes types.AnnoPodRoutesValue
-	errPrefix := fmt.Errorf("%w, invalid format of Pod annotation '%s'", constant.ErrWrongInput, constant.AnnoPodRoutes)
-	err := json.Unmarshal([]byte(anno), &annoPodRoutes)
+	annoPodRoutes, err := podmanager.ParsePodRoutesAnnotation(anno)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", errPrefix, err)
+		return nil, fmt.Errorf("%w, invalid format of Pod annotation '%s': %v", constant.ErrWrongInput, constant.AnnoPodRoutes, err)
 	}
 
+	// the advanced routes are installed by coordinator
+	var routes types.AnnoPodRoutesValue
 	for _, route := range annoPodRoutes {
-		if err := spiderpoolip.IsRouteWithoutIPVersion(route.Dst, route.Gw); err != nil {
-			return nil, fmt.Errorf("%w: %v", errPrefix, err)
+		if !route.IsAdvanced() {
+			routes = append(routes, route)
 		}
 	}
 
-	return convert.ConvertAnnoPodRoutesToOAIRoutes(annoPodRoutes), nil
+	return convert.ConvertAnnoPodRoutesToOAIRoutes(routes), nil
+}
+
+// getStaticIPs returns the static IP addresses requested for the NICs. The
+// ones passed by the CNI plugin for the NIC being set up take precedence over
+// the Pod annotation.
+func getStaticIPs(addArgs *models.IpamAddArgs, pod *corev1.Pod) (map[string][]net.IP, error) {
+	nicToIPs := map[string][]net.IP{}
+	if anno, ok := pod.Annotations[constant.AnnoPodStaticIPs]; ok {
+		staticIPs, err := podmanager.ParsePodStaticIPsAnnotation(anno)
+		if err != nil {
+			return nil, fmt.Errorf("%w, invalid format of Pod annotation '%s': %v", constant.ErrWrongInput, constant.AnnoPodStaticIPs, err)
+		}
+
+		for _, item := range staticIPs {
+			var ips []net.IP
+			for _, ip := range []string{item.IPv4, item.IPv6} {
+				if ip != "" {
+					ips = append(ips, net.ParseIP(ip))
+				}
+			}
+			nicToIPs[item.NIC] = ips
+		}
+	}
+
+	if len(addArgs.Ips) != 0 {
+		var ips []net.IP
+		for _, s := range addArgs.Ips {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("%w, invalid static IP '%s' of NIC %s", constant.ErrWrongInput, s, *addArgs.IfName)
+			}
+			ips = append(ips, ip)
+		}
+		nicToIPs[*addArgs.IfName] = ips
+	}
+
+	return nicToIPs, nil
+}
+
+// checkRetrievedStaticIPs checks that the IP addresses allocated to the NICs
+// are the static IP addresses requested for them.
+func checkRetrievedStaticIPs(details []spiderpoolv2beta1.IPAllocationDetail, nicToIPs map[string][]net.IP) error {
+	for _, d := range details {
+		for _, ip := range nicToIPs[d.NIC] {
+			version, allocated := constant.IPv4, d.IPv4
+			if ip.To4() == nil {
+				version, allocated = constant.IPv6, d.IPv6
+			}
+			if allocated == nil {
+				return fmt.Errorf("%w, static IP %s is requested for NIC %s, but no IPv%d IP address is allocated to it", constant.ErrWrongInput, ip, d.NIC, version)
+			}
+
+			allocatedIP, _, err := net.ParseCIDR(*allocated)
+			if err != nil || !allocatedIP.Equal(ip) {
+				return fmt.Errorf("%w, static IP %s is requested for NIC %s, but IP %s is allocated to it", constant.ErrWrongInput, ip, d.NIC, *allocated)
+			}
+		}
+	}
+
+	return nil
+}
+
+// attachmentOf returns the attachment of the interface being set up.
+func attachmentOf(addArgs *models.IpamAddArgs) types.Attachment {
+	return types.Attachment{
+		ContainerID: *addArgs.ContainerID,
+		NIC:         *addArgs.IfName,
+		Network:     addArgs.Network,
+	}
+}
+
+// isEUI64Candidate reports whether the candidate derives the EUI-64 IPv6
+// address from the MAC address of the NIC.
+func isEUI64Candidate(c *PoolCandidate) bool {
+	if c.IP != nil {
+		return false
+	}
+
+	for _, pool := range c.PToIPPool {
+		if ippoolmanager.IsDerivedIPPool(pool) && *pool.Spec.IPv6Derivation == constant.IPv6DerivationEUI64 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allocatedMAC returns the MAC address allocated along with the IP addresses
+// of the NIC, in which the one of the IPv4 address takes precedence as the
+// CNI result does. It returns nil if no MAC address is allocated.
+func allocatedMAC(results []*types.AllocationResult, nic string) net.HardwareAddr {
+	var mac net.HardwareAddr
+	for _, res := range results {
+		if res.IP == nil || res.IP.Nic == nil || *res.IP.Nic != nic || res.IP.Mac == "" {
+			continue
+		}
+
+		hwAddr, err := net.ParseMAC(res.IP.Mac)
+		if err != nil {
+			continue
+		}
+		if mac == nil || (res.IP.Version != nil && *res.IP.Version == constant.IPv4) {
+			mac = hwAddr
+		}
+	}
+
+	return mac
 }
 
 func groupCustomRoutes(ctx context.Context, customRoutes []*models.Route, results []*types.AllocationResult) error {
@@ -52,14 +160,25 @@ func groupCustomRoutes(ctx context.Context, customRoutes []*models.Route, result
 	}
 
 	for _, res := range results {
-		_, ipNet, err := net.ParseCIDR(*res.IP.Address)
+		ip, ipNet, err := net.ParseCIDR(*res.IP.Address)
 		if err != nil {
 			return err
 		}
 
 		for i := 0; i < len(customRoutes); i++ {
 			route := customRoutes[i]
-			if ipNet.Contains(net.ParseIP(*route.Gw)) {
+			gw := net.ParseIP(*route.Gw)
+
+			var matched bool
+			if *route.IfName != "" {
+				// the route specifying the interface only matches the IP
+				// address of the same version
+				matched = *route.IfName == *res.IP.Nic && (gw.To4() != nil) == (ip.To4() != nil)
+			} else {
+				matched = ipNet.Contains(gw)
+			}
+
+			if matched {
 				route.IfName = res.IP.Nic
 				res.Routes = append(res.Routes, route)
 
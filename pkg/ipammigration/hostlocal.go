@@ -0,0 +1,243 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipammigration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+)
+
+const (
+	DefaultCNIConfDir       = "/etc/cni/net.d"
+	DefaultHostLocalDataDir = "/var/lib/cni/networks"
+
+	// hostLocalUsePodCIDR is the subnet of host-local replaced with the
+	// PodCIDRs of the node.
+	hostLocalUsePodCIDR = "usePodCidr"
+)
+
+// HostLocalOptions specifies where to read the IP ranges and allocations of
+// host-local on a node.
+type HostLocalOptions struct {
+	// ConfDir is the directory of CNI configuration files.
+	ConfDir string
+	// DataDir overrides the 'dataDir' of the host-local configurations.
+	DataDir string
+	// Node is the node where host-local runs.
+	Node string
+	// PodCIDRs of the node, which replace the subnet 'usePodCidr'.
+	PodCIDRs []string
+}
+
+type hostLocalConf struct {
+	Name string `json:"name"`
+}
+
+type hostLocalIPAM struct {
+	Type    string             `json:"type"`
+	DataDir string             `json:"dataDir,omitempty"`
+	Ranges  [][]hostLocalRange `json:"ranges,omitempty"`
+	hostLocalRange
+}
+
+type hostLocalRange struct {
+	Subnet     string `json:"subnet,omitempty"`
+	RangeStart string `json:"rangeStart,omitempty"`
+	RangeEnd   string `json:"rangeEnd,omitempty"`
+	Gateway    string `json:"gateway,omitempty"`
+}
+
+// ReadHostLocal reads the IP ranges of host-local from the CNI configuration
+// files, and the allocations of them from the files named after the allocated
+// IP addresses under '<dataDir>/<network name>'. The ranges are local to the
+// node.
+func ReadHostLocal(opts HostLocalOptions) ([]Range, error) {
+	if opts.ConfDir == "" {
+		opts.ConfDir = DefaultCNIConfDir
+	}
+	if opts.Node == "" {
+		return nil, fmt.Errorf("node %w", constant.ErrMissingRequiredParam)
+	}
+
+	files, err := os.ReadDir(opts.ConfDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []Range
+	for _, f := range files {
+		switch filepath.Ext(f.Name()) {
+		case ".conf", ".conflist", ".json":
+		default:
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(opts.ConfDir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var conf hostLocalConf
+		if err := json.Unmarshal(data, &conf); err != nil {
+			return nil, fmt.Errorf("%w, failed to parse CNI configuration file %s: %v", constant.ErrWrongInput, f.Name(), err)
+		}
+		ipams, err := parseIPAMs(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w, failed to parse CNI configuration file %s: %v", constant.ErrWrongInput, f.Name(), err)
+		}
+
+		for _, raw := range ipams {
+			var ipam hostLocalIPAM
+			if err := json.Unmarshal(raw, &ipam); err != nil || ipam.Type != SourceHostLocal {
+				continue
+			}
+
+			rs, err := readHostLocalNetwork(conf.Name, ipam, opts)
+			if err != nil {
+				return nil, fmt.Errorf("invalid host-local configuration in file %s: %w", f.Name(), err)
+			}
+			ranges = append(ranges, rs...)
+		}
+	}
+
+	return ranges, nil
+}
+
+func readHostLocalNetwork(network string, ipam hostLocalIPAM, opts HostLocalOptions) ([]Range, error) {
+	var hrs []hostLocalRange
+	for _, set := range ipam.Ranges {
+		hrs = append(hrs, set...)
+	}
+	if ipam.Subnet != "" {
+		hrs = append(hrs, ipam.hostLocalRange)
+	}
+
+	var ranges []Range
+	for _, hr := range hrs {
+		subnets := []string{hr.Subnet}
+		if hr.Subnet == hostLocalUsePodCIDR {
+			subnets = opts.PodCIDRs
+		}
+
+		for _, s := range subnets {
+			r, err := newHostLocalRange(hr, s)
+			if err != nil {
+				return nil, err
+			}
+			r.Name = poolName(SourceHostLocal, network, opts.Node, r.Subnet.String())
+			r.Node = opts.Node
+			ranges = append(ranges, *r)
+		}
+	}
+
+	dataDir := ipam.DataDir
+	if opts.DataDir != "" {
+		dataDir = opts.DataDir
+	} else if dataDir == "" {
+		dataDir = DefaultHostLocalDataDir
+	}
+
+	allocations, err := readHostLocalAllocations(filepath.Join(dataDir, network))
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range allocations {
+		for i := range ranges {
+			if ranges[i].Subnet.Contains(a.IP) {
+				ranges[i].Allocations = append(ranges[i].Allocations, a)
+				break
+			}
+		}
+	}
+
+	return ranges, nil
+}
+
+// newHostLocalRange returns the range with the defaults of host-local: the
+// range starts after the network address and the gateway defaults to the
+// first address of the subnet.
+func newHostLocalRange(hr hostLocalRange, subnet string) (*Range, error) {
+	prefix, err := netip.ParsePrefix(subnet)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", constant.ErrWrongInput, err)
+	}
+
+	r := &Range{Source: SourceHostLocal, Subnet: prefix.Masked()}
+	r.Start, r.End = defaultBounds(r.Subnet)
+	if !r.Subnet.Addr().Is4() {
+		r.Start = r.Subnet.Addr().Next()
+	}
+	if hr.RangeStart != "" {
+		if r.Start, err = netip.ParseAddr(hr.RangeStart); err != nil {
+			return nil, fmt.Errorf("%w, %v", constant.ErrWrongInput, err)
+		}
+	}
+	if hr.RangeEnd != "" {
+		if r.End, err = netip.ParseAddr(hr.RangeEnd); err != nil {
+			return nil, fmt.Errorf("%w, %v", constant.ErrWrongInput, err)
+		}
+	}
+	if !r.Subnet.Contains(r.Start) || !r.Subnet.Contains(r.End) || r.End.Less(r.Start) {
+		return nil, fmt.Errorf("%w, range %s-%s is not in subnet %s", constant.ErrWrongInput, r.Start, r.End, r.Subnet)
+	}
+
+	r.Gateway = r.Subnet.Addr().Next()
+	if hr.Gateway != "" {
+		if r.Gateway, err = netip.ParseAddr(hr.Gateway); err != nil {
+			return nil, fmt.Errorf("%w, invalid gateway %s: %v", constant.ErrWrongInput, hr.Gateway, err)
+		}
+	}
+
+	return r, nil
+}
+
+// readHostLocalAllocations reads the files named after the allocated IP
+// addresses, whose content is the container ID and the interface name
+// separated by a line break.
+func readHostLocalAllocations(dir string) ([]Allocation, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var allocations []Allocation
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		ip, err := netip.ParseAddr(f.Name())
+		if err != nil {
+			// last_reserved_ip.* and lock
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		lines := strings.Fields(string(data))
+		allocation := Allocation{IP: ip}
+		if len(lines) > 0 {
+			allocation.ContainerID = lines[0]
+		}
+		if len(lines) > 1 {
+			allocation.NIC = lines[1]
+		}
+		allocations = append(allocations, allocation)
+	}
+
+	sortAllocations(allocations)
+
+	return allocations, nil
+}
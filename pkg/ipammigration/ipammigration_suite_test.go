@@ -0,0 +1,42 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipammigration_test
+
+import (
+	"testing"
+
+	netv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+)
+
+var scheme *runtime.Scheme
+
+func TestIPAMMigration(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "IPAMMigration Suite", Label("ipammigration", "unitest"))
+}
+
+var _ = BeforeSuite(func() {
+	scheme = runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+	err = spiderpoolv2beta1.AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+	err = netv1.AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	// Whereabouts CRDs
+	gv := schema.GroupVersion{Group: "whereabouts.cni.cncf.io", Version: "v1alpha1"}
+	for _, kind := range []string{"IPPool", "OverlappingRangeIPReservation"} {
+		scheme.AddKnownTypeWithName(gv.WithKind(kind), &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(gv.WithKind(kind+"List"), &unstructured.UnstructuredList{})
+	}
+})
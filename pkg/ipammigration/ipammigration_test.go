@@ -0,0 +1,260 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipammigration_test
+
+import (
+	"context"
+	"net/netip"
+	"os"
+	"path/filepath"
+
+	netv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/ipammigration"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
+)
+
+func newWhereaboutsObject(kind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetAPIVersion("whereabouts.cni.cncf.io/v1alpha1")
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+
+	return obj
+}
+
+func newPod(name, node, ip string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID(name + "-uid"),
+		},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+		},
+		Status: corev1.PodStatus{
+			Phase:  corev1.PodRunning,
+			PodIPs: []corev1.PodIP{{IP: ip}},
+		},
+	}
+}
+
+var _ = Describe("IPAMMigration", Label("ipammigration_test"), func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+	})
+
+	Describe("ReadWhereabouts", func() {
+		It("reads ranges and allocations", func() {
+			nad := &netv1.NetworkAttachmentDefinition{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "macvlan"},
+				Spec: netv1.NetworkAttachmentDefinitionSpec{
+					Config: `{"cniVersion":"0.3.1","plugins":[{"type":"macvlan","ipam":{"type":"whereabouts","range":"10.6.0.0/24","range_start":"10.6.0.10","exclude":["10.6.0.16/30"],"gateway":"10.6.0.1"}}]}`,
+				},
+			}
+			ipPool := newWhereaboutsObject("IPPool", "kube-system", "10.6.0.0-24", map[string]interface{}{
+				"range": "10.6.0.0/24",
+				"allocations": map[string]interface{}{
+					"10": map[string]interface{}{"id": "c1", "podref": "default/pod1"},
+					"11": map[string]interface{}{"id": "c2", "podref": "default/gone"},
+				},
+			})
+			reservation := newWhereaboutsObject("OverlappingRangeIPReservation", "kube-system", "10.6.0.12", map[string]interface{}{
+				"containerid": "c3",
+				"podref":      "default/pod2",
+			})
+
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nad, ipPool, reservation).Build()
+			ranges, err := ipammigration.ReadWhereabouts(ctx, c)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ranges).To(HaveLen(1))
+
+			r := ranges[0]
+			Expect(r.Name).To(Equal("whereabouts-10-6-0-0-24"))
+			Expect(r.Subnet.String()).To(Equal("10.6.0.0/24"))
+			Expect(r.IPs()).To(Equal([]string{"10.6.0.10-10.6.0.254"}))
+			Expect(r.ExcludeIPs()).To(Equal([]string{"10.6.0.16-10.6.0.19"}))
+			Expect(r.Gateway.String()).To(Equal("10.6.0.1"))
+			Expect(r.Allocations).To(HaveLen(3))
+			Expect(r.Allocations[0].IP.String()).To(Equal("10.6.0.10"))
+			Expect(r.Allocations[0].PodName).To(Equal("pod1"))
+			Expect(r.Allocations[2].IP.String()).To(Equal("10.6.0.12"))
+			Expect(r.Allocations[2].PodName).To(Equal("pod2"))
+		})
+
+		It("ignores other IPAM plugins", func() {
+			nad := &netv1.NetworkAttachmentDefinition{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "macvlan"},
+				Spec: netv1.NetworkAttachmentDefinitionSpec{
+					Config: `{"cniVersion":"0.3.1","type":"macvlan","ipam":{"type":"spiderpool"}}`,
+				},
+			}
+
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nad).Build()
+			ranges, err := ipammigration.ReadWhereabouts(ctx, c)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ranges).To(BeEmpty())
+		})
+	})
+
+	Describe("ReadHostLocal", func() {
+		It("reads ranges and allocations", func() {
+			confDir := GinkgoT().TempDir()
+			dataDir := GinkgoT().TempDir()
+
+			conf := `{"cniVersion":"0.3.1","name":"mynet","type":"bridge","ipam":{"type":"host-local","ranges":[[{"subnet":"usePodCidr"}],[{"subnet":"fd00::/120","rangeStart":"fd00::10"}]]}}`
+			Expect(os.WriteFile(filepath.Join(confDir, "10-mynet.conf"), []byte(conf), 0600)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(dataDir, "mynet"), 0700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dataDir, "mynet", "10.244.1.5"), []byte("c1\r\neth0"), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dataDir, "mynet", "fd00::10"), []byte("c1\r\neth0"), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dataDir, "mynet", "last_reserved_ip.0"), []byte("10.244.1.5"), 0600)).To(Succeed())
+
+			ranges, err := ipammigration.ReadHostLocal(ipammigration.HostLocalOptions{
+				ConfDir:  confDir,
+				DataDir:  dataDir,
+				Node:     "node1",
+				PodCIDRs: []string{"10.244.1.0/24"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ranges).To(HaveLen(2))
+
+			Expect(ranges[0].Name).To(Equal("host-local-mynet-node1-10-244-1-0-24"))
+			Expect(ranges[0].IPs()).To(Equal([]string{"10.244.1.1-10.244.1.254"}))
+			Expect(ranges[0].ExcludeIPs()).To(Equal([]string{"10.244.1.1"}))
+			Expect(ranges[0].Node).To(Equal("node1"))
+			Expect(ranges[0].Allocations).To(HaveLen(1))
+			Expect(ranges[0].Allocations[0].ContainerID).To(Equal("c1"))
+			Expect(ranges[0].Allocations[0].NIC).To(Equal("eth0"))
+
+			Expect(ranges[1].IPs()).To(Equal([]string{"fd00::10-fd00::ff"}))
+			Expect(ranges[1].Allocations).To(HaveLen(1))
+		})
+
+		It("requires the node", func() {
+			_, err := ipammigration.ReadHostLocal(ipammigration.HostLocalOptions{ConfDir: GinkgoT().TempDir()})
+			Expect(err).To(MatchError(constant.ErrMissingRequiredParam))
+		})
+	})
+
+	Describe("Migrate", func() {
+		var c client.Client
+		var ranges []ipammigration.Range
+
+		BeforeEach(func() {
+			c = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(newPod("pod1", "node1", "10.244.1.5"), newPod("pod2", "node2", "10.244.1.6")).
+				Build()
+
+			ranges = []ipammigration.Range{{
+				Name:    "host-local-mynet-node1-10-244-1-0-24",
+				Source:  ipammigration.SourceHostLocal,
+				Subnet:  netip.MustParsePrefix("10.244.1.0/24"),
+				Start:   netip.MustParseAddr("10.244.1.1"),
+				End:     netip.MustParseAddr("10.244.1.254"),
+				Gateway: netip.MustParseAddr("10.244.1.1"),
+				Node:    "node1",
+				Allocations: []ipammigration.Allocation{
+					{IP: netip.MustParseAddr("10.244.1.5"), ContainerID: "c1", NIC: "eth0"},
+					{IP: netip.MustParseAddr("10.244.1.6"), ContainerID: "c2", NIC: "eth0"},
+				},
+			}}
+		})
+
+		It("creates IPPools, allocation records and Endpoints", func() {
+			changes, err := ipammigration.Migrate(ctx, c, ranges, ipammigration.Options{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(ConsistOf(
+				ipammigration.Change{Kind: constant.KindSpiderIPPool, Name: ranges[0].Name, Action: ipammigration.ActionCreate},
+				ipammigration.Change{Kind: "IP", Name: ranges[0].Name + "/10.244.1.5", Action: ipammigration.ActionCreate, Reason: "default/pod1"},
+				// The Pod using the same IP address runs on another node.
+				ipammigration.Change{Kind: "IP", Name: ranges[0].Name + "/10.244.1.6", Action: ipammigration.ActionSkip, Reason: "no running Pod uses the IP address of container c2"},
+				ipammigration.Change{Kind: constant.KindSpiderEndpoint, Name: "default/pod1", Action: ipammigration.ActionCreate},
+			))
+
+			var ipPool spiderpoolv2beta1.SpiderIPPool
+			Expect(c.Get(ctx, types.NamespacedName{Name: ranges[0].Name}, &ipPool)).To(Succeed())
+			Expect(ipPool.Spec.ExcludeIPs).To(Equal([]string{"10.244.1.1"}))
+			Expect(ipPool.Spec.NodeAffinity.MatchLabels).To(HaveKeyWithValue(corev1.LabelHostname, "node1"))
+
+			records, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(Equal(spiderpoolv2beta1.PoolIPAllocations{
+				"10.244.1.5": {NIC: "eth0", NamespacedName: "default/pod1", PodUID: "pod1-uid"},
+			}))
+
+			var endpoint spiderpoolv2beta1.SpiderEndpoint
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "pod1"}, &endpoint)).To(Succeed())
+			Expect(endpoint.Status.Current.UID).To(Equal("pod1-uid"))
+			Expect(endpoint.Status.Current.IPs).To(HaveLen(1))
+			Expect(*endpoint.Status.Current.IPs[0].IPv4).To(Equal("10.244.1.5/24"))
+			Expect(endpoint.Finalizers).To(ContainElement(constant.SpiderFinalizer))
+			Expect(endpoint.OwnerReferences).To(HaveLen(1))
+
+			By("running again")
+			changes, err = ipammigration.Migrate(ctx, c, ranges, ipammigration.Options{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(ContainElements(
+				ipammigration.Change{Kind: constant.KindSpiderIPPool, Name: ranges[0].Name, Action: ipammigration.ActionExists},
+				ipammigration.Change{Kind: "IP", Name: ranges[0].Name + "/10.244.1.5", Action: ipammigration.ActionExists, Reason: "default/pod1"},
+				ipammigration.Change{Kind: constant.KindSpiderEndpoint, Name: "default/pod1", Action: ipammigration.ActionExists},
+			))
+		})
+
+		It("reports the IP address recorded for another Pod", func() {
+			_, err := ipammigration.Migrate(ctx, c, ranges, ipammigration.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.Delete(ctx, newPod("pod1", "node1", "10.244.1.5"))).To(Succeed())
+			Expect(c.Create(ctx, newPod("pod3", "node1", "10.244.1.5"))).To(Succeed())
+
+			changes, err := ipammigration.Migrate(ctx, c, ranges, ipammigration.Options{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(ContainElement(ipammigration.Change{
+				Kind:   "IP",
+				Name:   ranges[0].Name + "/10.244.1.5",
+				Action: ipammigration.ActionConflict,
+				Reason: "recorded for Pod default/pod1 (pod1-uid)",
+			}))
+		})
+
+		It("creates Subnets as well", func() {
+			changes, err := ipammigration.Migrate(ctx, c, ranges, ipammigration.Options{CreateSubnets: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(ContainElement(ipammigration.Change{Kind: constant.KindSpiderSubnet, Name: ranges[0].Name, Action: ipammigration.ActionCreate}))
+
+			var subnet spiderpoolv2beta1.SpiderSubnet
+			Expect(c.Get(ctx, types.NamespacedName{Name: ranges[0].Name}, &subnet)).To(Succeed())
+			Expect(subnet.Spec.IPs).To(Equal([]string{"10.244.1.1-10.244.1.254"}))
+		})
+
+		It("changes nothing in dry-run mode", func() {
+			changes, err := ipammigration.Migrate(ctx, c, ranges, ipammigration.Options{DryRun: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(HaveLen(4))
+
+			var ipPoolList spiderpoolv2beta1.SpiderIPPoolList
+			Expect(c.List(ctx, &ipPoolList)).To(Succeed())
+			Expect(ipPoolList.Items).To(BeEmpty())
+
+			var endpointList spiderpoolv2beta1.SpiderEndpointList
+			Expect(c.List(ctx, &endpointList)).To(Succeed())
+			Expect(endpointList.Items).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,525 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipammigration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/netip"
+	"sort"
+
+	netv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/ippoolmanager"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/podmanager"
+	"github.com/spidernet-io/spiderpool/pkg/types"
+	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
+	"github.com/spidernet-io/spiderpool/pkg/utils/retry"
+)
+
+// defaultNIC is the interface of the IP addresses whose interface is
+// neither recorded by the IPAM plugin nor found in the Pod.
+const defaultNIC = "eth0"
+
+type Action string
+
+const (
+	// ActionCreate means the resource or the IP allocation record is created.
+	ActionCreate Action = "create"
+	// ActionUpdate means the IP allocation details are added to the existing
+	// SpiderEndpoint.
+	ActionUpdate Action = "update"
+	// ActionExists means the resource or the IP allocation record already
+	// exists, which happens when the migration is run again.
+	ActionExists Action = "exists"
+	// ActionConflict means the IP address is recorded for another Pod, it is
+	// left untouched.
+	ActionConflict Action = "conflict"
+	// ActionSkip means the IP address is not in use by a running Pod.
+	ActionSkip Action = "skip"
+)
+
+// Change describes what the migration does.
+type Change struct {
+	Kind   string
+	Name   string
+	Action Action
+	Reason string
+}
+
+type Options struct {
+	// DryRun only reports the changes.
+	DryRun bool
+	// CreateSubnets creates a SpiderSubnet for each range as well, which
+	// requires the SpiderSubnet feature.
+	CreateSubnets bool
+}
+
+// Migrate creates a SpiderIPPool, and optionally a SpiderSubnet, for each
+// range, then records the IP addresses in use by running Pods in the
+// SpiderIPPools and SpiderEndpoints, so that the next CNI DEL and ADD of the
+// Pods are handled by Spiderpool. The IP addresses not in use by running
+// Pods are skipped. It is safe to run again, the existing resources and
+// records are kept.
+func Migrate(ctx context.Context, c client.Client, ranges []Range, opts Options) ([]Change, error) {
+	store, err := ippoolmanager.NewAllocationStore(c, c)
+	if err != nil {
+		return nil, err
+	}
+	podManager, err := podmanager.NewPodManager(c, c)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &migrator{
+		client:     c,
+		store:      store,
+		podManager: podManager,
+		dryRun:     opts.DryRun,
+	}
+
+	pods, err := m.indexPods(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	podResults := map[apitypes.NamespacedName][]*types.AllocationResult{}
+	for i := range ranges {
+		r := &ranges[i]
+		if opts.CreateSubnets {
+			if err := m.ensureSubnet(ctx, r); err != nil {
+				return m.changes, err
+			}
+		}
+
+		ipPool, err := m.ensureIPPool(ctx, r)
+		if err != nil {
+			return m.changes, err
+		}
+
+		for _, a := range r.Allocations {
+			name := ipPool.Name + "/" + a.IP.String()
+			pod, nic, reason := pods.lookup(r, a)
+			if pod == nil {
+				m.record(Change{Kind: "IP", Name: name, Action: ActionSkip, Reason: reason})
+				continue
+			}
+
+			allocation := spiderpoolv2beta1.PoolIPAllocation{
+				NIC:            nic,
+				NamespacedName: pod.Namespace + "/" + pod.Name,
+				PodUID:         string(pod.UID),
+			}
+			if err := m.allocate(ctx, ipPool.Name, a.IP.String(), allocation); err != nil {
+				return m.changes, fmt.Errorf("failed to record IP address %s in IPPool %s: %w", a.IP, ipPool.Name, err)
+			}
+
+			key := client.ObjectKeyFromObject(pod)
+			podResults[key] = append(podResults[key], &types.AllocationResult{
+				IP:     convert.GenIPConfigResult(net.IP(a.IP.AsSlice()), nic, ipPool),
+				Routes: convert.ConvertSpecRoutesToOAIRoutes(nic, ipPool.Spec.Routes),
+			})
+		}
+	}
+
+	keys := make([]apitypes.NamespacedName, 0, len(podResults))
+	for key := range podResults {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	for _, key := range keys {
+		if err := m.ensureEndpoint(ctx, pods.byName[key], podResults[key]); err != nil {
+			return m.changes, fmt.Errorf("failed to record the IP addresses of Pod %s in SpiderEndpoint: %w", key, err)
+		}
+	}
+
+	return m.changes, nil
+}
+
+type migrator struct {
+	client     client.Client
+	store      ippoolmanager.AllocationStore
+	podManager podmanager.PodManager
+	dryRun     bool
+	changes    []Change
+}
+
+func (m *migrator) record(change Change) {
+	m.changes = append(m.changes, change)
+}
+
+func newIPPool(r *Range) *spiderpoolv2beta1.SpiderIPPool {
+	ipPool := &spiderpoolv2beta1.SpiderIPPool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: r.Name,
+		},
+		Spec: spiderpoolv2beta1.IPPoolSpec{
+			IPVersion:  pointer.Int64(r.IPVersion()),
+			Subnet:     r.Subnet.String(),
+			IPs:        r.IPs(),
+			ExcludeIPs: r.ExcludeIPs(),
+			Vlan:       pointer.Int64(0),
+		},
+	}
+	if r.Gateway.IsValid() {
+		ipPool.Spec.Gateway = pointer.String(r.Gateway.String())
+	}
+	if r.Node != "" {
+		ipPool.Spec.NodeAffinity = &metav1.LabelSelector{
+			MatchLabels: map[string]string{corev1.LabelHostname: r.Node},
+		}
+	}
+
+	return ipPool
+}
+
+func (m *migrator) ensureSubnet(ctx context.Context, r *Range) error {
+	subnet := &spiderpoolv2beta1.SpiderSubnet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: r.Name,
+		},
+		Spec: spiderpoolv2beta1.SubnetSpec{
+			IPVersion:  pointer.Int64(r.IPVersion()),
+			Subnet:     r.Subnet.String(),
+			IPs:        r.IPs(),
+			ExcludeIPs: r.ExcludeIPs(),
+		},
+	}
+	if r.Gateway.IsValid() {
+		subnet.Spec.Gateway = pointer.String(r.Gateway.String())
+	}
+
+	var live spiderpoolv2beta1.SpiderSubnet
+	err := m.client.Get(ctx, client.ObjectKeyFromObject(subnet), &live)
+	if err == nil {
+		if live.Spec.Subnet != subnet.Spec.Subnet {
+			return fmt.Errorf("%w, Subnet %s already exists with subnet %s rather than %s", constant.ErrWrongInput, live.Name, live.Spec.Subnet, subnet.Spec.Subnet)
+		}
+		m.record(Change{Kind: constant.KindSpiderSubnet, Name: subnet.Name, Action: ActionExists})
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get Subnet %s: %w", subnet.Name, err)
+	}
+
+	m.record(Change{Kind: constant.KindSpiderSubnet, Name: subnet.Name, Action: ActionCreate})
+	if m.dryRun {
+		return nil
+	}
+
+	if err := m.client.Create(ctx, subnet); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create Subnet %s: %w", subnet.Name, err)
+	}
+
+	return nil
+}
+
+// ensureIPPool creates the SpiderIPPool of the range if it does not exist,
+// and returns the one in the cluster. An existing SpiderIPPool is reused if
+// its subnet is the same.
+func (m *migrator) ensureIPPool(ctx context.Context, r *Range) (*spiderpoolv2beta1.SpiderIPPool, error) {
+	ipPool := newIPPool(r)
+
+	var live spiderpoolv2beta1.SpiderIPPool
+	err := m.client.Get(ctx, client.ObjectKeyFromObject(ipPool), &live)
+	if err == nil {
+		if live.Spec.Subnet != ipPool.Spec.Subnet {
+			return nil, fmt.Errorf("%w, IPPool %s already exists with subnet %s rather than %s", constant.ErrWrongInput, live.Name, live.Spec.Subnet, ipPool.Spec.Subnet)
+		}
+		if live.Spec.Vlan == nil {
+			live.Spec.Vlan = pointer.Int64(0)
+		}
+		m.record(Change{Kind: constant.KindSpiderIPPool, Name: live.Name, Action: ActionExists})
+		return &live, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get IPPool %s: %w", ipPool.Name, err)
+	}
+
+	m.record(Change{Kind: constant.KindSpiderIPPool, Name: ipPool.Name, Action: ActionCreate})
+	if m.dryRun {
+		return ipPool, nil
+	}
+
+	if err := m.client.Create(ctx, ipPool); err != nil {
+		return nil, fmt.Errorf("failed to create IPPool %s: %w", ipPool.Name, err)
+	}
+
+	return ipPool, nil
+}
+
+// allocate records the IP allocation in the SpiderIPPool unless the IP
+// address is already recorded.
+func (m *migrator) allocate(ctx context.Context, poolName, ip string, allocation spiderpoolv2beta1.PoolIPAllocation) error {
+	name := poolName + "/" + ip
+	retriable := func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}
+
+	var change Change
+	err := retry.OnErrorWithContext(ctx, retry.DefaultRetry, retriable, func(ctx context.Context) error {
+		var ipPool spiderpoolv2beta1.SpiderIPPool
+		if err := m.client.Get(ctx, apitypes.NamespacedName{Name: poolName}, &ipPool); err != nil {
+			if apierrors.IsNotFound(err) && m.dryRun {
+				change = Change{Kind: "IP", Name: name, Action: ActionCreate, Reason: allocation.NamespacedName}
+				return nil
+			}
+			return err
+		}
+
+		existing, ok, err := m.getAllocation(ctx, &ipPool, ip)
+		if err != nil {
+			return err
+		}
+		if ok {
+			change = compareAllocation(name, existing, allocation)
+			return nil
+		}
+
+		change = Change{Kind: "IP", Name: name, Action: ActionCreate, Reason: allocation.NamespacedName}
+		if m.dryRun {
+			return nil
+		}
+
+		return m.store.Allocate(ctx, &ipPool, ip, allocation)
+	})
+	if err != nil {
+		if err == wait.ErrWaitTimeout {
+			err = fmt.Errorf("%w (%d times)", constant.ErrRetriesExhausted, retry.DefaultRetry.Steps)
+		}
+		return err
+	}
+
+	m.record(change)
+	return nil
+}
+
+func (m *migrator) getAllocation(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool, ip string) (spiderpoolv2beta1.PoolIPAllocation, bool, error) {
+	if ippoolmanager.IsIPClaimStorage(ipPool) {
+		var claim spiderpoolv2beta1.SpiderIPClaim
+		err := m.client.Get(ctx, apitypes.NamespacedName{Name: ippoolmanager.IPClaimName(ipPool.Name, ip)}, &claim)
+		if err == nil {
+			return spiderpoolv2beta1.PoolIPAllocation{
+				NIC:            claim.Spec.NIC,
+				NamespacedName: claim.Spec.NamespacedName,
+				PodUID:         claim.Spec.PodUID,
+				MAC:            claim.Spec.MAC,
+			}, true, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return spiderpoolv2beta1.PoolIPAllocation{}, false, err
+		}
+	}
+
+	records, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
+	if err != nil {
+		return spiderpoolv2beta1.PoolIPAllocation{}, false, err
+	}
+	existing, ok := records[ip]
+
+	return existing, ok, nil
+}
+
+func compareAllocation(name string, existing, allocation spiderpoolv2beta1.PoolIPAllocation) Change {
+	if existing.PodUID == allocation.PodUID {
+		return Change{Kind: "IP", Name: name, Action: ActionExists, Reason: allocation.NamespacedName}
+	}
+
+	return Change{
+		Kind:   "IP",
+		Name:   name,
+		Action: ActionConflict,
+		Reason: fmt.Sprintf("recorded for Pod %s (%s)", existing.NamespacedName, existing.PodUID),
+	}
+}
+
+// ensureEndpoint creates the SpiderEndpoint of the Pod in the same way as
+// IPAM, or adds the IP allocation details of the interfaces not recorded yet
+// to the existing one.
+func (m *migrator) ensureEndpoint(ctx context.Context, pod *corev1.Pod, results []*types.AllocationResult) error {
+	name := pod.Namespace + "/" + pod.Name
+	details := convert.ConvertResultsToIPDetails(results)
+	sort.Slice(details, func(i, j int) bool { return details[i].NIC < details[j].NIC })
+
+	var endpoint spiderpoolv2beta1.SpiderEndpoint
+	err := m.client.Get(ctx, client.ObjectKeyFromObject(pod), &endpoint)
+	if err == nil {
+		if endpoint.Status.Current.UID != string(pod.UID) {
+			m.record(Change{
+				Kind:   constant.KindSpiderEndpoint,
+				Name:   name,
+				Action: ActionConflict,
+				Reason: fmt.Sprintf("recorded for Pod UID %s", endpoint.Status.Current.UID),
+			})
+			return nil
+		}
+
+		recorded := map[string]struct{}{}
+		for _, d := range endpoint.Status.Current.IPs {
+			recorded[d.NIC] = struct{}{}
+		}
+		added := false
+		for _, d := range details {
+			if _, ok := recorded[d.NIC]; !ok {
+				endpoint.Status.Current.IPs = append(endpoint.Status.Current.IPs, d)
+				added = true
+			}
+		}
+		if !added {
+			m.record(Change{Kind: constant.KindSpiderEndpoint, Name: name, Action: ActionExists})
+			return nil
+		}
+
+		m.record(Change{Kind: constant.KindSpiderEndpoint, Name: name, Action: ActionUpdate})
+		if m.dryRun {
+			return nil
+		}
+		return m.client.Update(ctx, &endpoint)
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	m.record(Change{Kind: constant.KindSpiderEndpoint, Name: name, Action: ActionCreate})
+	if m.dryRun {
+		return nil
+	}
+
+	podController, err := m.podManager.GetPodTopController(ctx, pod)
+	if err != nil {
+		return err
+	}
+
+	endpoint = spiderpoolv2beta1.SpiderEndpoint{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+		Status: spiderpoolv2beta1.WorkloadEndpointStatus{
+			Current: spiderpoolv2beta1.PodIPAllocation{
+				UID:  string(pod.UID),
+				Node: pod.Spec.NodeName,
+				IPs:  details,
+			},
+			OwnerControllerType: podController.Kind,
+			OwnerControllerName: podController.Name,
+		},
+	}
+	if podController.Kind != constant.KindStatefulSet {
+		if err := controllerutil.SetOwnerReference(pod, &endpoint, m.client.Scheme()); err != nil {
+			return err
+		}
+	}
+	controllerutil.AddFinalizer(&endpoint, constant.SpiderFinalizer)
+
+	return m.client.Create(ctx, &endpoint)
+}
+
+type podNIC struct {
+	pod *corev1.Pod
+	nic string
+}
+
+type podIndex struct {
+	byName map[apitypes.NamespacedName]*corev1.Pod
+	// IP address -> Pods and interfaces, an IP address of different Pods
+	// on different nodes is possible with the node-local ranges.
+	byIP map[netip.Addr][]podNIC
+}
+
+// indexPods indexes the running Pods by the name and the IP addresses in
+// 'status.podIPs' and the Multus network status annotation.
+func (m *migrator) indexPods(ctx context.Context) (*podIndex, error) {
+	var podList corev1.PodList
+	if err := m.client.List(ctx, &podList); err != nil {
+		return nil, fmt.Errorf("failed to list Pods: %w", err)
+	}
+
+	index := &podIndex{
+		byName: map[apitypes.NamespacedName]*corev1.Pod{},
+		byIP:   map[netip.Addr][]podNIC{},
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Spec.HostNetwork || pod.DeletionTimestamp != nil ||
+			pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		index.byName[client.ObjectKeyFromObject(pod)] = pod
+
+		var networkStatus []netv1.NetworkStatus
+		if v, ok := pod.Annotations[netv1.NetworkStatusAnnot]; ok {
+			_ = json.Unmarshal([]byte(v), &networkStatus)
+		}
+		indexed := map[netip.Addr]struct{}{}
+		for _, s := range networkStatus {
+			for _, v := range s.IPs {
+				if ip, err := netip.ParseAddr(v); err == nil && s.Interface != "" {
+					index.byIP[ip] = append(index.byIP[ip], podNIC{pod: pod, nic: s.Interface})
+					indexed[ip] = struct{}{}
+				}
+			}
+		}
+		for _, podIP := range pod.Status.PodIPs {
+			if ip, err := netip.ParseAddr(podIP.IP); err == nil {
+				if _, ok := indexed[ip]; !ok {
+					index.byIP[ip] = append(index.byIP[ip], podNIC{pod: pod})
+				}
+			}
+		}
+	}
+
+	return index, nil
+}
+
+// lookup returns the running Pod using the allocated IP address and its
+// interface, or the reason why none is found.
+func (pi *podIndex) lookup(r *Range, a Allocation) (*corev1.Pod, string, string) {
+	nic := a.NIC
+	if a.PodName != "" {
+		pod, ok := pi.byName[apitypes.NamespacedName{Namespace: a.PodNamespace, Name: a.PodName}]
+		if !ok {
+			return nil, "", fmt.Sprintf("Pod %s/%s is not running", a.PodNamespace, a.PodName)
+		}
+		if nic == "" {
+			for _, pn := range pi.byIP[a.IP] {
+				if pn.pod == pod {
+					nic = pn.nic
+				}
+			}
+		}
+		if nic == "" {
+			nic = defaultNIC
+		}
+		return pod, nic, ""
+	}
+
+	for _, pn := range pi.byIP[a.IP] {
+		if r.Node != "" && pn.pod.Spec.NodeName != r.Node {
+			continue
+		}
+		if nic == "" {
+			nic = pn.nic
+		}
+		if nic == "" {
+			nic = defaultNIC
+		}
+		return pn.pod, nic, ""
+	}
+
+	return nil, "", fmt.Sprintf("no running Pod uses the IP address of container %s", a.ContainerID)
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipammigration
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+)
+
+const (
+	SourceWhereabouts = "whereabouts"
+	SourceHostLocal   = "host-local"
+)
+
+// Range is an IP range managed by the IPAM plugin migrated from, it becomes a
+// SpiderIPPool.
+type Range struct {
+	// Name is the name of the SpiderIPPool.
+	Name   string
+	Source string
+	Subnet netip.Prefix
+	Start  netip.Addr
+	End    netip.Addr
+	// Exclude is the IP ranges in the form of 'start-end' or single IP
+	// addresses which are never allocated.
+	Exclude []string
+	// Gateway is invalid if the range has no gateway.
+	Gateway netip.Addr
+	// Node is set if the range is local to the node, like the ones of
+	// host-local.
+	Node        string
+	Allocations []Allocation
+}
+
+// Allocation is an IP address allocated by the IPAM plugin migrated from.
+type Allocation struct {
+	IP          netip.Addr
+	ContainerID string
+	NIC         string
+	// PodNamespace and PodName are empty if the IPAM plugin does not record
+	// the Pod, the Pod is looked up by the IP address then.
+	PodNamespace string
+	PodName      string
+}
+
+// IPs returns the IP range in the form of SpiderIPPool 'spec.ips'.
+func (r *Range) IPs() []string {
+	if r.Start == r.End {
+		return []string{r.Start.String()}
+	}
+
+	return []string{r.Start.String() + "-" + r.End.String()}
+}
+
+// ExcludeIPs returns the excluded IP addresses in the form of SpiderIPPool
+// 'spec.excludeIPs', the gateway is included if it is in the range.
+func (r *Range) ExcludeIPs() []string {
+	excludeIPs := append([]string{}, r.Exclude...)
+	if r.Gateway.IsValid() && r.Start.Compare(r.Gateway) <= 0 && r.Gateway.Compare(r.End) <= 0 {
+		excludeIPs = append(excludeIPs, r.Gateway.String())
+	}
+
+	return excludeIPs
+}
+
+// IPVersion returns the IP version of the range.
+func (r *Range) IPVersion() int64 {
+	if r.Subnet.Addr().Is4() {
+		return constant.IPv4
+	}
+
+	return constant.IPv6
+}
+
+// defaultBounds returns the first and last usable IP addresses of the
+// subnet, the network and broadcast addresses of IPv4 are not usable.
+func defaultBounds(subnet netip.Prefix) (netip.Addr, netip.Addr) {
+	first := subnet.Masked().Addr()
+	last := lastAddr(subnet)
+	if first.Is4() && subnet.Bits() < 31 {
+		first = first.Next()
+		last = last.Prev()
+	}
+
+	return first, last
+}
+
+func lastAddr(subnet netip.Prefix) netip.Addr {
+	addr := subnet.Masked().Addr()
+	bytes := addr.AsSlice()
+	for i := subnet.Bits(); i < len(bytes)*8; i++ {
+		bytes[i/8] |= 1 << (7 - uint(i%8))
+	}
+	last, _ := netip.AddrFromSlice(bytes)
+
+	return last
+}
+
+// addOffset returns the IP address at the offset from the base address.
+func addOffset(base netip.Addr, offset *big.Int) (netip.Addr, error) {
+	n := new(big.Int).SetBytes(base.AsSlice())
+	n.Add(n, offset)
+
+	bytes := n.Bytes()
+	size := len(base.AsSlice())
+	if n.Sign() < 0 || len(bytes) > size {
+		return netip.Addr{}, fmt.Errorf("%w, offset %s of %s overflows", constant.ErrWrongInput, offset, base)
+	}
+
+	buf := make([]byte, size)
+	copy(buf[size-len(bytes):], bytes)
+	addr, _ := netip.AddrFromSlice(buf)
+
+	return addr, nil
+}
+
+// prefixToRange returns the IP range of the prefix in the form of 'start-end'.
+func prefixToRange(prefix netip.Prefix) string {
+	first := prefix.Masked().Addr()
+	last := lastAddr(prefix)
+	if first == last {
+		return first.String()
+	}
+
+	return first.String() + "-" + last.String()
+}
+
+// poolName makes a valid SpiderIPPool name from the parts.
+func poolName(parts ...string) string {
+	name := strings.ToLower(strings.Join(parts, "-"))
+	name = strings.NewReplacer(".", "-", ":", "-", "/", "-", "_", "-").Replace(name)
+	for strings.Contains(name, "--") {
+		name = strings.ReplaceAll(name, "--", "-")
+	}
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		name = name[:validation.DNS1123SubdomainMaxLength]
+	}
+
+	return strings.Trim(name, "-")
+}
@@ -0,0 +1,359 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipammigration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"sort"
+	"strings"
+
+	netv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+)
+
+var (
+	whereaboutsIPPoolListGVK = schema.GroupVersionKind{
+		Group:   "whereabouts.cni.cncf.io",
+		Version: "v1alpha1",
+		Kind:    "IPPoolList",
+	}
+	whereaboutsReservationListGVK = schema.GroupVersionKind{
+		Group:   "whereabouts.cni.cncf.io",
+		Version: "v1alpha1",
+		Kind:    "OverlappingRangeIPReservationList",
+	}
+)
+
+// whereaboutsIPAM is the IPAM configuration of Whereabouts in the CNI
+// configuration of a NetworkAttachmentDefinition.
+type whereaboutsIPAM struct {
+	Type        string             `json:"type"`
+	NetworkName string             `json:"network_name,omitempty"`
+	Gateway     string             `json:"gateway,omitempty"`
+	IPRanges    []whereaboutsRange `json:"ipRanges,omitempty"`
+	whereaboutsRange
+}
+
+type whereaboutsRange struct {
+	Range      string   `json:"range,omitempty"`
+	RangeStart string   `json:"range_start,omitempty"`
+	RangeEnd   string   `json:"range_end,omitempty"`
+	Exclude    []string `json:"exclude,omitempty"`
+}
+
+// whereaboutsIPPoolSpec is the spec of Whereabouts IPPool CR, the
+// allocations are keyed by the offset of the IP address in the range.
+type whereaboutsIPPoolSpec struct {
+	Range       string `json:"range"`
+	Allocations map[string]struct {
+		ID     string `json:"id"`
+		PodRef string `json:"podref,omitempty"`
+		IfName string `json:"ifname,omitempty"`
+	} `json:"allocations"`
+}
+
+type whereaboutsReservationSpec struct {
+	ContainerID string `json:"containerid"`
+	PodRef      string `json:"podref,omitempty"`
+	IfName      string `json:"ifname,omitempty"`
+}
+
+// ReadWhereabouts reads the IP ranges of Whereabouts from the CNI
+// configurations of NetworkAttachmentDefinitions, and the allocations of them
+// from Whereabouts IPPool and OverlappingRangeIPReservation CRs. The
+// NetworkAttachmentDefinitions sharing a range result in one Range.
+func ReadWhereabouts(ctx context.Context, reader client.Reader) ([]Range, error) {
+	var nadList netv1.NetworkAttachmentDefinitionList
+	if err := reader.List(ctx, &nadList); err != nil {
+		return nil, fmt.Errorf("failed to list NetworkAttachmentDefinitions: %w", err)
+	}
+
+	// Whereabouts IPPool name -> Range
+	ranges := map[string]*Range{}
+	for _, nad := range nadList.Items {
+		if nad.Spec.Config == "" {
+			continue
+		}
+
+		ipams, err := parseIPAMs([]byte(nad.Spec.Config))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CNI configuration of NetworkAttachmentDefinition %s/%s: %w", nad.Namespace, nad.Name, err)
+		}
+
+		for _, raw := range ipams {
+			var ipam whereaboutsIPAM
+			if err := json.Unmarshal(raw, &ipam); err != nil || ipam.Type != SourceWhereabouts {
+				continue
+			}
+
+			wrs := ipam.IPRanges
+			if ipam.Range != "" {
+				wrs = append([]whereaboutsRange{ipam.whereaboutsRange}, wrs...)
+			}
+			for _, wr := range wrs {
+				r, err := newWhereaboutsRange(wr, ipam.Gateway)
+				if err != nil {
+					return nil, fmt.Errorf("invalid Whereabouts range of NetworkAttachmentDefinition %s/%s: %w", nad.Namespace, nad.Name, err)
+				}
+
+				name := whereaboutsIPPoolName(ipam.NetworkName, r.Subnet)
+				if _, ok := ranges[name]; !ok {
+					r.Name = poolName(SourceWhereabouts, name)
+					ranges[name] = r
+				}
+			}
+		}
+	}
+
+	if len(ranges) == 0 {
+		return nil, nil
+	}
+
+	if err := readWhereaboutsIPPools(ctx, reader, ranges); err != nil {
+		return nil, err
+	}
+	if err := readWhereaboutsReservations(ctx, reader, ranges); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(ranges))
+	for name := range ranges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]Range, 0, len(names))
+	for _, name := range names {
+		result = append(result, *ranges[name])
+	}
+
+	return result, nil
+}
+
+func newWhereaboutsRange(wr whereaboutsRange, gateway string) (*Range, error) {
+	cidr := wr.Range
+	start, end := wr.RangeStart, wr.RangeEnd
+
+	// The range may be in the form of '192.168.2.225-192.168.2.230/28'.
+	dashed := false
+	if i := strings.Index(cidr, "-"); i > 0 {
+		start = cidr[:i]
+		cidr = cidr[i+1:]
+		dashed = true
+	}
+
+	subnet, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", constant.ErrWrongInput, err)
+	}
+	if dashed {
+		end = subnet.Addr().String()
+	}
+
+	r := &Range{Source: SourceWhereabouts, Subnet: subnet.Masked()}
+	r.Start, r.End = defaultBounds(subnet)
+	if start != "" {
+		if r.Start, err = netip.ParseAddr(start); err != nil {
+			return nil, fmt.Errorf("%w, %v", constant.ErrWrongInput, err)
+		}
+	} else if subnet.Addr() != subnet.Masked().Addr() {
+		r.Start = subnet.Addr()
+	}
+	if end != "" {
+		if r.End, err = netip.ParseAddr(end); err != nil {
+			return nil, fmt.Errorf("%w, %v", constant.ErrWrongInput, err)
+		}
+	}
+	if !subnet.Contains(r.Start) || !subnet.Contains(r.End) || r.End.Less(r.Start) {
+		return nil, fmt.Errorf("%w, range %s-%s is not in subnet %s", constant.ErrWrongInput, r.Start, r.End, r.Subnet)
+	}
+
+	for _, e := range wr.Exclude {
+		prefix, err := netip.ParsePrefix(e)
+		if err != nil {
+			return nil, fmt.Errorf("%w, invalid exclude %s: %v", constant.ErrWrongInput, e, err)
+		}
+		r.Exclude = append(r.Exclude, prefixToRange(prefix))
+	}
+
+	if gateway != "" {
+		if r.Gateway, err = netip.ParseAddr(gateway); err != nil {
+			return nil, fmt.Errorf("%w, invalid gateway %s: %v", constant.ErrWrongInput, gateway, err)
+		}
+	}
+
+	return r, nil
+}
+
+// whereaboutsIPPoolName returns the name of Whereabouts IPPool CR of the
+// range, in the same way as Whereabouts.
+func whereaboutsIPPoolName(networkName string, subnet netip.Prefix) string {
+	name := strings.NewReplacer(":", "-", "/", "-").Replace(subnet.String())
+	if networkName != "" {
+		name = networkName + "-" + name
+	}
+
+	return name
+}
+
+func readWhereaboutsIPPools(ctx context.Context, reader client.Reader, ranges map[string]*Range) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(whereaboutsIPPoolListGVK)
+	if err := reader.List(ctx, list); err != nil {
+		return fmt.Errorf("failed to list Whereabouts IPPools: %w", err)
+	}
+
+	for _, item := range list.Items {
+		r, ok := ranges[item.GetName()]
+		if !ok {
+			continue
+		}
+
+		var spec whereaboutsIPPoolSpec
+		if err := unstructuredSpec(&item, &spec); err != nil {
+			return fmt.Errorf("failed to parse Whereabouts IPPool %s: %w", item.GetName(), err)
+		}
+
+		for offset, a := range spec.Allocations {
+			n, ok := new(big.Int).SetString(offset, 10)
+			if !ok {
+				return fmt.Errorf("%w, invalid allocation offset %s of Whereabouts IPPool %s", constant.ErrWrongInput, offset, item.GetName())
+			}
+			ip, err := addOffset(r.Subnet.Addr(), n)
+			if err != nil {
+				return fmt.Errorf("invalid allocation of Whereabouts IPPool %s: %w", item.GetName(), err)
+			}
+
+			allocation := Allocation{IP: ip, ContainerID: a.ID, NIC: a.IfName}
+			allocation.PodNamespace, allocation.PodName = splitPodRef(a.PodRef)
+			r.Allocations = append(r.Allocations, allocation)
+		}
+		sortAllocations(r.Allocations)
+	}
+
+	return nil
+}
+
+// readWhereaboutsReservations adds the allocations only recorded by
+// OverlappingRangeIPReservations, which are named after the IP addresses.
+func readWhereaboutsReservations(ctx context.Context, reader client.Reader, ranges map[string]*Range) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(whereaboutsReservationListGVK)
+	if err := reader.List(ctx, list); err != nil {
+		return fmt.Errorf("failed to list Whereabouts OverlappingRangeIPReservations: %w", err)
+	}
+
+	for _, item := range list.Items {
+		ip, ok := parseReservationName(item.GetName())
+		if !ok {
+			continue
+		}
+
+		var spec whereaboutsReservationSpec
+		if err := unstructuredSpec(&item, &spec); err != nil {
+			return fmt.Errorf("failed to parse Whereabouts OverlappingRangeIPReservation %s: %w", item.GetName(), err)
+		}
+
+		for _, r := range ranges {
+			if !r.Subnet.Contains(ip) || hasAllocation(r.Allocations, ip) {
+				continue
+			}
+
+			allocation := Allocation{IP: ip, ContainerID: spec.ContainerID, NIC: spec.IfName}
+			allocation.PodNamespace, allocation.PodName = splitPodRef(spec.PodRef)
+			r.Allocations = append(r.Allocations, allocation)
+			sortAllocations(r.Allocations)
+			break
+		}
+	}
+
+	return nil
+}
+
+// parseReservationName parses the IP address from the name of
+// OverlappingRangeIPReservation, the colons of IPv6 addresses are replaced by
+// hyphens.
+func parseReservationName(name string) (netip.Addr, bool) {
+	if ip, err := netip.ParseAddr(name); err == nil {
+		return ip, true
+	}
+	if ip, err := netip.ParseAddr(strings.ReplaceAll(name, "-", ":")); err == nil {
+		return ip, true
+	}
+
+	return netip.Addr{}, false
+}
+
+func unstructuredSpec(obj *unstructured.Unstructured, spec interface{}) error {
+	raw, ok := obj.Object["spec"]
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, spec)
+}
+
+func splitPodRef(podRef string) (string, string) {
+	namespace, name, ok := strings.Cut(podRef, "/")
+	if !ok {
+		return "", ""
+	}
+
+	return namespace, name
+}
+
+func hasAllocation(allocations []Allocation, ip netip.Addr) bool {
+	for _, a := range allocations {
+		if a.IP == ip {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortAllocations(allocations []Allocation) {
+	sort.Slice(allocations, func(i, j int) bool {
+		return allocations[i].IP.Less(allocations[j].IP)
+	})
+}
+
+// parseIPAMs returns the raw IPAM configurations of the plugins in the CNI
+// configuration, which is either a single plugin or a plugin list.
+func parseIPAMs(config []byte) ([]json.RawMessage, error) {
+	var conf struct {
+		IPAM    json.RawMessage `json:"ipam,omitempty"`
+		Plugins []struct {
+			IPAM json.RawMessage `json:"ipam,omitempty"`
+		} `json:"plugins,omitempty"`
+	}
+	if err := json.Unmarshal(config, &conf); err != nil {
+		return nil, err
+	}
+
+	var ipams []json.RawMessage
+	if len(conf.IPAM) != 0 {
+		ipams = append(ipams, conf.IPAM)
+	}
+	for _, p := range conf.Plugins {
+		if len(p.IPAM) != 0 {
+			ipams = append(ipams, p.IPAM)
+		}
+	}
+
+	return ipams, nil
+}
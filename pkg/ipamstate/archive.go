@@ -0,0 +1,93 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipamstate
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+)
+
+const (
+	// ArchiveVersion is the version of the archive format, bump it when the
+	// format changes incompatibly.
+	ArchiveVersion = "v1"
+	ArchiveKind    = "SpiderpoolState"
+)
+
+// Archive is a snapshot of all Spiderpool IPAM resources, including their
+// status.
+type Archive struct {
+	Kind       string      `json:"kind"`
+	Version    string      `json:"version"`
+	APIVersion string      `json:"apiVersion"`
+	ExportTime metav1.Time `json:"exportTime"`
+
+	Subnets     []spiderpoolv2beta1.SpiderSubnet     `json:"subnets,omitempty"`
+	IPPools     []spiderpoolv2beta1.SpiderIPPool     `json:"ippools,omitempty"`
+	IPClaims    []spiderpoolv2beta1.SpiderIPClaim    `json:"ipclaims,omitempty"`
+	ReservedIPs []spiderpoolv2beta1.SpiderReservedIP `json:"reservedips,omitempty"`
+	Endpoints   []spiderpoolv2beta1.SpiderEndpoint   `json:"endpoints,omitempty"`
+}
+
+// WriteArchive encodes the archive as indented JSON, gzip-compressed if
+// compress is true.
+func WriteArchive(w io.Writer, archive *Archive, compress bool) error {
+	if compress {
+		gw := gzip.NewWriter(w)
+		defer gw.Close()
+		w = gw
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(archive)
+}
+
+// ReadArchive decodes the archive written by WriteArchive, the gzip
+// compression is detected automatically.
+func ReadArchive(r io.Reader) (*Archive, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// gzip magic number
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+
+		if data, err = io.ReadAll(gr); err != nil {
+			return nil, err
+		}
+	}
+
+	var archive Archive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("%w, failed to decode archive: %v", constant.ErrWrongInput, err)
+	}
+
+	if archive.Kind != ArchiveKind {
+		return nil, fmt.Errorf("%w, unknown archive kind '%s'", constant.ErrWrongInput, archive.Kind)
+	}
+	if archive.Version != ArchiveVersion {
+		return nil, fmt.Errorf("%w, unsupported archive version '%s', expect '%s'", constant.ErrWrongInput, archive.Version, ArchiveVersion)
+	}
+	if archive.APIVersion != spiderpoolv2beta1.GroupVersion.String() {
+		return nil, fmt.Errorf("%w, unsupported API version '%s' of the archived resources", constant.ErrWrongInput, archive.APIVersion)
+	}
+
+	return &archive, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipamstate
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+)
+
+// Export takes a snapshot of all Spiderpool IPAM resources. The terminating
+// ones are skipped, and the metadata generated by the API server is stripped.
+func Export(ctx context.Context, reader client.Reader) (*Archive, error) {
+	archive := &Archive{
+		Kind:       ArchiveKind,
+		Version:    ArchiveVersion,
+		APIVersion: spiderpoolv2beta1.GroupVersion.String(),
+		ExportTime: metav1.Now(),
+	}
+
+	var subnetList spiderpoolv2beta1.SpiderSubnetList
+	if err := reader.List(ctx, &subnetList); err != nil {
+		return nil, fmt.Errorf("failed to list Subnets: %w", err)
+	}
+	for _, subnet := range subnetList.Items {
+		if subnet.DeletionTimestamp != nil {
+			continue
+		}
+		subnet.TypeMeta = typeMeta(constant.KindSpiderSubnet)
+		stripObjectMeta(&subnet.ObjectMeta)
+		archive.Subnets = append(archive.Subnets, subnet)
+	}
+
+	var ipPoolList spiderpoolv2beta1.SpiderIPPoolList
+	if err := reader.List(ctx, &ipPoolList); err != nil {
+		return nil, fmt.Errorf("failed to list IPPools: %w", err)
+	}
+	for _, ipPool := range ipPoolList.Items {
+		if ipPool.DeletionTimestamp != nil {
+			continue
+		}
+		ipPool.TypeMeta = typeMeta(constant.KindSpiderIPPool)
+		stripObjectMeta(&ipPool.ObjectMeta)
+		archive.IPPools = append(archive.IPPools, ipPool)
+	}
+
+	var ipClaimList spiderpoolv2beta1.SpiderIPClaimList
+	if err := reader.List(ctx, &ipClaimList); err != nil {
+		return nil, fmt.Errorf("failed to list IPClaims: %w", err)
+	}
+	for _, ipClaim := range ipClaimList.Items {
+		if ipClaim.DeletionTimestamp != nil {
+			continue
+		}
+		ipClaim.TypeMeta = typeMeta(constant.KindSpiderIPClaim)
+		stripObjectMeta(&ipClaim.ObjectMeta)
+		archive.IPClaims = append(archive.IPClaims, ipClaim)
+	}
+
+	var reservedIPList spiderpoolv2beta1.SpiderReservedIPList
+	if err := reader.List(ctx, &reservedIPList); err != nil {
+		return nil, fmt.Errorf("failed to list ReservedIPs: %w", err)
+	}
+	for _, reservedIP := range reservedIPList.Items {
+		if reservedIP.DeletionTimestamp != nil {
+			continue
+		}
+		reservedIP.TypeMeta = typeMeta(constant.KindSpiderReservedIP)
+		stripObjectMeta(&reservedIP.ObjectMeta)
+		archive.ReservedIPs = append(archive.ReservedIPs, reservedIP)
+	}
+
+	var endpointList spiderpoolv2beta1.SpiderEndpointList
+	if err := reader.List(ctx, &endpointList); err != nil {
+		return nil, fmt.Errorf("failed to list Endpoints: %w", err)
+	}
+	for _, endpoint := range endpointList.Items {
+		if endpoint.DeletionTimestamp != nil {
+			continue
+		}
+		endpoint.TypeMeta = typeMeta(constant.KindSpiderEndpoint)
+		stripObjectMeta(&endpoint.ObjectMeta)
+		archive.Endpoints = append(archive.Endpoints, endpoint)
+	}
+
+	return archive, nil
+}
+
+func typeMeta(kind string) metav1.TypeMeta {
+	return metav1.TypeMeta{
+		Kind:       kind,
+		APIVersion: spiderpoolv2beta1.GroupVersion.String(),
+	}
+}
+
+// stripObjectMeta removes the metadata which is only meaningful to the
+// cluster it is exported from. The UIDs of owner references are kept to
+// identify the owners, they are resolved again when imported.
+func stripObjectMeta(meta *metav1.ObjectMeta) {
+	meta.UID = ""
+	meta.ResourceVersion = ""
+	meta.Generation = 0
+	meta.SelfLink = ""
+	meta.CreationTimestamp = metav1.Time{}
+	meta.ManagedFields = nil
+}
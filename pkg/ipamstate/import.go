@@ -0,0 +1,218 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipamstate
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+)
+
+type Action string
+
+const (
+	// ActionCreate means the resource does not exist in the cluster.
+	ActionCreate Action = "create"
+	// ActionUnchanged means the resource in the cluster is the same as the
+	// archived one.
+	ActionUnchanged Action = "unchanged"
+	// ActionConflict means the resource in the cluster differs from the
+	// archived one, it is never overwritten.
+	ActionConflict Action = "conflict"
+)
+
+// Change describes what the import does to a resource.
+type Change struct {
+	Kind   string
+	Name   string
+	Action Action
+	// Diff is the difference between the resource in the cluster and the
+	// archived one, only set for ActionConflict.
+	Diff string
+}
+
+type ImportOptions struct {
+	// DryRun only compares the archive with the cluster.
+	DryRun bool
+}
+
+// Import validates the archive and creates the archived resources absent
+// from the cluster, in the order of Subnets, IPPools, IPClaims, ReservedIPs
+// and Endpoints. The status of a created resource is restored through the
+// status subresource, and the UIDs of its owner references are resolved in
+// the cluster, the owners not found are dropped.
+func Import(ctx context.Context, c client.Client, archive *Archive, opts ImportOptions) ([]Change, error) {
+	if err := Validate(archive); err != nil {
+		return nil, err
+	}
+
+	im := &importer{client: c, dryRun: opts.DryRun}
+
+	for i := range archive.Subnets {
+		archived := archive.Subnets[i].DeepCopy()
+		var live spiderpoolv2beta1.SpiderSubnet
+		if err := im.importObject(ctx, constant.KindSpiderSubnet, archived, &live, func() (bool, string) {
+			return diffSpecAndStatus(live.Spec, archived.Spec, live.Status, archived.Status)
+		}, func() {
+			live.Status = archived.Status
+		}); err != nil {
+			return im.changes, err
+		}
+	}
+
+	for i := range archive.IPPools {
+		archived := archive.IPPools[i].DeepCopy()
+		var live spiderpoolv2beta1.SpiderIPPool
+		if err := im.importObject(ctx, constant.KindSpiderIPPool, archived, &live, func() (bool, string) {
+			return diffSpecAndStatus(live.Spec, archived.Spec, live.Status, archived.Status)
+		}, func() {
+			live.Status = archived.Status
+		}); err != nil {
+			return im.changes, err
+		}
+	}
+
+	for i := range archive.IPClaims {
+		archived := archive.IPClaims[i].DeepCopy()
+		var live spiderpoolv2beta1.SpiderIPClaim
+		if err := im.importObject(ctx, constant.KindSpiderIPClaim, archived, &live, func() (bool, string) {
+			return diffSpecAndStatus(live.Spec, archived.Spec, nil, nil)
+		}, nil); err != nil {
+			return im.changes, err
+		}
+	}
+
+	for i := range archive.ReservedIPs {
+		archived := archive.ReservedIPs[i].DeepCopy()
+		var live spiderpoolv2beta1.SpiderReservedIP
+		if err := im.importObject(ctx, constant.KindSpiderReservedIP, archived, &live, func() (bool, string) {
+			return diffSpecAndStatus(live.Spec, archived.Spec, nil, nil)
+		}, nil); err != nil {
+			return im.changes, err
+		}
+	}
+
+	// SpiderEndpoint has no status subresource, its status is created as is.
+	for i := range archive.Endpoints {
+		archived := archive.Endpoints[i].DeepCopy()
+		var live spiderpoolv2beta1.SpiderEndpoint
+		if err := im.importObject(ctx, constant.KindSpiderEndpoint, archived, &live, func() (bool, string) {
+			return diffSpecAndStatus(nil, nil, live.Status, archived.Status)
+		}, nil); err != nil {
+			return im.changes, err
+		}
+	}
+
+	return im.changes, nil
+}
+
+type importer struct {
+	client  client.Client
+	dryRun  bool
+	changes []Change
+}
+
+// importObject creates the archived object if it does not exist in the
+// cluster, otherwise compares it with the live one fetched into live.
+// restoreStatus copies the archived status into live after creation, nil
+// if the kind has no status subresource.
+func (im *importer) importObject(ctx context.Context, kind string, archived, live client.Object, compare func() (bool, string), restoreStatus func()) error {
+	name := archived.GetName()
+	if archived.GetNamespace() != "" {
+		name = archived.GetNamespace() + "/" + name
+	}
+
+	err := im.client.Get(ctx, apitypes.NamespacedName{Namespace: archived.GetNamespace(), Name: archived.GetName()}, live)
+	if err == nil {
+		change := Change{Kind: kind, Name: name, Action: ActionUnchanged}
+		if differs, d := compare(); differs {
+			change.Action = ActionConflict
+			change.Diff = d
+		}
+		im.changes = append(im.changes, change)
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get %s %s: %w", kind, name, err)
+	}
+
+	im.changes = append(im.changes, Change{Kind: kind, Name: name, Action: ActionCreate})
+	if im.dryRun {
+		return nil
+	}
+
+	if err := im.resolveOwnerReferences(ctx, archived); err != nil {
+		return fmt.Errorf("failed to resolve the owners of %s %s: %w", kind, name, err)
+	}
+
+	// Keep the archived object for the status restoration, the creation
+	// overwrites the object passed in with the response.
+	obj := archived.DeepCopyObject().(client.Object)
+	if err := im.client.Create(ctx, obj); err != nil {
+		return fmt.Errorf("failed to create %s %s: %w", kind, name, err)
+	}
+
+	if restoreStatus == nil {
+		return nil
+	}
+
+	if err := im.client.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		return fmt.Errorf("failed to get the created %s %s: %w", kind, name, err)
+	}
+	restoreStatus()
+	if err := im.client.Status().Update(ctx, live); err != nil {
+		return fmt.Errorf("failed to restore the status of %s %s: %w", kind, name, err)
+	}
+
+	return nil
+}
+
+// resolveOwnerReferences replaces the UIDs of the owner references with the
+// ones of the owners in the cluster, and drops the owners not found.
+func (im *importer) resolveOwnerReferences(ctx context.Context, obj client.Object) error {
+	var refs []metav1.OwnerReference
+	for _, ref := range obj.GetOwnerReferences() {
+		owner := &unstructured.Unstructured{}
+		owner.SetAPIVersion(ref.APIVersion)
+		owner.SetKind(ref.Kind)
+
+		err := im.client.Get(ctx, apitypes.NamespacedName{Namespace: obj.GetNamespace(), Name: ref.Name}, owner)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		ref.UID = owner.GetUID()
+		refs = append(refs, ref)
+	}
+	obj.SetOwnerReferences(refs)
+
+	return nil
+}
+
+// diffSpecAndStatus compares the live spec and status with the archived
+// ones, the status counters maintained by the controllers are included.
+func diffSpecAndStatus(liveSpec, archivedSpec, liveStatus, archivedStatus interface{}) (bool, string) {
+	var d string
+	if !equality.Semantic.DeepEqual(liveSpec, archivedSpec) {
+		d += "spec:\n" + diff.ObjectReflectDiff(liveSpec, archivedSpec) + "\n"
+	}
+	if !equality.Semantic.DeepEqual(liveStatus, archivedStatus) {
+		d += "status:\n" + diff.ObjectReflectDiff(liveStatus, archivedStatus) + "\n"
+	}
+
+	return d != "", d
+}
@@ -0,0 +1,209 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipamstate_test
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/ipamstate"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+)
+
+var _ = Describe("IPAMState", Label("ipamstate_test"), func() {
+	var ctx context.Context
+	var subnetT *spiderpoolv2beta1.SpiderSubnet
+	var ipPoolT *spiderpoolv2beta1.SpiderIPPool
+	var endpointT *spiderpoolv2beta1.SpiderEndpoint
+	var podT *corev1.Pod
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+
+		subnetT = &spiderpoolv2beta1.SpiderSubnet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "subnet",
+				UID:             "subnet-uid",
+				ResourceVersion: "1",
+			},
+			Spec: spiderpoolv2beta1.SubnetSpec{
+				IPVersion: pointer.Int64(constant.IPv4),
+				Subnet:    "172.18.40.0/24",
+				IPs:       []string{"172.18.40.1-172.18.40.10"},
+			},
+			Status: spiderpoolv2beta1.SubnetStatus{
+				TotalIPCount: pointer.Int64(10),
+			},
+		}
+		ipPoolT = &spiderpoolv2beta1.SpiderIPPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "pool",
+				UID:             "pool-uid",
+				ResourceVersion: "1",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: spiderpoolv2beta1.GroupVersion.String(),
+					Kind:       constant.KindSpiderSubnet,
+					Name:       subnetT.Name,
+					UID:        subnetT.UID,
+					Controller: pointer.Bool(true),
+				}},
+			},
+			Spec: spiderpoolv2beta1.IPPoolSpec{
+				IPVersion: pointer.Int64(constant.IPv4),
+				Subnet:    "172.18.40.0/24",
+				IPs:       []string{"172.18.40.1-172.18.40.5"},
+			},
+			Status: spiderpoolv2beta1.IPPoolStatus{
+				AllocatedIPs:     pointer.String(`{"172.18.40.1":{"interface":"eth0","pod":"default/pod","podUid":"pod-uid"}}`),
+				AllocatedIPCount: pointer.Int64(1),
+				TotalIPCount:     pointer.Int64(5),
+			},
+		}
+		podT = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "pod",
+				UID:       "pod-uid",
+			},
+		}
+		endpointT = &spiderpoolv2beta1.SpiderEndpoint{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "pod",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: corev1.SchemeGroupVersion.String(),
+					Kind:       constant.KindPod,
+					Name:       podT.Name,
+					UID:        "old-pod-uid",
+				}},
+			},
+			Status: spiderpoolv2beta1.WorkloadEndpointStatus{
+				Current: spiderpoolv2beta1.PodIPAllocation{
+					UID:  "pod-uid",
+					Node: "node",
+					IPs: []spiderpoolv2beta1.IPAllocationDetail{{
+						NIC:      "eth0",
+						IPv4:     pointer.String("172.18.40.1/24"),
+						IPv4Pool: pointer.String(ipPoolT.Name),
+					}},
+				},
+			},
+		}
+	})
+
+	It("exports and imports the state", func() {
+		src := fake.NewClientBuilder().WithScheme(scheme).WithObjects(subnetT, ipPoolT, endpointT).Build()
+		archive, err := ipamstate.Export(ctx, src)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(archive.Subnets).To(HaveLen(1))
+		Expect(archive.IPPools).To(HaveLen(1))
+		Expect(archive.Endpoints).To(HaveLen(1))
+		Expect(archive.IPPools[0].UID).To(BeEmpty())
+		Expect(archive.IPPools[0].ResourceVersion).To(BeEmpty())
+
+		var buf bytes.Buffer
+		err = ipamstate.WriteArchive(&buf, archive, true)
+		Expect(err).NotTo(HaveOccurred())
+		archive, err = ipamstate.ReadArchive(&buf)
+		Expect(err).NotTo(HaveOccurred())
+
+		dst := fake.NewClientBuilder().WithScheme(scheme).WithObjects(podT).Build()
+		changes, err := ipamstate.Import(ctx, dst, archive, ipamstate.ImportOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(HaveLen(3))
+		for _, change := range changes {
+			Expect(change.Action).To(Equal(ipamstate.ActionCreate))
+		}
+
+		var subnet spiderpoolv2beta1.SpiderSubnet
+		err = dst.Get(ctx, types.NamespacedName{Name: subnetT.Name}, &subnet)
+		Expect(err).NotTo(HaveOccurred())
+
+		var ipPool spiderpoolv2beta1.SpiderIPPool
+		err = dst.Get(ctx, types.NamespacedName{Name: ipPoolT.Name}, &ipPool)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ipPool.Status).To(Equal(ipPoolT.Status))
+		Expect(ipPool.OwnerReferences).To(HaveLen(1))
+		Expect(ipPool.OwnerReferences[0].UID).To(Equal(subnet.UID))
+
+		var endpoint spiderpoolv2beta1.SpiderEndpoint
+		err = dst.Get(ctx, client.ObjectKeyFromObject(endpointT), &endpoint)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(endpoint.Status).To(Equal(endpointT.Status))
+		Expect(endpoint.OwnerReferences).To(HaveLen(1))
+		Expect(endpoint.OwnerReferences[0].UID).To(Equal(podT.UID))
+	})
+
+	It("drops the owners not found", func() {
+		archive := &ipamstate.Archive{Endpoints: []spiderpoolv2beta1.SpiderEndpoint{*endpointT}}
+		dst := fake.NewClientBuilder().WithScheme(scheme).Build()
+		_, err := ipamstate.Import(ctx, dst, archive, ipamstate.ImportOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		var endpoint spiderpoolv2beta1.SpiderEndpoint
+		err = dst.Get(ctx, client.ObjectKeyFromObject(endpointT), &endpoint)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(endpoint.OwnerReferences).To(BeEmpty())
+	})
+
+	It("compares the archive with the cluster in dry-run mode", func() {
+		archive := &ipamstate.Archive{
+			Subnets: []spiderpoolv2beta1.SpiderSubnet{*subnetT},
+			IPPools: []spiderpoolv2beta1.SpiderIPPool{*ipPoolT},
+		}
+
+		live := ipPoolT.DeepCopy()
+		live.Spec.IPs = []string{"172.18.40.1-172.18.40.6"}
+		dst := fake.NewClientBuilder().WithScheme(scheme).WithObjects(live).Build()
+
+		changes, err := ipamstate.Import(ctx, dst, archive, ipamstate.ImportOptions{DryRun: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(HaveLen(2))
+		Expect(changes[0].Action).To(Equal(ipamstate.ActionCreate))
+		Expect(changes[1].Action).To(Equal(ipamstate.ActionConflict))
+		Expect(changes[1].Diff).To(ContainSubstring("spec"))
+
+		err = dst.Get(ctx, types.NamespacedName{Name: subnetT.Name}, &spiderpoolv2beta1.SpiderSubnet{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects the IP address allocated by multiple IPPools", func() {
+		another := ipPoolT.DeepCopy()
+		another.Name = "another"
+		archive := &ipamstate.Archive{
+			IPPools: []spiderpoolv2beta1.SpiderIPPool{*ipPoolT, *another},
+		}
+
+		err := ipamstate.Validate(archive)
+		Expect(err).To(MatchError(constant.ErrWrongInput))
+		Expect(err.Error()).To(ContainSubstring("172.18.40.1"))
+	})
+
+	It("rejects the IPClaim of IPPool not archived", func() {
+		archive := &ipamstate.Archive{
+			IPClaims: []spiderpoolv2beta1.SpiderIPClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "pool-172.18.40.2"},
+				Spec:       spiderpoolv2beta1.IPClaimSpec{IPPool: "pool", IP: "172.18.40.2"},
+			}},
+		}
+
+		err := ipamstate.Validate(archive)
+		Expect(err).To(MatchError(constant.ErrWrongInput))
+	})
+
+	It("rejects the archive of unsupported version", func() {
+		_, err := ipamstate.ReadArchive(bytes.NewBufferString(`{"kind":"SpiderpoolState","version":"v0"}`))
+		Expect(err).To(MatchError(constant.ErrWrongInput))
+	})
+})
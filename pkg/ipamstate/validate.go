@@ -0,0 +1,93 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipamstate
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
+)
+
+// Validate checks the consistency of the archive: the resources are not
+// duplicated, every SpiderIPClaim belongs to an archived IPPool, and no IP
+// address is allocated by more than one IPPool.
+func Validate(archive *Archive) error {
+	var errs []error
+
+	names := map[string]struct{}{}
+	checkDuplicated := func(kind, name string) {
+		key := kind + "/" + name
+		if _, ok := names[key]; ok {
+			errs = append(errs, fmt.Errorf("%w, %s is duplicated", constant.ErrWrongInput, key))
+		}
+		names[key] = struct{}{}
+	}
+	for _, subnet := range archive.Subnets {
+		checkDuplicated(constant.KindSpiderSubnet, subnet.Name)
+	}
+	for _, ipPool := range archive.IPPools {
+		checkDuplicated(constant.KindSpiderIPPool, ipPool.Name)
+	}
+	for _, ipClaim := range archive.IPClaims {
+		checkDuplicated(constant.KindSpiderIPClaim, ipClaim.Name)
+	}
+	for _, reservedIP := range archive.ReservedIPs {
+		checkDuplicated(constant.KindSpiderReservedIP, reservedIP.Name)
+	}
+	for _, endpoint := range archive.Endpoints {
+		checkDuplicated(constant.KindSpiderEndpoint, endpoint.Namespace+"/"+endpoint.Name)
+	}
+
+	// IP address -> IPPools allocating it
+	allocated := map[string][]string{}
+	allocate := func(ip, poolName string) {
+		if addr := net.ParseIP(ip); addr != nil {
+			ip = addr.String()
+		}
+		for _, p := range allocated[ip] {
+			if p == poolName {
+				return
+			}
+		}
+		allocated[ip] = append(allocated[ip], poolName)
+	}
+
+	for _, ipPool := range archive.IPPools {
+		records, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w, failed to parse the allocated IP addresses of IPPool %s: %v", constant.ErrWrongInput, ipPool.Name, err))
+			continue
+		}
+		for ip := range records {
+			allocate(ip, ipPool.Name)
+		}
+	}
+
+	for _, ipClaim := range archive.IPClaims {
+		if _, ok := names[constant.KindSpiderIPPool+"/"+ipClaim.Spec.IPPool]; !ok {
+			errs = append(errs, fmt.Errorf("%w, IPPool %s of IPClaim %s is not archived", constant.ErrWrongInput, ipClaim.Spec.IPPool, ipClaim.Name))
+			continue
+		}
+		allocate(ipClaim.Spec.IP, ipClaim.Spec.IPPool)
+	}
+
+	ips := make([]string, 0, len(allocated))
+	for ip := range allocated {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+	for _, ip := range ips {
+		if pools := allocated[ip]; len(pools) > 1 {
+			sort.Strings(pools)
+			errs = append(errs, fmt.Errorf("%w, IP address %s is allocated by multiple IPPools %v", constant.ErrWrongInput, ip, pools))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
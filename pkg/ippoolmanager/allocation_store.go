@@ -0,0 +1,313 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ippoolmanager
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/types"
+	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
+)
+
+// IPClaimIPPoolIndex is the field index of SpiderIPClaims by the name of
+// their IPPool, it must be registered on the cache of the client passed to
+// NewAllocationStore.
+const IPClaimIPPoolIndex = "spec.ippool"
+
+// AllocationStore reads and writes the IP allocation records of IPPools. The
+// records of an IPPool are kept in 'status.allocatedIPs', or in SpiderIPClaims
+// if its 'spec.allocationStorage' is 'ipclaim'.
+type AllocationStore interface {
+	// ListAllocations returns all IP allocation records of the IPPool.
+	ListAllocations(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool) (spiderpoolv2beta1.PoolIPAllocations, error)
+	// Allocate records the IP allocation, a conflict or an already-exists
+	// error means that the IPPool or the IP address is taken concurrently.
+	Allocate(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool, ip string, allocation spiderpoolv2beta1.PoolIPAllocation) error
+	// Release removes the IP allocation records which still belong to the UIDs.
+	Release(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool, ipAndUIDs []types.IPAndUID) error
+	// Rebind changes the owner UIDs of the IP allocation records.
+	Rebind(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool, ipAndUIDs []types.IPAndUID) error
+	// Migrate moves the IP allocation records in 'status.allocatedIPs' into
+	// SpiderIPClaims for the IPPool whose allocation storage is 'ipclaim'.
+	Migrate(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool) error
+}
+
+type allocationStore struct {
+	client    client.Client
+	apiReader client.Reader
+}
+
+func NewAllocationStore(client client.Client, apiReader client.Reader) (AllocationStore, error) {
+	if client == nil {
+		return nil, fmt.Errorf("k8s client %w", constant.ErrMissingRequiredParam)
+	}
+	if apiReader == nil {
+		return nil, fmt.Errorf("api reader %w", constant.ErrMissingRequiredParam)
+	}
+
+	return &allocationStore{
+		client:    client,
+		apiReader: apiReader,
+	}, nil
+}
+
+// IsIPClaimStorage returns true if the IP allocation records of the IPPool are
+// kept in SpiderIPClaims.
+func IsIPClaimStorage(ipPool *spiderpoolv2beta1.SpiderIPPool) bool {
+	return ipPool.Spec.AllocationStorage != nil && *ipPool.Spec.AllocationStorage == constant.IPPoolAllocationStorageIPClaim
+}
+
+// IPClaimName returns the name of the SpiderIPClaim of the IP address in the
+// IPPool. The name of the IPPool is hashed if the result is too long.
+func IPClaimName(poolName, ip string) string {
+	suffix := ip
+	if addr, err := netip.ParseAddr(ip); err == nil && addr.Is6() {
+		suffix = strings.ReplaceAll(addr.StringExpanded(), ":", "-")
+	}
+
+	name := poolName + "-" + suffix
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		name = fmt.Sprintf("%x", sha256.Sum256([]byte(poolName)))[:16] + "-" + suffix
+	}
+
+	return name
+}
+
+func (s *allocationStore) ListAllocations(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool) (spiderpoolv2beta1.PoolIPAllocations, error) {
+	records, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsIPClaimStorage(ipPool) {
+		return records, nil
+	}
+
+	// The records which are not migrated yet are still in use.
+	var claimList spiderpoolv2beta1.SpiderIPClaimList
+	if err := s.client.List(ctx, &claimList, client.MatchingFields{IPClaimIPPoolIndex: ipPool.Name}); err != nil {
+		return nil, err
+	}
+
+	if records == nil && len(claimList.Items) != 0 {
+		records = spiderpoolv2beta1.PoolIPAllocations{}
+	}
+	for _, claim := range claimList.Items {
+		if claim.DeletionTimestamp != nil {
+			continue
+		}
+		records[claim.Spec.IP] = spiderpoolv2beta1.PoolIPAllocation{
+			NIC:            claim.Spec.NIC,
+			NamespacedName: claim.Spec.NamespacedName,
+			PodUID:         claim.Spec.PodUID,
+			MAC:            claim.Spec.MAC,
+		}
+	}
+
+	return records, nil
+}
+
+func (s *allocationStore) Allocate(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool, ip string, allocation spiderpoolv2beta1.PoolIPAllocation) error {
+	if IsIPClaimStorage(ipPool) {
+		return s.client.Create(ctx, newIPClaim(ipPool, ip, allocation))
+	}
+
+	records, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
+	if err != nil {
+		return err
+	}
+	if records == nil {
+		records = spiderpoolv2beta1.PoolIPAllocations{}
+	}
+	records[ip] = allocation
+
+	data, err := convert.MarshalIPPoolAllocatedIPs(records)
+	if err != nil {
+		return err
+	}
+	ipPool.Status.AllocatedIPs = data
+
+	if ipPool.Status.AllocatedIPCount == nil {
+		ipPool.Status.AllocatedIPCount = new(int64)
+	}
+	*ipPool.Status.AllocatedIPCount++
+
+	return s.client.Status().Update(ctx, ipPool)
+}
+
+func (s *allocationStore) Release(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool, ipAndUIDs []types.IPAndUID) error {
+	if IsIPClaimStorage(ipPool) {
+		for _, iu := range ipAndUIDs {
+			claim, err := s.getIPClaim(ctx, ipPool.Name, iu.IP)
+			if err != nil {
+				return err
+			}
+			if claim == nil || claim.Spec.PodUID != iu.UID {
+				continue
+			}
+
+			// Make sure that the SpiderIPClaim is not re-bound concurrently.
+			err = s.client.Delete(ctx, claim, client.Preconditions{UID: &claim.UID, ResourceVersion: &claim.ResourceVersion})
+			if client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+	}
+
+	return s.releaseStatus(ctx, ipPool, ipAndUIDs)
+}
+
+func (s *allocationStore) releaseStatus(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool, ipAndUIDs []types.IPAndUID) error {
+	records, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
+	if err != nil {
+		return err
+	}
+
+	if ipPool.Status.AllocatedIPCount == nil {
+		ipPool.Status.AllocatedIPCount = new(int64)
+	}
+
+	release := false
+	for _, iu := range ipAndUIDs {
+		if record, ok := records[iu.IP]; ok {
+			if record.PodUID == iu.UID {
+				delete(records, iu.IP)
+				if *ipPool.Status.AllocatedIPCount > 0 {
+					*ipPool.Status.AllocatedIPCount--
+				}
+				release = true
+			}
+		}
+	}
+
+	if !release {
+		return nil
+	}
+
+	data, err := convert.MarshalIPPoolAllocatedIPs(records)
+	if err != nil {
+		return err
+	}
+	ipPool.Status.AllocatedIPs = data
+
+	return s.client.Status().Update(ctx, ipPool)
+}
+
+func (s *allocationStore) Rebind(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool, ipAndUIDs []types.IPAndUID) error {
+	if IsIPClaimStorage(ipPool) {
+		for _, iu := range ipAndUIDs {
+			claim, err := s.getIPClaim(ctx, ipPool.Name, iu.IP)
+			if err != nil {
+				return err
+			}
+			if claim == nil || claim.Spec.PodUID == iu.UID {
+				continue
+			}
+
+			claim.Spec.PodUID = iu.UID
+			if err := s.client.Update(ctx, claim); err != nil {
+				return err
+			}
+		}
+	}
+
+	records, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
+	if err != nil {
+		return err
+	}
+
+	rebind := false
+	for _, iu := range ipAndUIDs {
+		if record, ok := records[iu.IP]; ok {
+			if record.PodUID != iu.UID {
+				record.PodUID = iu.UID
+				records[iu.IP] = record
+				rebind = true
+			}
+		}
+	}
+
+	if !rebind {
+		return nil
+	}
+
+	data, err := convert.MarshalIPPoolAllocatedIPs(records)
+	if err != nil {
+		return err
+	}
+	ipPool.Status.AllocatedIPs = data
+
+	return s.client.Status().Update(ctx, ipPool)
+}
+
+func (s *allocationStore) Migrate(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool) error {
+	if !IsIPClaimStorage(ipPool) || ipPool.Status.AllocatedIPs == nil {
+		return nil
+	}
+
+	records, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
+	if err != nil {
+		return err
+	}
+
+	// Create the SpiderIPClaims before cleaning the records in status, so
+	// that the IP addresses are always in use during the migration.
+	for ip, allocation := range records {
+		if err := s.client.Create(ctx, newIPClaim(ipPool, ip, allocation)); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create SpiderIPClaim for IP address %s: %w", ip, err)
+		}
+	}
+
+	ipPool.Status.AllocatedIPs = nil
+	return s.client.Status().Update(ctx, ipPool)
+}
+
+func (s *allocationStore) getIPClaim(ctx context.Context, poolName, ip string) (*spiderpoolv2beta1.SpiderIPClaim, error) {
+	var claim spiderpoolv2beta1.SpiderIPClaim
+	if err := s.apiReader.Get(ctx, apitypes.NamespacedName{Name: IPClaimName(poolName, ip)}, &claim); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	if claim.Spec.IPPool != poolName || claim.DeletionTimestamp != nil {
+		return nil, nil
+	}
+
+	return &claim, nil
+}
+
+func newIPClaim(ipPool *spiderpoolv2beta1.SpiderIPPool, ip string, allocation spiderpoolv2beta1.PoolIPAllocation) *spiderpoolv2beta1.SpiderIPClaim {
+	return &spiderpoolv2beta1.SpiderIPClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: IPClaimName(ipPool.Name, ip),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         spiderpoolv2beta1.GroupVersion.String(),
+				Kind:               constant.KindSpiderIPPool,
+				Name:               ipPool.Name,
+				UID:                ipPool.UID,
+				BlockOwnerDeletion: pointer.Bool(true),
+			}},
+		},
+		Spec: spiderpoolv2beta1.IPClaimSpec{
+			IPPool:         ipPool.Name,
+			IP:             ip,
+			NIC:            allocation.NIC,
+			NamespacedName: allocation.NamespacedName,
+			PodUID:         allocation.PodUID,
+			MAC:            allocation.MAC,
+		},
+	}
+}
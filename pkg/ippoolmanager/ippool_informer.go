@@ -12,9 +12,11 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/pointer"
@@ -33,17 +35,26 @@ import (
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
 	"github.com/spidernet-io/spiderpool/pkg/metric"
 	"github.com/spidernet-io/spiderpool/pkg/types"
+	"github.com/spidernet-io/spiderpool/pkg/utilization"
 )
 
 var informerLogger *zap.Logger
 
+var ipClaimGVR = spiderpoolv2beta1.SchemeGroupVersion.WithResource("spideripclaims")
+
+// ipClaimSyncDelay is the delay of recounting the allocated IP addresses of
+// the IPPool when its SpiderIPClaim changes.
+const ipClaimSyncDelay = time.Second
+
 type IPPoolController struct {
 	IPPoolControllerConfig
 	client        client.Client
 	dynamicClient dynamic.Interface
+	store         AllocationStore
 	poolLister    listers.SpiderIPPoolLister
 	poolSynced    cache.InformerSynced
 	poolWorkqueue workqueue.RateLimitingInterface
+	usageTracker  *utilization.Tracker
 }
 
 type IPPoolControllerConfig struct {
@@ -54,6 +65,8 @@ type IPPoolControllerConfig struct {
 	LeaderRetryElectGap           time.Duration
 	WorkQueueRequeueDelayDuration time.Duration
 	ResyncPeriod                  time.Duration
+	UtilizationWindow             time.Duration
+	UtilizationThresholds         []int
 }
 
 func NewIPPoolController(poolControllerConfig IPPoolControllerConfig, client client.Client, dynamicClient dynamic.Interface) *IPPoolController {
@@ -63,6 +76,8 @@ func NewIPPoolController(poolControllerConfig IPPoolControllerConfig, client cli
 		IPPoolControllerConfig: poolControllerConfig,
 		client:                 client,
 		dynamicClient:          dynamicClient,
+		store:                  &allocationStore{client: client, apiReader: client},
+		usageTracker:           utilization.NewTracker(poolControllerConfig.UtilizationWindow, poolControllerConfig.UtilizationThresholds),
 	}
 
 	return c
@@ -114,6 +129,15 @@ func (ic *IPPoolController) SetupInformer(ctx context.Context, client crdclients
 			}
 			factory.Start(innerCtx.Done())
 
+			// there is no typed informer of SpiderIPClaim
+			dynamicFactory := dynamicinformer.NewDynamicSharedInformerFactory(ic.dynamicClient, ic.ResyncPeriod)
+			err = ic.addIPClaimEventHandlers(dynamicFactory.ForResource(ipClaimGVR).Informer())
+			if nil != err {
+				informerLogger.Error(err.Error())
+				continue
+			}
+			dynamicFactory.Start(innerCtx.Done())
+
 			if err := ic.Run(innerCtx.Done()); nil != err {
 				informerLogger.Sugar().Errorf("failed to run ippool controller, error: %v", err)
 			}
@@ -137,7 +161,12 @@ func (ic *IPPoolController) addEventHandlers(poolInformer informers.SpiderIPPool
 			ic.enqueueIPPool(newObj)
 		},
 		DeleteFunc: func(obj interface{}) {
-
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if pool, ok := obj.(*spiderpoolv2beta1.SpiderIPPool); ok {
+				ic.forgetUsage(pool.Name)
+			}
 		},
 	})
 	if nil != err {
@@ -147,6 +176,45 @@ func (ic *IPPoolController) addEventHandlers(poolInformer informers.SpiderIPPool
 	return nil
 }
 
+// addIPClaimEventHandlers enqueues the IPPool of the SpiderIPClaim when it is
+// created or deleted, because the spiderpool-agents allocate and release the
+// IP addresses of the IPPool whose allocation storage is 'ipclaim' without
+// updating the IPPool, whose 'status.allocatedIPCount' is recounted then.
+func (ic *IPPoolController) addIPClaimEventHandlers(claimInformer cache.SharedIndexInformer) error {
+	_, err := claimInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ic.enqueueIPPoolOfIPClaim,
+		DeleteFunc: ic.enqueueIPPoolOfIPClaim,
+	})
+
+	return err
+}
+
+// enqueueIPPoolOfIPClaim enqueues the IPPool of the SpiderIPClaim after
+// ipClaimSyncDelay, for the SpiderIPClaims are counted from the cache of the
+// client, which may lag behind this informer.
+func (ic *IPPoolController) enqueueIPPoolOfIPClaim(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	claim, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	poolName, _, err := unstructured.NestedString(claim.Object, "spec", "ippool")
+	if err != nil || poolName == "" {
+		informerLogger.Sugar().Warnf("failed to get the IPPool of SpiderIPClaim '%s': %v", claim.GetName(), err)
+		return
+	}
+
+	if ic.poolWorkqueue.Len() >= ic.MaxWorkqueueLength {
+		informerLogger.Sugar().Errorf("The IPPool workqueue is out of capacity, discard enqueue IPPool '%s'", poolName)
+		return
+	}
+	ic.poolWorkqueue.AddAfter(poolName, ipClaimSyncDelay)
+	informerLogger.Sugar().Debugf("added '%s' to IPPool workqueue for SpiderIPClaim '%s'", poolName, claim.GetName())
+}
+
 // enqueueIPPool will check the given pool and enqueue them into different workqueue
 func (ic *IPPoolController) enqueueIPPool(obj interface{}) {
 	pool := obj.(*spiderpoolv2beta1.SpiderIPPool)
@@ -280,6 +348,9 @@ func (ic *IPPoolController) handleIPPool(ctx context.Context, pool *spiderpoolv2
 	}
 
 	// metrics
+	if err := ic.recordUsage(ctx, pool); nil != err {
+		informerLogger.Sugar().Warnf("failed to record SpiderIPPool '%s' IP utilization: %v", pool.Name, err)
+	}
 	if pool.Status.TotalIPCount != nil {
 		attr := attribute.String(constant.KindSpiderIPPool, pool.Name)
 		metric.IPPoolTotalIPCounts.Add(ctx, *pool.Status.TotalIPCount, attr)
@@ -296,8 +367,20 @@ func (ic *IPPoolController) handleIPPool(ctx context.Context, pool *spiderpoolv2
 // syncHandler will calculate and update the provided SpiderIPPool status AllocatedIPCount or TotalIPCount.
 // And it will also remove finalizer once the IPPool is dying and no longer being used.
 func (ic *IPPoolController) syncHandler(ctx context.Context, pool *spiderpoolv2beta1.SpiderIPPool) error {
+	if IsIPClaimStorage(pool) && pool.Status.AllocatedIPs != nil {
+		if err := ic.store.Migrate(ctx, pool); err != nil {
+			return fmt.Errorf("failed to migrate SpiderIPPool '%s' allocated IPs to SpiderIPClaims: %w", pool.Name, err)
+		}
+		informerLogger.Sugar().Infof("migrate SpiderIPPool '%s' allocated IPs to SpiderIPClaims successfully", pool.Name)
+	}
+
+	allocatedRecords, err := ic.store.ListAllocations(ctx, pool)
+	if nil != err {
+		return fmt.Errorf("failed to list SpiderIPPool '%s' allocated IPs: %w", pool.Name, err)
+	}
+
 	//remove finalizer to delete the dying IPPool when the IPPool is no longer being used
-	if pool.DeletionTimestamp != nil && pool.Status.AllocatedIPs == nil {
+	if pool.DeletionTimestamp != nil && len(allocatedRecords) == 0 {
 		err := ic.removeFinalizer(ctx, pool)
 		if client.IgnoreNotFound(err) != nil {
 			return fmt.Errorf("failed to remove SpiderIPPool '%s' finalizer: %w", pool.Name, err)
@@ -315,14 +398,24 @@ func (ic *IPPoolController) syncHandler(ctx context.Context, pool *spiderpoolv2b
 		informerLogger.Sugar().Infof("initial SpiderIPPool '%s' status AllocatedIPCount to 0", pool.Name)
 	}
 
-	totalIPs, err := spiderpoolip.AssembleTotalIPs(*pool.Spec.IPVersion, pool.Spec.IPs, pool.Spec.ExcludeIPs)
-	if nil != err {
-		return fmt.Errorf("%w: failed to calculate SpiderIPPool '%s' total IP count, error: %v", constant.ErrWrongInput, pool.Name, err)
+	// the agents do not update the count for each SpiderIPClaim
+	if IsIPClaimStorage(pool) && *pool.Status.AllocatedIPCount != int64(len(allocatedRecords)) {
+		needUpdate = true
+		pool.Status.AllocatedIPCount = pointer.Int64(int64(len(allocatedRecords)))
+	}
+
+	totalIPCount := int64(DerivedIPPoolSize)
+	if !IsDerivedIPPool(pool) {
+		totalIPs, err := spiderpoolip.AssembleTotalIPs(*pool.Spec.IPVersion, pool.Spec.IPs, pool.Spec.ExcludeIPs)
+		if nil != err {
+			return fmt.Errorf("%w: failed to calculate SpiderIPPool '%s' total IP count, error: %v", constant.ErrWrongInput, pool.Name, err)
+		}
+		totalIPCount = int64(len(totalIPs))
 	}
 
-	if pool.Status.TotalIPCount == nil || *pool.Status.TotalIPCount != int64(len(totalIPs)) {
+	if pool.Status.TotalIPCount == nil || *pool.Status.TotalIPCount != totalIPCount {
 		needUpdate = true
-		pool.Status.TotalIPCount = pointer.Int64(int64(len(totalIPs)))
+		pool.Status.TotalIPCount = pointer.Int64(totalIPCount)
 	}
 
 	if needUpdate {
@@ -365,7 +458,12 @@ func (ic *IPPoolController) cleanAutoIPPoolLegacy(ctx context.Context, pool *spi
 		return nil
 	}
 
-	if pool.Status.AllocatedIPs == nil {
+	allocatedRecords, err := ic.store.ListAllocations(ctx, pool)
+	if nil != err {
+		return fmt.Errorf("failed to list SpiderIPPool '%s' allocated IPs: %w", pool.Name, err)
+	}
+
+	if len(allocatedRecords) == 0 {
 		// unpack the IPPool corresponding application type,namespace and name
 		appGVStr := poolLabels[constant.LabelIPPoolOwnerApplicationGV]
 		appAPIVersion, isMatch := applicationinformers.ParseApplicationGVStr(appGVStr)
@@ -14,7 +14,9 @@ import (
 	"github.com/agiledragon/gomonkey/v2"
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/pointer"
@@ -197,6 +199,47 @@ var _ = Describe("IPPool-informer", Label("unitest"), Ordered, func() {
 			})
 		})
 
+		Context("enqueue the IPPool of an IPClaim", func() {
+			var claim *unstructured.Unstructured
+			BeforeEach(func() {
+				claim = &unstructured.Unstructured{Object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"ippool": pool.Name,
+						"ip":     "10.1.0.1",
+					},
+				}}
+				claim.SetName(IPClaimName(pool.Name, "10.1.0.1"))
+			})
+
+			It("registers the event handlers of IPClaim informer", func() {
+				factory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicfake.NewSimpleDynamicClient(scheme), 0)
+				err := control.addIPClaimEventHandlers(factory.ForResource(ipClaimGVR).Informer())
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("enqueues the IPPool of the created IPClaim", func() {
+				control.enqueueIPPoolOfIPClaim(claim)
+
+				Eventually(control.poolWorkqueue.Len).WithTimeout(3 * time.Second).Should(Equal(1))
+				item, _ := control.poolWorkqueue.Get()
+				Expect(item).To(Equal(pool.Name))
+			})
+
+			It("enqueues the IPPool of the deleted IPClaim in tombstone", func() {
+				control.enqueueIPPoolOfIPClaim(cache.DeletedFinalStateUnknown{Key: claim.GetName(), Obj: claim})
+
+				Eventually(control.poolWorkqueue.Len).WithTimeout(3 * time.Second).Should(Equal(1))
+				item, _ := control.poolWorkqueue.Get()
+				Expect(item).To(Equal(pool.Name))
+			})
+
+			It("ignores the IPClaim without IPPool", func() {
+				unstructured.RemoveNestedField(claim.Object, "spec", "ippool")
+				control.enqueueIPPoolOfIPClaim(claim)
+
+				Consistently(control.poolWorkqueue.Len).WithTimeout(2 * time.Second).Should(Equal(0))
+			})
+		})
 	})
 
 })
@@ -5,9 +5,13 @@ package ippoolmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
 	"net"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -23,6 +27,7 @@ import (
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
 	"github.com/spidernet-io/spiderpool/pkg/metric"
 	"github.com/spidernet-io/spiderpool/pkg/reservedipmanager"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
 	"github.com/spidernet-io/spiderpool/pkg/types"
 	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
 	"github.com/spidernet-io/spiderpool/pkg/utils/retry"
@@ -32,15 +37,25 @@ type IPPoolManager interface {
 	GetIPPoolByName(ctx context.Context, poolName string, cached bool) (*spiderpoolv2beta1.SpiderIPPool, error)
 	ListIPPools(ctx context.Context, cached bool, opts ...client.ListOption) (*spiderpoolv2beta1.SpiderIPPoolList, error)
 	AllocateIP(ctx context.Context, poolName, nic string, pod *corev1.Pod) (*models.IPConfig, error)
+	AllocateStaticIP(ctx context.Context, poolName, nic string, ip net.IP, pod *corev1.Pod) (*models.IPConfig, error)
+	AllocateDerivedIP(ctx context.Context, poolName, nic string, mac net.HardwareAddr, pod *corev1.Pod) (*models.IPConfig, error)
 	ReleaseIP(ctx context.Context, poolName string, ipAndUIDs []types.IPAndUID) error
 	UpdateAllocatedIPs(ctx context.Context, poolName string, ipAndCIDs []types.IPAndUID) error
+	ListAllocatedIPs(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool) (spiderpoolv2beta1.PoolIPAllocations, error)
+	MarkIPConflicted(ctx context.Context, poolName string, ipAndUID types.IPAndUID, conflict spiderpoolv2beta1.PoolIPConflict) error
+	UnmarkIPConflicted(ctx context.Context, poolName, ip string) error
 }
 
+// maxDerivationAttempts is the number of the hashed IPv6 addresses tried
+// for a NIC before the derived IPPool is considered used out.
+const maxDerivationAttempts = 16
+
 type ipPoolManager struct {
 	config     IPPoolManagerConfig
 	client     client.Client
 	apiReader  client.Reader
 	rIPManager reservedipmanager.ReservedIPManager
+	store      AllocationStore
 }
 
 func NewIPPoolManager(config IPPoolManagerConfig, client client.Client, apiReader client.Reader, rIPManager reservedipmanager.ReservedIPManager) (IPPoolManager, error) {
@@ -54,11 +69,17 @@ func NewIPPoolManager(config IPPoolManagerConfig, client client.Client, apiReade
 		return nil, fmt.Errorf("reserved-IP manager %w", constant.ErrMissingRequiredParam)
 	}
 
+	store, err := NewAllocationStore(client, apiReader)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ipPoolManager{
 		config:     setDefaultsForIPPoolManagerConfig(config),
 		client:     client,
 		apiReader:  apiReader,
 		rIPManager: rIPManager,
+		store:      store,
 	}, nil
 }
 
@@ -90,40 +111,135 @@ func (im *ipPoolManager) ListIPPools(ctx context.Context, cached bool, opts ...c
 	return &ipPoolList, nil
 }
 
-func (im *ipPoolManager) AllocateIP(ctx context.Context, poolName, nic string, pod *corev1.Pod) (*models.IPConfig, error) {
+func (im *ipPoolManager) AllocateIP(ctx context.Context, poolName, nic string, pod *corev1.Pod) (_ *models.IPConfig, err error) {
+	ctx, span := tracing.Start(ctx, "ippool AllocateIP",
+		attribute.String("ippool", poolName),
+		attribute.String("nic", nic),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	return im.allocate(ctx, span, poolName, nic, nil, nil, pod)
+}
+
+// AllocateStaticIP allocates the specified IP address of the IPPool, and
+// fails rather than allocating another one if it is unavailable. It succeeds
+// without recording again if the IP address is already allocated to the NIC
+// of the Pod.
+func (im *ipPoolManager) AllocateStaticIP(ctx context.Context, poolName, nic string, ip net.IP, pod *corev1.Pod) (_ *models.IPConfig, err error) {
+	ctx, span := tracing.Start(ctx, "ippool AllocateStaticIP",
+		attribute.String("ippool", poolName),
+		attribute.String("nic", nic),
+		attribute.String("staticIP", ip.String()),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	return im.allocate(ctx, span, poolName, nic, ip, nil, pod)
+}
+
+// AllocateDerivedIP allocates the IPv6 address derived from the MAC address
+// of the NIC, or from the Pod if the MAC address is unknown. It falls back to
+// AllocateIP if the IPPool does not derive its IPv6 addresses.
+func (im *ipPoolManager) AllocateDerivedIP(ctx context.Context, poolName, nic string, mac net.HardwareAddr, pod *corev1.Pod) (_ *models.IPConfig, err error) {
+	ctx, span := tracing.Start(ctx, "ippool AllocateDerivedIP",
+		attribute.String("ippool", poolName),
+		attribute.String("nic", nic),
+		attribute.String("mac", mac.String()),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	return im.allocate(ctx, span, poolName, nic, nil, mac, pod)
+}
+
+// allocate allocates a random IP address of the IPPool if staticIP is nil,
+// or the derived one if the IPPool derives its IPv6 addresses.
+func (im *ipPoolManager) allocate(ctx context.Context, span trace.Span, poolName, nic string, staticIP net.IP, mac net.HardwareAddr, pod *corev1.Pod) (*models.IPConfig, error) {
 	logger := logutils.FromContext(ctx)
 
+	key, err := cache.MetaNamespaceKeyFunc(pod)
+	if err != nil {
+		return nil, err
+	}
+	allocation := spiderpoolv2beta1.PoolIPAllocation{
+		NIC:            nic,
+		NamespacedName: key,
+		PodUID:         string(pod.UID),
+	}
+
 	backoff := retry.DefaultRetry
 	steps := backoff.Steps
 	var ipConfig *models.IPConfig
-	err := retry.RetryOnConflictWithContext(ctx, backoff, func(ctx context.Context) error {
+	err = retry.OnErrorWithContext(ctx, backoff, isAllocationRetriable, func(ctx context.Context) error {
 		logger := logger.With(
 			zap.String("IPPoolName", poolName),
 			zap.Int("Times", steps-backoff.Steps+1),
 		)
+		span.AddEvent("attempt", trace.WithAttributes(attribute.Int("times", steps-backoff.Steps+1)))
 		logger.Debug("Re-get IPPool for IP allocation")
 		ipPool, err := im.GetIPPoolByName(ctx, poolName, constant.IgnoreCache)
 		if err != nil {
 			return err
 		}
 
-		logger.Debug("Generate a random IP address")
-		allocatedIP, err := im.genRandomIP(ctx, nic, ipPool, pod)
-		if err != nil {
-			return err
+		allocatedIP := staticIP
+		switch {
+		case staticIP == nil && IsDerivedIPPool(ipPool):
+			logger.Sugar().Debugf("Derive an IPv6 address by %s", *ipPool.Spec.IPv6Derivation)
+			var allocated bool
+			allocatedIP, allocated, err = im.genDerivedIP(ctx, ipPool, mac, allocation)
+			if err != nil {
+				return err
+			}
+			if allocated {
+				logger.Sugar().Infof("Derived IP %s has been allocated to NIC %s of the Pod", allocatedIP, nic)
+				ipConfig, err = im.genAllocatedIPConfig(ctx, ipPool, allocatedIP, nic)
+				return err
+			}
+		case staticIP == nil:
+			logger.Debug("Generate a random IP address")
+			allocatedIP, err = im.genRandomIP(ctx, ipPool)
+			if err != nil {
+				return err
+			}
+		default:
+			logger.Sugar().Debugf("Check the availability of static IP %s", staticIP)
+			allocated, err := im.checkStaticIP(ctx, ipPool, staticIP, allocation)
+			if err != nil {
+				return err
+			}
+			if allocated {
+				logger.Sugar().Infof("Static IP %s has been allocated to NIC %s of the Pod", staticIP, nic)
+				ipConfig, err = im.genAllocatedIPConfig(ctx, ipPool, staticIP, nic)
+				return err
+			}
+		}
+
+		allocation.MAC = ""
+		if ipPool.Spec.MACAllocation != nil {
+			mac, err := im.genMAC(ctx, ipPool, allocatedIP, allocation)
+			if err != nil {
+				return err
+			}
+			logger.Sugar().Debugf("Generate MAC address %s for IP %s", mac, allocatedIP)
+			allocation.MAC = mac.String()
 		}
 
 		resourceVersion := ipPool.ResourceVersion
 		logger.With(zap.String("IPPool-ResourceVersion", resourceVersion)).
-			Sugar().Debugf("Try to update the allocation status of IPPool using random IP %s", allocatedIP)
-		if err := im.client.Status().Update(ctx, ipPool); err != nil {
+			Sugar().Debugf("Try to record the allocation of IP %s", allocatedIP)
+		if err := im.store.Allocate(ctx, ipPool, allocatedIP.String(), allocation); err != nil {
 			if apierrors.IsConflict(err) {
 				metric.IpamAllocationUpdateIPPoolConflictCounts.Add(ctx, 1)
+				span.AddEvent("conflict", trace.WithAttributes(attribute.String("resourceVersion", resourceVersion)))
 				logger.With(zap.String("IPPool-ResourceVersion", resourceVersion)).Warn("An conflict occurred when updating the status of IPPool")
 			}
+			if apierrors.IsAlreadyExists(err) {
+				logger.Sugar().Debugf("IP address %s is claimed concurrently", allocatedIP)
+			}
 			return err
 		}
 		ipConfig = convert.GenIPConfigResult(allocatedIP, nic, ipPool)
+		ipConfig.Mac = allocation.MAC
+		span.SetAttributes(attribute.String("ip", allocatedIP.String()))
 
 		return nil
 	})
@@ -137,21 +253,35 @@ func (im *ipPoolManager) AllocateIP(ctx context.Context, poolName, nic string, p
 	return ipConfig, nil
 }
 
-func (im *ipPoolManager) genRandomIP(ctx context.Context, nic string, ipPool *spiderpoolv2beta1.SpiderIPPool, pod *corev1.Pod) (net.IP, error) {
+// isAllocationRetriable returns true if the IPPool is updated, or the IP
+// address is claimed by others in the meantime.
+func isAllocationRetriable(err error) bool {
+	return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+}
+
+func (im *ipPoolManager) genRandomIP(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool) (net.IP, error) {
 	reservedIPs, err := im.rIPManager.AssembleReservedIPs(ctx, *ipPool.Spec.IPVersion)
 	if err != nil {
 		return nil, err
 	}
 
-	allocatedRecords, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
+	allocatedRecords, err := im.store.ListAllocations(ctx, ipPool)
 	if err != nil {
 		return nil, err
 	}
 
+	// SpiderIPClaims are not limited by the size of a single object.
+	if !IsIPClaimStorage(ipPool) && len(allocatedRecords) >= *im.config.MaxAllocatedIPs {
+		return nil, fmt.Errorf("%w, threshold of IP records(<=%d) for IPPool %s exceeded", constant.ErrIPUsedOut, *im.config.MaxAllocatedIPs, ipPool.Name)
+	}
+
 	var used []string
 	for ip := range allocatedRecords {
 		used = append(used, ip)
 	}
+	for ip := range ipPool.Status.ConflictIPs {
+		used = append(used, ip)
+	}
 	usedIPs, err := spiderpoolip.ParseIPRanges(*ipPool.Spec.IPVersion, used)
 	if err != nil {
 		return nil, err
@@ -166,46 +296,190 @@ func (im *ipPoolManager) genRandomIP(ctx context.Context, nic string, ipPool *sp
 	if len(availableIPs) == 0 {
 		return nil, constant.ErrIPUsedOut
 	}
-	resIP := availableIPs[0]
 
-	key, err := cache.MetaNamespaceKeyFunc(pod)
+	// Concurrent allocations from the same IPPool only collide on the same
+	// SpiderIPClaim, so spread them over the available IP addresses.
+	if IsIPClaimStorage(ipPool) {
+		return availableIPs[rand.Intn(len(availableIPs))], nil
+	}
+
+	return availableIPs[0], nil
+}
+
+// genDerivedIP derives the IPv6 address of the IPPool for the NIC of the Pod.
+// The EUI-64 address of the MAC address is the only candidate, while the
+// hashed address is re-hashed up to maxDerivationAttempts times if it is
+// unavailable. It returns true if the IPv6 address is already allocated to
+// the same NIC of the same Pod.
+func (im *ipPoolManager) genDerivedIP(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool, mac net.HardwareAddr, allocation spiderpoolv2beta1.PoolIPAllocation) (net.IP, bool, error) {
+	logger := logutils.FromContext(ctx)
+
+	if *ipPool.Spec.IPv6Derivation == constant.IPv6DerivationEUI64 {
+		if len(mac) != 0 {
+			ip, err := spiderpoolip.DeriveEUI64IP(ipPool.Spec.Subnet, mac)
+			if err != nil {
+				return nil, false, err
+			}
+			allocated, err := im.checkStaticIP(ctx, ipPool, ip, allocation)
+			if err != nil {
+				return nil, false, fmt.Errorf("EUI-64 IP %s of MAC address %s: %w", ip, mac, err)
+			}
+
+			return ip, allocated, nil
+		}
+		logger.Sugar().Warnf("The MAC address of NIC %s is unknown, derive the IPv6 address by hash instead", allocation.NIC)
+	}
+
+	key := allocation.NamespacedName + "/" + allocation.NIC
+	for attempt := 0; attempt < maxDerivationAttempts; attempt++ {
+		ip, err := spiderpoolip.DeriveHashIP(ipPool.Spec.Subnet, key, attempt)
+		if err != nil {
+			return nil, false, err
+		}
+		if spiderpoolip.IsReservedInterfaceID(ip) {
+			continue
+		}
+
+		allocated, err := im.checkStaticIP(ctx, ipPool, ip, allocation)
+		if err != nil {
+			if errors.Is(err, constant.ErrIPUnavailable) || errors.Is(err, constant.ErrWrongInput) {
+				logger.Sugar().Debugf("Hashed IP %s is unavailable, re-hash it: %v", ip, err)
+				continue
+			}
+			return nil, false, err
+		}
+
+		return ip, allocated, nil
+	}
+
+	return nil, false, fmt.Errorf("%w, all of the %d hashed IP addresses of IPPool %s for NIC %s of Pod %s are unavailable",
+		constant.ErrIPUsedOut, maxDerivationAttempts, ipPool.Name, allocation.NIC, allocation.NamespacedName)
+}
+
+// genMAC generates the MAC address allocated along with the IP address. The
+// MAC address made up of the IP address is the only candidate, while the
+// hashed one is re-hashed up to maxDerivationAttempts times if it is used by
+// another NIC.
+func (im *ipPoolManager) genMAC(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool, ip net.IP, allocation spiderpoolv2beta1.PoolIPAllocation) (net.HardwareAddr, error) {
+	logger := logutils.FromContext(ctx)
+
+	allocatedRecords, err := im.store.ListAllocations(ctx, ipPool)
 	if err != nil {
 		return nil, err
 	}
+	usedMACs := map[string]spiderpoolv2beta1.PoolIPAllocation{}
+	for _, record := range allocatedRecords {
+		if record.MAC != "" {
+			usedMACs[record.MAC] = record
+		}
+	}
+	isUsed := func(mac net.HardwareAddr) (spiderpoolv2beta1.PoolIPAllocation, bool) {
+		record, ok := usedMACs[mac.String()]
+		if !ok || (record.NamespacedName == allocation.NamespacedName && record.NIC == allocation.NIC) {
+			return record, false
+		}
+		return record, true
+	}
+
+	macAllocation := ipPool.Spec.MACAllocation
+	if macAllocation.Source == nil || *macAllocation.Source == constant.MACSourceIP {
+		mac, err := spiderpoolip.GenMACFromIP(macAllocation.Prefix, ip)
+		if err != nil {
+			return nil, err
+		}
+		if record, used := isUsed(mac); used {
+			return nil, fmt.Errorf("%w: MAC address %s of IP %s is allocated to NIC %s of Pod %s in IPPool %s",
+				constant.ErrIPUnavailable, mac, ip, record.NIC, record.NamespacedName, ipPool.Name)
+		}
 
-	if allocatedRecords == nil {
-		allocatedRecords = spiderpoolv2beta1.PoolIPAllocations{}
+		return mac, nil
 	}
-	allocatedRecords[resIP.String()] = spiderpoolv2beta1.PoolIPAllocation{
-		NIC:            nic,
-		NamespacedName: key,
-		PodUID:         string(pod.UID),
+
+	key := allocation.NamespacedName + "/" + allocation.NIC
+	for attempt := 0; attempt < maxDerivationAttempts; attempt++ {
+		mac, err := spiderpoolip.GenMACFromHash(macAllocation.Prefix, key, attempt)
+		if err != nil {
+			return nil, err
+		}
+		if record, used := isUsed(mac); used {
+			logger.Sugar().Debugf("Hashed MAC address %s is allocated to NIC %s of Pod %s, re-hash it", mac, record.NIC, record.NamespacedName)
+			continue
+		}
+
+		return mac, nil
 	}
 
-	data, err := convert.MarshalIPPoolAllocatedIPs(allocatedRecords)
+	return nil, fmt.Errorf("%w, all of the %d hashed MAC addresses of IPPool %s for NIC %s of Pod %s are allocated",
+		constant.ErrIPUsedOut, maxDerivationAttempts, ipPool.Name, allocation.NIC, allocation.NamespacedName)
+}
+
+// genAllocatedIPConfig returns the IP config of the IP address which is
+// already allocated, with the MAC address allocated along with it.
+func (im *ipPoolManager) genAllocatedIPConfig(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool, ip net.IP, nic string) (*models.IPConfig, error) {
+	allocatedRecords, err := im.store.ListAllocations(ctx, ipPool)
 	if err != nil {
 		return nil, err
 	}
-	ipPool.Status.AllocatedIPs = data
 
-	if ipPool.Status.AllocatedIPCount == nil {
-		ipPool.Status.AllocatedIPCount = new(int64)
+	ipConfig := convert.GenIPConfigResult(ip, nic, ipPool)
+	ipConfig.Mac = allocatedRecords[ip.String()].MAC
+
+	return ipConfig, nil
+}
+
+// checkStaticIP returns true if the IP address is already allocated to the
+// same NIC of the same Pod, or an error if it is not available.
+func (im *ipPoolManager) checkStaticIP(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool, ip net.IP, allocation spiderpoolv2beta1.PoolIPAllocation) (bool, error) {
+	if !IsIPInIPPool(ipPool, ip) {
+		return false, fmt.Errorf("%w: static IP %s is not within IPPool %s", constant.ErrWrongInput, ip, ipPool.Name)
+	}
+
+	reservedIPs, err := im.rIPManager.AssembleReservedIPs(ctx, *ipPool.Spec.IPVersion)
+	if err != nil {
+		return false, err
+	}
+	for _, reservedIP := range reservedIPs {
+		if reservedIP.Equal(ip) {
+			return false, fmt.Errorf("%w: static IP %s is reserved by SpiderReservedIP", constant.ErrIPUnavailable, ip)
+		}
+	}
+
+	if _, ok := ipPool.Status.ConflictIPs[ip.String()]; ok {
+		return false, fmt.Errorf("%w: static IP %s is marked as conflicted in IPPool %s", constant.ErrIPUnavailable, ip, ipPool.Name)
+	}
+
+	allocatedRecords, err := im.store.ListAllocations(ctx, ipPool)
+	if err != nil {
+		return false, err
+	}
+	if record, ok := allocatedRecords[ip.String()]; ok {
+		if record.PodUID == allocation.PodUID && record.NIC == allocation.NIC {
+			return true, nil
+		}
+		return false, fmt.Errorf("%w: static IP %s of IPPool %s is allocated to NIC %s of Pod %s (UID %s)",
+			constant.ErrIPUnavailable, ip, ipPool.Name, record.NIC, record.NamespacedName, record.PodUID)
 	}
 
-	*ipPool.Status.AllocatedIPCount++
-	if *ipPool.Status.AllocatedIPCount > int64(*im.config.MaxAllocatedIPs) {
-		return nil, fmt.Errorf("%w, threshold of IP records(<=%d) for IPPool %s exceeded", constant.ErrIPUsedOut, im.config.MaxAllocatedIPs, ipPool.Name)
+	if !IsIPClaimStorage(ipPool) && len(allocatedRecords) >= *im.config.MaxAllocatedIPs {
+		return false, fmt.Errorf("%w, threshold of IP records(<=%d) for IPPool %s exceeded", constant.ErrIPUsedOut, *im.config.MaxAllocatedIPs, ipPool.Name)
 	}
 
-	return resIP, nil
+	return false, nil
+}
+
+func (im *ipPoolManager) ListAllocatedIPs(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool) (spiderpoolv2beta1.PoolIPAllocations, error) {
+	return im.store.ListAllocations(ctx, ipPool)
 }
 
-func (im *ipPoolManager) ReleaseIP(ctx context.Context, poolName string, ipAndUIDs []types.IPAndUID) error {
+func (im *ipPoolManager) ReleaseIP(ctx context.Context, poolName string, ipAndUIDs []types.IPAndUID) (err error) {
 	logger := logutils.FromContext(ctx)
 
+	ctx, span := tracing.Start(ctx, "ippool ReleaseIP", attribute.String("ippool", poolName))
+	defer func() { tracing.End(span, err) }()
+
 	backoff := retry.DefaultRetry
 	steps := backoff.Steps
-	err := retry.RetryOnConflictWithContext(ctx, backoff, func(ctx context.Context) error {
+	err = retry.RetryOnConflictWithContext(ctx, backoff, func(ctx context.Context) error {
 		logger := logger.With(
 			zap.String("IPPoolName", poolName),
 			zap.Int("Times", steps-backoff.Steps+1),
@@ -216,43 +490,51 @@ func (im *ipPoolManager) ReleaseIP(ctx context.Context, poolName string, ipAndUI
 			return err
 		}
 
-		allocatedRecords, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
-		if err != nil {
+		resourceVersion := ipPool.ResourceVersion
+		logger.With(zap.String("IPPool-ResourceVersion", resourceVersion)).
+			Sugar().Debugf("Try to clean the IP allocation records of IPPool with IP addresses %+v", ipAndUIDs)
+		if err := im.store.Release(ctx, ipPool, ipAndUIDs); err != nil {
+			if apierrors.IsConflict(err) {
+				metric.IpamReleaseUpdateIPPoolConflictCounts.Add(ctx, 1)
+				span.AddEvent("conflict", trace.WithAttributes(attribute.String("resourceVersion", resourceVersion)))
+				logger.With(zap.String("IPPool-ResourceVersion", resourceVersion)).Warn("An conflict occurred when cleaning the IP allocation records of IPPool")
+			}
 			return err
 		}
 
-		if ipPool.Status.AllocatedIPCount == nil {
-			ipPool.Status.AllocatedIPCount = new(int64)
+		return nil
+	})
+	if err != nil {
+		if err == wait.ErrWaitTimeout {
+			err = fmt.Errorf("%w (%d times), failed to release IP addresses %+v from IPPool %s", constant.ErrRetriesExhausted, steps, ipAndUIDs, poolName)
 		}
+		return err
+	}
 
-		release := false
-		for _, iu := range ipAndUIDs {
-			if record, ok := allocatedRecords[iu.IP]; ok {
-				if record.PodUID == iu.UID {
-					delete(allocatedRecords, iu.IP)
-					*ipPool.Status.AllocatedIPCount--
-					release = true
-				}
-			}
-		}
+	return nil
+}
 
-		if !release {
-			return nil
-		}
+func (im *ipPoolManager) UpdateAllocatedIPs(ctx context.Context, poolName string, ipAndUIDs []types.IPAndUID) error {
+	logger := logutils.FromContext(ctx)
 
-		data, err := convert.MarshalIPPoolAllocatedIPs(allocatedRecords)
+	backoff := retry.DefaultRetry
+	steps := backoff.Steps
+	err := retry.RetryOnConflictWithContext(ctx, backoff, func(ctx context.Context) error {
+		logger := logger.With(
+			zap.String("IPPoolName", poolName),
+			zap.Int("Times", steps-backoff.Steps+1),
+		)
+
+		ipPool, err := im.GetIPPoolByName(ctx, poolName, constant.IgnoreCache)
 		if err != nil {
 			return err
 		}
-		ipPool.Status.AllocatedIPs = data
 
 		resourceVersion := ipPool.ResourceVersion
-		logger.With(zap.String("IPPool-ResourceVersion", resourceVersion)).
-			Sugar().Debugf("Try to clean the IP allocation records of IPPool with IP addresses %+v", ipAndUIDs)
-		if err := im.client.Status().Update(ctx, ipPool); err != nil {
+		if err := im.store.Rebind(ctx, ipPool, ipAndUIDs); err != nil {
 			if apierrors.IsConflict(err) {
-				metric.IpamReleaseUpdateIPPoolConflictCounts.Add(ctx, 1)
-				logger.With(zap.String("IPPool-ResourceVersion", resourceVersion)).Warn("An conflict occurred when cleaning the IP allocation records of IPPool")
+				metric.IpamAllocationUpdateIPPoolConflictCounts.Add(ctx, 1)
+				logger.With(zap.String("IPPool-ResourceVersion", resourceVersion)).Warn("An conflict occurred when updating the status of IPPool")
 			}
 			return err
 		}
@@ -261,7 +543,7 @@ func (im *ipPoolManager) ReleaseIP(ctx context.Context, poolName string, ipAndUI
 	})
 	if err != nil {
 		if err == wait.ErrWaitTimeout {
-			err = fmt.Errorf("%w (%d times), failed to release IP addresses %+v from IPPool %s", constant.ErrRetriesExhausted, steps, ipAndUIDs, poolName)
+			err = fmt.Errorf("%w (%d times), failed to re-allocate the IP addresses %+v from IPPool %s", constant.ErrRetriesExhausted, steps, ipAndUIDs, poolName)
 		}
 		return err
 	}
@@ -269,7 +551,7 @@ func (im *ipPoolManager) ReleaseIP(ctx context.Context, poolName string, ipAndUI
 	return nil
 }
 
-func (im *ipPoolManager) UpdateAllocatedIPs(ctx context.Context, poolName string, ipAndUIDs []types.IPAndUID) error {
+func (im *ipPoolManager) MarkIPConflicted(ctx context.Context, poolName string, ipAndUID types.IPAndUID, conflict spiderpoolv2beta1.PoolIPConflict) error {
 	logger := logutils.FromContext(ctx)
 
 	backoff := retry.DefaultRetry
@@ -285,36 +567,65 @@ func (im *ipPoolManager) UpdateAllocatedIPs(ctx context.Context, poolName string
 			return err
 		}
 
-		allocatedRecords, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
-		if err != nil {
+		// The conflicting IP address will never be used by the Pod, so clean
+		// its allocation record at the same time.
+		if err := im.store.Release(ctx, ipPool, []types.IPAndUID{ipAndUID}); err != nil {
 			return err
 		}
 
-		recreate := false
-		for _, iu := range ipAndUIDs {
-			if record, ok := allocatedRecords[iu.IP]; ok {
-				if record.PodUID != iu.UID {
-					record.PodUID = iu.UID
-					allocatedRecords[iu.IP] = record
-					recreate = true
-				}
+		if ipPool.Status.ConflictIPs == nil {
+			ipPool.Status.ConflictIPs = spiderpoolv2beta1.PoolIPConflicts{}
+		}
+		ipPool.Status.ConflictIPs[ipAndUID.IP] = conflict
+
+		resourceVersion := ipPool.ResourceVersion
+		logger.With(zap.String("IPPool-ResourceVersion", resourceVersion)).
+			Sugar().Debugf("Try to mark IP address %s as conflicted with %s", ipAndUID.IP, conflict.MAC)
+		if err := im.client.Status().Update(ctx, ipPool); err != nil {
+			if apierrors.IsConflict(err) {
+				logger.With(zap.String("IPPool-ResourceVersion", resourceVersion)).Warn("An conflict occurred when updating the status of IPPool")
 			}
+			return err
 		}
 
-		if !recreate {
-			return nil
+		return nil
+	})
+	if err != nil {
+		if err == wait.ErrWaitTimeout {
+			err = fmt.Errorf("%w (%d times), failed to mark IP address %s of IPPool %s as conflicted", constant.ErrRetriesExhausted, steps, ipAndUID.IP, poolName)
 		}
+		return err
+	}
+
+	return nil
+}
 
-		data, err := convert.MarshalIPPoolAllocatedIPs(allocatedRecords)
+func (im *ipPoolManager) UnmarkIPConflicted(ctx context.Context, poolName, ip string) error {
+	logger := logutils.FromContext(ctx)
+
+	backoff := retry.DefaultRetry
+	steps := backoff.Steps
+	err := retry.RetryOnConflictWithContext(ctx, backoff, func(ctx context.Context) error {
+		logger := logger.With(
+			zap.String("IPPoolName", poolName),
+			zap.Int("Times", steps-backoff.Steps+1),
+		)
+
+		ipPool, err := im.GetIPPoolByName(ctx, poolName, constant.IgnoreCache)
 		if err != nil {
 			return err
 		}
-		ipPool.Status.AllocatedIPs = data
+
+		if _, ok := ipPool.Status.ConflictIPs[ip]; !ok {
+			return nil
+		}
+		delete(ipPool.Status.ConflictIPs, ip)
 
 		resourceVersion := ipPool.ResourceVersion
+		logger.With(zap.String("IPPool-ResourceVersion", resourceVersion)).
+			Sugar().Debugf("Try to clean the conflict mark of IP address %s", ip)
 		if err := im.client.Status().Update(ctx, ipPool); err != nil {
 			if apierrors.IsConflict(err) {
-				metric.IpamAllocationUpdateIPPoolConflictCounts.Add(ctx, 1)
 				logger.With(zap.String("IPPool-ResourceVersion", resourceVersion)).Warn("An conflict occurred when updating the status of IPPool")
 			}
 			return err
@@ -324,7 +635,7 @@ func (im *ipPoolManager) UpdateAllocatedIPs(ctx context.Context, poolName string
 	})
 	if err != nil {
 		if err == wait.ErrWaitTimeout {
-			err = fmt.Errorf("%w (%d times), failed to re-allocate the IP addresses %+v from IPPool %s", constant.ErrRetriesExhausted, steps, ipAndUIDs, poolName)
+			err = fmt.Errorf("%w (%d times), failed to clean the conflict mark of IP address %s of IPPool %s", constant.ErrRetriesExhausted, steps, ip, poolName)
 		}
 		return err
 	}
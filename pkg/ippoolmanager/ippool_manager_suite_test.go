@@ -65,6 +65,10 @@ var _ = BeforeSuite(func() {
 			ipPool := raw.(*spiderpoolv2beta1.SpiderIPPool)
 			return []string{strconv.FormatBool(*ipPool.Spec.Default)}
 		}).
+		WithIndex(&spiderpoolv2beta1.SpiderIPClaim{}, ippoolmanager.IPClaimIPPoolIndex, func(raw client.Object) []string {
+			ipClaim := raw.(*spiderpoolv2beta1.SpiderIPClaim)
+			return []string{ipClaim.Spec.IPPool}
+		}).
 		Build()
 
 	tracker = k8stesting.NewObjectTracker(scheme, k8sscheme.Codecs.UniversalDecoder())
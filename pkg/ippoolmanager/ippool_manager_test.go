@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
 	"sync/atomic"
 
 	"github.com/agiledragon/gomonkey/v2"
@@ -23,6 +24,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolip "github.com/spidernet-io/spiderpool/pkg/ip"
 	"github.com/spidernet-io/spiderpool/pkg/ippoolmanager"
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
 	spiderpooltypes "github.com/spidernet-io/spiderpool/pkg/types"
@@ -364,6 +366,430 @@ var _ = Describe("IPPoolManager", Label("ippool_manager_test"), func() {
 				Expect(res.Gateway).To(Equal(gateway))
 				Expect(res.Vlan).To(Equal(vlan))
 			})
+			It("does not allocate the conflicting IP address", func() {
+				mockRIPManager.EXPECT().
+					AssembleReservedIPs(gomock.Eq(ctx), gomock.Eq(constant.IPv4)).
+					Return(nil, nil).
+					Times(1)
+
+				ipPoolT.Spec.IPVersion = pointer.Int64(constant.IPv4)
+				ipPoolT.Spec.Subnet = "172.18.40.0/24"
+				ipPoolT.Spec.IPs = append(ipPoolT.Spec.IPs, "172.18.40.40-172.18.40.41")
+				ipPoolT.Spec.Vlan = pointer.Int64(0)
+				ipPoolT.Status.ConflictIPs = spiderpoolv2beta1.PoolIPConflicts{
+					"172.18.40.40": {MAC: "00:00:00:00:00:01", Node: "node1"},
+				}
+
+				err := fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				res, err := ipPoolManager.AllocateIP(ctx, ipPoolName, nic, podT)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res.Address).To(Equal("172.18.40.41/24"))
+			})
+		})
+
+		Describe("AllocateStaticIP", func() {
+			var nic string
+			var staticIP net.IP
+			var podT *corev1.Pod
+
+			BeforeEach(func() {
+				nic = "eth0"
+				staticIP = net.ParseIP("172.18.40.41")
+				podT = &corev1.Pod{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Pod",
+						APIVersion: corev1.SchemeGroupVersion.String(),
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod",
+						Namespace: "default",
+						UID:       uuid.NewUUID(),
+					},
+					Spec: corev1.PodSpec{},
+				}
+
+				ipPoolT.Spec.IPVersion = pointer.Int64(constant.IPv4)
+				ipPoolT.Spec.Subnet = "172.18.40.0/24"
+				ipPoolT.Spec.IPs = append(ipPoolT.Spec.IPs, "172.18.40.40-172.18.40.42")
+				ipPoolT.Spec.Vlan = pointer.Int64(0)
+			})
+
+			It("allocates the static IP address out of IPPool", func() {
+				ipPoolT.Spec.ExcludeIPs = append(ipPoolT.Spec.ExcludeIPs, staticIP.String())
+
+				err := fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				res, err := ipPoolManager.AllocateStaticIP(ctx, ipPoolName, nic, staticIP, podT)
+				Expect(err).To(MatchError(constant.ErrWrongInput))
+				Expect(res).To(BeNil())
+			})
+
+			It("allocates the reserved IP address", func() {
+				mockRIPManager.EXPECT().
+					AssembleReservedIPs(gomock.Eq(ctx), gomock.Eq(constant.IPv4)).
+					Return([]net.IP{staticIP}, nil).
+					Times(1)
+
+				err := fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				res, err := ipPoolManager.AllocateStaticIP(ctx, ipPoolName, nic, staticIP, podT)
+				Expect(err).To(MatchError(constant.ErrIPUnavailable))
+				Expect(res).To(BeNil())
+			})
+
+			It("allocates the IP address allocated to another Pod", func() {
+				mockRIPManager.EXPECT().
+					AssembleReservedIPs(gomock.Eq(ctx), gomock.Eq(constant.IPv4)).
+					Return(nil, nil).
+					Times(1)
+
+				data, err := convert.MarshalIPPoolAllocatedIPs(spiderpoolv2beta1.PoolIPAllocations{
+					staticIP.String(): spiderpoolv2beta1.PoolIPAllocation{
+						NIC:            nic,
+						NamespacedName: "default/other",
+						PodUID:         string(uuid.NewUUID()),
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				ipPoolT.Status.AllocatedIPs = data
+
+				err = fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				res, err := ipPoolManager.AllocateStaticIP(ctx, ipPoolName, nic, staticIP, podT)
+				Expect(err).To(MatchError(constant.ErrIPUnavailable))
+				Expect(res).To(BeNil())
+			})
+
+			It("allocates the IP address already allocated to the Pod", func() {
+				mockRIPManager.EXPECT().
+					AssembleReservedIPs(gomock.Eq(ctx), gomock.Eq(constant.IPv4)).
+					Return(nil, nil).
+					Times(1)
+
+				data, err := convert.MarshalIPPoolAllocatedIPs(spiderpoolv2beta1.PoolIPAllocations{
+					staticIP.String(): spiderpoolv2beta1.PoolIPAllocation{
+						NIC:            nic,
+						NamespacedName: "default/pod",
+						PodUID:         string(podT.UID),
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				ipPoolT.Status.AllocatedIPs = data
+
+				err = fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				patches := gomonkey.ApplyMethodReturn(fakeClient, "Update", constant.ErrUnknown)
+				defer patches.Reset()
+
+				res, err := ipPoolManager.AllocateStaticIP(ctx, ipPoolName, nic, staticIP, podT)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res.Address).To(Equal("172.18.40.41/24"))
+			})
+
+			It("allocates the static IP address", func() {
+				mockRIPManager.EXPECT().
+					AssembleReservedIPs(gomock.Eq(ctx), gomock.Eq(constant.IPv4)).
+					Return(nil, nil).
+					Times(1)
+
+				err := fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				res, err := ipPoolManager.AllocateStaticIP(ctx, ipPoolName, nic, staticIP, podT)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res.Nic).To(Equal(nic))
+				Expect(*res.Address).To(Equal("172.18.40.41/24"))
+				Expect(res.IPPool).To(Equal(ipPoolT.Name))
+			})
+		})
+
+		Describe("AllocateDerivedIP", func() {
+			var nic string
+			var mac net.HardwareAddr
+			var podT *corev1.Pod
+
+			BeforeEach(func() {
+				nic = "net1"
+				mac, _ = net.ParseMAC("00:1a:2b:3c:4d:5e")
+				podT = &corev1.Pod{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Pod",
+						APIVersion: corev1.SchemeGroupVersion.String(),
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "sts-0",
+						Namespace: "default",
+						UID:       uuid.NewUUID(),
+					},
+					Spec: corev1.PodSpec{},
+				}
+
+				ipPoolT.Spec.IPVersion = pointer.Int64(constant.IPv6)
+				ipPoolT.Spec.Subnet = "abcd:1234::/64"
+				ipPoolT.Spec.Vlan = pointer.Int64(0)
+			})
+
+			It("allocates the EUI-64 IP address", func() {
+				ipPoolT.Spec.IPv6Derivation = pointer.String(constant.IPv6DerivationEUI64)
+
+				mockRIPManager.EXPECT().
+					AssembleReservedIPs(gomock.Eq(ctx), gomock.Eq(constant.IPv6)).
+					Return(nil, nil).
+					Times(1)
+
+				err := fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				res, err := ipPoolManager.AllocateDerivedIP(ctx, ipPoolName, nic, mac, podT)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res.Address).To(Equal("abcd:1234::21a:2bff:fe3c:4d5e/64"))
+			})
+
+			It("allocates the EUI-64 IP address allocated to another Pod", func() {
+				ipPoolT.Spec.IPv6Derivation = pointer.String(constant.IPv6DerivationEUI64)
+
+				mockRIPManager.EXPECT().
+					AssembleReservedIPs(gomock.Eq(ctx), gomock.Eq(constant.IPv6)).
+					Return(nil, nil).
+					Times(1)
+
+				data, err := convert.MarshalIPPoolAllocatedIPs(spiderpoolv2beta1.PoolIPAllocations{
+					"abcd:1234::21a:2bff:fe3c:4d5e": spiderpoolv2beta1.PoolIPAllocation{
+						NIC:            nic,
+						NamespacedName: "default/other",
+						PodUID:         string(uuid.NewUUID()),
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				ipPoolT.Status.AllocatedIPs = data
+
+				err = fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				res, err := ipPoolManager.AllocateDerivedIP(ctx, ipPoolName, nic, mac, podT)
+				Expect(err).To(MatchError(constant.ErrIPUnavailable))
+				Expect(res).To(BeNil())
+			})
+
+			It("allocates the same hashed IP address to the Pod with the same name", func() {
+				ipPoolT.Spec.IPv6Derivation = pointer.String(constant.IPv6DerivationHash)
+
+				mockRIPManager.EXPECT().
+					AssembleReservedIPs(gomock.Eq(ctx), gomock.Eq(constant.IPv6)).
+					Return(nil, nil).
+					Times(1)
+
+				err := fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				res, err := ipPoolManager.AllocateDerivedIP(ctx, ipPoolName, nic, mac, podT)
+				Expect(err).NotTo(HaveOccurred())
+
+				ip, err := spiderpoolip.DeriveHashIP(ipPoolT.Spec.Subnet, "default/sts-0/net1", 0)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res.Address).To(Equal(ip.String() + "/64"))
+			})
+
+			It("re-hashes the IP address allocated to another Pod", func() {
+				ipPoolT.Spec.IPv6Derivation = pointer.String(constant.IPv6DerivationHash)
+
+				mockRIPManager.EXPECT().
+					AssembleReservedIPs(gomock.Eq(ctx), gomock.Eq(constant.IPv6)).
+					Return(nil, nil).
+					Times(2)
+
+				first, err := spiderpoolip.DeriveHashIP(ipPoolT.Spec.Subnet, "default/sts-0/net1", 0)
+				Expect(err).NotTo(HaveOccurred())
+				data, err := convert.MarshalIPPoolAllocatedIPs(spiderpoolv2beta1.PoolIPAllocations{
+					first.String(): spiderpoolv2beta1.PoolIPAllocation{
+						NIC:            nic,
+						NamespacedName: "default/other",
+						PodUID:         string(uuid.NewUUID()),
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				ipPoolT.Status.AllocatedIPs = data
+
+				err = fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				res, err := ipPoolManager.AllocateDerivedIP(ctx, ipPoolName, nic, nil, podT)
+				Expect(err).NotTo(HaveOccurred())
+
+				second, err := spiderpoolip.DeriveHashIP(ipPoolT.Spec.Subnet, "default/sts-0/net1", 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res.Address).To(Equal(second.String() + "/64"))
+			})
+		})
+
+		Describe("MAC allocation", func() {
+			var nic string
+			var staticIP net.IP
+			var podT *corev1.Pod
+
+			BeforeEach(func() {
+				nic = "eth0"
+				staticIP = net.ParseIP("172.18.40.41")
+				podT = &corev1.Pod{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Pod",
+						APIVersion: corev1.SchemeGroupVersion.String(),
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "sts-0",
+						Namespace: "default",
+						UID:       uuid.NewUUID(),
+					},
+					Spec: corev1.PodSpec{},
+				}
+
+				ipPoolT.Spec.IPVersion = pointer.Int64(constant.IPv4)
+				ipPoolT.Spec.Subnet = "172.18.40.0/24"
+				ipPoolT.Spec.IPs = append(ipPoolT.Spec.IPs, "172.18.40.40-172.18.40.42")
+				ipPoolT.Spec.Vlan = pointer.Int64(0)
+			})
+
+			It("allocates the MAC address made up of the IP address", func() {
+				ipPoolT.Spec.MACAllocation = &spiderpoolv2beta1.MACAllocation{Prefix: "0a:1b"}
+
+				mockRIPManager.EXPECT().
+					AssembleReservedIPs(gomock.Eq(ctx), gomock.Eq(constant.IPv4)).
+					Return(nil, nil).
+					Times(1)
+
+				err := fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				res, err := ipPoolManager.AllocateStaticIP(ctx, ipPoolName, nic, staticIP, podT)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Mac).To(Equal("0a:1b:ac:12:28:29"))
+
+				var ipPool spiderpoolv2beta1.SpiderIPPool
+				err = fakeClient.Get(ctx, types.NamespacedName{Name: ipPoolName}, &ipPool)
+				Expect(err).NotTo(HaveOccurred())
+
+				records, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(records[staticIP.String()].MAC).To(Equal("0a:1b:ac:12:28:29"))
+			})
+
+			It("allocates the hashed MAC address of the Pod", func() {
+				ipPoolT.Spec.MACAllocation = &spiderpoolv2beta1.MACAllocation{
+					Prefix: "0a:1b",
+					Source: pointer.String(constant.MACSourcePod),
+				}
+
+				mockRIPManager.EXPECT().
+					AssembleReservedIPs(gomock.Eq(ctx), gomock.Eq(constant.IPv4)).
+					Return(nil, nil).
+					Times(1)
+
+				err := fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				res, err := ipPoolManager.AllocateStaticIP(ctx, ipPoolName, nic, staticIP, podT)
+				Expect(err).NotTo(HaveOccurred())
+
+				mac, err := spiderpoolip.GenMACFromHash("0a:1b", "default/sts-0/eth0", 0)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Mac).To(Equal(mac.String()))
+			})
+
+			It("re-hashes the MAC address allocated to another Pod", func() {
+				ipPoolT.Spec.MACAllocation = &spiderpoolv2beta1.MACAllocation{
+					Prefix: "0a:1b",
+					Source: pointer.String(constant.MACSourcePod),
+				}
+
+				mockRIPManager.EXPECT().
+					AssembleReservedIPs(gomock.Eq(ctx), gomock.Eq(constant.IPv4)).
+					Return(nil, nil).
+					Times(1)
+
+				first, err := spiderpoolip.GenMACFromHash("0a:1b", "default/sts-0/eth0", 0)
+				Expect(err).NotTo(HaveOccurred())
+				data, err := convert.MarshalIPPoolAllocatedIPs(spiderpoolv2beta1.PoolIPAllocations{
+					"172.18.40.40": spiderpoolv2beta1.PoolIPAllocation{
+						NIC:            nic,
+						NamespacedName: "default/other",
+						PodUID:         string(uuid.NewUUID()),
+						MAC:            first.String(),
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				ipPoolT.Status.AllocatedIPs = data
+
+				err = fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				res, err := ipPoolManager.AllocateStaticIP(ctx, ipPoolName, nic, staticIP, podT)
+				Expect(err).NotTo(HaveOccurred())
+
+				second, err := spiderpoolip.GenMACFromHash("0a:1b", "default/sts-0/eth0", 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Mac).To(Equal(second.String()))
+			})
+
+			It("keeps the MAC address of the IP address allocated to the same Pod", func() {
+				ipPoolT.Spec.MACAllocation = &spiderpoolv2beta1.MACAllocation{Prefix: "0a:1b"}
+
+				mockRIPManager.EXPECT().
+					AssembleReservedIPs(gomock.Eq(ctx), gomock.Eq(constant.IPv4)).
+					Return(nil, nil).
+					Times(1)
+
+				data, err := convert.MarshalIPPoolAllocatedIPs(spiderpoolv2beta1.PoolIPAllocations{
+					staticIP.String(): spiderpoolv2beta1.PoolIPAllocation{
+						NIC:            nic,
+						NamespacedName: "default/sts-0",
+						PodUID:         string(podT.UID),
+						MAC:            "0a:1b:00:00:00:01",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				ipPoolT.Status.AllocatedIPs = data
+
+				err = fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				res, err := ipPoolManager.AllocateStaticIP(ctx, ipPoolName, nic, staticIP, podT)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.Mac).To(Equal("0a:1b:00:00:00:01"))
+			})
 		})
 
 		Describe("ReleaseIP", func() {
@@ -548,5 +974,265 @@ var _ = Describe("IPPoolManager", Label("ippool_manager_test"), func() {
 				Expect(newRecords[ip].PodUID).To(Equal(newUID))
 			})
 		})
+
+		Describe("MarkIPConflicted", func() {
+			var ip string
+			var uid string
+			var records spiderpoolv2beta1.PoolIPAllocations
+			var conflict spiderpoolv2beta1.PoolIPConflict
+
+			BeforeEach(func() {
+				ip = "172.18.40.40"
+				uid = string(uuid.NewUUID())
+				records = spiderpoolv2beta1.PoolIPAllocations{
+					ip: spiderpoolv2beta1.PoolIPAllocation{
+						NIC:            "eth0",
+						NamespacedName: "default/pod",
+						PodUID:         uid,
+					},
+				}
+				conflict = spiderpoolv2beta1.PoolIPConflict{
+					MAC:  "00:00:00:00:00:01",
+					Node: "node1",
+				}
+			})
+
+			It("marks the IP address of non-existent IPPool", func() {
+				err := ipPoolManager.MarkIPConflicted(ctx, ipPoolName, spiderpooltypes.IPAndUID{IP: ip, UID: uid}, conflict)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("runs out of retries to update IPPool, but conflicts still occur", func() {
+				patches := gomonkey.ApplyMethodReturn(fakeClient, "Update", apierrors.NewConflict(schema.GroupResource{Resource: "test"}, "other", nil))
+				defer patches.Reset()
+
+				err := fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = ipPoolManager.MarkIPConflicted(ctx, ipPoolName, spiderpooltypes.IPAndUID{IP: ip, UID: uid}, conflict)
+				Expect(err).To(MatchError(constant.ErrRetriesExhausted))
+			})
+
+			It("marks the IP address and cleans its allocation record", func() {
+				data, err := convert.MarshalIPPoolAllocatedIPs(records)
+				Expect(err).NotTo(HaveOccurred())
+
+				ipPoolT.Status.AllocatedIPs = data
+				ipPoolT.Status.AllocatedIPCount = pointer.Int64(1)
+				err = fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = ipPoolManager.MarkIPConflicted(ctx, ipPoolName, spiderpooltypes.IPAndUID{IP: ip, UID: uid}, conflict)
+				Expect(err).NotTo(HaveOccurred())
+
+				var ipPool spiderpoolv2beta1.SpiderIPPool
+				err = fakeClient.Get(ctx, types.NamespacedName{Name: ipPoolT.Name}, &ipPool)
+				Expect(err).NotTo(HaveOccurred())
+
+				newRecords, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(newRecords).To(BeEmpty())
+				Expect(*ipPool.Status.AllocatedIPCount).To(Equal(int64(0)))
+				Expect(ipPool.Status.ConflictIPs).To(HaveKeyWithValue(ip, conflict))
+			})
+
+			It("keeps the allocation record of another Pod", func() {
+				data, err := convert.MarshalIPPoolAllocatedIPs(records)
+				Expect(err).NotTo(HaveOccurred())
+
+				ipPoolT.Status.AllocatedIPs = data
+				err = fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = ipPoolManager.MarkIPConflicted(ctx, ipPoolName, spiderpooltypes.IPAndUID{IP: ip, UID: string(uuid.NewUUID())}, conflict)
+				Expect(err).NotTo(HaveOccurred())
+
+				var ipPool spiderpoolv2beta1.SpiderIPPool
+				err = fakeClient.Get(ctx, types.NamespacedName{Name: ipPoolT.Name}, &ipPool)
+				Expect(err).NotTo(HaveOccurred())
+
+				newRecords, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(newRecords).To(HaveKey(ip))
+			})
+		})
+
+		Describe("UnmarkIPConflicted", func() {
+			var ip string
+
+			BeforeEach(func() {
+				ip = "172.18.40.40"
+			})
+
+			It("unmarks the IP address of non-existent IPPool", func() {
+				err := ipPoolManager.UnmarkIPConflicted(ctx, ipPoolName, ip)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("unmarks the IP address which is not conflicting", func() {
+				err := tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = ipPoolManager.UnmarkIPConflicted(ctx, ipPoolName, ip)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("unmarks the conflicting IP address", func() {
+				ipPoolT.Status.ConflictIPs = spiderpoolv2beta1.PoolIPConflicts{
+					ip: {MAC: "00:00:00:00:00:01", Node: "node1"},
+				}
+				err := fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = ipPoolManager.UnmarkIPConflicted(ctx, ipPoolName, ip)
+				Expect(err).NotTo(HaveOccurred())
+
+				var ipPool spiderpoolv2beta1.SpiderIPPool
+				err = fakeClient.Get(ctx, types.NamespacedName{Name: ipPoolT.Name}, &ipPool)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ipPool.Status.ConflictIPs).NotTo(HaveKey(ip))
+			})
+		})
+
+		Describe("SpiderIPClaim storage", func() {
+			var ip string
+			var uid string
+			var claimT *spiderpoolv2beta1.SpiderIPClaim
+
+			BeforeEach(func() {
+				ip = "172.18.40.40"
+				uid = string(uuid.NewUUID())
+				ipPoolT.Spec.IPVersion = pointer.Int64(constant.IPv4)
+				ipPoolT.Spec.Subnet = "172.18.40.0/24"
+				ipPoolT.Spec.IPs = []string{"172.18.40.40-172.18.40.41"}
+				ipPoolT.Spec.Vlan = pointer.Int64(0)
+				ipPoolT.Spec.AllocationStorage = pointer.String(constant.IPPoolAllocationStorageIPClaim)
+				claimT = &spiderpoolv2beta1.SpiderIPClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: ippoolmanager.IPClaimName(ipPoolName, ip),
+					},
+					Spec: spiderpoolv2beta1.IPClaimSpec{
+						IPPool:         ipPoolName,
+						IP:             ip,
+						NIC:            "eth0",
+						NamespacedName: "default/pod",
+						PodUID:         uid,
+					},
+				}
+			})
+
+			It("allocates the IP address which is not claimed", func() {
+				mockRIPManager.EXPECT().
+					AssembleReservedIPs(gomock.Eq(ctx), gomock.Eq(constant.IPv4)).
+					Return(nil, nil).
+					Times(1)
+
+				err := fakeClient.Create(ctx, claimT)
+				Expect(err).NotTo(HaveOccurred())
+				err = fakeClient.Create(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				podT := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod1",
+						Namespace: "default",
+						UID:       uuid.NewUUID(),
+					},
+				}
+				res, err := ipPoolManager.AllocateIP(ctx, ipPoolName, "eth0", podT)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*res.Address).To(Equal("172.18.40.41/24"))
+
+				var ipClaim spiderpoolv2beta1.SpiderIPClaim
+				err = fakeClient.Get(ctx, types.NamespacedName{Name: ippoolmanager.IPClaimName(ipPoolName, "172.18.40.41")}, &ipClaim)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ipClaim.Spec.PodUID).To(Equal(string(podT.UID)))
+				Expect(ipClaim.OwnerReferences).To(HaveLen(1))
+
+				var ipPool spiderpoolv2beta1.SpiderIPPool
+				err = fakeClient.Get(ctx, types.NamespacedName{Name: ipPoolT.Name}, &ipPool)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ipPool.Status.AllocatedIPs).To(BeNil())
+			})
+
+			It("lists the SpiderIPClaims and the records not migrated yet", func() {
+				data, err := convert.MarshalIPPoolAllocatedIPs(spiderpoolv2beta1.PoolIPAllocations{
+					"172.18.40.41": {NIC: "eth0", NamespacedName: "default/pod1", PodUID: string(uuid.NewUUID())},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				ipPoolT.Status.AllocatedIPs = data
+
+				err = fakeClient.Create(ctx, claimT)
+				Expect(err).NotTo(HaveOccurred())
+
+				records, err := ipPoolManager.ListAllocatedIPs(ctx, ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(records).To(HaveLen(2))
+				Expect(records).To(HaveKeyWithValue(ip, spiderpoolv2beta1.PoolIPAllocation{NIC: "eth0", NamespacedName: "default/pod", PodUID: uid}))
+			})
+
+			It("releases the IP address by deleting its SpiderIPClaim", func() {
+				err := fakeClient.Create(ctx, claimT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(claimT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = ipPoolManager.ReleaseIP(ctx, ipPoolName, []spiderpooltypes.IPAndUID{{IP: ip, UID: string(uuid.NewUUID())}})
+				Expect(err).NotTo(HaveOccurred())
+				err = fakeClient.Get(ctx, types.NamespacedName{Name: claimT.Name}, &spiderpoolv2beta1.SpiderIPClaim{})
+				Expect(err).NotTo(HaveOccurred())
+
+				err = ipPoolManager.ReleaseIP(ctx, ipPoolName, []spiderpooltypes.IPAndUID{{IP: ip, UID: uid}})
+				Expect(err).NotTo(HaveOccurred())
+				err = fakeClient.Get(ctx, types.NamespacedName{Name: claimT.Name}, &spiderpoolv2beta1.SpiderIPClaim{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("re-binds the SpiderIPClaim to the new Pod", func() {
+				err := fakeClient.Create(ctx, claimT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(claimT)
+				Expect(err).NotTo(HaveOccurred())
+				err = tracker.Add(ipPoolT)
+				Expect(err).NotTo(HaveOccurred())
+
+				newUID := string(uuid.NewUUID())
+				err = ipPoolManager.UpdateAllocatedIPs(ctx, ipPoolName, []spiderpooltypes.IPAndUID{{IP: ip, UID: newUID}})
+				Expect(err).NotTo(HaveOccurred())
+
+				var ipClaim spiderpoolv2beta1.SpiderIPClaim
+				err = fakeClient.Get(ctx, types.NamespacedName{Name: claimT.Name}, &ipClaim)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ipClaim.Spec.PodUID).To(Equal(newUID))
+			})
+		})
+	})
+
+	Describe("IPClaimName", func() {
+		It("names the SpiderIPClaim of IPv4 address", func() {
+			Expect(ippoolmanager.IPClaimName("pool", "172.18.40.40")).To(Equal("pool-172.18.40.40"))
+		})
+
+		It("names the SpiderIPClaim of IPv6 address", func() {
+			Expect(ippoolmanager.IPClaimName("pool", "abcd:1234::1")).To(Equal("pool-abcd-1234-0000-0000-0000-0000-0000-0001"))
+		})
+
+		It("hashes the long name of IPPool", func() {
+			name := ippoolmanager.IPClaimName(strings.Repeat("a", 250), "172.18.40.40")
+			Expect(len(name)).To(BeNumerically("<=", 253))
+			Expect(name).To(HaveSuffix("-172.18.40.40"))
+		})
 	})
 })
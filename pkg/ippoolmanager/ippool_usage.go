@@ -0,0 +1,170 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ippoolmanager
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apitypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/event"
+	spiderpoolip "github.com/spidernet-io/spiderpool/pkg/ip"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/metric"
+	"github.com/spidernet-io/spiderpool/pkg/utilization"
+)
+
+// recordUsage reports the IP utilization of the IPPool with metrics, and
+// emits a Warning event once a utilization threshold is crossed.
+func (ic *IPPoolController) recordUsage(ctx context.Context, pool *spiderpoolv2beta1.SpiderIPPool) error {
+	if pool.Spec.IPVersion == nil {
+		return nil
+	}
+
+	usage, total, err := ic.computeUsage(ctx, pool)
+	if err != nil {
+		return err
+	}
+
+	forecast := ic.usageTracker.Observe(pool.Name, usage.Allocated, usage.Free)
+	usage.AllocationRate = forecast.AllocationRate
+	usage.ReleaseRate = forecast.ReleaseRate
+	usage.ExhaustionSeconds = forecast.Seconds()
+	metric.IPPoolUsage.Record(pool.Name, usage)
+
+	used := total - usage.Free
+	if threshold, crossed := ic.usageTracker.Cross(pool.Name, total, used); crossed {
+		event.EventRecorder.Event(pool, corev1.EventTypeWarning, constant.EventReasonIPUtilizationHigh, utilization.Message(total, used, threshold, forecast))
+	}
+
+	return nil
+}
+
+// forgetUsage stops reporting the IP utilization of the deleted IPPool.
+func (ic *IPPoolController) forgetUsage(poolName string) {
+	ic.usageTracker.Forget(poolName)
+	metric.IPPoolUsage.Delete(poolName)
+}
+
+// computeUsage classifies the IPs of the IPPool, and returns the number of
+// the IPs not excluded by 'spec.excludeIPs'. The IPs of the derived IPPool
+// are too many to classify one by one, all of them but the allocated and
+// conflicting ones are free.
+func (ic *IPPoolController) computeUsage(ctx context.Context, pool *spiderpoolv2beta1.SpiderIPPool) (metric.IPUsage, int64, error) {
+	version := *pool.Spec.IPVersion
+	usage := metric.IPUsage{AllocatedByNamespace: map[string]int64{}}
+
+	var allIPs, totalIPs []net.IP
+	if !IsDerivedIPPool(pool) {
+		var err error
+		allIPs, err = spiderpoolip.ParseIPRanges(version, pool.Spec.IPs)
+		if err != nil {
+			return usage, 0, err
+		}
+		totalIPs, err = spiderpoolip.AssembleTotalIPs(version, pool.Spec.IPs, pool.Spec.ExcludeIPs)
+		if err != nil {
+			return usage, 0, err
+		}
+		usage.Reserved = int64(len(allIPs) - len(totalIPs))
+	}
+
+	reservedIPs, err := ic.reservedIPs(ctx, version)
+	if err != nil {
+		return usage, 0, err
+	}
+
+	records, err := ic.store.ListAllocations(ctx, pool)
+	if err != nil {
+		return usage, 0, err
+	}
+	usage.Allocated = int64(len(records))
+
+	total := int64(len(totalIPs))
+	if IsDerivedIPPool(pool) {
+		total = DerivedIPPoolSize
+		usage.Quarantined = int64(len(pool.Status.ConflictIPs))
+		usage.Free = total - usage.Allocated - usage.Quarantined
+	}
+
+	for _, ip := range totalIPs {
+		s := ip.String()
+		if _, ok := records[s]; ok {
+			continue
+		}
+		if _, ok := pool.Status.ConflictIPs[s]; ok {
+			usage.Quarantined++
+			continue
+		}
+		if _, ok := reservedIPs[s]; ok {
+			usage.Reserved++
+			continue
+		}
+		usage.Free++
+	}
+
+	for _, record := range records {
+		namespace, name, found := strings.Cut(record.NamespacedName, "/")
+		if !found {
+			continue
+		}
+		usage.AllocatedByNamespace[namespace]++
+
+		leaked, err := ic.isLeaked(ctx, namespace, name, record.PodUID)
+		if err != nil {
+			return usage, 0, err
+		}
+		if leaked {
+			usage.Leaked++
+		}
+	}
+
+	return usage, total, nil
+}
+
+// reservedIPs returns the IPs of the SpiderReservedIPs in the IP version.
+func (ic *IPPoolController) reservedIPs(ctx context.Context, version int64) (map[string]struct{}, error) {
+	var rIPList spiderpoolv2beta1.SpiderReservedIPList
+	if err := ic.client.List(ctx, &rIPList); err != nil {
+		return nil, fmt.Errorf("failed to list SpiderReservedIPs: %w", err)
+	}
+
+	var ranges []string
+	for _, rIP := range rIPList.Items {
+		if rIP.DeletionTimestamp == nil && rIP.Spec.IPVersion != nil && *rIP.Spec.IPVersion == version {
+			ranges = append(ranges, rIP.Spec.IPs...)
+		}
+	}
+
+	ips, err := spiderpoolip.ParseIPRanges(version, ranges)
+	if err != nil {
+		return nil, err
+	}
+
+	reserved := make(map[string]struct{}, len(ips))
+	for _, ip := range ips {
+		reserved[ip.String()] = struct{}{}
+	}
+
+	return reserved, nil
+}
+
+// isLeaked returns true if the Pod the IP is allocated to no longer exists,
+// the IP is expected to be released by the IP GC.
+func (ic *IPPoolController) isLeaked(ctx context.Context, namespace, name, uid string) (bool, error) {
+	var pod corev1.Pod
+	if err := ic.client.Get(ctx, apitypes.NamespacedName{Namespace: namespace, Name: name}, &pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to get Pod %s/%s: %w", namespace, name, err)
+	}
+
+	return string(pod.UID) != uid, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ippoolmanager
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/spidernet-io/spiderpool/pkg/event"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/metric"
+	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
+)
+
+var _ = Describe("IPPool-usage", Label("ippool_usage_test"), func() {
+	var ic *IPPoolController
+	var pool *spiderpoolv2beta1.SpiderIPPool
+	var recorder *record.FakeRecorder
+
+	BeforeEach(func() {
+		usageScheme := runtime.NewScheme()
+		Expect(spiderpoolv2beta1.AddToScheme(usageScheme)).To(Succeed())
+		Expect(corev1.AddToScheme(usageScheme)).To(Succeed())
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "pod-1",
+				UID:       apitypes.UID("uid-1"),
+			},
+		}
+		reservedIP := &spiderpoolv2beta1.SpiderReservedIP{
+			ObjectMeta: metav1.ObjectMeta{Name: "reserved"},
+			Spec: spiderpoolv2beta1.ReservedIPSpec{
+				IPVersion: pointer.Int64(4),
+				IPs:       []string{"10.1.0.9"},
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(usageScheme).
+			WithObjects(pod, reservedIP).
+			Build()
+		ic = NewIPPoolController(
+			IPPoolControllerConfig{UtilizationWindow: time.Hour, UtilizationThresholds: []int{50}},
+			fakeClient,
+			dynamicfake.NewSimpleDynamicClient(usageScheme),
+		)
+
+		records := spiderpoolv2beta1.PoolIPAllocations{
+			"10.1.0.1": {NIC: "eth0", NamespacedName: "default/pod-1", PodUID: "uid-1"},
+			"10.1.0.2": {NIC: "eth0", NamespacedName: "default/pod-2", PodUID: "uid-2"},
+			"10.1.0.3": {NIC: "eth0", NamespacedName: "kube-system/pod-3", PodUID: "uid-3"},
+			"10.1.0.4": {NIC: "eth0", NamespacedName: "default/pod-1", PodUID: "uid-old"},
+		}
+		allocatedIPs, err := convert.MarshalIPPoolAllocatedIPs(records)
+		Expect(err).NotTo(HaveOccurred())
+
+		pool = &spiderpoolv2beta1.SpiderIPPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "usage-ippool"},
+			Spec: spiderpoolv2beta1.IPPoolSpec{
+				IPVersion:  pointer.Int64(4),
+				Subnet:     "10.1.0.0/16",
+				IPs:        []string{"10.1.0.1-10.1.0.10"},
+				ExcludeIPs: []string{"10.1.0.10"},
+			},
+			Status: spiderpoolv2beta1.IPPoolStatus{
+				AllocatedIPs: allocatedIPs,
+				ConflictIPs: spiderpoolv2beta1.PoolIPConflicts{
+					"10.1.0.8": {MAC: "00:00:00:00:00:01"},
+				},
+			},
+		}
+
+		recorder = record.NewFakeRecorder(10)
+		event.EventRecorder = recorder
+		DeferCleanup(func() {
+			event.EventRecorder = record.NewFakeRecorder(event.FakeRecorderBufferSize)
+			metric.IPPoolUsage.Delete(pool.Name)
+		})
+	})
+
+	It("classifies the IPs of the IPPool", func() {
+		usage, total, err := ic.computeUsage(context.TODO(), pool)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(total).To(Equal(int64(9)))
+		Expect(usage.Allocated).To(Equal(int64(4)))
+		Expect(usage.Free).To(Equal(int64(3)))
+		Expect(usage.Reserved).To(Equal(int64(2)))
+		Expect(usage.Quarantined).To(Equal(int64(1)))
+		Expect(usage.Leaked).To(Equal(int64(3)))
+		Expect(usage.AllocatedByNamespace).To(Equal(map[string]int64{"default": 3, "kube-system": 1}))
+	})
+
+	It("records the usage and emits an event once the threshold is crossed", func() {
+		Expect(ic.recordUsage(context.TODO(), pool)).To(Succeed())
+
+		usage, ok := metric.IPPoolUsage.Get(pool.Name)
+		Expect(ok).To(BeTrue())
+		Expect(usage.Free).To(Equal(int64(3)))
+		Expect(usage.ExhaustionSeconds).To(Equal(float64(-1)))
+		Expect(recorder.Events).To(Receive(ContainSubstring("IPUtilizationHigh")))
+
+		Expect(ic.recordUsage(context.TODO(), pool)).To(Succeed())
+		Expect(recorder.Events).NotTo(Receive())
+
+		ic.forgetUsage(pool.Name)
+		_, ok = metric.IPPoolUsage.Get(pool.Name)
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -6,9 +6,13 @@ package ippoolmanager
 import (
 	"context"
 	"fmt"
+	"net"
+	"reflect"
 	"strconv"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/strings/slices"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -17,7 +21,6 @@ import (
 	spiderpoolip "github.com/spidernet-io/spiderpool/pkg/ip"
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
 	"github.com/spidernet-io/spiderpool/pkg/types"
-	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
 )
 
 var (
@@ -28,6 +31,11 @@ var (
 	gatewayField     *field.Path = field.NewPath("spec").Child("gateway")
 	routesField      *field.Path = field.NewPath("spec").Child("routes")
 	podAffinityField *field.Path = field.NewPath("spec").Child("podAffinity")
+	poolGroupField   *field.Path = field.NewPath("spec").Child("poolGroup")
+
+	allocationStorageField *field.Path = field.NewPath("spec").Child("allocationStorage")
+	ipv6DerivationField    *field.Path = field.NewPath("spec").Child("ipv6Derivation")
+	macAllocationField     *field.Path = field.NewPath("spec").Child("macAllocation")
 )
 
 func (iw *IPPoolWebhook) validateCreateIPPool(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool) field.ErrorList {
@@ -75,7 +83,7 @@ func (iw *IPPoolWebhook) validateUpdateIPPool(ctx context.Context, oldIPPool, ne
 	}
 
 	var errs field.ErrorList
-	if err := validateIPPoolIPInUse(newIPPool); err != nil {
+	if err := iw.validateIPPoolIPInUse(ctx, newIPPool); err != nil {
 		errs = append(errs, err)
 	}
 
@@ -102,24 +110,64 @@ func validateIPPoolShouldNotBeChanged(oldIPPool, newIPPool *spiderpoolv2beta1.Sp
 		)
 	}
 
+	if !reflect.DeepEqual(newIPPool.Spec.IPv6Derivation, oldIPPool.Spec.IPv6Derivation) {
+		return field.Forbidden(
+			ipv6DerivationField,
+			"is not changeable",
+		)
+	}
+
+	if IsIPClaimStorage(oldIPPool) && !IsIPClaimStorage(newIPPool) {
+		return field.Forbidden(
+			allocationStorageField,
+			"can not be changed from 'ipclaim' back to 'status'",
+		)
+	}
+
 	return nil
 }
 
 func (iw *IPPoolWebhook) validateIPPoolSpec(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool) *field.Error {
+	if err := iw.validateIPPoolIPv6Derivation(ctx, ipPool); err != nil {
+		return err
+	}
 	if err := iw.validateIPPoolAvailableIPs(ctx, ipPool); err != nil {
 		return err
 	}
+	if err := iw.validateIPPoolMACAllocation(ctx, ipPool); err != nil {
+		return err
+	}
 	if err := validateIPPoolGateway(ipPool); err != nil {
 		return err
 	}
+	if err := validateIPPoolPoolGroup(ipPool.Spec.PoolGroup); err != nil {
+		return err
+	}
 
 	return validateIPPoolRoutes(*ipPool.Spec.IPVersion, ipPool.Spec.Subnet, ipPool.Spec.Routes)
 }
 
-func validateIPPoolIPInUse(ipPool *spiderpoolv2beta1.SpiderIPPool) *field.Error {
-	allocatedRecords, err := convert.UnmarshalIPPoolAllocatedIPs(ipPool.Status.AllocatedIPs)
+func (iw *IPPoolWebhook) validateIPPoolIPInUse(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool) *field.Error {
+	store, err := NewAllocationStore(iw.Client, iw.APIReader)
 	if err != nil {
-		return field.InternalError(ipsField, fmt.Errorf("failed to unmarshal the allocated IP records of IPPool %s: %v", ipPool.Name, err))
+		return field.InternalError(ipsField, err)
+	}
+
+	allocatedRecords, err := store.ListAllocations(ctx, ipPool)
+	if err != nil {
+		return field.InternalError(ipsField, fmt.Errorf("failed to list the allocated IP records of IPPool %s: %v", ipPool.Name, err))
+	}
+
+	if IsDerivedIPPool(ipPool) {
+		for ip, allocation := range allocatedRecords {
+			if !IsIPInIPPool(ipPool, net.ParseIP(ip)) {
+				return field.Forbidden(
+					excludeIPsField,
+					fmt.Sprintf("exclude an IP address %s that is being used by Pod %s", ip, allocation.NamespacedName),
+				)
+			}
+		}
+		return nil
 	}
 
 	totalIPs, err := spiderpoolip.AssembleTotalIPs(*ipPool.Spec.IPVersion, ipPool.Spec.IPs, ipPool.Spec.ExcludeIPs)
@@ -279,6 +327,108 @@ func (iw *IPPoolWebhook) validateIPPoolAvailableIPs(ctx context.Context, ipPool
 	return nil
 }
 
+// validateIPPoolIPv6Derivation checks that the derived IPPool is an IPv6 /64
+// one without 'spec.ips', and it is the only IPPool of its subnet because it
+// owns all of the IPv6 addresses of the subnet.
+func (iw *IPPoolWebhook) validateIPPoolIPv6Derivation(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool) *field.Error {
+	if IsDerivedIPPool(ipPool) {
+		if *ipPool.Spec.IPVersion != constant.IPv6 {
+			return field.Forbidden(
+				ipv6DerivationField,
+				"only supported in IPv6 IPPool",
+			)
+		}
+
+		if err := spiderpoolip.IsDerivationCIDR(ipPool.Spec.Subnet); err != nil {
+			return field.Invalid(
+				subnetField,
+				ipPool.Spec.Subnet,
+				err.Error(),
+			)
+		}
+
+		if len(ipPool.Spec.IPs) != 0 {
+			return field.Forbidden(
+				ipsField,
+				"must be empty when the IPv6 addresses are derived",
+			)
+		}
+	}
+
+	cidr, err := spiderpoolip.CIDRToLabelValue(*ipPool.Spec.IPVersion, ipPool.Spec.Subnet)
+	if err != nil {
+		return field.InternalError(subnetField, fmt.Errorf("failed to parse CIDR %s as a valid label value: %v", ipPool.Spec.Subnet, err))
+	}
+
+	var ipPoolList spiderpoolv2beta1.SpiderIPPoolList
+	if err := iw.APIReader.List(
+		ctx,
+		&ipPoolList,
+		client.MatchingLabels{constant.LabelIPPoolCIDR: cidr},
+	); err != nil {
+		return field.InternalError(subnetField, fmt.Errorf("failed to list IPPools: %v", err))
+	}
+
+	for _, pool := range ipPoolList.Items {
+		if pool.Name == ipPool.Name {
+			continue
+		}
+		if IsDerivedIPPool(ipPool) || IsDerivedIPPool(&pool) {
+			return field.Forbidden(
+				subnetField,
+				fmt.Sprintf("shared with IPPool %s, the IPPool which derives IPv6 addresses owns the whole subnet", pool.Name),
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateIPPoolMACAllocation checks that the MAC prefix is a valid unicast
+// one, and no other IPPool allocates MAC addresses with the same prefix. The
+// IPPool deriving EUI-64 addresses can't allocate the MAC addresses which
+// they are derived from.
+func (iw *IPPoolWebhook) validateIPPoolMACAllocation(ctx context.Context, ipPool *spiderpoolv2beta1.SpiderIPPool) *field.Error {
+	if ipPool.Spec.MACAllocation == nil {
+		return nil
+	}
+
+	if IsDerivedIPPool(ipPool) && *ipPool.Spec.IPv6Derivation == constant.IPv6DerivationEUI64 {
+		return field.Forbidden(
+			macAllocationField,
+			fmt.Sprintf("the IPPool derives the IPv6 addresses by %s from the MAC addresses, which can't be allocated along with them", constant.IPv6DerivationEUI64),
+		)
+	}
+
+	prefix := ipPool.Spec.MACAllocation.Prefix
+	if _, err := spiderpoolip.ParseMACPrefix(prefix); err != nil {
+		return field.Invalid(
+			macAllocationField.Child("prefix"),
+			prefix,
+			err.Error(),
+		)
+	}
+
+	var ipPoolList spiderpoolv2beta1.SpiderIPPoolList
+	if err := iw.APIReader.List(ctx, &ipPoolList); err != nil {
+		return field.InternalError(macAllocationField, fmt.Errorf("failed to list IPPools: %v", err))
+	}
+
+	for _, pool := range ipPoolList.Items {
+		if pool.Name == ipPool.Name || pool.Spec.MACAllocation == nil {
+			continue
+		}
+		if strings.EqualFold(pool.Spec.MACAllocation.Prefix, prefix) {
+			return field.Forbidden(
+				macAllocationField.Child("prefix"),
+				fmt.Sprintf("MAC prefix %s is used by IPPool %s", prefix, pool.Name),
+			)
+		}
+	}
+
+	return nil
+}
+
 func (iw *IPPoolWebhook) validateIPPoolIPs(version types.IPVersion, subnet string, ips []string) *field.Error {
 	for i, r := range ips {
 		if err := ValidateContainsIPRange(ipsField.Index(i), version, subnet, r); err != nil {
@@ -370,6 +520,38 @@ func validateIPPoolRoutes(version types.IPVersion, subnet string, routes []spide
 	return nil
 }
 
+func validateIPPoolPoolGroup(poolGroup *spiderpoolv2beta1.PoolGroup) *field.Error {
+	if poolGroup == nil {
+		return nil
+	}
+
+	if errs := utilvalidation.IsValidLabelValue(poolGroup.Name); len(errs) != 0 || poolGroup.Name == "" {
+		return field.Invalid(
+			poolGroupField.Child("name"),
+			poolGroup.Name,
+			"must be a non-empty valid label value",
+		)
+	}
+
+	if errs := utilvalidation.IsQualifiedName(poolGroup.TopologyKey); len(errs) != 0 {
+		return field.Invalid(
+			poolGroupField.Child("topologyKey"),
+			poolGroup.TopologyKey,
+			strings.Join(errs, "; "),
+		)
+	}
+
+	if errs := utilvalidation.IsValidLabelValue(poolGroup.TopologyValue); len(errs) != 0 {
+		return field.Invalid(
+			poolGroupField.Child("topologyValue"),
+			poolGroup.TopologyValue,
+			strings.Join(errs, "; "),
+		)
+	}
+
+	return nil
+}
+
 func ValidateContainsIPRange(fieldPath *field.Path, version types.IPVersion, subnet string, ipRange string) *field.Error {
 	contains, err := spiderpoolip.ContainsIPRange(version, subnet, ipRange)
 	if err != nil {
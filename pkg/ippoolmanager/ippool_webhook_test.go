@@ -1001,6 +1001,157 @@ var _ = Describe("IPPoolWebhook", Label("ippool_webhook_test"), func() {
 				})
 			})
 
+			When("Validating 'spec.poolGroup'", func() {
+				BeforeEach(func() {
+					ipPoolT.Spec.IPVersion = pointer.Int64(constant.IPv4)
+					ipPoolT.Spec.Subnet = "172.18.40.0/24"
+					ipPoolT.Spec.IPs = append(ipPoolT.Spec.IPs, "172.18.40.10")
+					ipPoolT.Spec.PoolGroup = &spiderpoolv2beta1.PoolGroup{
+						Name:          "rack-vlan",
+						TopologyKey:   "topology.kubernetes.io/zone",
+						TopologyValue: "zone-a",
+					}
+				})
+
+				It("inputs empty group name", func() {
+					ipPoolT.Spec.PoolGroup.Name = ""
+
+					err := ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				})
+
+				It("inputs invalid topology key", func() {
+					ipPoolT.Spec.PoolGroup.TopologyKey = "topology.kubernetes.io/zone/a"
+
+					err := ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				})
+
+				It("inputs invalid topology value", func() {
+					ipPoolT.Spec.PoolGroup.TopologyValue = "zone a"
+
+					err := ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				})
+
+				It("inputs valid pool group", func() {
+					err := ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+
+			When("Validating 'spec.ipv6Derivation'", func() {
+				BeforeEach(func() {
+					ipPoolT.Spec.IPVersion = pointer.Int64(constant.IPv6)
+					ipPoolT.Spec.Subnet = "abcd:1234::/64"
+					ipPoolT.Spec.IPv6Derivation = pointer.String(constant.IPv6DerivationEUI64)
+				})
+
+				It("derives IPv4 addresses", func() {
+					ipPoolT.Spec.IPVersion = pointer.Int64(constant.IPv4)
+					ipPoolT.Spec.Subnet = "172.18.40.0/24"
+
+					err := ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				})
+
+				It("inputs the 'spec.subnet' which is not /64", func() {
+					ipPoolT.Spec.Subnet = "abcd:1234::/120"
+
+					err := ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				})
+
+				It("inputs 'spec.ips' at the same time", func() {
+					ipPoolT.Spec.IPs = append(ipPoolT.Spec.IPs, "abcd:1234::1-abcd:1234::2")
+
+					err := ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				})
+
+				It("shares 'spec.subnet' with existing IPPool", func() {
+					cidr, err := spiderpoolip.CIDRToLabelValue(constant.IPv6, ipPoolT.Spec.Subnet)
+					Expect(err).NotTo(HaveOccurred())
+
+					existIPPoolT.Labels[constant.LabelIPPoolCIDR] = cidr
+					existIPPoolT.Spec.IPVersion = pointer.Int64(constant.IPv6)
+					existIPPoolT.Spec.Subnet = ipPoolT.Spec.Subnet
+					existIPPoolT.Spec.IPs = append(existIPPoolT.Spec.IPs, "abcd:1234::a")
+
+					err = tracker.Add(existIPPoolT)
+					Expect(err).NotTo(HaveOccurred())
+
+					err = ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				})
+
+				It("inputs valid IPv6 derivation", func() {
+					err := ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+
+			When("Validating 'spec.macAllocation'", func() {
+				BeforeEach(func() {
+					ipPoolT.Spec.IPVersion = pointer.Int64(constant.IPv4)
+					ipPoolT.Spec.Subnet = "172.18.40.0/24"
+					ipPoolT.Spec.IPs = append(ipPoolT.Spec.IPs, "172.18.40.10")
+					ipPoolT.Spec.MACAllocation = &spiderpoolv2beta1.MACAllocation{Prefix: "0a:1b"}
+				})
+
+				It("inputs invalid MAC prefix", func() {
+					ipPoolT.Spec.MACAllocation.Prefix = "0a:1b:2c"
+
+					err := ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				})
+
+				It("inputs multicast MAC prefix", func() {
+					ipPoolT.Spec.MACAllocation.Prefix = "01:00"
+
+					err := ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				})
+
+				It("inputs the MAC prefix used by existing IPPool", func() {
+					existIPPoolT.Spec.IPVersion = pointer.Int64(constant.IPv6)
+					existIPPoolT.Spec.Subnet = "abcd:1234::/120"
+					existIPPoolT.Spec.IPs = append(existIPPoolT.Spec.IPs, "abcd:1234::a")
+					existIPPoolT.Spec.MACAllocation = &spiderpoolv2beta1.MACAllocation{Prefix: "0A:1B"}
+
+					err := tracker.Add(existIPPoolT)
+					Expect(err).NotTo(HaveOccurred())
+
+					err = ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				})
+
+				It("allocates the MAC addresses which the EUI-64 addresses are derived from", func() {
+					ipPoolT.Spec.IPVersion = pointer.Int64(constant.IPv6)
+					ipPoolT.Spec.Subnet = "abcd:1234::/64"
+					ipPoolT.Spec.IPs = nil
+					ipPoolT.Spec.IPv6Derivation = pointer.String(constant.IPv6DerivationEUI64)
+
+					err := ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				})
+
+				It("allocates the MAC addresses along with the hashed IPv6 addresses", func() {
+					ipPoolT.Spec.IPVersion = pointer.Int64(constant.IPv6)
+					ipPoolT.Spec.Subnet = "abcd:1234::/64"
+					ipPoolT.Spec.IPs = nil
+					ipPoolT.Spec.IPv6Derivation = pointer.String(constant.IPv6DerivationHash)
+
+					err := ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("inputs valid MAC allocation", func() {
+					err := ipPoolWebhook.ValidateCreate(ctx, ipPoolT)
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+
 			When("Validating the total IP addresses contained in the controller Subnet", func() {
 				BeforeEach(func() {
 					ipPoolWebhook.EnableSpiderSubnet = true
@@ -1367,6 +1518,18 @@ var _ = Describe("IPPoolWebhook", Label("ippool_webhook_test"), func() {
 					err := ipPoolWebhook.ValidateUpdate(ctx, ipPoolT, newIPPoolT)
 					Expect(apierrors.IsInvalid(err)).To(BeTrue())
 				})
+
+				It("changes 'spec.ipv6Derivation'", func() {
+					ipPoolT.Spec.IPVersion = pointer.Int64(constant.IPv6)
+					ipPoolT.Spec.Subnet = "abcd:1234::/64"
+					ipPoolT.Spec.IPv6Derivation = pointer.String(constant.IPv6DerivationEUI64)
+
+					newIPPoolT := ipPoolT.DeepCopy()
+					newIPPoolT.Spec.IPv6Derivation = pointer.String(constant.IPv6DerivationHash)
+
+					err := ipPoolWebhook.ValidateUpdate(ctx, ipPoolT, newIPPoolT)
+					Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				})
 			})
 
 			When("Validating 'spec.default'", func() {
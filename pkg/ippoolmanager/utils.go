@@ -4,12 +4,15 @@
 package ippoolmanager
 
 import (
+	"math"
+	"net"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolip "github.com/spidernet-io/spiderpool/pkg/ip"
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
 	"github.com/spidernet-io/spiderpool/pkg/types"
 )
@@ -21,6 +24,40 @@ func IsAutoCreatedIPPool(pool *spiderpoolv2beta1.SpiderIPPool) bool {
 	return ok
 }
 
+// IsIPInIPPool reports whether the IP address is within the IP ranges of the
+// IPPool, or the subnet of the derived IPPool, and not excluded.
+func IsIPInIPPool(pool *spiderpoolv2beta1.SpiderIPPool, ip net.IP) bool {
+	if pool.Spec.IPVersion == nil {
+		return false
+	}
+
+	contains := func(ipRanges []string) bool {
+		for _, ipRange := range ipRanges {
+			if ok, err := spiderpoolip.IPRangeContainsIP(*pool.Spec.IPVersion, ipRange, ip.String()); err == nil && ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	if IsDerivedIPPool(pool) {
+		inSubnet, err := spiderpoolip.ContainsIP(*pool.Spec.IPVersion, pool.Spec.Subnet, ip.String())
+		return err == nil && inSubnet && !contains(pool.Spec.ExcludeIPs)
+	}
+
+	return contains(pool.Spec.IPs) && !contains(pool.Spec.ExcludeIPs)
+}
+
+// DerivedIPPoolSize is the total IP count of the derived IPPool, whose /64
+// subnet has more IPv6 addresses than an int64 can count.
+const DerivedIPPoolSize = math.MaxInt64
+
+// IsDerivedIPPool reports whether the IPv6 addresses of the IPPool are
+// derived from its /64 subnet rather than allocated from 'spec.ips'.
+func IsDerivedIPPool(pool *spiderpoolv2beta1.SpiderIPPool) bool {
+	return pool.Spec.IPv6Derivation != nil
+}
+
 func NewAutoPoolPodAffinity(podTopController types.PodTopController) *metav1.LabelSelector {
 	var group, version string
 
@@ -65,3 +102,61 @@ func IsMatchAutoPoolAffinity(podAffinity *metav1.LabelSelector, podTopController
 
 	return true
 }
+
+// IsMatchPoolGroupTopology reports whether the IPPool is a member of an
+// IPPool group and serves the node with the given labels.
+func IsMatchPoolGroupTopology(pool *spiderpoolv2beta1.SpiderIPPool, nodeLabels map[string]string) bool {
+	if pool.Spec.PoolGroup == nil {
+		return false
+	}
+
+	value, ok := nodeLabels[pool.Spec.PoolGroup.TopologyKey]
+	return ok && value == pool.Spec.PoolGroup.TopologyValue
+}
+
+// IsMatchNamespaceAffinity reports whether the labels of the Namespace match
+// the Namespace affinity of the IPPool, which matches any Namespace if unset.
+func IsMatchNamespaceAffinity(pool *spiderpoolv2beta1.SpiderIPPool, namespaceLabels map[string]string) (bool, error) {
+	if pool.Spec.NamespaceAffinity == nil {
+		return true, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(pool.Spec.NamespaceAffinity)
+	if err != nil {
+		return false, err
+	}
+
+	return selector.Matches(labels.Set(namespaceLabels)), nil
+}
+
+// FreeIPCount returns the number of the IPs of the IPPool which are neither
+// allocated nor conflicting, according to its status. The status may lag
+// behind the allocations made by spiderpool-agents, and the IPs of the
+// SpiderReservedIPs are not taken into account.
+func FreeIPCount(pool *spiderpoolv2beta1.SpiderIPPool) (int64, error) {
+	var total int64
+	switch {
+	case pool.Status.TotalIPCount != nil:
+		total = *pool.Status.TotalIPCount
+	case IsDerivedIPPool(pool):
+		total = DerivedIPPoolSize
+	default:
+		totalIPs, err := spiderpoolip.AssembleTotalIPs(*pool.Spec.IPVersion, pool.Spec.IPs, pool.Spec.ExcludeIPs)
+		if err != nil {
+			return 0, err
+		}
+		total = int64(len(totalIPs))
+	}
+
+	var allocated int64
+	if pool.Status.AllocatedIPCount != nil {
+		allocated = *pool.Status.AllocatedIPCount
+	}
+
+	free := total - allocated - int64(len(pool.Status.ConflictIPs))
+	if free < 0 {
+		free = 0
+	}
+
+	return free, nil
+}
@@ -4,10 +4,13 @@
 package ippoolmanager
 
 import (
+	"net"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	types2 "k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 
 	"github.com/spidernet-io/spiderpool/pkg/constant"
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
@@ -73,4 +76,69 @@ var _ = Describe("IPPoolManager-utils", Label("ippool_manager_utils"), func() {
 		})
 	})
 
+	Context("IsMatchPoolGroupTopology", Labels{"unitest", "IsMatchPoolGroupTopology"}, func() {
+		var pool spiderpoolv2beta1.SpiderIPPool
+
+		BeforeEach(func() {
+			pool = spiderpoolv2beta1.SpiderIPPool{}
+			pool.Spec.PoolGroup = &spiderpoolv2beta1.PoolGroup{
+				Name:          "rack-vlan",
+				TopologyKey:   "topology.kubernetes.io/zone",
+				TopologyValue: "zone-a",
+			}
+		})
+
+		It("IPPool is not a member of any group", func() {
+			pool.Spec.PoolGroup = nil
+
+			isMatch := IsMatchPoolGroupTopology(&pool, map[string]string{"topology.kubernetes.io/zone": "zone-a"})
+			Expect(isMatch).To(BeFalse())
+		})
+
+		It("node does not have the topology label", func() {
+			isMatch := IsMatchPoolGroupTopology(&pool, map[string]string{"kubernetes.io/hostname": "node1"})
+			Expect(isMatch).To(BeFalse())
+		})
+
+		It("node is in another topology domain", func() {
+			isMatch := IsMatchPoolGroupTopology(&pool, map[string]string{"topology.kubernetes.io/zone": "zone-b"})
+			Expect(isMatch).To(BeFalse())
+		})
+
+		It("node is in the topology domain of the IPPool", func() {
+			isMatch := IsMatchPoolGroupTopology(&pool, map[string]string{"topology.kubernetes.io/zone": "zone-a"})
+			Expect(isMatch).To(BeTrue())
+		})
+	})
+
+	Context("IsIPInIPPool", func() {
+		var pool spiderpoolv2beta1.SpiderIPPool
+
+		BeforeEach(func() {
+			pool = spiderpoolv2beta1.SpiderIPPool{
+				Spec: spiderpoolv2beta1.IPPoolSpec{
+					IPVersion:  pointer.Int64(constant.IPv4),
+					IPs:        []string{"172.18.40.10-172.18.40.20", "172.18.40.30"},
+					ExcludeIPs: []string{"172.18.40.15"},
+				},
+			}
+		})
+
+		It("IP address is within the IP ranges", func() {
+			Expect(IsIPInIPPool(&pool, net.ParseIP("172.18.40.12"))).To(BeTrue())
+			Expect(IsIPInIPPool(&pool, net.ParseIP("172.18.40.30"))).To(BeTrue())
+		})
+
+		It("IP address is excluded", func() {
+			Expect(IsIPInIPPool(&pool, net.ParseIP("172.18.40.15"))).To(BeFalse())
+		})
+
+		It("IP address is out of the IP ranges", func() {
+			Expect(IsIPInIPPool(&pool, net.ParseIP("172.18.40.21"))).To(BeFalse())
+		})
+
+		It("IP address of another IP version", func() {
+			Expect(IsIPInIPPool(&pool, net.ParseIP("fd00::10"))).To(BeFalse())
+		})
+	})
 })
@@ -45,6 +45,106 @@ type CoordinatorSpec struct {
 	// +kubebuilder:default=false
 	// +kubebuilder:validation:Optional
 	DetectGateway *bool `json:"detectGateway,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	DetectGatewayOptions *DetectGatewayOptions `json:"detectGatewayOptions,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	GratuitousNeighbor *GratuitousNeighbor `json:"gratuitousNeighbor,omitempty"`
+
+	// MTU is the MTU of the veth pair in underlay mode, auto means the
+	// smaller one of the Pod's interface and the host's path to the
+	// cluster, which excludes the overhead of the overlay. It defaults to
+	// 1500.
+	// +kubebuilder:validation:Pattern=`^(auto|[0-9]+)$`
+	// +kubebuilder:validation:Optional
+	MTU *string `json:"mtu,omitempty"`
+
+	// Bandwidth is only supported in SpiderMultusConfig.
+	// +kubebuilder:validation:Optional
+	Bandwidth *Bandwidth `json:"bandwidth,omitempty"`
+
+	// VRF is only supported in SpiderMultusConfig.
+	// +kubebuilder:validation:Optional
+	VRF *VRF `json:"vrf,omitempty"`
+}
+
+// DetectGatewayOptions configures how the gateways of the Pod are detected
+// when detectGateway is enabled, for the gateways which rate-limit or filter
+// ICMP.
+type DetectGatewayOptions struct {
+	// Method is icmp, arp (ARP for IPv4 gateways), ndp (NDP for IPv6
+	// gateways) or any (either ICMP or ARP/NDP succeeds).
+	// +kubebuilder:default=icmp
+	// +kubebuilder:validation:Enum=icmp;arp;ndp;any
+	// +kubebuilder:validation:Optional
+	Method *string `json:"method,omitempty"`
+
+	// LossTolerance is the percentage of lost probes tolerated.
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=99
+	// +kubebuilder:validation:Optional
+	LossTolerance *int `json:"lossTolerance,omitempty"`
+
+	// WarnOnly records an Event on the Pod instead of failing the Pod
+	// when the gateway is unreachable.
+	// +kubebuilder:default=false
+	// +kubebuilder:validation:Optional
+	WarnOnly *bool `json:"warnOnly,omitempty"`
+}
+
+// GratuitousNeighbor configures the burst of gratuitous ARPs (IPv4) and
+// unsolicited neighbor advertisements (IPv6) sent from the Pod's interface
+// after it is set up, so that switches and neighbors refresh the stale MAC
+// address of a re-used IP address.
+type GratuitousNeighbor struct {
+	// +kubebuilder:default=false
+	// +kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Optional
+	Count *int `json:"count,omitempty"`
+
+	// +kubebuilder:default="100ms"
+	// +kubebuilder:validation:Optional
+	Interval *string `json:"interval,omitempty"`
+}
+
+// Bandwidth limits the rates of the Pod's interface and marks the DSCP of its
+// egress packets with tc and iptables in the Pod's network namespace. The
+// rates are in bits per second, such as 100M, and in the range of 1k to 32G.
+type Bandwidth struct {
+	// +kubebuilder:validation:Optional
+	IngressRate *string `json:"ingressRate,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	EgressRate *string `json:"egressRate,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=63
+	// +kubebuilder:validation:Optional
+	DSCP *int `json:"dscp,omitempty"`
+}
+
+// VRF enslaves the Pod's interface to a Linux VRF device in the Pod's network
+// namespace, whose route table isolates the interface from the other
+// interfaces of the Pod, so that the interfaces of different tenants may
+// have overlapping subnets.
+type VRF struct {
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=15
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Table is the route table of the VRF. It defaults to the table of the
+	// existing VRF with the same name, or the rule table of the interface.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	Table *int `json:"table,omitempty"`
 }
 
 // CoordinationStatus defines the observed state of SpiderCoordinator.
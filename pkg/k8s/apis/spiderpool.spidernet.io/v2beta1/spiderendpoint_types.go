@@ -26,6 +26,11 @@ type PodIPAllocation struct {
 	// +kubebuilder:validation:Required
 	Node string `json:"node"`
 
+	// ContainerID is the ID of the Pod sandbox the IP addresses are
+	// allocated to, which is matched by the CNI GC.
+	// +kubebuilder:validation:Optional
+	ContainerID string `json:"containerID,omitempty"`
+
 	// +kubebuilder:validation:Required
 	IPs []IPAllocationDetail `json:"ips"`
 }
@@ -63,6 +68,16 @@ type IPAllocationDetail struct {
 
 	// +kubebuilder:validation:Optional
 	Routes []Route `json:"routes,omitempty"`
+
+	// MAC is the MAC address allocated along with the IP addresses, the one of
+	// the IPv4 address takes precedence in dual-stack.
+	// +kubebuilder:validation:Optional
+	MAC *string `json:"mac,omitempty"`
+
+	// Network is the name of the CNI network the interface is attached to,
+	// which is matched by the CNI GC.
+	// +kubebuilder:validation:Optional
+	Network *string `json:"network,omitempty"`
 }
 
 // +kubebuilder:resource:categories={spiderpool},path="spiderendpoints",scope="Namespaced",shortName={se},singular="spiderendpoint"
@@ -0,0 +1,60 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package v2beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IPClaimSpec defines the desired state of SpiderIPClaim.
+type IPClaimSpec struct {
+	// +kubebuilder:validation:Required
+	IPPool string `json:"ippool"`
+
+	// +kubebuilder:validation:Required
+	IP string `json:"ip"`
+
+	// +kubebuilder:validation:Optional
+	NIC string `json:"interface,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	NamespacedName string `json:"pod,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	PodUID string `json:"podUid,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	MAC string `json:"mac,omitempty"`
+}
+
+// +kubebuilder:resource:categories={spiderpool},path="spideripclaims",scope="Cluster",shortName={sic},singular="spideripclaim"
+// +kubebuilder:printcolumn:JSONPath=".spec.ippool",description="ippool",name="IPPOOL",type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.ip",description="ip",name="IP",type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.pod",description="pod",name="POD",type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.interface",description="interface",name="INTERFACE",type=string,priority=10
+// +kubebuilder:object:root=true
+
+// SpiderIPClaim records the allocation of an IP address of a SpiderIPPool whose
+// allocation storage is 'ipclaim'. It is named after the IPPool and the IP
+// address, so that only one of the concurrent creations succeeds.
+type SpiderIPClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IPClaimSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SpiderIPClaimList contains a list of SpiderIPClaim.
+type SpiderIPClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []SpiderIPClaim `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SpiderIPClaim{}, &SpiderIPClaimList{})
+}
@@ -50,6 +50,62 @@ type IPPoolSpec struct {
 	// +kubebuilder:default=false
 	// +kubebuilder:validation:Optional
 	Disable *bool `json:"disable,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	PoolGroup *PoolGroup `json:"poolGroup,omitempty"`
+
+	// AllocationStorage is where the IP allocation records of the IPPool are
+	// kept: 'status' for 'status.allocatedIPs', or 'ipclaim' for one
+	// SpiderIPClaim per allocated IP address. Switching from 'status' to
+	// 'ipclaim' migrates the existing records, the reverse is not supported.
+	// +kubebuilder:default=status
+	// +kubebuilder:validation:Enum=status;ipclaim
+	// +kubebuilder:validation:Optional
+	AllocationStorage *string `json:"allocationStorage,omitempty"`
+
+	// IPv6Derivation derives the IPv6 addresses of the /64 'spec.subnet'
+	// instead of allocating them from 'spec.ips': 'eui64' for the modified
+	// EUI-64 interface identifier of the MAC address of the Pod's interface,
+	// or 'hash' for a stable hash of the Pod's namespace, name and interface.
+	// +kubebuilder:validation:Enum=eui64;hash
+	// +kubebuilder:validation:Optional
+	IPv6Derivation *string `json:"ipv6Derivation,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	MACAllocation *MACAllocation `json:"macAllocation,omitempty"`
+}
+
+// MACAllocation makes the IPPool allocate a MAC address along with each IP
+// address, which is recorded with the IP address and returned in the CNI
+// result.
+type MACAllocation struct {
+	// Prefix is the first 2 bytes of the MAC addresses, e.g. '0a:1b', which
+	// must be unicast and unique among IPPools.
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F]{2}:[0-9a-fA-F]{2}$`
+	// +kubebuilder:validation:Required
+	Prefix string `json:"prefix"`
+
+	// Source is where the remaining 4 bytes come from: 'ip' for the last 4
+	// bytes of the IP address, or 'pod' for a stable hash of the Pod's
+	// namespace, name and interface, which keeps the MAC address of a
+	// StatefulSet Pod regardless of its IP address.
+	// +kubebuilder:default=ip
+	// +kubebuilder:validation:Enum=ip;pod
+	// +kubebuilder:validation:Optional
+	Source *string `json:"source,omitempty"`
+}
+
+// PoolGroup makes the IPPool a member of a named group of IPPools, each of
+// which serves the nodes whose label TopologyKey equals TopologyValue.
+type PoolGroup struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	TopologyKey string `json:"topologyKey"`
+
+	// +kubebuilder:validation:Required
+	TopologyValue string `json:"topologyValue"`
 }
 
 type Route struct {
@@ -72,6 +128,9 @@ type IPPoolStatus struct {
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Optional
 	AllocatedIPCount *int64 `json:"allocatedIPCount,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	ConflictIPs PoolIPConflicts `json:"conflictIPs,omitempty"`
 }
 
 // PoolIPAllocations is a map of IP allocation details indexed by IP address.
@@ -81,6 +140,22 @@ type PoolIPAllocation struct {
 	NIC            string `json:"interface"`
 	NamespacedName string `json:"pod"`
 	PodUID         string `json:"podUid"`
+	MAC            string `json:"mac,omitempty"`
+}
+
+// PoolIPConflicts is a map of IP conflict details indexed by IP address.
+// The IP addresses in it are excluded from allocation.
+type PoolIPConflicts map[string]PoolIPConflict
+
+type PoolIPConflict struct {
+	// MAC is the hardware address of the host which answered for the IP address.
+	MAC string `json:"mac"`
+
+	// Node is where the conflict was detected, the spiderpool-agent on it
+	// re-probes the IP address to clear the conflict.
+	Node string `json:"node"`
+
+	DetectedTime metav1.Time `json:"detectedTime"`
 }
 
 // +kubebuilder:resource:categories={spiderpool},path="spiderippools",scope="Cluster",shortName={sp},singular="spiderippool"
@@ -64,6 +64,14 @@ type SpiderMacvlanCniConfig struct {
 	// +kubebuilder:validation:Maximum=4094
 	VlanID *int32 `json:"vlanID,omitempty"`
 
+	// MTU is the MTU of the Pod's interface, it defaults to the MTU of
+	// the master interface. auto means the MTU of the master interface
+	// minus the overhead of the VLAN and overlay devices under it, which
+	// is applied by the coordinator.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^(auto|[0-9]+)$`
+	MTU *string `json:"mtu,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	Bond *BondConfig `json:"bond,omitempty"`
 
@@ -80,6 +88,14 @@ type SpiderIPvlanCniConfig struct {
 	// +kubebuilder:validation:Maximum=4094
 	VlanID *int32 `json:"vlanID,omitempty"`
 
+	// MTU is the MTU of the Pod's interface, it defaults to the MTU of
+	// the master interface. auto means the MTU of the master interface
+	// minus the overhead of the VLAN and overlay devices under it, which
+	// is applied by the coordinator.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^(auto|[0-9]+)$`
+	MTU *string `json:"mtu,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	Bond *BondConfig `json:"bond,omitempty"`
 
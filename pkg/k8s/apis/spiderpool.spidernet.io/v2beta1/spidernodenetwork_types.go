@@ -0,0 +1,122 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package v2beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Types of the node interfaces.
+const (
+	NodeInterfacePhysical = "physical"
+	NodeInterfaceBond     = "bond"
+	NodeInterfaceVlan     = "vlan"
+	NodeInterfaceVF       = "vf"
+)
+
+// NodeNetworkStatus defines the observed network interfaces of the node.
+type NodeNetworkStatus struct {
+	// LastUpdateTime is when the interfaces changed last time.
+	// +kubebuilder:validation:Optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Interfaces []NodeInterface `json:"interfaces,omitempty"`
+}
+
+// NodeInterface is a physical NIC, bond, VLAN sub-interface or SR-IOV VF
+// of the node.
+type NodeInterface struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=physical;bond;vlan;vf
+	Type string `json:"type"`
+
+	// +kubebuilder:validation:Optional
+	MAC string `json:"mac,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	MTU int32 `json:"mtu,omitempty"`
+
+	// State is the operational state of the interface, such as up or down.
+	// +kubebuilder:validation:Optional
+	State string `json:"state,omitempty"`
+
+	// Master is the bond or bridge the interface is enslaved to.
+	// +kubebuilder:validation:Optional
+	Master string `json:"master,omitempty"`
+
+	// Slaves are the interfaces enslaved to the bond.
+	// +kubebuilder:validation:Optional
+	Slaves []string `json:"slaves,omitempty"`
+
+	// Parent is the interface the VLAN sub-interface is created on.
+	// +kubebuilder:validation:Optional
+	Parent string `json:"parent,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	VlanID *int32 `json:"vlanID,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Driver string `json:"driver,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	PCIAddress string `json:"pciAddress,omitempty"`
+
+	// NUMANode is the NUMA node the device is attached to.
+	// +kubebuilder:validation:Optional
+	NUMANode *int32 `json:"numaNode,omitempty"`
+
+	// Speed is the link speed in Mb/s.
+	// +kubebuilder:validation:Optional
+	Speed *int32 `json:"speed,omitempty"`
+
+	// PF is the SR-IOV physical function of the VF.
+	// +kubebuilder:validation:Optional
+	PF string `json:"pf,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	SRIOV *SRIOVStatus `json:"sriov,omitempty"`
+}
+
+// SRIOVStatus is the SR-IOV capability of a physical function.
+type SRIOVStatus struct {
+	// +kubebuilder:validation:Optional
+	TotalVFs int32 `json:"totalVFs,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	NumVFs int32 `json:"numVFs,omitempty"`
+}
+
+// +kubebuilder:resource:categories={spiderpool},path="spidernodenetworks",scope="Cluster",shortName={snn},singular="spidernodenetwork"
+// +kubebuilder:printcolumn:JSONPath=".status.lastUpdateTime",description="lastUpdateTime",name="LAST-UPDATE",type=date
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +genclient
+// +genclient:nonNamespaced
+
+// SpiderNodeNetwork is the inventory of the network interfaces of a node,
+// which is named after the node and published by spiderpool-agent.
+type SpiderNodeNetwork struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status NodeNetworkStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SpiderNodeNetworkList contains a list of SpiderNodeNetwork.
+type SpiderNodeNetworkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []SpiderNodeNetwork `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SpiderNodeNetwork{}, &SpiderNodeNetworkList{})
+}
@@ -13,6 +13,36 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Bandwidth) DeepCopyInto(out *Bandwidth) {
+	*out = *in
+	if in.IngressRate != nil {
+		in, out := &in.IngressRate, &out.IngressRate
+		*out = new(string)
+		**out = **in
+	}
+	if in.EgressRate != nil {
+		in, out := &in.EgressRate, &out.EgressRate
+		*out = new(string)
+		**out = **in
+	}
+	if in.DSCP != nil {
+		in, out := &in.DSCP, &out.DSCP
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Bandwidth.
+func (in *Bandwidth) DeepCopy() *Bandwidth {
+	if in == nil {
+		return nil
+	}
+	out := new(Bandwidth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BondConfig) DeepCopyInto(out *BondConfig) {
 	*out = *in
@@ -81,6 +111,31 @@ func (in *CoordinatorSpec) DeepCopyInto(out *CoordinatorSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DetectGatewayOptions != nil {
+		in, out := &in.DetectGatewayOptions, &out.DetectGatewayOptions
+		*out = new(DetectGatewayOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GratuitousNeighbor != nil {
+		in, out := &in.GratuitousNeighbor, &out.GratuitousNeighbor
+		*out = new(GratuitousNeighbor)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MTU != nil {
+		in, out := &in.MTU, &out.MTU
+		*out = new(string)
+		**out = **in
+	}
+	if in.Bandwidth != nil {
+		in, out := &in.Bandwidth, &out.Bandwidth
+		*out = new(Bandwidth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VRF != nil {
+		in, out := &in.VRF, &out.VRF
+		*out = new(VRF)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoordinatorSpec.
@@ -118,6 +173,66 @@ func (in *CoordinatorStatus) DeepCopy() *CoordinatorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DetectGatewayOptions) DeepCopyInto(out *DetectGatewayOptions) {
+	*out = *in
+	if in.Method != nil {
+		in, out := &in.Method, &out.Method
+		*out = new(string)
+		**out = **in
+	}
+	if in.LossTolerance != nil {
+		in, out := &in.LossTolerance, &out.LossTolerance
+		*out = new(int)
+		**out = **in
+	}
+	if in.WarnOnly != nil {
+		in, out := &in.WarnOnly, &out.WarnOnly
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DetectGatewayOptions.
+func (in *DetectGatewayOptions) DeepCopy() *DetectGatewayOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DetectGatewayOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GratuitousNeighbor) DeepCopyInto(out *GratuitousNeighbor) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GratuitousNeighbor.
+func (in *GratuitousNeighbor) DeepCopy() *GratuitousNeighbor {
+	if in == nil {
+		return nil
+	}
+	out := new(GratuitousNeighbor)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPAllocationDetail) DeepCopyInto(out *IPAllocationDetail) {
 	*out = *in
@@ -166,6 +281,16 @@ func (in *IPAllocationDetail) DeepCopyInto(out *IPAllocationDetail) {
 		*out = make([]Route, len(*in))
 		copy(*out, *in)
 	}
+	if in.MAC != nil {
+		in, out := &in.MAC, &out.MAC
+		*out = new(string)
+		**out = **in
+	}
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAllocationDetail.
@@ -178,6 +303,21 @@ func (in *IPAllocationDetail) DeepCopy() *IPAllocationDetail {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPClaimSpec) DeepCopyInto(out *IPClaimSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPClaimSpec.
+func (in *IPClaimSpec) DeepCopy() *IPClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IPClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPPoolSpec) DeepCopyInto(out *IPPoolSpec) {
 	*out = *in
@@ -236,6 +376,26 @@ func (in *IPPoolSpec) DeepCopyInto(out *IPPoolSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.PoolGroup != nil {
+		in, out := &in.PoolGroup, &out.PoolGroup
+		*out = new(PoolGroup)
+		**out = **in
+	}
+	if in.AllocationStorage != nil {
+		in, out := &in.AllocationStorage, &out.AllocationStorage
+		*out = new(string)
+		**out = **in
+	}
+	if in.IPv6Derivation != nil {
+		in, out := &in.IPv6Derivation, &out.IPv6Derivation
+		*out = new(string)
+		**out = **in
+	}
+	if in.MACAllocation != nil {
+		in, out := &in.MACAllocation, &out.MACAllocation
+		*out = new(MACAllocation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPoolSpec.
@@ -266,6 +426,13 @@ func (in *IPPoolStatus) DeepCopyInto(out *IPPoolStatus) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ConflictIPs != nil {
+		in, out := &in.ConflictIPs, &out.ConflictIPs
+		*out = make(PoolIPConflicts, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPoolStatus.
@@ -278,6 +445,26 @@ func (in *IPPoolStatus) DeepCopy() *IPPoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MACAllocation) DeepCopyInto(out *MACAllocation) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MACAllocation.
+func (in *MACAllocation) DeepCopy() *MACAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(MACAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MultusCNIConfigSpec) DeepCopyInto(out *MultusCNIConfigSpec) {
 	*out = *in
@@ -323,6 +510,72 @@ func (in *MultusCNIConfigSpec) DeepCopy() *MultusCNIConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeInterface) DeepCopyInto(out *NodeInterface) {
+	*out = *in
+	if in.Slaves != nil {
+		in, out := &in.Slaves, &out.Slaves
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VlanID != nil {
+		in, out := &in.VlanID, &out.VlanID
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NUMANode != nil {
+		in, out := &in.NUMANode, &out.NUMANode
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Speed != nil {
+		in, out := &in.Speed, &out.Speed
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SRIOV != nil {
+		in, out := &in.SRIOV, &out.SRIOV
+		*out = new(SRIOVStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeInterface.
+func (in *NodeInterface) DeepCopy() *NodeInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNetworkStatus) DeepCopyInto(out *NodeNetworkStatus) {
+	*out = *in
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]NodeInterface, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNetworkStatus.
+func (in *NodeNetworkStatus) DeepCopy() *NodeNetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodIPAllocation) DeepCopyInto(out *PodIPAllocation) {
 	*out = *in
@@ -345,6 +598,21 @@ func (in *PodIPAllocation) DeepCopy() *PodIPAllocation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolGroup) DeepCopyInto(out *PoolGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolGroup.
+func (in *PoolGroup) DeepCopy() *PoolGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolIPAllocation) DeepCopyInto(out *PoolIPAllocation) {
 	*out = *in
@@ -381,6 +649,43 @@ func (in PoolIPAllocations) DeepCopy() PoolIPAllocations {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolIPConflict) DeepCopyInto(out *PoolIPConflict) {
+	*out = *in
+	in.DetectedTime.DeepCopyInto(&out.DetectedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolIPConflict.
+func (in *PoolIPConflict) DeepCopy() *PoolIPConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolIPConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in PoolIPConflicts) DeepCopyInto(out *PoolIPConflicts) {
+	{
+		in := &in
+		*out = make(PoolIPConflicts, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolIPConflicts.
+func (in PoolIPConflicts) DeepCopy() PoolIPConflicts {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolIPConflicts)
+	in.DeepCopyInto(out)
+	return *out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolIPPreAllocation) DeepCopyInto(out *PoolIPPreAllocation) {
 	*out = *in
@@ -467,6 +772,21 @@ func (in *Route) DeepCopy() *Route {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SRIOVStatus) DeepCopyInto(out *SRIOVStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SRIOVStatus.
+func (in *SRIOVStatus) DeepCopy() *SRIOVStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SRIOVStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpiderCoordinator) DeepCopyInto(out *SpiderCoordinator) {
 	*out = *in
@@ -584,6 +904,64 @@ func (in *SpiderEndpointList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpiderIPClaim) DeepCopyInto(out *SpiderIPClaim) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpiderIPClaim.
+func (in *SpiderIPClaim) DeepCopy() *SpiderIPClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(SpiderIPClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpiderIPClaim) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpiderIPClaimList) DeepCopyInto(out *SpiderIPClaimList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SpiderIPClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpiderIPClaimList.
+func (in *SpiderIPClaimList) DeepCopy() *SpiderIPClaimList {
+	if in == nil {
+		return nil
+	}
+	out := new(SpiderIPClaimList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpiderIPClaimList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpiderIPPool) DeepCopyInto(out *SpiderIPPool) {
 	*out = *in
@@ -656,6 +1034,11 @@ func (in *SpiderIPvlanCniConfig) DeepCopyInto(out *SpiderIPvlanCniConfig) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MTU != nil {
+		in, out := &in.MTU, &out.MTU
+		*out = new(string)
+		**out = **in
+	}
 	if in.Bond != nil {
 		in, out := &in.Bond, &out.Bond
 		*out = new(BondConfig)
@@ -691,6 +1074,11 @@ func (in *SpiderMacvlanCniConfig) DeepCopyInto(out *SpiderMacvlanCniConfig) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MTU != nil {
+		in, out := &in.MTU, &out.MTU
+		*out = new(string)
+		**out = **in
+	}
 	if in.Bond != nil {
 		in, out := &in.Bond, &out.Bond
 		*out = new(BondConfig)
@@ -771,6 +1159,64 @@ func (in *SpiderMultusConfigList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpiderNodeNetwork) DeepCopyInto(out *SpiderNodeNetwork) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpiderNodeNetwork.
+func (in *SpiderNodeNetwork) DeepCopy() *SpiderNodeNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(SpiderNodeNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpiderNodeNetwork) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpiderNodeNetworkList) DeepCopyInto(out *SpiderNodeNetworkList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SpiderNodeNetwork, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpiderNodeNetworkList.
+func (in *SpiderNodeNetworkList) DeepCopy() *SpiderNodeNetworkList {
+	if in == nil {
+		return nil
+	}
+	out := new(SpiderNodeNetworkList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpiderNodeNetworkList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpiderReservedIP) DeepCopyInto(out *SpiderReservedIP) {
 	*out = *in
@@ -1013,6 +1459,26 @@ func (in *SubnetStatus) DeepCopy() *SubnetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VRF) DeepCopyInto(out *VRF) {
+	*out = *in
+	if in.Table != nil {
+		in, out := &in.Table, &out.Table
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VRF.
+func (in *VRF) DeepCopy() *VRF {
+	if in == nil {
+		return nil
+	}
+	out := new(VRF)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadEndpointStatus) DeepCopyInto(out *WorkloadEndpointStatus) {
 	*out = *in
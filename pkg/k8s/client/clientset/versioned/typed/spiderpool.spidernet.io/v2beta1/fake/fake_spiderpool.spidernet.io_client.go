@@ -27,6 +27,10 @@ func (c *FakeSpiderpoolV2beta1) SpiderMultusConfigs(namespace string) v2beta1.Sp
 	return &FakeSpiderMultusConfigs{c, namespace}
 }
 
+func (c *FakeSpiderpoolV2beta1) SpiderNodeNetworks() v2beta1.SpiderNodeNetworkInterface {
+	return &FakeSpiderNodeNetworks{c}
+}
+
 func (c *FakeSpiderpoolV2beta1) SpiderSubnets() v2beta1.SpiderSubnetInterface {
 	return &FakeSpiderSubnets{c}
 }
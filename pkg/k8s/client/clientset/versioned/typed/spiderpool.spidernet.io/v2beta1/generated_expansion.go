@@ -11,4 +11,6 @@ type SpiderIPPoolExpansion interface{}
 
 type SpiderMultusConfigExpansion interface{}
 
+type SpiderNodeNetworkExpansion interface{}
+
 type SpiderSubnetExpansion interface{}
@@ -18,6 +18,7 @@ type SpiderpoolV2beta1Interface interface {
 	SpiderCoordinatorsGetter
 	SpiderIPPoolsGetter
 	SpiderMultusConfigsGetter
+	SpiderNodeNetworksGetter
 	SpiderSubnetsGetter
 }
 
@@ -38,6 +39,10 @@ func (c *SpiderpoolV2beta1Client) SpiderMultusConfigs(namespace string) SpiderMu
 	return newSpiderMultusConfigs(c, namespace)
 }
 
+func (c *SpiderpoolV2beta1Client) SpiderNodeNetworks() SpiderNodeNetworkInterface {
+	return newSpiderNodeNetworks(c)
+}
+
 func (c *SpiderpoolV2beta1Client) SpiderSubnets() SpiderSubnetInterface {
 	return newSpiderSubnets(c)
 }
@@ -17,6 +17,8 @@ type Interface interface {
 	SpiderIPPools() SpiderIPPoolInformer
 	// SpiderMultusConfigs returns a SpiderMultusConfigInformer.
 	SpiderMultusConfigs() SpiderMultusConfigInformer
+	// SpiderNodeNetworks returns a SpiderNodeNetworkInformer.
+	SpiderNodeNetworks() SpiderNodeNetworkInformer
 	// SpiderSubnets returns a SpiderSubnetInformer.
 	SpiderSubnets() SpiderSubnetInformer
 }
@@ -47,6 +49,11 @@ func (v *version) SpiderMultusConfigs() SpiderMultusConfigInformer {
 	return &spiderMultusConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// SpiderNodeNetworks returns a SpiderNodeNetworkInformer.
+func (v *version) SpiderNodeNetworks() SpiderNodeNetworkInformer {
+	return &spiderNodeNetworkInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // SpiderSubnets returns a SpiderSubnetInformer.
 func (v *version) SpiderSubnets() SpiderSubnetInformer {
 	return &spiderSubnetInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -21,6 +21,10 @@ type SpiderMultusConfigListerExpansion interface{}
 // SpiderMultusConfigNamespaceLister.
 type SpiderMultusConfigNamespaceListerExpansion interface{}
 
+// SpiderNodeNetworkListerExpansion allows custom methods to be added to
+// SpiderNodeNetworkLister.
+type SpiderNodeNetworkListerExpansion interface{}
+
 // SpiderSubnetListerExpansion allows custom methods to be added to
 // SpiderSubnetLister.
 type SpiderSubnetListerExpansion interface{}
@@ -9,8 +9,11 @@ import (
 	"fmt"
 	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/spidernet-io/spiderpool/pkg/lock"
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
 )
 
 type Limiter interface {
@@ -57,10 +60,13 @@ type e struct {
 
 type empty struct{}
 
-func (q *queue) AcquireTicket(ctx context.Context, tickets ...string) error {
+func (q *queue) AcquireTicket(ctx context.Context, tickets ...string) (err error) {
 	logger := logutils.FromContext(ctx)
 	logger.Sugar().Debugf("Waiting in queue with expect tickets: %v", tickets)
 
+	_, span := tracing.Start(ctx, "limiter AcquireTicket", attribute.StringSlice("tickets", tickets))
+	defer func() { tracing.End(span, err) }()
+
 	// TODO(iiiceoo): When ctx times out or is canceled, AcquireTicket should
 	// not still be blocked.
 
@@ -0,0 +1,143 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package metric
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	api "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/lock"
+)
+
+const (
+	// spiderpool agent gateway health metrics name
+	gateway_reachable                     = metricPrefix + "gateway_reachable"
+	gateway_probe_failure_counts          = metricPrefix + "gateway_probe_failure_counts"
+	ippool_gateway_unreachable_pod_counts = metricPrefix + "ippool_gateway_unreachable_pod_counts"
+)
+
+const (
+	attrPod       = "pod"
+	attrInterface = "interface"
+	attrGateway   = "gateway"
+)
+
+// GatewayTarget is a gateway of a Pod interface probed by spiderpool-agent.
+type GatewayTarget struct {
+	Namespace string
+	Pod       string
+	Interface string
+	Gateway   string
+	IPPool    string
+}
+
+// Attributes returns the metric attributes of the target.
+func (t GatewayTarget) Attributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String(attrNamespace, t.Namespace),
+		attribute.String(attrPod, t.Pod),
+		attribute.String(attrInterface, t.Interface),
+		attribute.String(attrGateway, t.Gateway),
+		attribute.String(constant.KindSpiderIPPool, t.IPPool),
+	}
+}
+
+var (
+	// gateway health metrics in spiderpool-agent
+	GatewayHealth             = &gatewayGauges{targets: map[GatewayTarget]bool{}}
+	GatewayProbeFailureCounts instrument.Int64Counter
+)
+
+// gatewayGauges reports the reachability of the gateways probed with otel
+// async gauges.
+type gatewayGauges struct {
+	reachable       instrument.Int64ObservableGauge
+	unreachablePods instrument.Int64ObservableGauge
+
+	targets     map[GatewayTarget]bool
+	targetsLock lock.RWMutex
+}
+
+// Record replaces the reachability of all gateways probed.
+func (g *gatewayGauges) Record(targets map[GatewayTarget]bool) {
+	cp := make(map[GatewayTarget]bool, len(targets))
+	for target, reachable := range targets {
+		cp[target] = reachable
+	}
+
+	g.targetsLock.Lock()
+	g.targets = cp
+	g.targetsLock.Unlock()
+}
+
+// Get returns the reachability of the gateway last recorded.
+func (g *gatewayGauges) Get(target GatewayTarget) (reachable, ok bool) {
+	g.targetsLock.RLock()
+	defer g.targetsLock.RUnlock()
+
+	reachable, ok = g.targets[target]
+	return
+}
+
+// observe reports the reachability of all gateways recorded, and the Pods
+// which can not reach the gateway of each IPPool.
+func (g *gatewayGauges) observe(_ context.Context, observer api.Observer) error {
+	g.targetsLock.RLock()
+	defer g.targetsLock.RUnlock()
+
+	unreachablePods := map[string]int64{}
+	for target, reachable := range g.targets {
+		var value int64
+		if reachable {
+			value = 1
+		}
+		observer.ObserveInt64(g.reachable, value, target.Attributes()...)
+
+		if _, ok := unreachablePods[target.IPPool]; !ok {
+			unreachablePods[target.IPPool] = 0
+		}
+		if !reachable {
+			unreachablePods[target.IPPool]++
+		}
+	}
+
+	for pool, count := range unreachablePods {
+		observer.ObserveInt64(g.unreachablePods, count, attribute.String(constant.KindSpiderIPPool, pool))
+	}
+
+	return nil
+}
+
+// initSpiderpoolAgentGatewayMetrics will init spiderpool-agent gateway health metrics
+func initSpiderpoolAgentGatewayMetrics(ctx context.Context) error {
+	reachable, err := newMetricInt64Gauge(gateway_reachable, "spiderpool agent gateway reachability of single Pod interface, 1 for reachable and 0 for unreachable", false)
+	if nil != err {
+		return fmt.Errorf("failed to new spiderpool agent metric '%s', error: %v", gateway_reachable, err)
+	}
+	GatewayHealth.reachable = reachable
+
+	unreachablePods, err := newMetricInt64Gauge(ippool_gateway_unreachable_pod_counts, "spiderpool agent single SpiderIPPool corresponding Pod interface counts which can not reach the gateway", false)
+	if nil != err {
+		return fmt.Errorf("failed to new spiderpool agent metric '%s', error: %v", ippool_gateway_unreachable_pod_counts, err)
+	}
+	GatewayHealth.unreachablePods = unreachablePods
+
+	if _, err = meter.RegisterCallback(GatewayHealth.observe, reachable, unreachablePods); nil != err {
+		return fmt.Errorf("failed to register callback for spiderpool agent gateway health metrics, error: %v", err)
+	}
+
+	probeFailureCounts, err := newMetricInt64Counter(gateway_probe_failure_counts, "spiderpool agent gateway probe failure counts", false)
+	if nil != err {
+		return fmt.Errorf("failed to new spiderpool agent metric '%s', error: %v", gateway_probe_failure_counts, err)
+	}
+	GatewayProbeFailureCounts = probeFailureCounts
+	GatewayProbeFailureCounts.Add(ctx, 0)
+
+	return nil
+}
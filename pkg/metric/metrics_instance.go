@@ -237,6 +237,11 @@ func InitSpiderpoolAgentMetrics(ctx context.Context) error {
 	}
 	AutoPoolWaitedForAvailableCounts = autoPoolWaitedForAvailableCounts
 
+	err = initSpiderpoolAgentGatewayMetrics(ctx)
+	if nil != err {
+		return err
+	}
+
 	return nil
 }
 
@@ -542,5 +547,15 @@ func initSpiderpoolControllerCRMetrics(ctx context.Context) error {
 		return err
 	}
 
+	err = initIPPoolUsageMetrics()
+	if nil != err {
+		return err
+	}
+
+	err = initSubnetUsageMetrics()
+	if nil != err {
+		return err
+	}
+
 	return nil
 }
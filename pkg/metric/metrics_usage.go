@@ -0,0 +1,239 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package metric
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	api "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/lock"
+)
+
+const (
+	// spiderpool IPPool utilization metrics name
+	ippool_allocated_ip_counts           = metricPrefix + "ippool_allocated_ip_counts"
+	ippool_free_ip_counts                = metricPrefix + "ippool_free_ip_counts"
+	ippool_reserved_ip_counts            = metricPrefix + "ippool_reserved_ip_counts"
+	ippool_quarantined_ip_counts         = metricPrefix + "ippool_quarantined_ip_counts"
+	ippool_leaked_ip_counts              = metricPrefix + "ippool_leaked_ip_counts"
+	ippool_namespace_allocated_ip_counts = metricPrefix + "ippool_namespace_allocated_ip_counts"
+	ippool_allocation_rate               = metricPrefix + "ippool_allocation_rate"
+	ippool_release_rate                  = metricPrefix + "ippool_release_rate"
+	ippool_exhaustion_seconds            = metricPrefix + "ippool_exhaustion_seconds"
+
+	// spiderpool Subnet utilization metrics name
+	subnet_allocated_ip_counts = metricPrefix + "subnet_allocated_ip_counts"
+	subnet_free_ip_counts      = metricPrefix + "subnet_free_ip_counts"
+	subnet_reserved_ip_counts  = metricPrefix + "subnet_reserved_ip_counts"
+	subnet_allocation_rate     = metricPrefix + "subnet_allocation_rate"
+	subnet_release_rate        = metricPrefix + "subnet_release_rate"
+	subnet_exhaustion_seconds  = metricPrefix + "subnet_exhaustion_seconds"
+)
+
+const attrNamespace = "namespace"
+
+// IPUsage is the IP utilization of a SpiderIPPool or SpiderSubnet.
+type IPUsage struct {
+	// Allocated is the IPs allocated to Pods for SpiderIPPool, or the IPs
+	// allocated to the controlled IPPools for SpiderSubnet.
+	Allocated int64
+	// Free is the IPs available for allocation.
+	Free int64
+	// Reserved is the IPs excluded by 'spec.excludeIPs' or SpiderReservedIPs.
+	Reserved int64
+	// Quarantined is the IPs excluded for the conflicts detected, only for
+	// SpiderIPPool.
+	Quarantined int64
+	// Leaked is the IPs allocated to the Pods no longer existing, only for
+	// SpiderIPPool.
+	Leaked int64
+	// AllocatedByNamespace is the IPs allocated to the Pods of each
+	// Namespace, only for SpiderIPPool.
+	AllocatedByNamespace map[string]int64
+
+	// AllocationRate and ReleaseRate are the IPs allocated and released per
+	// second recently.
+	AllocationRate float64
+	ReleaseRate    float64
+	// ExhaustionSeconds is the seconds left before the free IPs run out with
+	// the recent allocation velocity, -1 if the allocated IPs are not growing.
+	ExhaustionSeconds float64
+}
+
+var (
+	// IPPool&Subnet utilization metrics in spiderpool-controller
+	IPPoolUsage = &usageGauges{kind: constant.KindSpiderIPPool, usages: map[string]IPUsage{}}
+	SubnetUsage = &usageGauges{kind: constant.KindSpiderSubnet, usages: map[string]IPUsage{}}
+)
+
+// usageGauges reports the IP utilization of each SpiderIPPool or SpiderSubnet
+// with otel async gauges.
+type usageGauges struct {
+	kind string
+
+	allocated            instrument.Int64ObservableGauge
+	free                 instrument.Int64ObservableGauge
+	reserved             instrument.Int64ObservableGauge
+	quarantined          instrument.Int64ObservableGauge
+	leaked               instrument.Int64ObservableGauge
+	namespaceAllocated   instrument.Int64ObservableGauge
+	allocationRate       instrument.Float64ObservableGauge
+	releaseRate          instrument.Float64ObservableGauge
+	exhaustionSeconds    instrument.Float64ObservableGauge
+	observableInstrument []instrument.Asynchronous
+
+	usages     map[string]IPUsage
+	usagesLock lock.RWMutex
+}
+
+// Record replaces the IP utilization of the object.
+func (u *usageGauges) Record(name string, usage IPUsage) {
+	u.usagesLock.Lock()
+	u.usages[name] = usage
+	u.usagesLock.Unlock()
+}
+
+// Delete stops reporting the IP utilization of the deleted object.
+func (u *usageGauges) Delete(name string) {
+	u.usagesLock.Lock()
+	delete(u.usages, name)
+	u.usagesLock.Unlock()
+}
+
+// Get returns the IP utilization of the object last recorded.
+func (u *usageGauges) Get(name string) (IPUsage, bool) {
+	u.usagesLock.RLock()
+	defer u.usagesLock.RUnlock()
+
+	usage, ok := u.usages[name]
+	return usage, ok
+}
+
+func (u *usageGauges) newInt64Gauge(metricName, description string) (instrument.Int64ObservableGauge, error) {
+	gauge, err := newMetricInt64Gauge(metricName, description, false)
+	if nil != err {
+		return nil, fmt.Errorf("failed to new spiderpool controller metric '%s', error: %v", metricName, err)
+	}
+	u.observableInstrument = append(u.observableInstrument, gauge)
+
+	return gauge, nil
+}
+
+func (u *usageGauges) newFloat64Gauge(metricName, description string) (instrument.Float64ObservableGauge, error) {
+	gauge, err := newMetricFloat64Gauge(metricName, description, false)
+	if nil != err {
+		return nil, fmt.Errorf("failed to new spiderpool controller metric '%s', error: %v", metricName, err)
+	}
+	u.observableInstrument = append(u.observableInstrument, gauge)
+
+	return gauge, nil
+}
+
+// observe reports the IP utilization of all objects recorded.
+func (u *usageGauges) observe(_ context.Context, observer api.Observer) error {
+	u.usagesLock.RLock()
+	defer u.usagesLock.RUnlock()
+
+	for name, usage := range u.usages {
+		attr := attribute.String(u.kind, name)
+		observer.ObserveInt64(u.allocated, usage.Allocated, attr)
+		observer.ObserveInt64(u.free, usage.Free, attr)
+		observer.ObserveInt64(u.reserved, usage.Reserved, attr)
+		observer.ObserveFloat64(u.allocationRate, usage.AllocationRate, attr)
+		observer.ObserveFloat64(u.releaseRate, usage.ReleaseRate, attr)
+		observer.ObserveFloat64(u.exhaustionSeconds, usage.ExhaustionSeconds, attr)
+
+		if u.quarantined != nil {
+			observer.ObserveInt64(u.quarantined, usage.Quarantined, attr)
+		}
+		if u.leaked != nil {
+			observer.ObserveInt64(u.leaked, usage.Leaked, attr)
+		}
+		if u.namespaceAllocated != nil {
+			for namespace, count := range usage.AllocatedByNamespace {
+				observer.ObserveInt64(u.namespaceAllocated, count, attr, attribute.String(attrNamespace, namespace))
+			}
+		}
+	}
+
+	return nil
+}
+
+// initIPPoolUsageMetrics will init spiderpool-controller SpiderIPPool utilization metrics
+func initIPPoolUsageMetrics() error {
+	u := IPPoolUsage
+	u.observableInstrument = nil
+
+	var err error
+	if u.allocated, err = u.newInt64Gauge(ippool_allocated_ip_counts, "spiderpool single SpiderIPPool corresponding IP counts allocated to Pods"); nil != err {
+		return err
+	}
+	if u.free, err = u.newInt64Gauge(ippool_free_ip_counts, "spiderpool single SpiderIPPool corresponding free IP counts"); nil != err {
+		return err
+	}
+	if u.reserved, err = u.newInt64Gauge(ippool_reserved_ip_counts, "spiderpool single SpiderIPPool corresponding IP counts excluded or reserved"); nil != err {
+		return err
+	}
+	if u.quarantined, err = u.newInt64Gauge(ippool_quarantined_ip_counts, "spiderpool single SpiderIPPool corresponding IP counts quarantined for conflicts"); nil != err {
+		return err
+	}
+	if u.leaked, err = u.newInt64Gauge(ippool_leaked_ip_counts, "spiderpool single SpiderIPPool corresponding IP counts allocated to Pods no longer existing"); nil != err {
+		return err
+	}
+	if u.namespaceAllocated, err = u.newInt64Gauge(ippool_namespace_allocated_ip_counts, "spiderpool single SpiderIPPool corresponding IP counts allocated to the Pods of each Namespace"); nil != err {
+		return err
+	}
+	if u.allocationRate, err = u.newFloat64Gauge(ippool_allocation_rate, "spiderpool single SpiderIPPool corresponding IPs allocated per second recently"); nil != err {
+		return err
+	}
+	if u.releaseRate, err = u.newFloat64Gauge(ippool_release_rate, "spiderpool single SpiderIPPool corresponding IPs released per second recently"); nil != err {
+		return err
+	}
+	if u.exhaustionSeconds, err = u.newFloat64Gauge(ippool_exhaustion_seconds, "spiderpool single SpiderIPPool corresponding seconds left before the free IPs run out, -1 if not growing"); nil != err {
+		return err
+	}
+
+	if _, err = meter.RegisterCallback(u.observe, u.observableInstrument...); nil != err {
+		return fmt.Errorf("failed to register callback for spiderpool SpiderIPPool utilization metrics, error: %v", err)
+	}
+
+	return nil
+}
+
+// initSubnetUsageMetrics will init spiderpool-controller SpiderSubnet utilization metrics
+func initSubnetUsageMetrics() error {
+	u := SubnetUsage
+	u.observableInstrument = nil
+
+	var err error
+	if u.allocated, err = u.newInt64Gauge(subnet_allocated_ip_counts, "spiderpool single SpiderSubnet corresponding IP counts allocated to IPPools"); nil != err {
+		return err
+	}
+	if u.free, err = u.newInt64Gauge(subnet_free_ip_counts, "spiderpool single SpiderSubnet corresponding free IP counts"); nil != err {
+		return err
+	}
+	if u.reserved, err = u.newInt64Gauge(subnet_reserved_ip_counts, "spiderpool single SpiderSubnet corresponding IP counts excluded"); nil != err {
+		return err
+	}
+	if u.allocationRate, err = u.newFloat64Gauge(subnet_allocation_rate, "spiderpool single SpiderSubnet corresponding IPs allocated per second recently"); nil != err {
+		return err
+	}
+	if u.releaseRate, err = u.newFloat64Gauge(subnet_release_rate, "spiderpool single SpiderSubnet corresponding IPs released per second recently"); nil != err {
+		return err
+	}
+	if u.exhaustionSeconds, err = u.newFloat64Gauge(subnet_exhaustion_seconds, "spiderpool single SpiderSubnet corresponding seconds left before the free IPs run out, -1 if not growing"); nil != err {
+		return err
+	}
+
+	if _, err = meter.RegisterCallback(u.observe, u.observableInstrument...); nil != err {
+		return fmt.Errorf("failed to register callback for spiderpool SpiderSubnet utilization metrics, error: %v", err)
+	}
+
+	return nil
+}
@@ -9,11 +9,15 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/containernetworking/cni/pkg/types"
 	netv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ktypes "k8s.io/apimachinery/pkg/types"
@@ -31,11 +35,13 @@ import (
 	spiderpoolcmd "github.com/spidernet-io/spiderpool/cmd/spiderpool/cmd"
 	"github.com/spidernet-io/spiderpool/pkg/constant"
 	"github.com/spidernet-io/spiderpool/pkg/election"
+	"github.com/spidernet-io/spiderpool/pkg/event"
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
 	crdclientset "github.com/spidernet-io/spiderpool/pkg/k8s/client/clientset/versioned"
 	"github.com/spidernet-io/spiderpool/pkg/k8s/client/informers/externalversions"
 	informers "github.com/spidernet-io/spiderpool/pkg/k8s/client/informers/externalversions/spiderpool.spidernet.io/v2beta1"
 	listers "github.com/spidernet-io/spiderpool/pkg/k8s/client/listers/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/lock"
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
 )
 
@@ -47,6 +53,11 @@ type MultusConfigController struct {
 	multusConfigLister    listers.SpiderMultusConfigLister
 	multusConfigSynced    cache.InformerSynced
 	multusConfigWorkqueue workqueue.RateLimitingInterface
+
+	// interfaceReports records the last result of the interface check of
+	// each MultusConfig, so that the Events are only recorded on changes.
+	interfaceReportsLock lock.Mutex
+	interfaceReports     map[string]string
 }
 
 type MultusConfigControllerConfig struct {
@@ -55,6 +66,9 @@ type MultusConfigControllerConfig struct {
 	WorkQueueRequeueDelayDuration time.Duration
 	LeaderRetryElectGap           time.Duration
 	ResyncPeriod                  time.Duration
+	// InterfaceCheck records Warning Events on the MultusConfig referring
+	// to the interfaces missing on the nodes unless it is disabled.
+	InterfaceCheck string
 }
 
 func NewMultusConfigController(multusConfigControllerConfig MultusConfigControllerConfig, client client.Client) *MultusConfigController {
@@ -63,6 +77,7 @@ func NewMultusConfigController(multusConfigControllerConfig MultusConfigControll
 	m := &MultusConfigController{
 		MultusConfigControllerConfig: multusConfigControllerConfig,
 		client:                       client,
+		interfaceReports:             map[string]string{},
 	}
 
 	return m
@@ -135,7 +150,7 @@ func (mcc *MultusConfigController) addEventHandlers(multusConfigInformer informe
 		UpdateFunc: func(oldObj, newObj interface{}) {
 			mcc.enqueueMultusConfig(newObj)
 		},
-		DeleteFunc: nil,
+		DeleteFunc: mcc.forgetMultusConfig,
 	})
 	if nil != err {
 		return err
@@ -155,6 +170,18 @@ func (mcc *MultusConfigController) enqueueMultusConfig(obj interface{}) {
 	informerLogger.Sugar().Debugf("added %s to MultusConfig workqueue", key)
 }
 
+func (mcc *MultusConfigController) forgetMultusConfig(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if nil != err {
+		informerLogger.Sugar().Errorf("failed to parse object %+v meta key", obj)
+		return
+	}
+
+	mcc.interfaceReportsLock.Lock()
+	delete(mcc.interfaceReports, key)
+	mcc.interfaceReportsLock.Unlock()
+}
+
 func (mcc *MultusConfigController) Run(stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer mcc.multusConfigWorkqueue.ShutDown()
@@ -260,6 +287,10 @@ func (mcc *MultusConfigController) syncHandler(ctx context.Context, multusConfig
 		return nil
 	}
 
+	if mcc.InterfaceCheck == InterfaceCheckWarn || mcc.InterfaceCheck == InterfaceCheckBlock {
+		mcc.checkNodeInterfaces(ctx, multusConfig)
+	}
+
 	isExist := true
 
 	// use the annotation specified name as the CNI configuration name if set
@@ -342,6 +373,50 @@ func (mcc *MultusConfigController) syncHandler(ctx context.Context, multusConfig
 	return nil
 }
 
+func (mcc *MultusConfigController) checkNodeInterfaces(ctx context.Context, multusConfig *spiderpoolv2beta1.SpiderMultusConfig) {
+	errs, err := checkNodeInterfaces(ctx, mcc.client, multusConfig)
+	if nil != err {
+		informerLogger.Sugar().Warnf("failed to check the interfaces of MultusConfig %s/%s on the nodes: %v", multusConfig.Namespace, multusConfig.Name, err)
+		return
+	}
+
+	details := make([]string, 0, len(errs))
+	for _, e := range errs {
+		details = append(details, e.Detail)
+	}
+	sort.Strings(details)
+
+	// The check runs on every sync and resync, only record the Events when
+	// the missing interfaces change to avoid flooding the Event stream.
+	if !mcc.updateInterfaceReport(multusConfig.Namespace+"/"+multusConfig.Name, strings.Join(details, "\n")) {
+		return
+	}
+
+	for _, detail := range details {
+		informerLogger.Sugar().Warnf("MultusConfig %s/%s: %s", multusConfig.Namespace, multusConfig.Name, detail)
+		event.EventRecorder.Event(multusConfig, corev1.EventTypeWarning, constant.EventReasonNodeInterfaceMissing, detail)
+	}
+}
+
+// updateInterfaceReport records the report of the interface check of the
+// MultusConfig, and returns true if it differs from the last one.
+func (mcc *MultusConfigController) updateInterfaceReport(key, report string) bool {
+	mcc.interfaceReportsLock.Lock()
+	defer mcc.interfaceReportsLock.Unlock()
+
+	last, ok := mcc.interfaceReports[key]
+	if len(report) == 0 {
+		delete(mcc.interfaceReports, key)
+		return false
+	}
+	if ok && last == report {
+		return false
+	}
+
+	mcc.interfaceReports[key] = report
+	return true
+}
+
 func generateNetAttachDef(netAttachName string, multusConf *spiderpoolv2beta1.SpiderMultusConfig) (*netv1.NetworkAttachmentDefinition, error) {
 	multusConfSpec := multusConf.Spec.DeepCopy()
 	anno := multusConf.Annotations
@@ -351,7 +426,7 @@ func generateNetAttachDef(netAttachName string, multusConf *spiderpoolv2beta1.Sp
 	// with Kubernetes OpenAPI validation, multusConfSpec.EnableCoordinator must not be nil
 	hasCoordinator := *multusConfSpec.EnableCoordinator
 	if hasCoordinator {
-		coordinatorCNIConf := generateCoordinatorCNIConf(multusConfSpec.CoordinatorConfig)
+		coordinatorCNIConf := generateCoordinatorCNIConf(multusConfSpec.CoordinatorConfig, podMTU(*multusConfSpec))
 		// head insertion later
 		plugins = append(plugins, coordinatorCNIConf)
 	}
@@ -466,6 +541,12 @@ func generateMacvlanCNIConf(multusConfSpec spiderpoolv2beta1.MultusCNIConfigSpec
 		Mode:   "bridge",
 	}
 
+	// the coordinator applies the auto MTU, see podMTU
+	if multusConfSpec.MacvlanConfig.MTU != nil && *multusConfSpec.MacvlanConfig.MTU != coordinatorcmd.MTUAuto {
+		// the webhook makes sure that the MTU is a number
+		netConf.MTU, _ = strconv.Atoi(*multusConfSpec.MacvlanConfig.MTU)
+	}
+
 	// set default IPPools for spiderpool cni configuration
 	if multusConfSpec.MacvlanConfig.SpiderpoolConfigPools != nil {
 		netConf.IPAM.DefaultIPv4IPPool = multusConfSpec.MacvlanConfig.SpiderpoolConfigPools.IPv4IPPool
@@ -499,6 +580,12 @@ func generateIPvlanCNIConf(multusConfSpec spiderpoolv2beta1.MultusCNIConfigSpec)
 		Master: masterName,
 	}
 
+	// the coordinator applies the auto MTU, see podMTU
+	if multusConfSpec.IPVlanConfig.MTU != nil && *multusConfSpec.IPVlanConfig.MTU != coordinatorcmd.MTUAuto {
+		// the webhook makes sure that the MTU is a number
+		netConf.MTU, _ = strconv.Atoi(*multusConfSpec.IPVlanConfig.MTU)
+	}
+
 	// set default IPPools for spiderpool cni configuration
 	if multusConfSpec.IPVlanConfig.SpiderpoolConfigPools != nil {
 		netConf.IPAM.DefaultIPv4IPPool = multusConfSpec.IPVlanConfig.SpiderpoolConfigPools.IPv4IPPool
@@ -550,11 +637,30 @@ func generateIfacer(master []string, vlanID int32, bond *spiderpoolv2beta1.BondC
 	return netConf
 }
 
-func generateCoordinatorCNIConf(coordinatorSpec *spiderpoolv2beta1.CoordinatorSpec) interface{} {
+// podMTU returns the MTU of the Pod's interface which is applied by the
+// coordinator, it is only the auto MTU of macvlan and ipvlan interfaces, for
+// the coordinator finds their master interfaces on the host.
+func podMTU(multusConfSpec spiderpoolv2beta1.MultusCNIConfigSpec) string {
+	var mtu *string
+	switch multusConfSpec.CniType {
+	case MacVlanType:
+		mtu = multusConfSpec.MacvlanConfig.MTU
+	case IpVlanType:
+		mtu = multusConfSpec.IPVlanConfig.MTU
+	}
+
+	if mtu != nil && *mtu == coordinatorcmd.MTUAuto {
+		return coordinatorcmd.MTUAuto
+	}
+	return ""
+}
+
+func generateCoordinatorCNIConf(coordinatorSpec *spiderpoolv2beta1.CoordinatorSpec, podMTU string) interface{} {
 	coordinatorNetConf := coordinatorcmd.Config{
 		NetConf: types.NetConf{
 			Type: coordinatorBinName,
 		},
+		PodMTU: podMTU,
 	}
 
 	// coordinatorSpec could be nil, and we just need the coorinator CNI specified and use the default configuration
@@ -586,6 +692,48 @@ func generateCoordinatorCNIConf(coordinatorSpec *spiderpoolv2beta1.CoordinatorSp
 		if coordinatorSpec.DetectGateway != nil {
 			coordinatorNetConf.DetectGateway = coordinatorSpec.DetectGateway
 		}
+		if dg := coordinatorSpec.DetectGatewayOptions; dg != nil {
+			coordinatorNetConf.DetectGatewayOptions = &coordinatorcmd.DetectGatewayOptions{
+				LossTolerance: dg.LossTolerance,
+				WarnOnly:      dg.WarnOnly,
+			}
+			if dg.Method != nil {
+				coordinatorNetConf.DetectGatewayOptions.Method = *dg.Method
+			}
+		}
+		if gn := coordinatorSpec.GratuitousNeighbor; gn != nil {
+			coordinatorNetConf.GratuitousNeighbor = &coordinatorcmd.GratuitousNeighborOptions{
+				Enabled: gn.Enabled,
+			}
+			if gn.Count != nil {
+				coordinatorNetConf.GratuitousNeighbor.Count = *gn.Count
+			}
+			if gn.Interval != nil {
+				coordinatorNetConf.GratuitousNeighbor.Interval = *gn.Interval
+			}
+		}
+		if coordinatorSpec.MTU != nil {
+			coordinatorNetConf.MTU = *coordinatorSpec.MTU
+		}
+		if bw := coordinatorSpec.Bandwidth; bw != nil {
+			coordinatorNetConf.Bandwidth = &coordinatorcmd.BandwidthOptions{
+				DSCP: bw.DSCP,
+			}
+			if bw.IngressRate != nil {
+				coordinatorNetConf.Bandwidth.IngressRate = *bw.IngressRate
+			}
+			if bw.EgressRate != nil {
+				coordinatorNetConf.Bandwidth.EgressRate = *bw.EgressRate
+			}
+		}
+		if vrf := coordinatorSpec.VRF; vrf != nil {
+			coordinatorNetConf.VRF = &coordinatorcmd.VRFOptions{
+				Name: vrf.Name,
+			}
+			if vrf.Table != nil {
+				coordinatorNetConf.VRF.Table = *vrf.Table
+			}
+		}
 	}
 
 	return coordinatorNetConf
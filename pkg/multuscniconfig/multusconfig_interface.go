@@ -0,0 +1,175 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package multuscniconfig
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+)
+
+// Modes of checking the interfaces referenced by the MultusConfig against
+// the SpiderNodeNetworks of the nodes.
+const (
+	InterfaceCheckDisabled = "disabled"
+	// InterfaceCheckWarn records Warning Events on the MultusConfig.
+	InterfaceCheckWarn = "warn"
+	// InterfaceCheckBlock records Warning Events and also rejects the
+	// MultusConfig in the webhook.
+	InterfaceCheckBlock = "block"
+)
+
+func validateInterfaceCheckMode(mode string) error {
+	switch mode {
+	case InterfaceCheckDisabled, InterfaceCheckWarn, InterfaceCheckBlock:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown interface check mode '%s', expect '%s', '%s' or '%s'",
+			constant.ErrWrongInput, mode, InterfaceCheckDisabled, InterfaceCheckWarn, InterfaceCheckBlock)
+	}
+}
+
+// checkNodeInterfaces checks that the master interfaces of macvlan and ipvlan
+// exist in the SpiderNodeNetworks, and that the SR-IOV resource is
+// allocatable, on the nodes selected by the nodeAffinity of the IPPools the
+// MultusConfig refers to. All nodes are selected if it refers to no IPPool or
+// to an IPPool without nodeAffinity. The nodes whose SpiderNodeNetwork is not
+// published yet are skipped for the master interfaces.
+func checkNodeInterfaces(ctx context.Context, reader client.Reader, multusConfig *spiderpoolv2beta1.SpiderMultusConfig) (field.ErrorList, error) {
+	var fieldPath *field.Path
+	var masters []string
+	var resourceName string
+	var pools *spiderpoolv2beta1.SpiderpoolPools
+
+	spec := multusConfig.Spec
+	switch {
+	case spec.CniType == MacVlanType && spec.MacvlanConfig != nil:
+		fieldPath = macvlanConfigField.Child("master")
+		masters = spec.MacvlanConfig.Master
+		pools = spec.MacvlanConfig.SpiderpoolConfigPools
+	case spec.CniType == IpVlanType && spec.IPVlanConfig != nil:
+		fieldPath = ipvlanConfigField.Child("master")
+		masters = spec.IPVlanConfig.Master
+		pools = spec.IPVlanConfig.SpiderpoolConfigPools
+	case spec.CniType == SriovType && spec.SriovConfig != nil:
+		fieldPath = sriovConfigField.Child("resourceName")
+		resourceName = spec.SriovConfig.ResourceName
+		pools = spec.SriovConfig.SpiderpoolConfigPools
+	default:
+		return nil, nil
+	}
+
+	nodes, err := selectNodes(ctx, reader, pools)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs field.ErrorList
+	if resourceName != "" {
+		var missing []string
+		for _, node := range nodes {
+			if q, ok := node.Status.Allocatable[corev1.ResourceName(resourceName)]; !ok || q.IsZero() {
+				missing = append(missing, node.Name)
+			}
+		}
+		if len(missing) != 0 {
+			errs = append(errs, field.Invalid(fieldPath, resourceName, fmt.Sprintf("resource %s is not allocatable on Nodes %v", resourceName, missing)))
+		}
+
+		return errs, nil
+	}
+
+	var nodeNetworkList spiderpoolv2beta1.SpiderNodeNetworkList
+	if err := reader.List(ctx, &nodeNetworkList); err != nil {
+		return nil, err
+	}
+	interfaces := make(map[string]map[string]struct{}, len(nodeNetworkList.Items))
+	for _, nodeNetwork := range nodeNetworkList.Items {
+		names := make(map[string]struct{}, len(nodeNetwork.Status.Interfaces))
+		for _, iface := range nodeNetwork.Status.Interfaces {
+			names[iface.Name] = struct{}{}
+		}
+		interfaces[nodeNetwork.Name] = names
+	}
+
+	for _, master := range masters {
+		var missing []string
+		for _, node := range nodes {
+			names, ok := interfaces[node.Name]
+			if !ok {
+				continue
+			}
+			if _, ok := names[master]; !ok {
+				missing = append(missing, node.Name)
+			}
+		}
+		if len(missing) != 0 {
+			errs = append(errs, field.Invalid(fieldPath, master, fmt.Sprintf("interface %s is missing on Nodes %v", master, missing)))
+		}
+	}
+
+	return errs, nil
+}
+
+// selectNodes returns the nodes selected by the nodeAffinity of the IPPools,
+// sorted by name. The IPPools which do not exist are ignored.
+func selectNodes(ctx context.Context, reader client.Reader, pools *spiderpoolv2beta1.SpiderpoolPools) ([]corev1.Node, error) {
+	var nodeList corev1.NodeList
+	if err := reader.List(ctx, &nodeList); err != nil {
+		return nil, err
+	}
+	sort.Slice(nodeList.Items, func(i, j int) bool {
+		return nodeList.Items[i].Name < nodeList.Items[j].Name
+	})
+
+	if pools == nil {
+		return nodeList.Items, nil
+	}
+
+	var selectors []labels.Selector
+	for _, poolName := range append(append([]string{}, pools.IPv4IPPool...), pools.IPv6IPPool...) {
+		var ipPool spiderpoolv2beta1.SpiderIPPool
+		if err := reader.Get(ctx, client.ObjectKey{Name: poolName}, &ipPool); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if ipPool.Spec.NodeAffinity == nil {
+			return nodeList.Items, nil
+		}
+		selector, err := metav1.LabelSelectorAsSelector(ipPool.Spec.NodeAffinity)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+	}
+
+	if len(selectors) == 0 {
+		return nodeList.Items, nil
+	}
+
+	var nodes []corev1.Node
+	for _, node := range nodeList.Items {
+		for _, selector := range selectors {
+			if selector.Matches(labels.Set(node.Labels)) {
+				nodes = append(nodes, node)
+				break
+			}
+		}
+	}
+
+	return nodes, nil
+}
@@ -5,9 +5,11 @@ package multuscniconfig
 
 import (
 	"fmt"
+	"strconv"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	coordinatorcmd "github.com/spidernet-io/spiderpool/cmd/coordinator/cmd"
 	"github.com/spidernet-io/spiderpool/pkg/coordinatormanager"
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
 )
@@ -18,6 +20,8 @@ var (
 	ipvlanConfigField    = field.NewPath("spec").Child("ipvlanConfig")
 	sriovConfigField     = field.NewPath("spec").Child("sriovConfig")
 	customCniConfigField = field.NewPath("spec").Child("customCniTypeConfig")
+
+	coordinatorPodMACPrefixField = field.NewPath("spec").Child("coordinator").Child("podMACPrefix")
 )
 
 func validateCNIConfig(multusConfig *spiderpoolv2beta1.SpiderMultusConfig) *field.Error {
@@ -31,6 +35,10 @@ func validateCNIConfig(multusConfig *spiderpoolv2beta1.SpiderMultusConfig) *fiel
 			return field.Invalid(macvlanConfigField, *multusConfig.Spec.MacvlanConfig, err.Error())
 		}
 
+		if err := validatePodMTU(multusConfig.Spec.MacvlanConfig.MTU, multusConfig.Spec.EnableCoordinator); err != nil {
+			return field.Invalid(macvlanConfigField.Child("mtu"), *multusConfig.Spec.MacvlanConfig.MTU, err.Error())
+		}
+
 		if multusConfig.Spec.IPVlanConfig != nil || multusConfig.Spec.SriovConfig != nil || multusConfig.Spec.CustomCNIConfig != nil {
 			return field.Forbidden(cniTypeField, fmt.Sprintf("the cniType %s only supports %s, please remove other CNI configs", MacVlanType, macvlanConfigField.String()))
 		}
@@ -44,6 +52,10 @@ func validateCNIConfig(multusConfig *spiderpoolv2beta1.SpiderMultusConfig) *fiel
 			return field.Invalid(ipvlanConfigField, *multusConfig.Spec.IPVlanConfig, err.Error())
 		}
 
+		if err := validatePodMTU(multusConfig.Spec.IPVlanConfig.MTU, multusConfig.Spec.EnableCoordinator); err != nil {
+			return field.Invalid(ipvlanConfigField.Child("mtu"), *multusConfig.Spec.IPVlanConfig.MTU, err.Error())
+		}
+
 		if multusConfig.Spec.MacvlanConfig != nil || multusConfig.Spec.SriovConfig != nil || multusConfig.Spec.CustomCNIConfig != nil {
 			return field.Forbidden(cniTypeField, fmt.Sprintf("the cniType %s only supports %s, please remove other CNI configs", IpVlanType, ipvlanConfigField.String()))
 		}
@@ -83,3 +95,24 @@ func validateVlanCNIConfig(master []string, bond *spiderpoolv2beta1.BondConfig)
 
 	return nil
 }
+
+// validatePodMTU checks the MTU of the Pod's interface is auto or in the range
+// of 68 to 65535, and the auto MTU is applied by the coordinator.
+func validatePodMTU(mtu *string, enableCoordinator *bool) error {
+	if mtu == nil {
+		return nil
+	}
+
+	if *mtu == coordinatorcmd.MTUAuto {
+		if enableCoordinator == nil || !*enableCoordinator {
+			return fmt.Errorf("the mtu %s is applied by the coordinator, please enable the coordinator", coordinatorcmd.MTUAuto)
+		}
+		return nil
+	}
+
+	v, err := strconv.Atoi(*mtu)
+	if err != nil || v < 68 || v > 65535 {
+		return fmt.Errorf("must be %s or in the range of 68 to 65535", coordinatorcmd.MTUAuto)
+	}
+	return nil
+}
@@ -10,8 +10,10 @@ import (
 	"go.uber.org/zap"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	apitypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/spidernet-io/spiderpool/pkg/constant"
@@ -21,13 +23,28 @@ import (
 
 var logger *zap.Logger
 
-type MultusConfigWebhook struct{}
+type MultusConfigWebhook struct {
+	Client client.Reader
+	// InterfaceCheck rejects the MultusConfig referring to the interfaces
+	// missing on the nodes if it is block.
+	InterfaceCheck string
+}
 
 func (mcw *MultusConfigWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	if logger == nil {
 		logger = logutils.Logger.Named("MultusConfig-Webhook")
 	}
 
+	if mcw.InterfaceCheck == "" {
+		mcw.InterfaceCheck = InterfaceCheckDisabled
+	}
+	if err := validateInterfaceCheckMode(mcw.InterfaceCheck); err != nil {
+		return err
+	}
+	if mcw.InterfaceCheck == InterfaceCheckBlock && mcw.Client == nil {
+		return fmt.Errorf("k8s client %w", constant.ErrMissingRequiredParam)
+	}
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&spiderpoolv2beta1.SpiderMultusConfig{}).
 		WithValidator(mcw).
@@ -54,7 +71,11 @@ func (mcw *MultusConfigWebhook) ValidateCreate(ctx context.Context, obj runtime.
 		)
 	}
 
-	return nil
+	if err := mcw.validatePodMACPrefix(logutils.IntoContext(ctx, log), multusConfig); err != nil {
+		return err
+	}
+
+	return mcw.validateNodeInterfaces(logutils.IntoContext(ctx, log), multusConfig)
 }
 
 func (mcw *MultusConfigWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
@@ -79,6 +100,97 @@ func (mcw *MultusConfigWebhook) ValidateUpdate(ctx context.Context, oldObj, newO
 		)
 	}
 
+	if err := mcw.validatePodMACPrefix(logutils.IntoContext(ctx, log), newMultusConfig); err != nil {
+		return err
+	}
+
+	return mcw.validateNodeInterfaces(logutils.IntoContext(ctx, log), newMultusConfig)
+}
+
+// validateNodeInterfaces rejects the MultusConfig referring to the interfaces
+// missing on the nodes in block mode. The failure to check is only logged,
+// so that an unhealthy API server does not block the MultusConfig.
+func (mcw *MultusConfigWebhook) validateNodeInterfaces(ctx context.Context, multusConfig *spiderpoolv2beta1.SpiderMultusConfig) error {
+	if mcw.InterfaceCheck != InterfaceCheckBlock {
+		return nil
+	}
+
+	log := logutils.FromContext(ctx)
+	errs, err := checkNodeInterfaces(ctx, mcw.Client, multusConfig)
+	if nil != err {
+		log.Sugar().Warnf("Failed to check the interfaces on the nodes: %v", err)
+		return nil
+	}
+	if len(errs) != 0 {
+		return apierrors.NewInvalid(
+			spiderpoolv2beta1.SchemeGroupVersion.WithKind(constant.KindSpiderMultusConfig).GroupKind(),
+			multusConfig.Name,
+			errs,
+		)
+	}
+
+	return nil
+}
+
+// validatePodMACPrefix rejects the podMACPrefix of the coordinator with the
+// default IPv6 IPPools deriving EUI-64 addresses, which are derived from the
+// hardware address before the coordinator overrides it. The failure to get
+// the IPPools is only logged, like validateNodeInterfaces.
+func (mcw *MultusConfigWebhook) validatePodMACPrefix(ctx context.Context, multusConfig *spiderpoolv2beta1.SpiderMultusConfig) error {
+	coordinator := multusConfig.Spec.CoordinatorConfig
+	if mcw.Client == nil || multusConfig.Spec.EnableCoordinator == nil || !*multusConfig.Spec.EnableCoordinator ||
+		coordinator == nil || coordinator.PodMACPrefix == nil || *coordinator.PodMACPrefix == "" {
+		return nil
+	}
+
+	pools := defaultIPPools(multusConfig.Spec)
+	if pools == nil {
+		return nil
+	}
+
+	log := logutils.FromContext(ctx)
+	for _, poolName := range pools.IPv6IPPool {
+		var ipPool spiderpoolv2beta1.SpiderIPPool
+		if err := mcw.Client.Get(ctx, apitypes.NamespacedName{Name: poolName}, &ipPool); err != nil {
+			if !apierrors.IsNotFound(err) {
+				log.Sugar().Warnf("Failed to get IPPool %s: %v", poolName, err)
+			}
+			continue
+		}
+
+		if ipPool.Spec.IPv6Derivation != nil && *ipPool.Spec.IPv6Derivation == constant.IPv6DerivationEUI64 {
+			return apierrors.NewInvalid(
+				spiderpoolv2beta1.SchemeGroupVersion.WithKind(constant.KindSpiderMultusConfig).GroupKind(),
+				multusConfig.Name,
+				field.ErrorList{field.Forbidden(
+					coordinatorPodMACPrefixField,
+					fmt.Sprintf("the default IPv6 IPPool %s derives the EUI-64 addresses from the hardware address of the Pod's interface, which can't be overridden", poolName),
+				)},
+			)
+		}
+	}
+
+	return nil
+}
+
+// defaultIPPools returns the default IPPools of the spiderpool IPAM of the
+// MultusConfig.
+func defaultIPPools(spec spiderpoolv2beta1.MultusCNIConfigSpec) *spiderpoolv2beta1.SpiderpoolPools {
+	switch spec.CniType {
+	case MacVlanType:
+		if spec.MacvlanConfig != nil {
+			return spec.MacvlanConfig.SpiderpoolConfigPools
+		}
+	case IpVlanType:
+		if spec.IPVlanConfig != nil {
+			return spec.IPVlanConfig.SpiderpoolConfigPools
+		}
+	case SriovType:
+		if spec.SriovConfig != nil {
+			return spec.SriovConfig.SpiderpoolConfigPools
+		}
+	}
+
 	return nil
 }
 
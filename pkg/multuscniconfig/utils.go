@@ -26,12 +26,14 @@ type MacvlanNetConf struct {
 	IPAM   spiderpoolcmd.IPAMConfig `json:"ipam"`
 	Master string                   `json:"master"`
 	Mode   string                   `json:"mode"`
+	MTU    int                      `json:"mtu,omitempty"`
 }
 
 type IPvlanNetConf struct {
 	Type   string                   `json:"type"`
 	IPAM   spiderpoolcmd.IPAMConfig `json:"ipam"`
 	Master string                   `json:"master"`
+	MTU    int                      `json:"mtu,omitempty"`
 }
 
 type SRIOVNetConf struct {
@@ -14,6 +14,8 @@ import (
 type Pinger struct {
 	logger *zap.Logger
 	pinger *ping.Pinger
+	// lossTolerance is the percentage of lost packets tolerated
+	lossTolerance float64
 }
 
 func NewPinger(count int, interval, timeout, gw string, logger *zap.Logger) (*Pinger, error) {
@@ -32,7 +34,13 @@ func NewPinger(count int, interval, timeout, gw string, logger *zap.Logger) (*Pi
 	}
 	pinger.Timeout = timeoutDuration
 
-	return &Pinger{logger, pinger}, nil
+	return &Pinger{logger: logger, pinger: pinger}, nil
+}
+
+// SetLossTolerance tolerates the percentage of lost packets, the gateway is
+// considered unreachable only when more packets are lost.
+func (p *Pinger) SetLossTolerance(percent int) {
+	p.lossTolerance = float64(percent)
 }
 
 func (p *Pinger) DetectGateway() error {
@@ -41,8 +49,8 @@ func (p *Pinger) DetectGateway() error {
 	}
 
 	stats := p.pinger.Statistics()
-	if stats.PacketLoss > 0 {
-		return fmt.Errorf("gateway %s is unreachable", p.pinger.Addr())
+	if stats.PacketsRecv == 0 || stats.PacketLoss > p.lossTolerance {
+		return fmt.Errorf("gateway %s is unreachable, %.0f%% packet loss", p.pinger.Addr(), stats.PacketLoss)
 	}
 
 	p.logger.Sugar().Debugf("gateway %s is reachable", p.pinger.Addr())
@@ -0,0 +1,125 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package gwconnection
+
+import (
+	"fmt"
+	"net/netip"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/spidernet-io/spiderpool/pkg/networking/ipchecking"
+)
+
+const (
+	// MethodICMP detects the gateways with ICMP echo.
+	MethodICMP = "icmp"
+	// MethodARP detects the IPv4 gateways with ARP, and the IPv6 gateways
+	// with ICMP.
+	MethodARP = "arp"
+	// MethodNDP detects the IPv6 gateways with NDP, and the IPv4 gateways
+	// with ICMP.
+	MethodNDP = "ndp"
+	// MethodAny considers the gateways reachable if either ICMP or ARP/NDP
+	// succeeds.
+	MethodAny = "any"
+)
+
+// resolveNeighbor is replaced in unit tests.
+var resolveNeighbor = ipchecking.ResolveNeighbor
+
+// DetectOptions configures how a gateway is detected.
+type DetectOptions struct {
+	Method   string
+	Count    int
+	Interval time.Duration
+	Timeout  time.Duration
+	// LossTolerance is the percentage of lost probes tolerated.
+	LossTolerance int
+}
+
+// Detect checks the gateway is reachable from the interface with the method
+// of the options. It must be called in the network namespace of the interface.
+func Detect(iface string, gw netip.Addr, opts DetectOptions, logger *zap.Logger) error {
+	switch opts.Method {
+	case MethodARP:
+		if gw.Is4() {
+			return detectNeighbor(iface, gw, opts, logger)
+		}
+		return detectICMP(gw, opts, logger)
+	case MethodNDP:
+		if gw.Is6() {
+			return detectNeighbor(iface, gw, opts, logger)
+		}
+		return detectICMP(gw, opts, logger)
+	case MethodAny:
+		icmpErr := detectICMP(gw, opts, logger)
+		if icmpErr == nil {
+			return nil
+		}
+		if err := detectNeighbor(iface, gw, opts, logger); err != nil {
+			return fmt.Errorf("%v, and %w", icmpErr, err)
+		}
+		return nil
+	case MethodICMP, "":
+		return detectICMP(gw, opts, logger)
+	default:
+		return fmt.Errorf("unknown gateway detection method '%s'", opts.Method)
+	}
+}
+
+func detectICMP(gw netip.Addr, opts DetectOptions, logger *zap.Logger) error {
+	p, err := NewPinger(opts.Count, opts.Interval.String(), opts.Timeout.String(), gw.String(), logger)
+	if err != nil {
+		return fmt.Errorf("failed to run NewPinger: %w", err)
+	}
+	p.SetLossTolerance(opts.LossTolerance)
+
+	return p.DetectGateway()
+}
+
+// detectNeighbor resolves the gateway by ARP or NDP for count times, the
+// gateway is unreachable once the lost probes exceed the tolerance.
+func detectNeighbor(iface string, gw netip.Addr, opts DetectOptions, logger *zap.Logger) error {
+	count := opts.Count
+	if count <= 0 {
+		count = 1
+	}
+
+	var lost int
+	var lastErr error
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			time.Sleep(opts.Interval)
+		}
+
+		if _, err := resolveNeighbor(iface, gw, opts.Timeout, logger); err != nil {
+			lost++
+			lastErr = err
+			if exceedLossTolerance(lost, count, opts.LossTolerance) {
+				return fmt.Errorf("gateway %s is unreachable, %d of %d neighbor probes lost: %w", gw, lost, count, lastErr)
+			}
+			continue
+		}
+
+		if !exceedLossTolerance(lost+count-i-1, count, opts.LossTolerance) {
+			// the remaining probes are unable to exceed the tolerance
+			break
+		}
+	}
+
+	logger.Sugar().Debugf("gateway %s is reachable by neighbor discovery", gw)
+	return nil
+}
+
+// exceedLossTolerance returns true if the lost probes are more than the
+// tolerated percentage of the total, at least one probe must succeed.
+func exceedLossTolerance(lost, total, tolerance int) bool {
+	if lost >= total {
+		return true
+	}
+
+	return lost*100 > total*tolerance
+}
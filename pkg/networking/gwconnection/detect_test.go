@@ -0,0 +1,97 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package gwconnection
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+)
+
+var _ = Describe("Detect", Label("detect_test"), func() {
+	var gw netip.Addr
+	var logger *zap.Logger
+
+	BeforeEach(func() {
+		gw = netip.MustParseAddr("10.6.0.1")
+		logger = logutils.Logger.Named("Detect")
+	})
+
+	DescribeTable("exceedLossTolerance",
+		func(lost, total, tolerance int, expected bool) {
+			Expect(exceedLossTolerance(lost, total, tolerance)).To(Equal(expected))
+		},
+		Entry("no loss", 0, 3, 0, false),
+		Entry("any loss without tolerance", 1, 3, 0, true),
+		Entry("loss within tolerance", 1, 4, 25, false),
+		Entry("loss beyond tolerance", 2, 4, 25, true),
+		Entry("all probes lost with the max tolerance", 3, 3, 99, true),
+		Entry("all but one probes lost with the max tolerance", 99, 100, 99, false),
+	)
+
+	Describe("detectNeighbor", func() {
+		// results are the errors of the successive probes, the probes
+		// beyond them succeed.
+		var results []error
+		var probes int
+
+		BeforeEach(func() {
+			results = nil
+			probes = 0
+
+			origin := resolveNeighbor
+			DeferCleanup(func() { resolveNeighbor = origin })
+			resolveNeighbor = func(iface string, target netip.Addr, timeout time.Duration, logger *zap.Logger) (net.HardwareAddr, error) {
+				defer func() { probes++ }()
+				Expect(iface).To(Equal("net1"))
+				Expect(target).To(Equal(gw))
+				if probes < len(results) && results[probes] != nil {
+					return nil, results[probes]
+				}
+				return net.HardwareAddr{0x0a, 0, 0, 0, 0, 1}, nil
+			}
+		})
+
+		It("passes the reachable gateway", func() {
+			err := detectNeighbor("net1", gw, DetectOptions{Count: 3}, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(probes).To(Equal(3))
+		})
+
+		It("fails on the first lost probe without tolerance", func() {
+			results = []error{nil, errors.New("timeout")}
+
+			err := detectNeighbor("net1", gw, DetectOptions{Count: 3}, logger)
+			Expect(err).To(MatchError(ContainSubstring("1 of 3 neighbor probes lost")))
+			Expect(probes).To(Equal(2))
+		})
+
+		It("tolerates the lost probes within the tolerance", func() {
+			results = []error{errors.New("timeout")}
+
+			err := detectNeighbor("net1", gw, DetectOptions{Count: 4, LossTolerance: 25}, logger)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("stops once the remaining probes can not exceed the tolerance", func() {
+			err := detectNeighbor("net1", gw, DetectOptions{Count: 4, LossTolerance: 50}, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(probes).To(Equal(2))
+		})
+
+		It("fails if all probes are lost", func() {
+			results = []error{errors.New("timeout"), errors.New("timeout")}
+
+			err := detectNeighbor("net1", gw, DetectOptions{Count: 2, LossTolerance: 99}, logger)
+			Expect(err).To(MatchError(ContainSubstring("2 of 2 neighbor probes lost")))
+		})
+	})
+})
@@ -0,0 +1,111 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipchecking
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+
+	types100 "github.com/containernetworking/cni/pkg/types/100"
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/mdlayher/arp"
+	"github.com/mdlayher/ethernet"
+	"github.com/mdlayher/ndp"
+	"go.uber.org/zap"
+)
+
+// AnnounceIPs sends a burst of gratuitous ARPs (IPv4) and unsolicited neighbor
+// advertisements (IPv6) for the ips from the interface in netns, so that the
+// switches and neighbors refresh their tables with the interface's current
+// MAC address.
+func AnnounceIPs(netns ns.NetNS, iface string, ipconfigs []*types100.IPConfig, count int, interval string, logger *zap.Logger) error {
+	if len(ipconfigs) == 0 {
+		logger.Info("No ips found in pod, ignore announcing pod's ip")
+		return nil
+	}
+
+	d, err := time.ParseDuration(interval)
+	if err != nil {
+		return fmt.Errorf("failed to parse interval %v: %v", interval, err)
+	}
+
+	// the sockets must be created in the pod's netns
+	return netns.Do(func(netNS ns.NetNS) error {
+		ifi, err := net.InterfaceByName(iface)
+		if err != nil {
+			return fmt.Errorf("failed to InterfaceByName %s: %w", iface, err)
+		}
+
+		var ip4s, ip6s []netip.Addr
+		for idx := range ipconfigs {
+			target, ok := netip.AddrFromSlice(ipconfigs[idx].Address.IP)
+			if !ok {
+				continue
+			}
+			target = target.Unmap()
+			if target.Is4() {
+				ip4s = append(ip4s, target)
+			} else {
+				ip6s = append(ip6s, target)
+			}
+		}
+
+		var arpClient *arp.Client
+		if len(ip4s) != 0 {
+			arpClient, err = arp.Dial(ifi)
+			if err != nil {
+				return fmt.Errorf("failed to init arp client: %w", err)
+			}
+			defer arpClient.Close()
+		}
+
+		var ndpClient *ndp.Conn
+		if len(ip6s) != 0 {
+			ndpClient, _, err = ndp.Listen(ifi, ndp.LinkLocal)
+			if err != nil {
+				return fmt.Errorf("failed to init ndp client: %w", err)
+			}
+			defer ndpClient.Close()
+		}
+
+		for i := 0; i < count; i++ {
+			if i != 0 {
+				time.Sleep(d)
+			}
+
+			for _, ip := range ip4s {
+				// gratuitous arp: both the sender and the target are the pod's ip
+				packet, err := arp.NewPacket(arp.OperationRequest, ifi.HardwareAddr, ip, ethernet.Broadcast, ip)
+				if err != nil {
+					return err
+				}
+				if err = arpClient.WriteTo(packet, ethernet.Broadcast); err != nil {
+					return fmt.Errorf("failed to send gratuitous arp for %s: %v", ip.String(), err)
+				}
+			}
+
+			for _, ip := range ip6s {
+				na := &ndp.NeighborAdvertisement{
+					Override:      true,
+					TargetAddress: ip,
+					Options: []ndp.Option{
+						&ndp.LinkLayerAddress{
+							Direction: ndp.Target,
+							Addr:      ifi.HardwareAddr,
+						},
+					},
+				}
+				if err = ndpClient.WriteTo(na, nil, netip.IPv6LinkLocalAllNodes()); err != nil {
+					return fmt.Errorf("failed to send unsolicited neighbor advertisement for %s: %v", ip.String(), err)
+				}
+			}
+		}
+
+		logger.Debug("Announce pod's ips successfully", zap.String("interface", iface),
+			zap.String("hardware address", ifi.HardwareAddr.String()), zap.Int("count", count))
+		return nil
+	})
+}
@@ -21,6 +21,23 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// IPConflictError is returned when another host answers for the IP address
+// being checked.
+type IPConflictError struct {
+	Interface string
+	IP        netip.Addr
+	MAC       string
+}
+
+func (e *IPConflictError) Error() string {
+	return fmt.Sprintf("pod's interface %s with an conflicting ip %s, %s is located at %s", e.Interface,
+		e.IP.String(), e.IP.String(), e.MAC)
+}
+
+// ErrNotOnLink is returned by ProbeIPOnHost when no interface of the host is
+// on-link to the IP address, so it can not be probed by ARP or NDP.
+var ErrNotOnLink = errors.New("no interface on-link to the ip")
+
 type IPChecker struct {
 	retries   int
 	interval  time.Duration
@@ -101,6 +118,49 @@ func (ipc *IPChecker) DoIPConflictChecking(ipconfigs []*types100.IPConfig, errg
 	})
 }
 
+// ProbeIPOnHost checks whether the IP address is answered by any host, from
+// the interface of the current network namespace which is on-link to it.
+func ProbeIPOnHost(target netip.Addr, retries int, interval, timeout string, logger *zap.Logger) error {
+	routes, err := netlink.RouteGet(target.AsSlice())
+	if err != nil {
+		return fmt.Errorf("failed to get route to %s: %w", target.String(), err)
+	}
+	if len(routes) == 0 || routes[0].Gw != nil {
+		return ErrNotOnLink
+	}
+
+	link, err := netlink.LinkByIndex(routes[0].LinkIndex)
+	if err != nil {
+		return fmt.Errorf("failed to get link of index %d: %w", routes[0].LinkIndex, err)
+	}
+	if len(link.Attrs().HardwareAddr) == 0 {
+		return ErrNotOnLink
+	}
+
+	netns, err := ns.GetCurrentNS()
+	if err != nil {
+		return fmt.Errorf("failed to get current netns: %w", err)
+	}
+	defer netns.Close()
+
+	ipfamily := netlink.FAMILY_V4
+	if target.Is6() {
+		ipfamily = netlink.FAMILY_V6
+	}
+
+	ipc, err := NewIPChecker(ipfamily, retries, link.Attrs().Name, interval, timeout, netns, logger)
+	if err != nil {
+		return err
+	}
+
+	if target.Is4() {
+		ipc.ip4 = target
+		return ipc.ipCheckingByARP()
+	}
+	ipc.ip6 = target
+	return ipc.ipCheckingByNDP()
+}
+
 func (ipc *IPChecker) ipCheckingByARP() error {
 	defer ipc.arpClient.Close()
 
@@ -167,8 +227,7 @@ func (ipc *IPChecker) ipCheckingByARP() error {
 	if conflictingMac != "" {
 		// found ip conflicting
 		ipc.logger.Error("Found IPv4 address conflicting", zap.String("Conflicting IP", ipc.ip4.String()), zap.String("Host", conflictingMac))
-		return fmt.Errorf("pod's interface %s with an conflicting ip %s, %s is located at %s", ipc.ifi.Name,
-			ipc.ip4.String(), ipc.ip4.String(), conflictingMac)
+		return &IPConflictError{Interface: ipc.ifi.Name, IP: ipc.ip4, MAC: conflictingMac}
 	}
 
 	ipc.logger.Debug("No ipv4 address conflict", zap.String("IPv4 address", ipc.ip4.String()))
@@ -198,8 +257,7 @@ func (ipc *IPChecker) ipCheckingByNDP() error {
 		if err.Error() == NDPFoundReply.Error() {
 			if replyMac != ipc.ifi.HardwareAddr.String() {
 				ipc.logger.Error("Found IPv6 address conflicting", zap.String("Conflicting IP", ipc.ip6.String()), zap.String("Host", replyMac))
-				return fmt.Errorf("pod's interface %s with an conflicting ip %s, %s is located at %s", ipc.ifi.Name,
-					ipc.ip6.String(), ipc.ip6.String(), replyMac)
+				return &IPConflictError{Interface: ipc.ifi.Name, IP: ipc.ip6, MAC: replyMac}
 			} else {
 				return fmt.Errorf("failed to checking ipv6 address conflicting: %v", err)
 			}
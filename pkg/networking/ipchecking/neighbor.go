@@ -0,0 +1,83 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package ipchecking
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/mdlayher/ndp"
+	"github.com/vishvananda/netlink"
+	"go.uber.org/zap"
+)
+
+// ResolveNeighbor resolves the hardware address of the neighbor with ARP for
+// IPv4 or NDP for IPv6, from the interface of the current network namespace.
+// It probes once within the timeout, which is an alternative to ICMP for the
+// gateways dropping or rate-limiting it.
+func ResolveNeighbor(iface string, target netip.Addr, timeout time.Duration, logger *zap.Logger) (net.HardwareAddr, error) {
+	netns, err := ns.GetCurrentNS()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current netns: %w", err)
+	}
+	defer netns.Close()
+
+	ipfamily := netlink.FAMILY_V4
+	if target.Is6() {
+		ipfamily = netlink.FAMILY_V6
+	}
+
+	ipc, err := NewIPChecker(ipfamily, 1, iface, timeout.String(), timeout.String(), netns, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.Is4() {
+		return ipc.resolveByARP(target)
+	}
+	return ipc.resolveByNDP(target)
+}
+
+func (ipc *IPChecker) resolveByARP(target netip.Addr) (net.HardwareAddr, error) {
+	defer ipc.arpClient.Close()
+
+	if err := ipc.arpClient.SetDeadline(time.Now().Add(ipc.timeout)); err != nil {
+		return nil, fmt.Errorf("failed to set deadline: %w", err)
+	}
+
+	mac, err := ipc.arpClient.Resolve(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s by ARP: %w", target, err)
+	}
+
+	return mac, nil
+}
+
+func (ipc *IPChecker) resolveByNDP(target netip.Addr) (net.HardwareAddr, error) {
+	defer ipc.ndpClient.Close()
+
+	ipc.ip6 = target
+	m := &ndp.NeighborSolicitation{
+		TargetAddress: target,
+		Options: []ndp.Option{
+			&ndp.LinkLayerAddress{
+				Direction: ndp.Source,
+				Addr:      ipc.ifi.HardwareAddr,
+			},
+		},
+	}
+
+	replyMac, err := ipc.sendReceiveLoop(m)
+	if err == nil {
+		return nil, fmt.Errorf("failed to resolve %s by NDP: no neighbor advertisement received", target)
+	}
+	if err.Error() != NDPFoundReply.Error() {
+		return nil, fmt.Errorf("failed to resolve %s by NDP: %w", target, err)
+	}
+
+	return net.ParseMAC(replyMac)
+}
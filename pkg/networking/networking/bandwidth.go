@@ -0,0 +1,217 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package networking
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var (
+	minBandwidthRate = resource.MustParse("1k")
+	// the rate of tc police action is 32 bits in bytes per second
+	maxBandwidthRate = resource.MustParse("32G")
+)
+
+const (
+	// the burst of the rate limits is the traffic of 100ms, but not less
+	// than 64KiB to let TSO/GRO packets through
+	burstPeriodDivisor = 10
+	minBurst           = 64 * 1024
+	// the latency of the egress packets queued by tbf
+	tbfLatencyMs = 25
+
+	mangleTable      = "mangle"
+	postroutingChain = "POSTROUTING"
+)
+
+// ParseBandwidthRate parses the rate in bits per second, such as 100M, which
+// must be in the range of 1k to 32G.
+func ParseBandwidthRate(rate string) (uint64, error) {
+	q, err := resource.ParseQuantity(rate)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate %s: %v", rate, err)
+	}
+
+	if q.Cmp(minBandwidthRate) < 0 || q.Cmp(maxBandwidthRate) > 0 {
+		return 0, fmt.Errorf("invalid rate %s, it must be in the range of %s to %s", rate, minBandwidthRate.String(), maxBandwidthRate.String())
+	}
+
+	return uint64(q.Value()), nil
+}
+
+func burstOf(rateInBytes uint64) uint32 {
+	burst := rateInBytes / burstPeriodDivisor
+	if burst < minBurst {
+		burst = minBurst
+	}
+	return uint32(burst)
+}
+
+// SetupEgressBandwidth limits the rate of the packets sent from the interface
+// with a tbf qdisc.
+// Equivalent: `tc qdisc replace dev <iface> root tbf rate <rate> burst <burst> latency 25ms`
+func SetupEgressBandwidth(iface string, rateInBits uint64) error {
+	link, err := netlink.LinkByName(iface)
+	if err != nil {
+		return fmt.Errorf("failed to LinkByName %s: %w", iface, err)
+	}
+
+	rate := rateInBits / 8
+	burst := burstOf(rate)
+	qdisc := &netlink.Tbf{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(1, 0),
+			Parent:    netlink.HANDLE_ROOT,
+		},
+		Rate:   rate,
+		Limit:  uint32(rate*tbfLatencyMs/1000) + burst,
+		Buffer: netlink.Xmittime(rate, burst),
+	}
+
+	if err = netlink.QdiscReplace(qdisc); err != nil {
+		return fmt.Errorf("failed to replace tbf qdisc of %s: %w", iface, err)
+	}
+	return nil
+}
+
+// SetupIngressBandwidth limits the rate of the packets received by the
+// interface with a police action, the packets exceeding the rate are dropped.
+// Equivalent: `tc qdisc add dev <iface> ingress` and
+// `tc filter add dev <iface> parent ffff: matchall action police rate <rate> burst <burst> drop`
+func SetupIngressBandwidth(iface string, rateInBits uint64) error {
+	link, err := netlink.LinkByName(iface)
+	if err != nil {
+		return fmt.Errorf("failed to LinkByName %s: %w", iface, err)
+	}
+
+	ingress := &netlink.Ingress{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(0xffff, 0),
+			Parent:    netlink.HANDLE_INGRESS,
+		},
+	}
+	// the filters are flushed with the ingress qdisc
+	_ = netlink.QdiscDel(ingress)
+	if err = netlink.QdiscAdd(ingress); err != nil {
+		return fmt.Errorf("failed to add ingress qdisc of %s: %w", iface, err)
+	}
+
+	rate := rateInBits / 8
+	police := netlink.NewPoliceAction()
+	police.Rate = uint32(rate)
+	police.Burst = burstOf(rate)
+	police.ExceedAction = netlink.TC_POLICE_SHOT
+	police.NotExceedAction = netlink.TC_POLICE_OK
+
+	filter := &netlink.MatchAll{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    ingress.Handle,
+			Priority:  1,
+			Protocol:  unix.ETH_P_ALL,
+		},
+		Actions: []netlink.Action{police},
+	}
+	if err = netlink.FilterAdd(filter); err != nil {
+		return fmt.Errorf("failed to add police filter of %s: %w", iface, err)
+	}
+	return nil
+}
+
+// CleanBandwidth removes the tbf and ingress qdiscs of the interface.
+func CleanBandwidth(iface string) error {
+	link, err := netlink.LinkByName(iface)
+	if err != nil {
+		if _, ok := err.(netlink.LinkNotFoundError); ok {
+			return nil
+		}
+		return fmt.Errorf("failed to LinkByName %s: %w", iface, err)
+	}
+
+	qdiscs, err := netlink.QdiscList(link)
+	if err != nil {
+		return fmt.Errorf("failed to list qdiscs of %s: %w", iface, err)
+	}
+
+	for _, qdisc := range qdiscs {
+		switch qdisc.(type) {
+		case *netlink.Tbf, *netlink.Ingress:
+			if err = netlink.QdiscDel(qdisc); err != nil {
+				return fmt.Errorf("failed to delete %s qdisc of %s: %w", qdisc.Type(), iface, err)
+			}
+		}
+	}
+	return nil
+}
+
+func newIPTables(ipFamily int) ([]*iptables.IPTables, error) {
+	var protocols []iptables.Protocol
+	if ipFamily == netlink.FAMILY_V4 || ipFamily == netlink.FAMILY_ALL {
+		protocols = append(protocols, iptables.ProtocolIPv4)
+	}
+	if ipFamily == netlink.FAMILY_V6 || ipFamily == netlink.FAMILY_ALL {
+		protocols = append(protocols, iptables.ProtocolIPv6)
+	}
+
+	var ipts []*iptables.IPTables
+	for _, proto := range protocols {
+		ipt, err := iptables.NewWithProtocol(proto)
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate iptables: %w", err)
+		}
+		ipts = append(ipts, ipt)
+	}
+	return ipts, nil
+}
+
+// SetupDSCP marks the DSCP of the packets sent from the interface.
+// Equivalent: `iptables -t mangle -A POSTROUTING -o <iface> -j DSCP --set-dscp <dscp>`
+func SetupDSCP(iface string, dscp int, ipFamily int) error {
+	ipts, err := newIPTables(ipFamily)
+	if err != nil {
+		return err
+	}
+
+	for _, ipt := range ipts {
+		if err = ipt.AppendUnique(mangleTable, postroutingChain, "-o", iface, "-j", "DSCP", "--set-dscp", strconv.Itoa(dscp)); err != nil {
+			return fmt.Errorf("failed to append DSCP rule of %s: %w", iface, err)
+		}
+	}
+	return nil
+}
+
+// CleanDSCP deletes the DSCP rules of the interface.
+func CleanDSCP(iface string, ipFamily int) error {
+	ipts, err := newIPTables(ipFamily)
+	if err != nil {
+		return err
+	}
+
+	for _, ipt := range ipts {
+		rules, err := ipt.List(mangleTable, postroutingChain)
+		if err != nil {
+			return fmt.Errorf("failed to list the rules of %s chain: %w", postroutingChain, err)
+		}
+
+		for _, rule := range rules {
+			// rule is like: -A POSTROUTING -o net1 -j DSCP --set-dscp 0x2e
+			if !strings.Contains(rule, " -o "+iface+" ") || !strings.Contains(rule, " -j DSCP ") {
+				continue
+			}
+			if err = ipt.Delete(mangleTable, postroutingChain, strings.Fields(rule)[2:]...); err != nil {
+				return fmt.Errorf("failed to delete DSCP rule of %s: %w", iface, err)
+			}
+		}
+	}
+	return nil
+}
@@ -26,6 +26,32 @@ func OverwriteHwAddress(logger *zap.Logger, netns ns.NetNS, macPrefix, iface str
 		return "", err
 	}
 
+	// the EUI-64 IPv6 address allocated by spiderpool is derived from the
+	// current hardware address, which must not be overwritten
+	var hwAddr string
+	err = netns.Do(func(netNS ns.NetNS) error {
+		link, err := netlink.LinkByName(iface)
+		if err != nil {
+			return err
+		}
+		for _, ip := range ips {
+			if isEUI64Of(ip.IP, link.Attrs().HardwareAddr) {
+				hwAddr = link.Attrs().HardwareAddr.String()
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("failed to get the hardware address", zap.String("interface", iface), zap.Error(err))
+		return "", err
+	}
+	if hwAddr != "" {
+		logger.Warn("The podMACPrefix is ignored to keep the hardware address of the EUI-64 IPv6 address, please don't use it with the IPPools deriving EUI-64 addresses",
+			zap.String("interface", iface), zap.String("hardware address", hwAddr), zap.String("podMACPrefix", macPrefix))
+		return hwAddr, nil
+	}
+
 	// we only focus on first element
 	nAddr, err := netip.ParseAddr(ips[0].IP.String())
 	if err != nil {
@@ -40,7 +66,7 @@ func OverwriteHwAddress(logger *zap.Logger, netns ns.NetNS, macPrefix, iface str
 	}
 
 	// newmac = xx:xx + xx:xx:xx:xx
-	hwAddr := macPrefix + ":" + suffix
+	hwAddr = macPrefix + ":" + suffix
 	err = netns.Do(func(netNS ns.NetNS) error {
 		link, err := netlink.LinkByName(iface)
 		if err != nil {
@@ -57,6 +83,18 @@ func OverwriteHwAddress(logger *zap.Logger, netns ns.NetNS, macPrefix, iface str
 	return hwAddr, nil
 }
 
+// isEUI64Of reports whether the interface identifier of the IPv6 address is
+// the modified EUI-64 format of the 48-bit hardware address.
+func isEUI64Of(ip net.IP, hwAddr net.HardwareAddr) bool {
+	ip = ip.To16()
+	if ip == nil || ip.To4() != nil || len(hwAddr) != 6 {
+		return false
+	}
+
+	iid := []byte{hwAddr[0] ^ 0x02, hwAddr[1], hwAddr[2], 0xff, 0xfe, hwAddr[3], hwAddr[4], hwAddr[5]}
+	return bytes.Equal(ip[8:], iid)
+}
+
 // parseMac parse hardware addr from given string
 func parseMac(s string) net.HardwareAddr {
 	hardwareAddr, err := net.ParseMAC(s)
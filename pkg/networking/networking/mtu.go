@@ -0,0 +1,58 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package networking
+
+import (
+	"fmt"
+
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// the 802.1Q tag
+	vlanOverhead = 4
+	// the outer Ethernet, IP, UDP and VXLAN headers
+	vxlanOverheadIPv4 = 50
+	vxlanOverheadIPv6 = 70
+)
+
+// linkByIndex is faked in tests.
+var linkByIndex = netlink.LinkByIndex
+
+// GetMasterMTU returns the MTU available to the interfaces created on the
+// master interface, which is the MTU of the master interface minus the
+// overhead of the VLAN and VXLAN devices, if their lower devices do not leave
+// room for it.
+func GetMasterMTU(index int) (int, error) {
+	link, err := linkByIndex(index)
+	if err != nil {
+		return 0, fmt.Errorf("failed to LinkByIndex %d: %w", index, err)
+	}
+
+	mtu := link.Attrs().MTU
+	var lowerIndex, overhead int
+	switch l := link.(type) {
+	case *netlink.Vlan:
+		lowerIndex, overhead = l.ParentIndex, vlanOverhead
+	case *netlink.Vxlan:
+		lowerIndex, overhead = l.VtepDevIndex, vxlanOverheadIPv4
+		if (l.Group != nil && l.Group.To4() == nil) || (l.SrcAddr != nil && l.SrcAddr.To4() == nil) {
+			overhead = vxlanOverheadIPv6
+		}
+	}
+
+	if lowerIndex == 0 || lowerIndex == index {
+		return mtu, nil
+	}
+
+	lowerMTU, err := GetMasterMTU(lowerIndex)
+	if err != nil {
+		return 0, err
+	}
+
+	if lowerMTU-overhead < mtu {
+		mtu = lowerMTU - overhead
+	}
+	return mtu, nil
+}
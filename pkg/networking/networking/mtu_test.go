@@ -0,0 +1,83 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package networking
+
+import (
+	"errors"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+)
+
+var _ = Describe("GetMasterMTU", Label("mtu_test"), func() {
+	var links map[int]netlink.Link
+
+	BeforeEach(func() {
+		links = map[int]netlink.Link{}
+		originLinkByIndex := linkByIndex
+		DeferCleanup(func() {
+			linkByIndex = originLinkByIndex
+		})
+		linkByIndex = func(index int) (netlink.Link, error) {
+			if l, ok := links[index]; ok {
+				return l, nil
+			}
+			return nil, netlink.LinkNotFoundError{}
+		}
+	})
+
+	device := func(index, mtu int) *netlink.Device {
+		return &netlink.Device{LinkAttrs: netlink.LinkAttrs{Index: index, MTU: mtu}}
+	}
+
+	It("returns the MTU of a physical master", func() {
+		links[2] = device(2, 9000)
+
+		Expect(GetMasterMTU(2)).To(Equal(9000))
+	})
+
+	It("subtracts the VLAN overhead if the parent leaves no room for it", func() {
+		links[2] = device(2, 1500)
+		links[3] = &netlink.Vlan{LinkAttrs: netlink.LinkAttrs{Index: 3, MTU: 1500, ParentIndex: 2}, VlanId: 100}
+
+		Expect(GetMasterMTU(3)).To(Equal(1496))
+	})
+
+	It("keeps the MTU of the VLAN if its parent leaves room for the tag", func() {
+		links[2] = device(2, 9000)
+		links[3] = &netlink.Vlan{LinkAttrs: netlink.LinkAttrs{Index: 3, MTU: 1500, ParentIndex: 2}, VlanId: 100}
+
+		Expect(GetMasterMTU(3)).To(Equal(1500))
+	})
+
+	It("subtracts the overhead of the VLANs and VXLAN stacked on each other", func() {
+		links[2] = device(2, 1500)
+		links[3] = &netlink.Vlan{LinkAttrs: netlink.LinkAttrs{Index: 3, MTU: 1500, ParentIndex: 2}, VlanId: 100}
+		links[4] = &netlink.Vxlan{LinkAttrs: netlink.LinkAttrs{Index: 4, MTU: 1500}, VtepDevIndex: 3}
+
+		Expect(GetMasterMTU(4)).To(Equal(1446))
+	})
+
+	It("subtracts the IPv6 VXLAN overhead", func() {
+		links[2] = device(2, 1500)
+		links[4] = &netlink.Vxlan{LinkAttrs: netlink.LinkAttrs{Index: 4, MTU: 1500}, VtepDevIndex: 2, Group: net.ParseIP("ff02::1")}
+
+		Expect(GetMasterMTU(4)).To(Equal(1430))
+	})
+
+	It("returns the MTU of a VXLAN without lower device", func() {
+		links[4] = &netlink.Vxlan{LinkAttrs: netlink.LinkAttrs{Index: 4, MTU: 1450}}
+
+		Expect(GetMasterMTU(4)).To(Equal(1450))
+	})
+
+	It("fails to get a missing lower device", func() {
+		links[3] = &netlink.Vlan{LinkAttrs: netlink.LinkAttrs{Index: 3, MTU: 1500, ParentIndex: 2}, VlanId: 100}
+
+		_, err := GetMasterMTU(3)
+		Expect(errors.As(err, &netlink.LinkNotFoundError{})).To(BeTrue())
+	})
+})
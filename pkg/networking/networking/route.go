@@ -90,8 +90,9 @@ func DelFromRuleTable(src *net.IPNet, ruleTable int) error {
 	return netlink.RuleAdd(rule)
 }
 
-// AddRoute add static route to specify rule table
-func AddRoute(logger *zap.Logger, ruleTable int, scope netlink.Scope, iface string, dst *net.IPNet, v4Gw, v6Gw net.IP) error {
+// AddRoute add static route to specify rule table, the MTU of the route is
+// set if mtu is not 0
+func AddRoute(logger *zap.Logger, ruleTable int, scope netlink.Scope, iface string, dst *net.IPNet, v4Gw, v6Gw net.IP, mtu int) error {
 	link, err := netlink.LinkByName(iface)
 	if err != nil {
 		logger.Error(err.Error())
@@ -103,6 +104,7 @@ func AddRoute(logger *zap.Logger, ruleTable int, scope netlink.Scope, iface stri
 		Scope:     scope,
 		Dst:       dst,
 		Table:     ruleTable,
+		MTU:       mtu,
 	}
 
 	if dst.IP.To4() != nil && v4Gw != nil {
@@ -309,3 +311,37 @@ func ConvertMaxMaskIPNet(nip net.IP) *net.IPNet {
 	}
 	return mIPNet
 }
+
+// GetPathMTU returns the smallest MTU of the routes to the given CIDRs, which
+// is the MTU of the route if it is locked, or else the MTU of its interface.
+// It returns 0 if none of the CIDRs is routable.
+// Equivalent: `ip route get <cidr>`
+func GetPathMTU(cidrs []string) (int, error) {
+	mtu := 0
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return 0, err
+		}
+
+		routes, err := netlink.RouteGet(ipNet.IP)
+		if err != nil || len(routes) == 0 {
+			continue
+		}
+
+		routeMTU := routes[0].MTU
+		if routeMTU == 0 {
+			link, err := netlink.LinkByIndex(routes[0].LinkIndex)
+			if err != nil {
+				return 0, fmt.Errorf("failed to LinkByIndex %d: %w", routes[0].LinkIndex, err)
+			}
+			routeMTU = link.Attrs().MTU
+		}
+
+		if mtu == 0 || routeMTU < mtu {
+			mtu = routeMTU
+		}
+	}
+
+	return mtu, nil
+}
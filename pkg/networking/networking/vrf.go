@@ -0,0 +1,150 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package networking
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vishvananda/netlink"
+)
+
+// The netlink operations managing the VRFs, which are faked in tests.
+var (
+	linkByName    = netlink.LinkByName
+	linkList      = netlink.LinkList
+	linkAdd       = netlink.LinkAdd
+	linkDel       = netlink.LinkDel
+	linkSetUp     = netlink.LinkSetUp
+	linkSetMaster = netlink.LinkSetMaster
+	addrList      = netlink.AddrList
+	addrReplace   = netlink.AddrReplace
+	routeList     = netlink.RouteList
+	routeAdd      = netlink.RouteAdd
+)
+
+// EnsureVRF returns the VRF device of the given name, it is created with the
+// table (or defaultTable if table is 0) and set up if it does not exist.
+// Equivalent: `ip link add <name> type vrf table <table> && ip link set <name> up`
+func EnsureVRF(name string, table, defaultTable uint32) (*netlink.Vrf, error) {
+	link, err := linkByName(name)
+	if err == nil {
+		vrf, ok := link.(*netlink.Vrf)
+		if !ok {
+			return nil, fmt.Errorf("interface %s already exists and is not a VRF but %s", name, link.Type())
+		}
+		if table != 0 && vrf.Table != table {
+			return nil, fmt.Errorf("VRF %s already exists with table %d, but table %d is expected", name, vrf.Table, table)
+		}
+		return vrf, nil
+	}
+	if _, ok := err.(netlink.LinkNotFoundError); !ok {
+		return nil, fmt.Errorf("failed to LinkByName %s: %w", name, err)
+	}
+
+	if table == 0 {
+		table = defaultTable
+	}
+
+	vrf := &netlink.Vrf{
+		LinkAttrs: netlink.LinkAttrs{Name: name},
+		Table:     table,
+	}
+	if err = linkAdd(vrf); err != nil && !os.IsExist(err) {
+		return nil, fmt.Errorf("failed to add VRF %s: %w", name, err)
+	}
+
+	if err = linkSetUp(vrf); err != nil {
+		return nil, fmt.Errorf("failed to set VRF %s up: %w", name, err)
+	}
+
+	return vrf, nil
+}
+
+// LinkSetVRFMaster enslaves the interface to the VRF, the addresses and the
+// routes of the interface in table main are moved to the table of the VRF.
+// Equivalent: `ip link set <iface> master <vrf>`
+func LinkSetVRFMaster(iface string, vrf *netlink.Vrf) error {
+	link, err := linkByName(iface)
+	if err != nil {
+		return fmt.Errorf("failed to LinkByName %s: %w", iface, err)
+	}
+
+	if link.Attrs().MasterIndex == vrf.Attrs().Index {
+		return nil
+	}
+
+	addrs, err := addrList(link, netlink.FAMILY_ALL)
+	if err != nil {
+		return fmt.Errorf("failed to list addresses of %s: %w", iface, err)
+	}
+
+	// the routes of table main are listed by default
+	routes, err := routeList(link, netlink.FAMILY_ALL)
+	if err != nil {
+		return fmt.Errorf("failed to list routes of %s: %w", iface, err)
+	}
+
+	if err = linkSetMaster(link, vrf); err != nil {
+		return fmt.Errorf("failed to enslave %s to VRF %s: %w", iface, vrf.Name, err)
+	}
+
+	// enslaving cycles the interface, which flushes its IPv6 addresses
+	// and the routes via gateways
+	for idx := range addrs {
+		if addrs[idx].IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if err = addrReplace(link, &addrs[idx]); err != nil {
+			return fmt.Errorf("failed to restore address %s of %s: %w", addrs[idx].IPNet.String(), iface, err)
+		}
+	}
+
+	for idx := range routes {
+		route := routes[idx]
+		if route.Dst != nil && route.Dst.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		route.Table = int(vrf.Table)
+		if err = routeAdd(&route); err != nil && !os.IsExist(err) {
+			return fmt.Errorf("failed to add route %s to table %d of VRF %s: %w", route.String(), vrf.Table, vrf.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// DelVRFIfUnused deletes the VRF device of the given name, once no interface
+// is enslaved to it except the ignored one. It returns whether the VRF is
+// deleted.
+func DelVRFIfUnused(name, ignore string) (bool, error) {
+	link, err := linkByName(name)
+	if err != nil {
+		if _, ok := err.(netlink.LinkNotFoundError); ok {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to LinkByName %s: %w", name, err)
+	}
+
+	if _, ok := link.(*netlink.Vrf); !ok {
+		return false, fmt.Errorf("interface %s is not a VRF but %s", name, link.Type())
+	}
+
+	links, err := linkList()
+	if err != nil {
+		return false, fmt.Errorf("failed to LinkList: %w", err)
+	}
+
+	for _, l := range links {
+		if l.Attrs().MasterIndex == link.Attrs().Index && l.Attrs().Name != ignore {
+			return false, nil
+		}
+	}
+
+	if err = linkDel(link); err != nil {
+		return false, fmt.Errorf("failed to delete VRF %s: %w", name, err)
+	}
+
+	return true, nil
+}
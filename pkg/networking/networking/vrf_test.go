@@ -0,0 +1,306 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package networking
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+)
+
+// fakeNetlink keeps the links, addresses and routes of a network namespace
+// in memory.
+type fakeNetlink struct {
+	links     []netlink.Link
+	addrs     map[string][]netlink.Addr
+	routes    map[string][]netlink.Route
+	added     []netlink.Route
+	restored  []netlink.Addr
+	nextIndex int
+}
+
+func (f *fakeNetlink) install() {
+	originLinkByName, originLinkList, originLinkAdd, originLinkDel, originLinkSetUp := linkByName, linkList, linkAdd, linkDel, linkSetUp
+	originLinkSetMaster, originAddrList, originAddrReplace, originRouteList, originRouteAdd := linkSetMaster, addrList, addrReplace, routeList, routeAdd
+	DeferCleanup(func() {
+		linkByName, linkList, linkAdd, linkDel, linkSetUp = originLinkByName, originLinkList, originLinkAdd, originLinkDel, originLinkSetUp
+		linkSetMaster, addrList, addrReplace, routeList, routeAdd = originLinkSetMaster, originAddrList, originAddrReplace, originRouteList, originRouteAdd
+	})
+
+	linkByName = func(name string) (netlink.Link, error) {
+		for _, l := range f.links {
+			if l.Attrs().Name == name {
+				return l, nil
+			}
+		}
+		return nil, netlink.LinkNotFoundError{}
+	}
+	linkList = func() ([]netlink.Link, error) {
+		return f.links, nil
+	}
+	linkAdd = func(link netlink.Link) error {
+		if _, err := linkByName(link.Attrs().Name); err == nil {
+			return os.NewSyscallError("netlink", syscall.EEXIST)
+		}
+		f.nextIndex++
+		link.Attrs().Index = f.nextIndex
+		f.links = append(f.links, link)
+		return nil
+	}
+	linkDel = func(link netlink.Link) error {
+		for j, l := range f.links {
+			if l.Attrs().Name == link.Attrs().Name {
+				f.links = append(f.links[:j], f.links[j+1:]...)
+				return nil
+			}
+		}
+		return netlink.LinkNotFoundError{}
+	}
+	linkSetUp = func(link netlink.Link) error {
+		link.Attrs().Flags |= net.FlagUp
+		return nil
+	}
+	linkSetMaster = func(link, master netlink.Link) error {
+		link.Attrs().MasterIndex = master.Attrs().Index
+		// enslaving cycles the interface
+		delete(f.routes, link.Attrs().Name)
+		return nil
+	}
+	addrList = func(link netlink.Link, family int) ([]netlink.Addr, error) {
+		return f.addrs[link.Attrs().Name], nil
+	}
+	addrReplace = func(link netlink.Link, addr *netlink.Addr) error {
+		f.restored = append(f.restored, *addr)
+		return nil
+	}
+	routeList = func(link netlink.Link, family int) ([]netlink.Route, error) {
+		return f.routes[link.Attrs().Name], nil
+	}
+	routeAdd = func(route *netlink.Route) error {
+		for _, r := range f.added {
+			if r.Equal(*route) {
+				return os.NewSyscallError("netlink", syscall.EEXIST)
+			}
+		}
+		f.added = append(f.added, *route)
+		return nil
+	}
+}
+
+func (f *fakeNetlink) addLink(link netlink.Link) netlink.Link {
+	Expect(linkAdd(link)).To(Succeed())
+	return link
+}
+
+var _ = Describe("VRF", Label("vrf_test"), func() {
+	var fake *fakeNetlink
+
+	BeforeEach(func() {
+		fake = &fakeNetlink{
+			addrs:  map[string][]netlink.Addr{},
+			routes: map[string][]netlink.Route{},
+		}
+		fake.install()
+		fake.addLink(&netlink.Device{LinkAttrs: netlink.LinkAttrs{Name: "lo"}})
+		fake.addLink(&netlink.Device{LinkAttrs: netlink.LinkAttrs{Name: "eth0"}})
+		fake.addLink(&netlink.Device{LinkAttrs: netlink.LinkAttrs{Name: "net1"}})
+	})
+
+	Describe("EnsureVRF", func() {
+		It("creates the VRF with the default table and sets it up", func() {
+			vrf, err := EnsureVRF("vrf-blue", 0, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vrf.Table).To(BeEquivalentTo(100))
+			Expect(vrf.Flags & net.FlagUp).NotTo(BeZero())
+
+			link, err := linkByName("vrf-blue")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(link).To(BeAssignableToTypeOf(&netlink.Vrf{}))
+		})
+
+		It("creates the VRF with the specified table", func() {
+			vrf, err := EnsureVRF("vrf-blue", 1000, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vrf.Table).To(BeEquivalentTo(1000))
+		})
+
+		It("reuses the existing VRF", func() {
+			existing := fake.addLink(&netlink.Vrf{LinkAttrs: netlink.LinkAttrs{Name: "vrf-blue"}, Table: 1000})
+
+			vrf, err := EnsureVRF("vrf-blue", 0, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vrf).To(BeIdenticalTo(existing))
+
+			vrf, err = EnsureVRF("vrf-blue", 1000, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vrf).To(BeIdenticalTo(existing))
+		})
+
+		It("fails if the existing VRF has another table", func() {
+			fake.addLink(&netlink.Vrf{LinkAttrs: netlink.LinkAttrs{Name: "vrf-blue"}, Table: 1000})
+
+			_, err := EnsureVRF("vrf-blue", 1001, 100)
+			Expect(err).To(MatchError(ContainSubstring("already exists with table 1000")))
+		})
+
+		It("fails if the name conflicts with an interface which is not a VRF", func() {
+			_, err := EnsureVRF("net1", 0, 100)
+			Expect(err).To(MatchError(ContainSubstring("already exists and is not a VRF")))
+		})
+
+		It("fails to look up the VRF", func() {
+			linkByName = func(string) (netlink.Link, error) {
+				return nil, errors.New("netlink error")
+			}
+
+			_, err := EnsureVRF("vrf-blue", 0, 100)
+			Expect(err).To(MatchError(ContainSubstring("netlink error")))
+		})
+
+		It("fails to add the VRF", func() {
+			linkAdd = func(netlink.Link) error {
+				return os.NewSyscallError("netlink", syscall.EOPNOTSUPP)
+			}
+
+			_, err := EnsureVRF("vrf-blue", 0, 100)
+			Expect(err).To(MatchError(syscall.EOPNOTSUPP))
+		})
+	})
+
+	Describe("LinkSetVRFMaster", func() {
+		var vrf *netlink.Vrf
+		var net1 netlink.Link
+
+		BeforeEach(func() {
+			var err error
+			vrf, err = EnsureVRF("vrf-blue", 1000, 100)
+			Expect(err).NotTo(HaveOccurred())
+
+			net1, err = linkByName("net1")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, dst, _ := net.ParseCIDR("10.6.0.0/16")
+			_, linkLocal, _ := net.ParseCIDR("fe80::/64")
+			fake.addrs["net1"] = []netlink.Addr{
+				{IPNet: &net.IPNet{IP: net.ParseIP("10.6.0.10"), Mask: net.CIDRMask(16, 32)}},
+				{IPNet: &net.IPNet{IP: net.ParseIP("fd00:10:6::10"), Mask: net.CIDRMask(64, 128)}},
+				{IPNet: &net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)}},
+			}
+			fake.routes["net1"] = []netlink.Route{
+				{LinkIndex: net1.Attrs().Index, Dst: dst},
+				{LinkIndex: net1.Attrs().Index, Gw: net.ParseIP("10.6.0.1")},
+				{LinkIndex: net1.Attrs().Index, Dst: linkLocal},
+			}
+		})
+
+		It("enslaves the interface and moves its addresses and routes to the VRF", func() {
+			err := LinkSetVRFMaster("net1", vrf)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(net1.Attrs().MasterIndex).To(Equal(vrf.Index))
+
+			Expect(fake.restored).To(HaveLen(2))
+			Expect(fake.restored[0].IP.String()).To(Equal("10.6.0.10"))
+			Expect(fake.restored[1].IP.String()).To(Equal("fd00:10:6::10"))
+
+			Expect(fake.added).To(HaveLen(2))
+			for _, r := range fake.added {
+				Expect(r.Table).To(Equal(1000))
+			}
+			Expect(fake.added[0].Dst.String()).To(Equal("10.6.0.0/16"))
+			Expect(fake.added[1].Gw.String()).To(Equal("10.6.0.1"))
+		})
+
+		It("ignores the routes already in the table of the VRF", func() {
+			for _, r := range fake.routes["net1"][:2] {
+				r.Table = 1000
+				fake.added = append(fake.added, r)
+			}
+
+			err := LinkSetVRFMaster("net1", vrf)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.added).To(HaveLen(2))
+		})
+
+		It("does nothing if the interface is already enslaved", func() {
+			net1.Attrs().MasterIndex = vrf.Index
+			linkSetMaster = func(netlink.Link, netlink.Link) error {
+				return errors.New("enslaved again")
+			}
+
+			err := LinkSetVRFMaster("net1", vrf)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fake.restored).To(BeEmpty())
+			Expect(fake.added).To(BeEmpty())
+		})
+
+		It("fails if the interface does not exist", func() {
+			err := LinkSetVRFMaster("net2", vrf)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("fails to enslave the interface", func() {
+			linkSetMaster = func(netlink.Link, netlink.Link) error {
+				return errors.New("netlink error")
+			}
+
+			err := LinkSetVRFMaster("net1", vrf)
+			Expect(err).To(MatchError(ContainSubstring("failed to enslave net1 to VRF vrf-blue")))
+		})
+	})
+
+	Describe("DelVRFIfUnused", func() {
+		var vrf *netlink.Vrf
+
+		BeforeEach(func() {
+			var err error
+			vrf, err = EnsureVRF("vrf-blue", 1000, 100)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does nothing if the VRF does not exist", func() {
+			deleted, err := DelVRFIfUnused("vrf-red", "net1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeFalse())
+		})
+
+		It("fails if the interface is not a VRF", func() {
+			deleted, err := DelVRFIfUnused("eth0", "net1")
+			Expect(err).To(MatchError(ContainSubstring("is not a VRF")))
+			Expect(deleted).To(BeFalse())
+		})
+
+		It("keeps the VRF used by the other interfaces", func() {
+			for _, name := range []string{"net1", "eth0"} {
+				link, err := linkByName(name)
+				Expect(err).NotTo(HaveOccurred())
+				link.Attrs().MasterIndex = vrf.Index
+			}
+
+			deleted, err := DelVRFIfUnused("vrf-blue", "net1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeFalse())
+
+			_, err = linkByName("vrf-blue")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("deletes the VRF only used by the ignored interface", func() {
+			link, err := linkByName("net1")
+			Expect(err).NotTo(HaveOccurred())
+			link.Attrs().MasterIndex = vrf.Index
+
+			deleted, err := DelVRFIfUnused("vrf-blue", "net1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeTrue())
+
+			_, err = linkByName("vrf-blue")
+			Expect(err).To(BeAssignableToTypeOf(netlink.LinkNotFoundError{}))
+		})
+	})
+})
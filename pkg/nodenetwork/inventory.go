@@ -0,0 +1,142 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package nodenetwork
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+)
+
+// DefaultSysfsNetDir is where the kernel exposes the attributes of the
+// network interfaces.
+const DefaultSysfsNetDir = "/sys/class/net"
+
+// collectInterfaces builds the inventory of the physical NICs, bonds, VLAN
+// sub-interfaces and SR-IOV VFs from the links of the host network namespace
+// and their sysfs attributes. The other links, such as veth, bridges and
+// tunnels, are not inventoried.
+func collectInterfaces(links []netlink.Link, sysfsNetDir string) []spiderpoolv2beta1.NodeInterface {
+	names := make(map[int]string, len(links))
+	for _, link := range links {
+		names[link.Attrs().Index] = link.Attrs().Name
+	}
+
+	slaves := map[int][]string{}
+	for _, link := range links {
+		if attrs := link.Attrs(); attrs.MasterIndex > 0 {
+			slaves[attrs.MasterIndex] = append(slaves[attrs.MasterIndex], attrs.Name)
+		}
+	}
+
+	interfaces := []spiderpoolv2beta1.NodeInterface{}
+	for _, link := range links {
+		attrs := link.Attrs()
+		iface := spiderpoolv2beta1.NodeInterface{
+			Name:   attrs.Name,
+			MAC:    attrs.HardwareAddr.String(),
+			MTU:    int32(attrs.MTU),
+			State:  attrs.OperState.String(),
+			Master: names[attrs.MasterIndex],
+		}
+
+		switch l := link.(type) {
+		case *netlink.Bond:
+			iface.Type = spiderpoolv2beta1.NodeInterfaceBond
+			iface.Slaves = slaves[attrs.Index]
+			sort.Strings(iface.Slaves)
+		case *netlink.Vlan:
+			iface.Type = spiderpoolv2beta1.NodeInterfaceVlan
+			iface.Parent = names[attrs.ParentIndex]
+			vlanID := int32(l.VlanId)
+			iface.VlanID = &vlanID
+		case *netlink.Device:
+			dir := filepath.Join(sysfsNetDir, attrs.Name)
+			// The loopback and the virtual devices, such as dummy, have no
+			// backing device.
+			if _, err := os.Stat(filepath.Join(dir, "device")); err != nil {
+				continue
+			}
+
+			iface.Type = spiderpoolv2beta1.NodeInterfacePhysical
+			if pf, ok := physicalFunction(dir); ok {
+				iface.Type = spiderpoolv2beta1.NodeInterfaceVF
+				iface.PF = pf
+			} else if total, ok := readInt(filepath.Join(dir, "device", "sriov_totalvfs")); ok && total > 0 {
+				num, _ := readInt(filepath.Join(dir, "device", "sriov_numvfs"))
+				iface.SRIOV = &spiderpoolv2beta1.SRIOVStatus{
+					TotalVFs: total,
+					NumVFs:   num,
+				}
+			}
+
+			iface.PCIAddress = linkBase(filepath.Join(dir, "device"))
+			iface.Driver = linkBase(filepath.Join(dir, "device", "driver"))
+			if numa, ok := readInt(filepath.Join(dir, "device", "numa_node")); ok && numa >= 0 {
+				iface.NUMANode = &numa
+			}
+			if speed, ok := readInt(filepath.Join(dir, "speed")); ok && speed > 0 {
+				iface.Speed = &speed
+			}
+		default:
+			continue
+		}
+
+		interfaces = append(interfaces, iface)
+	}
+
+	sort.Slice(interfaces, func(i, j int) bool {
+		return interfaces[i].Name < interfaces[j].Name
+	})
+
+	return interfaces
+}
+
+// physicalFunction returns the name of the PF if the interface is a VF.
+func physicalFunction(dir string) (string, bool) {
+	if _, err := os.Stat(filepath.Join(dir, "device", "physfn")); err != nil {
+		return "", false
+	}
+
+	// The PF may be bound to a userspace driver and have no netdev.
+	entries, err := os.ReadDir(filepath.Join(dir, "device", "physfn", "net"))
+	if err != nil || len(entries) == 0 {
+		return "", true
+	}
+
+	return entries[0].Name(), true
+}
+
+// linkBase returns the base name of the symlink target, or empty if the path
+// is not a symlink.
+func linkBase(path string) string {
+	target, err := os.Readlink(path)
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Base(target)
+}
+
+// readInt reads a sysfs attribute holding an integer. Reading the speed of a
+// link which is down fails with EINVAL.
+func readInt(path string) (int32, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+
+	v, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	return int32(v), true
+}
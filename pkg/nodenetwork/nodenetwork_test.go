@@ -0,0 +1,235 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package nodenetwork
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/vishvananda/netlink"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+)
+
+var _ = Describe("NodeNetwork", Label("nodenetwork_test"), func() {
+	var sysfsNetDir string
+	var links []netlink.Link
+
+	// writeSysfs fakes the sysfs attributes of a device.
+	writeSysfs := func(name, pci, driver string, attrs map[string]string) {
+		devicesDir := filepath.Join(sysfsNetDir, "..", "devices")
+		deviceDir := filepath.Join(devicesDir, pci)
+		Expect(os.MkdirAll(filepath.Join(devicesDir, "drivers", driver), 0o755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(deviceDir, "net", name), 0o755)).To(Succeed())
+		Expect(os.Symlink(filepath.Join(devicesDir, "drivers", driver), filepath.Join(deviceDir, "driver"))).To(Succeed())
+
+		dir := filepath.Join(sysfsNetDir, name)
+		Expect(os.MkdirAll(dir, 0o755)).To(Succeed())
+		Expect(os.Symlink(deviceDir, filepath.Join(dir, "device"))).To(Succeed())
+		for k, v := range attrs {
+			Expect(os.WriteFile(filepath.Join(dir, k), []byte(v+"\n"), 0o644)).To(Succeed())
+		}
+	}
+
+	newAttrs := func(index int, name, mac string) netlink.LinkAttrs {
+		hw, err := net.ParseMAC(mac)
+		Expect(err).NotTo(HaveOccurred())
+
+		return netlink.LinkAttrs{
+			Index:        index,
+			Name:         name,
+			HardwareAddr: hw,
+			MTU:          1500,
+			OperState:    netlink.OperUp,
+		}
+	}
+
+	BeforeEach(func() {
+		root := GinkgoT().TempDir()
+		sysfsNetDir = filepath.Join(root, "net")
+
+		writeSysfs("ens1f0", "0000:3b:00.0", "mlx5_core", map[string]string{
+			"speed":                 "25000",
+			"device/numa_node":      "0",
+			"device/sriov_totalvfs": "8",
+			"device/sriov_numvfs":   "2",
+		})
+		writeSysfs("ens1f0v0", "0000:3b:00.2", "mlx5_core", map[string]string{
+			"speed":            "-1",
+			"device/numa_node": "-1",
+		})
+		Expect(os.Symlink(filepath.Join(sysfsNetDir, "..", "devices", "0000:3b:00.0"),
+			filepath.Join(sysfsNetDir, "ens1f0v0", "device", "physfn"))).To(Succeed())
+		writeSysfs("ens2f0", "0000:5e:00.0", "ixgbe", nil)
+
+		ens1f0 := newAttrs(2, "ens1f0", "52:54:00:00:00:01")
+		vf := newAttrs(3, "ens1f0v0", "52:54:00:00:00:02")
+		ens2f0 := newAttrs(4, "ens2f0", "52:54:00:00:00:03")
+		ens2f0.MasterIndex = 5
+		ens2f0.OperState = netlink.OperDown
+		bond := newAttrs(5, "bond0", "52:54:00:00:00:03")
+		vlan := newAttrs(6, "bond0.100", "52:54:00:00:00:03")
+		vlan.ParentIndex = 5
+		lo := newAttrs(1, "lo", "00:00:00:00:00:00")
+		veth := newAttrs(7, "veth1234", "52:54:00:00:00:04")
+
+		links = []netlink.Link{
+			&netlink.Device{LinkAttrs: lo},
+			&netlink.Device{LinkAttrs: ens1f0},
+			&netlink.Device{LinkAttrs: vf},
+			&netlink.Device{LinkAttrs: ens2f0},
+			&netlink.Bond{LinkAttrs: bond},
+			&netlink.Vlan{LinkAttrs: vlan, VlanId: 100},
+			&netlink.Veth{LinkAttrs: veth},
+		}
+	})
+
+	Context("collectInterfaces", func() {
+		It("inventories the physical NICs, bonds, VLANs and VFs", func() {
+			interfaces := collectInterfaces(links, sysfsNetDir)
+			Expect(interfaces).To(Equal([]spiderpoolv2beta1.NodeInterface{
+				{
+					Name:   "bond0",
+					Type:   spiderpoolv2beta1.NodeInterfaceBond,
+					MAC:    "52:54:00:00:00:03",
+					MTU:    1500,
+					State:  "up",
+					Slaves: []string{"ens2f0"},
+				},
+				{
+					Name:   "bond0.100",
+					Type:   spiderpoolv2beta1.NodeInterfaceVlan,
+					MAC:    "52:54:00:00:00:03",
+					MTU:    1500,
+					State:  "up",
+					Parent: "bond0",
+					VlanID: pointer.Int32(100),
+				},
+				{
+					Name:       "ens1f0",
+					Type:       spiderpoolv2beta1.NodeInterfacePhysical,
+					MAC:        "52:54:00:00:00:01",
+					MTU:        1500,
+					State:      "up",
+					Driver:     "mlx5_core",
+					PCIAddress: "0000:3b:00.0",
+					NUMANode:   pointer.Int32(0),
+					Speed:      pointer.Int32(25000),
+					SRIOV: &spiderpoolv2beta1.SRIOVStatus{
+						TotalVFs: 8,
+						NumVFs:   2,
+					},
+				},
+				{
+					Name:       "ens1f0v0",
+					Type:       spiderpoolv2beta1.NodeInterfaceVF,
+					MAC:        "52:54:00:00:00:02",
+					MTU:        1500,
+					State:      "up",
+					Driver:     "mlx5_core",
+					PCIAddress: "0000:3b:00.2",
+					PF:         "ens1f0",
+				},
+				{
+					Name:       "ens2f0",
+					Type:       spiderpoolv2beta1.NodeInterfacePhysical,
+					MAC:        "52:54:00:00:00:03",
+					MTU:        1500,
+					State:      "down",
+					Master:     "bond0",
+					Driver:     "ixgbe",
+					PCIAddress: "0000:5e:00.0",
+				},
+			}))
+		})
+	})
+
+	Context("Publisher", func() {
+		var fakeClient client.Client
+		var publisher *Publisher
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			Expect(spiderpoolv2beta1.AddToScheme(scheme)).To(Succeed())
+
+			fakeClient = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "node1",
+						UID:  "node1-uid",
+					},
+				}).
+				Build()
+
+			var err error
+			publisher, err = NewPublisher(Config{
+				NodeName:    "node1",
+				SysfsNetDir: sysfsNetDir,
+			}, fakeClient, fakeClient)
+			Expect(err).NotTo(HaveOccurred())
+			publisher.listLinks = func() ([]netlink.Link, error) {
+				return links, nil
+			}
+		})
+
+		It("fails to create without required params", func() {
+			_, err := NewPublisher(Config{NodeName: "node1"}, nil, fakeClient)
+			Expect(err).To(MatchError(constant.ErrMissingRequiredParam))
+
+			_, err = NewPublisher(Config{}, fakeClient, fakeClient)
+			Expect(err).To(MatchError(constant.ErrMissingRequiredParam))
+		})
+
+		It("creates the SpiderNodeNetwork owned by the Node", func() {
+			Expect(publisher.Publish(context.TODO())).To(Succeed())
+
+			var nodeNetwork spiderpoolv2beta1.SpiderNodeNetwork
+			Expect(fakeClient.Get(context.TODO(), client.ObjectKey{Name: "node1"}, &nodeNetwork)).To(Succeed())
+			Expect(nodeNetwork.OwnerReferences).To(HaveLen(1))
+			Expect(nodeNetwork.OwnerReferences[0].Kind).To(Equal(constant.KindNode))
+			Expect(string(nodeNetwork.OwnerReferences[0].UID)).To(Equal("node1-uid"))
+			Expect(nodeNetwork.Status.Interfaces).To(HaveLen(5))
+			Expect(nodeNetwork.Status.LastUpdateTime).NotTo(BeNil())
+		})
+
+		It("updates the status only when the interfaces change", func() {
+			Expect(publisher.Publish(context.TODO())).To(Succeed())
+
+			var before spiderpoolv2beta1.SpiderNodeNetwork
+			Expect(fakeClient.Get(context.TODO(), client.ObjectKey{Name: "node1"}, &before)).To(Succeed())
+
+			Expect(publisher.Publish(context.TODO())).To(Succeed())
+			var unchanged spiderpoolv2beta1.SpiderNodeNetwork
+			Expect(fakeClient.Get(context.TODO(), client.ObjectKey{Name: "node1"}, &unchanged)).To(Succeed())
+			Expect(unchanged.ResourceVersion).To(Equal(before.ResourceVersion))
+
+			// bond0.100 is removed.
+			links = links[:len(links)-2]
+			Expect(publisher.Publish(context.TODO())).To(Succeed())
+			var changed spiderpoolv2beta1.SpiderNodeNetwork
+			Expect(fakeClient.Get(context.TODO(), client.ObjectKey{Name: "node1"}, &changed)).To(Succeed())
+			Expect(changed.ResourceVersion).NotTo(Equal(before.ResourceVersion))
+			Expect(changed.Status.Interfaces).To(HaveLen(4))
+		})
+
+		It("fails if the Node does not exist", func() {
+			publisher.config.NodeName = "node2"
+			Expect(publisher.Publish(context.TODO())).NotTo(Succeed())
+		})
+	})
+})
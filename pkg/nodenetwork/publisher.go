@@ -0,0 +1,162 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package nodenetwork
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+)
+
+type Config struct {
+	NodeName string
+	// Interval is the period to refresh the inventory.
+	Interval time.Duration
+	// SysfsNetDir is where the attributes of the network interfaces are
+	// read from.
+	SysfsNetDir string
+}
+
+func setDefaultsForConfig(config Config) Config {
+	if config.Interval <= 0 {
+		config.Interval = 60 * time.Second
+	}
+	if config.SysfsNetDir == "" {
+		config.SysfsNetDir = DefaultSysfsNetDir
+	}
+
+	return config
+}
+
+// Publisher periodically publishes the network interfaces of the node to the
+// SpiderNodeNetwork named after the node, which is owned by the Node and
+// garbage collected with it.
+type Publisher struct {
+	config    Config
+	client    client.Client
+	apiReader client.Reader
+
+	// listLinks is replaced in unit tests.
+	listLinks func() ([]netlink.Link, error)
+}
+
+// NewPublisher reads the SpiderNodeNetwork with the apiReader, so that the
+// agent does not cache the SpiderNodeNetworks of all nodes.
+func NewPublisher(config Config, client client.Client, apiReader client.Reader) (*Publisher, error) {
+	if client == nil {
+		return nil, fmt.Errorf("k8s client %w", constant.ErrMissingRequiredParam)
+	}
+	if apiReader == nil {
+		return nil, fmt.Errorf("api reader %w", constant.ErrMissingRequiredParam)
+	}
+	if config.NodeName == "" {
+		return nil, fmt.Errorf("node name %w", constant.ErrMissingRequiredParam)
+	}
+
+	return &Publisher{
+		config:    setDefaultsForConfig(config),
+		client:    client,
+		apiReader: apiReader,
+		listLinks: netlink.LinkList,
+	}, nil
+}
+
+// Start publishes the inventory immediately and then every interval until
+// the context is done.
+func (p *Publisher) Start(ctx context.Context) error {
+	logger := logutils.Logger.Named("Node-Network")
+	logger.Sugar().Infof("Start to publish the network interfaces of Node %s every %s", p.config.NodeName, p.config.Interval)
+
+	ctx = logutils.IntoContext(ctx, logger)
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Publish(ctx); err != nil {
+			logger.Sugar().Warnf("Failed to publish the network interfaces: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Publish creates the SpiderNodeNetwork of the node if absent, and updates
+// its status if the interfaces changed.
+func (p *Publisher) Publish(ctx context.Context) error {
+	logger := logutils.FromContext(ctx)
+
+	links, err := p.listLinks()
+	if err != nil {
+		return fmt.Errorf("failed to list links: %w", err)
+	}
+	interfaces := collectInterfaces(links, p.config.SysfsNetDir)
+
+	var nodeNetwork spiderpoolv2beta1.SpiderNodeNetwork
+	if err := p.apiReader.Get(ctx, client.ObjectKey{Name: p.config.NodeName}, &nodeNetwork); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		created, err := p.create(ctx)
+		if err != nil {
+			return err
+		}
+		logger.Sugar().Infof("Create SpiderNodeNetwork %s", created.Name)
+		nodeNetwork = *created
+	}
+
+	if apiequality.Semantic.DeepEqual(nodeNetwork.Status.Interfaces, interfaces) {
+		return nil
+	}
+
+	nodeNetwork.Status.Interfaces = interfaces
+	nodeNetwork.Status.LastUpdateTime = &metav1.Time{Time: time.Now()}
+	if err := p.client.Status().Update(ctx, &nodeNetwork); err != nil {
+		return fmt.Errorf("failed to update the status of SpiderNodeNetwork %s: %w", nodeNetwork.Name, err)
+	}
+	logger.Sugar().Infof("Update the %d network interfaces of SpiderNodeNetwork %s", len(interfaces), nodeNetwork.Name)
+
+	return nil
+}
+
+func (p *Publisher) create(ctx context.Context) (*spiderpoolv2beta1.SpiderNodeNetwork, error) {
+	var node corev1.Node
+	if err := p.apiReader.Get(ctx, client.ObjectKey{Name: p.config.NodeName}, &node); err != nil {
+		return nil, fmt.Errorf("failed to get Node %s: %w", p.config.NodeName, err)
+	}
+
+	nodeNetwork := &spiderpoolv2beta1.SpiderNodeNetwork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: p.config.NodeName,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         corev1.SchemeGroupVersion.String(),
+				Kind:               constant.KindNode,
+				Name:               node.Name,
+				UID:                node.UID,
+				BlockOwnerDeletion: pointer.Bool(true),
+			}},
+		},
+	}
+	if err := p.client.Create(ctx, nodeNetwork); err != nil {
+		return nil, fmt.Errorf("failed to create SpiderNodeNetwork %s: %w", p.config.NodeName, err)
+	}
+
+	return nodeNetwork, nil
+}
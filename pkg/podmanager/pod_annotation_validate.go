@@ -0,0 +1,260 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package podmanager
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/spidernet-io/spiderpool/pkg/applicationcontroller/applicationinformers"
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/ippoolmanager"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/types"
+)
+
+// poolAnnotations are the Pod annotations selecting IPPools or Subnets, which
+// are validated against the existing resources.
+var poolAnnotations = []string{
+	constant.AnnoPodIPPools,
+	constant.AnnoPodIPPool,
+	constant.AnnoPodIPPoolGroup,
+	constant.AnnoSpiderSubnets,
+	constant.AnnoSpiderSubnet,
+	constant.AnnoSpiderSubnetPoolIPNumber,
+}
+
+// validatePoolAnnotations parses the IPPool, IPPool group and Subnet
+// annotations with the same code as CNI ADD, and checks that the IPPools and
+// Subnets exist and match the IP versions, and at least one IPPool of each IP
+// version allows the Namespace. The IP number of the auto-created IPPools is
+// validated whenever it is set.
+func (pw *PodWebhook) validatePoolAnnotations(ctx context.Context, annoPath *field.Path, namespace string, annotations map[string]string) field.ErrorList {
+	if pw.Client == nil {
+		return nil
+	}
+
+	if value, ok := annotations[constant.AnnoSpiderSubnetPoolIPNumber]; ok {
+		_, ipNum, err := applicationinformers.GetPoolIPNumber(value)
+		if err == nil && ipNum < 0 {
+			err = fmt.Errorf("value must equal or greater than 0")
+		}
+		if err != nil {
+			return field.ErrorList{field.Invalid(
+				annoPath.Key(constant.AnnoSpiderSubnetPoolIPNumber),
+				value,
+				err.Error(),
+			)}
+		}
+	}
+
+	if pw.EnableSpiderSubnet {
+		subnetAnno := constant.AnnoSpiderSubnets
+		if _, ok := annotations[subnetAnno]; !ok {
+			subnetAnno = constant.AnnoSpiderSubnet
+		}
+		if _, ok := annotations[subnetAnno]; ok {
+			return pw.validateSubnetAnnotations(ctx, annoPath, subnetAnno, annotations)
+		}
+	}
+
+	if value, ok := annotations[constant.AnnoPodIPPools]; ok {
+		fldPath := annoPath.Key(constant.AnnoPodIPPools)
+		ipPools, err := ParsePodIPPoolsAnnotation(value)
+		if err != nil {
+			return field.ErrorList{field.Invalid(fldPath, value, err.Error())}
+		}
+
+		var errs field.ErrorList
+		for _, item := range ipPools {
+			errs = append(errs, pw.validateIPPools(ctx, fldPath, value, namespace, item.NIC, constant.IPv4, item.IPv4Pools)...)
+			errs = append(errs, pw.validateIPPools(ctx, fldPath, value, namespace, item.NIC, constant.IPv6, item.IPv6Pools)...)
+		}
+		return errs
+	}
+
+	if value, ok := annotations[constant.AnnoPodIPPool]; ok {
+		fldPath := annoPath.Key(constant.AnnoPodIPPool)
+		ipPool, err := ParsePodIPPoolAnnotation(value)
+		if err != nil {
+			return field.ErrorList{field.Invalid(fldPath, value, err.Error())}
+		}
+
+		var errs field.ErrorList
+		errs = append(errs, pw.validateIPPools(ctx, fldPath, value, namespace, "", constant.IPv4, ipPool.IPv4Pools)...)
+		errs = append(errs, pw.validateIPPools(ctx, fldPath, value, namespace, "", constant.IPv6, ipPool.IPv6Pools)...)
+		return errs
+	}
+
+	if value, ok := annotations[constant.AnnoPodIPPoolGroup]; ok {
+		return pw.validatePoolGroupAnnotation(ctx, annoPath.Key(constant.AnnoPodIPPoolGroup), value, namespace)
+	}
+
+	return nil
+}
+
+// validatePoolGroupAnnotation checks that the IPPool group has members of
+// each enabled IP version, and at least one of them allows the Namespace.
+// The topology of the members is left to CNI ADD, as the Node is unknown
+// before scheduling.
+func (pw *PodWebhook) validatePoolGroupAnnotation(ctx context.Context, fldPath *field.Path, value, namespace string) field.ErrorList {
+	group, err := ParsePodIPPoolGroupAnnotation(value)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, value, err.Error())}
+	}
+
+	var ipPoolList spiderpoolv2beta1.SpiderIPPoolList
+	if err := pw.Client.List(ctx, &ipPoolList, client.MatchingFields{"spec.poolGroup.name": group}); err != nil {
+		return field.ErrorList{field.InternalError(fldPath, fmt.Errorf("failed to list the members of IPPool group %s: %v", group, err))}
+	}
+	if len(ipPoolList.Items) == 0 {
+		return field.ErrorList{field.Invalid(fldPath, value, fmt.Sprintf("IPPool group %s does not exist", group))}
+	}
+
+	var v4Pools, v6Pools []string
+	for _, ipPool := range ipPoolList.Items {
+		if ipPool.Spec.IPVersion != nil && *ipPool.Spec.IPVersion == constant.IPv6 {
+			v6Pools = append(v6Pools, ipPool.Name)
+		} else {
+			v4Pools = append(v4Pools, ipPool.Name)
+		}
+	}
+
+	var errs field.ErrorList
+	if pw.EnableIPv4 && len(v4Pools) == 0 {
+		errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("no IPv4 IPPool in IPPool group %s", group)))
+	}
+	if pw.EnableIPv6 && len(v6Pools) == 0 {
+		errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("no IPv6 IPPool in IPPool group %s", group)))
+	}
+	errs = append(errs, pw.validateIPPools(ctx, fldPath, value, namespace, "", constant.IPv4, v4Pools)...)
+	errs = append(errs, pw.validateIPPools(ctx, fldPath, value, namespace, "", constant.IPv6, v6Pools)...)
+
+	return errs
+}
+
+func (pw *PodWebhook) validateSubnetAnnotations(ctx context.Context, annoPath *field.Path, subnetAnno string, annotations map[string]string) field.ErrorList {
+	fldPath := annoPath.Key(subnetAnno)
+	value := annotations[subnetAnno]
+	subnetConfig, err := applicationinformers.GetSubnetAnnoConfig(annotations, WebhookLogger)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, value, err.Error())}
+	}
+
+	items := subnetConfig.MultipleSubnets
+	if subnetConfig.SingleSubnet != nil {
+		items = append(items, *subnetConfig.SingleSubnet)
+	}
+
+	var errs field.ErrorList
+	for _, item := range items {
+		if pw.EnableIPv4 && len(item.IPv4) == 0 {
+			errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("no IPv4 Subnet specified for interface %s", item.Interface)))
+		}
+		if pw.EnableIPv6 && len(item.IPv6) == 0 {
+			errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("no IPv6 Subnet specified for interface %s", item.Interface)))
+		}
+		errs = append(errs, pw.validateSubnets(ctx, fldPath, value, constant.IPv4, item.IPv4)...)
+		errs = append(errs, pw.validateSubnets(ctx, fldPath, value, constant.IPv6, item.IPv6)...)
+	}
+
+	return errs
+}
+
+func (pw *PodWebhook) validateSubnets(ctx context.Context, fldPath *field.Path, value string, version types.IPVersion, subnets []string) field.ErrorList {
+	var errs field.ErrorList
+	for _, name := range subnets {
+		var subnet spiderpoolv2beta1.SpiderSubnet
+		if err := pw.Client.Get(ctx, apitypes.NamespacedName{Name: name}, &subnet); err != nil {
+			if apierrors.IsNotFound(err) {
+				errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("Subnet %s does not exist", name)))
+				continue
+			}
+			errs = append(errs, field.InternalError(fldPath, fmt.Errorf("failed to get Subnet %s: %v", name, err)))
+			continue
+		}
+
+		if subnet.Spec.IPVersion != nil && *subnet.Spec.IPVersion != version {
+			errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("expect an IPv%d Subnet, but the version of the Subnet %s is IPv%d", version, name, *subnet.Spec.IPVersion)))
+		}
+	}
+
+	return errs
+}
+
+// validateIPPools checks the IPPool candidates of the IP version for the
+// interface like CNI ADD does: all of them must exist without duplicates,
+// and at least one of them must match the IP version and the Namespace.
+func (pw *PodWebhook) validateIPPools(ctx context.Context, fldPath *field.Path, value, namespace, nic string, version types.IPVersion, pools []string) field.ErrorList {
+	if len(pools) == 0 {
+		return nil
+	}
+
+	var errs field.ErrorList
+	var ipPools []*spiderpoolv2beta1.SpiderIPPool
+	marks := map[string]bool{}
+	for _, name := range pools {
+		if marks[name] {
+			errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("duplicate IPPool %s", name)))
+			continue
+		}
+		marks[name] = true
+
+		var ipPool spiderpoolv2beta1.SpiderIPPool
+		if err := pw.Client.Get(ctx, apitypes.NamespacedName{Name: name}, &ipPool); err != nil {
+			if apierrors.IsNotFound(err) {
+				errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("IPPool %s does not exist", name)))
+				continue
+			}
+			errs = append(errs, field.InternalError(fldPath, fmt.Errorf("failed to get IPPool %s: %v", name, err)))
+			continue
+		}
+		ipPools = append(ipPools, &ipPool)
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+
+	var ns *corev1.Namespace
+	var reasons []error
+	for _, ipPool := range ipPools {
+		if ipPool.Spec.IPVersion != nil && *ipPool.Spec.IPVersion != version {
+			reasons = append(reasons, fmt.Errorf("expect an IPv%d IPPool, but the version of the IPPool %s is IPv%d", version, ipPool.Name, *ipPool.Spec.IPVersion))
+			continue
+		}
+
+		if ipPool.Spec.NamespaceAffinity != nil {
+			if ns == nil {
+				ns = &corev1.Namespace{}
+				if err := pw.Client.Get(ctx, apitypes.NamespacedName{Name: namespace}, ns); err != nil {
+					return field.ErrorList{field.InternalError(fldPath, fmt.Errorf("failed to get Namespace %s: %v", namespace, err))}
+				}
+			}
+			matched, err := ippoolmanager.IsMatchNamespaceAffinity(ipPool, ns.Labels)
+			if err != nil {
+				return field.ErrorList{field.InternalError(fldPath, err)}
+			}
+			if !matched {
+				reasons = append(reasons, fmt.Errorf("unmatched Namespace affinity of IPPool %s", ipPool.Name))
+				continue
+			}
+		}
+
+		return nil
+	}
+
+	msg := fmt.Sprintf("all IPv%d IPPools %v", version, pools)
+	if nic != "" {
+		msg += fmt.Sprintf(" of interface %s", nic)
+	}
+
+	return field.ErrorList{field.Invalid(fldPath, value, fmt.Sprintf("%s filtered out: %v", msg, utilerrors.NewAggregate(reasons)))}
+}
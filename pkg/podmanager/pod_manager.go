@@ -17,6 +17,7 @@ import (
 
 	"github.com/spidernet-io/spiderpool/pkg/constant"
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
 	"github.com/spidernet-io/spiderpool/pkg/types"
 )
 
@@ -77,9 +78,12 @@ func (pm *podManager) ListPods(ctx context.Context, cached bool, opts ...client.
 // For example, once we create a deployment then it will create replicaset and the replicaset will create pods.
 // So, the pods' top owner is deployment. That's what the method implements.
 // Notice: if the application is a third party controller, the types.PodTopController property App would be nil!
-func (pm *podManager) GetPodTopController(ctx context.Context, pod *corev1.Pod) (types.PodTopController, error) {
+func (pm *podManager) GetPodTopController(ctx context.Context, pod *corev1.Pod) (_ types.PodTopController, err error) {
 	logger := logutils.FromContext(ctx)
 
+	ctx, span := tracing.Start(ctx, "pod GetPodTopController")
+	defer func() { tracing.End(span, err) }()
+
 	var ownerErr = fmt.Errorf("failed to get pod '%s/%s' owner", pod.Namespace, pod.Name)
 
 	podOwner := metav1.GetControllerOf(pod)
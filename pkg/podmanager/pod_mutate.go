@@ -0,0 +1,59 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package podmanager
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+)
+
+// mutatePodAnnotations normalizes the coordinator annotation of the Pod, so
+// that the coordinator plugin gets the extra CIDRs as network prefixes which
+// could be installed as routes. The invalid annotation is left as it is and
+// refused by the validating webhook.
+func mutatePodAnnotations(ctx context.Context, pod *corev1.Pod) error {
+	logger := logutils.FromContext(ctx)
+
+	value, ok := pod.Annotations[constant.AnnoPodCoordinator]
+	if !ok {
+		return nil
+	}
+
+	anno, err := ParsePodCoordinatorAnnotation(value)
+	if err != nil {
+		logger.Sugar().Debugf("Skip to mutate the invalid coordinator annotation: %v", err)
+		return nil
+	}
+
+	var extraCIDR []string
+	seen := map[string]struct{}{}
+	for _, cidr := range anno.ExtraCIDR {
+		_, ipNet, _ := net.ParseCIDR(cidr)
+		prefix := ipNet.String()
+		if _, ok := seen[prefix]; ok {
+			continue
+		}
+		seen[prefix] = struct{}{}
+		extraCIDR = append(extraCIDR, prefix)
+	}
+	anno.ExtraCIDR = extraCIDR
+
+	b, err := json.Marshal(anno)
+	if err != nil {
+		return err
+	}
+
+	if string(b) != value {
+		pod.Annotations[constant.AnnoPodCoordinator] = string(b)
+		logger.Sugar().Infof("Normalize the coordinator annotation to %s", string(b))
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package podmanager
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+)
+
+var WebhookLogger *zap.Logger
+
+var annotationsField *field.Path = field.NewPath("metadata").Child("annotations")
+
+type PodWebhook struct {
+	// Client is used to look up the IPPools, Subnets and Namespaces referred
+	// to by the annotations, only the syntax of the annotations is validated
+	// without it.
+	Client client.Client
+
+	EnableIPv4         bool
+	EnableIPv6         bool
+	EnableSpiderSubnet bool
+}
+
+func (pw *PodWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if WebhookLogger == nil {
+		WebhookLogger = logutils.Logger.Named("Pod-Webhook")
+	}
+
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&corev1.Pod{}).
+		WithDefaulter(pw).
+		WithValidator(pw).
+		Complete()
+}
+
+var _ webhook.CustomDefaulter = (*PodWebhook)(nil)
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type.
+func (pw *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	pod := obj.(*corev1.Pod)
+
+	logger := WebhookLogger.Named("Mutating").With(
+		zap.String("PodNamespace", pod.Namespace),
+		zap.String("PodName", pod.Name),
+		zap.String("Operation", "DEFAULT"),
+	)
+
+	if err := mutatePodAnnotations(logutils.IntoContext(ctx, logger), pod); err != nil {
+		logger.Sugar().Errorf("Failed to mutate Pod: %v", err)
+	}
+
+	return nil
+}
+
+var _ webhook.CustomValidator = (*PodWebhook)(nil)
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (pw *PodWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	pod := obj.(*corev1.Pod)
+
+	logger := WebhookLogger.Named("Validating").With(
+		zap.String("PodNamespace", pod.Namespace),
+		zap.String("PodName", pod.Name),
+		zap.String("Operation", "CREATE"),
+	)
+
+	if errs := pw.validateAnnotations(ctx, annotationsField, pod.Namespace, pod.Annotations); len(errs) != 0 {
+		logger.Sugar().Errorf("Failed to create Pod: %v", errs.ToAggregate().Error())
+		return apierrors.NewInvalid(
+			schema.GroupKind{Group: corev1.GroupName, Kind: constant.KindPod},
+			pod.Name,
+			errs,
+		)
+	}
+
+	return nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (pw *PodWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldPod := oldObj.(*corev1.Pod)
+	newPod := newObj.(*corev1.Pod)
+
+	// the annotations of a running Pod have already been consumed, only
+	// validate the changed ones.
+	changed := oldPod.Annotations[constant.AnnoPodCoordinator] != newPod.Annotations[constant.AnnoPodCoordinator] ||
+		oldPod.Annotations[constant.AnnoPodRoutes] != newPod.Annotations[constant.AnnoPodRoutes] ||
+		oldPod.Annotations[constant.AnnoPodStaticIPs] != newPod.Annotations[constant.AnnoPodStaticIPs]
+	for _, anno := range poolAnnotations {
+		if oldPod.Annotations[anno] != newPod.Annotations[anno] {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	logger := WebhookLogger.Named("Validating").With(
+		zap.String("PodNamespace", newPod.Namespace),
+		zap.String("PodName", newPod.Name),
+		zap.String("Operation", "UPDATE"),
+	)
+
+	if errs := pw.validateAnnotations(ctx, annotationsField, newPod.Namespace, newPod.Annotations); len(errs) != 0 {
+		logger.Sugar().Errorf("Failed to update Pod: %v", errs.ToAggregate().Error())
+		return apierrors.NewInvalid(
+			schema.GroupKind{Group: corev1.GroupName, Kind: constant.KindPod},
+			newPod.Name,
+			errs,
+		)
+	}
+
+	return nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (pw *PodWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+// validateAnnotations validates the Spiderpool annotations of the Pod, or of
+// the Pod template of a workload in the namespace.
+func (pw *PodWebhook) validateAnnotations(ctx context.Context, annoPath *field.Path, namespace string, annotations map[string]string) field.ErrorList {
+	// the namespace of the object may be empty on CREATE, take it from the
+	// admission request.
+	if namespace == "" {
+		if req, err := admission.RequestFromContext(ctx); err == nil {
+			namespace = req.Namespace
+		}
+	}
+
+	errs := validatePodAnnotations(annoPath, annotations)
+	errs = append(errs, pw.validatePoolAnnotations(ctx, annoPath, namespace, annotations)...)
+
+	return errs
+}
+
+func validatePodAnnotations(annoPath *field.Path, annotations map[string]string) field.ErrorList {
+	var errs field.ErrorList
+
+	if value, ok := annotations[constant.AnnoPodCoordinator]; ok {
+		if _, err := ParsePodCoordinatorAnnotation(value); err != nil {
+			errs = append(errs, field.Invalid(
+				annoPath.Key(constant.AnnoPodCoordinator),
+				value,
+				err.Error(),
+			))
+		}
+	}
+
+	if value, ok := annotations[constant.AnnoPodRoutes]; ok {
+		if _, err := ParsePodRoutesAnnotation(value); err != nil {
+			errs = append(errs, field.Invalid(
+				annoPath.Key(constant.AnnoPodRoutes),
+				value,
+				err.Error(),
+			))
+		}
+	}
+
+	if value, ok := annotations[constant.AnnoPodStaticIPs]; ok {
+		if _, err := ParsePodStaticIPsAnnotation(value); err != nil {
+			errs = append(errs, field.Invalid(
+				annoPath.Key(constant.AnnoPodStaticIPs),
+				value,
+				err.Error(),
+			))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,347 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package podmanager_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+	"github.com/spidernet-io/spiderpool/pkg/podmanager"
+)
+
+var _ = Describe("PodWebhook", Label("pod_webhook_test"), func() {
+	var ctx context.Context
+	var podWebhook *podmanager.PodWebhook
+	var podT *corev1.Pod
+
+	BeforeEach(func() {
+		podmanager.WebhookLogger = logutils.Logger.Named("Pod-Webhook")
+		podWebhook = &podmanager.PodWebhook{}
+
+		ctx = context.TODO()
+		podT = &corev1.Pod{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       constant.KindPod,
+				APIVersion: corev1.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pod",
+				Namespace:   "default",
+				Annotations: map[string]string{},
+			},
+		}
+	})
+
+	Describe("ValidateCreate", func() {
+		It("passes Pod without coordinator annotation", func() {
+			err := podWebhook.ValidateCreate(ctx, podT)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("passes valid coordinator annotation", func() {
+			podT.Annotations[constant.AnnoPodCoordinator] = `{"detectGateway":false,"extraCIDR":["10.6.0.0/16"]}`
+
+			err := podWebhook.ValidateCreate(ctx, podT)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("refuses the fields out of the whitelist", func() {
+			podT.Annotations[constant.AnnoPodCoordinator] = `{"tuneMode":"overlay"}`
+
+			err := podWebhook.ValidateCreate(ctx, podT)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		})
+
+		It("refuses invalid extraCIDR", func() {
+			podT.Annotations[constant.AnnoPodCoordinator] = `{"extraCIDR":["10.6.0.0"]}`
+
+			err := podWebhook.ValidateCreate(ctx, podT)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		})
+
+		It("refuses invalid static IPs", func() {
+			podT.Annotations[constant.AnnoPodStaticIPs] = `[{"interface":"eth0","ipv4":"172.18.40.256"}]`
+
+			err := podWebhook.ValidateCreate(ctx, podT)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		})
+	})
+
+	Describe("ValidateUpdate", func() {
+		It("ignores the unchanged annotation", func() {
+			podT.Annotations[constant.AnnoPodCoordinator] = `invalid`
+			newPodT := podT.DeepCopy()
+			newPodT.Labels = map[string]string{"foo": "bar"}
+
+			err := podWebhook.ValidateUpdate(ctx, podT, newPodT)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("refuses the changed invalid annotation", func() {
+			newPodT := podT.DeepCopy()
+			newPodT.Annotations[constant.AnnoPodCoordinator] = `{"podDefaultRouteNIC":""}`
+
+			err := podWebhook.ValidateUpdate(ctx, podT, newPodT)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		})
+	})
+
+	Describe("Default", func() {
+		It("passes Pod without coordinator annotation", func() {
+			err := podWebhook.Default(ctx, podT)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podT.Annotations).To(BeEmpty())
+		})
+
+		It("normalizes the extraCIDR of coordinator annotation", func() {
+			podT.Annotations[constant.AnnoPodCoordinator] = `{"detectGateway": false, "extraCIDR": ["10.6.1.1/16", "10.6.0.0/16", "fd00::1/64"]}`
+
+			err := podWebhook.Default(ctx, podT)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podT.Annotations[constant.AnnoPodCoordinator]).To(Equal(`{"detectGateway":false,"extraCIDR":["10.6.0.0/16","fd00::/64"]}`))
+		})
+
+		It("leaves the invalid coordinator annotation to the validation", func() {
+			value := `{"tuneMode":"overlay"}`
+			podT.Annotations[constant.AnnoPodCoordinator] = value
+
+			err := podWebhook.Default(ctx, podT)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podT.Annotations[constant.AnnoPodCoordinator]).To(Equal(value))
+		})
+	})
+
+	Describe("Spiderpool annotations", func() {
+		var v4PoolT, v6PoolT *spiderpoolv2beta1.SpiderIPPool
+		var v4SubnetT *spiderpoolv2beta1.SpiderSubnet
+		var namespaceT *corev1.Namespace
+
+		BeforeEach(func() {
+			ipVersion4 := constant.IPv4
+			ipVersion6 := constant.IPv6
+			v4PoolT = &spiderpoolv2beta1.SpiderIPPool{
+				ObjectMeta: metav1.ObjectMeta{Name: "v4-pool"},
+				Spec: spiderpoolv2beta1.IPPoolSpec{
+					IPVersion: &ipVersion4,
+					Subnet:    "172.18.40.0/24",
+				},
+			}
+			v6PoolT = &spiderpoolv2beta1.SpiderIPPool{
+				ObjectMeta: metav1.ObjectMeta{Name: "v6-pool"},
+				Spec: spiderpoolv2beta1.IPPoolSpec{
+					IPVersion: &ipVersion6,
+					Subnet:    "abcd:1234::/120",
+				},
+			}
+			v4SubnetT = &spiderpoolv2beta1.SpiderSubnet{
+				ObjectMeta: metav1.ObjectMeta{Name: "v4-subnet"},
+				Spec: spiderpoolv2beta1.SubnetSpec{
+					IPVersion: &ipVersion4,
+					Subnet:    "172.18.40.0/24",
+				},
+			}
+			namespaceT = &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "default",
+					Labels: map[string]string{"foo": "bar"},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			podWebhook = &podmanager.PodWebhook{
+				Client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(v4PoolT, v6PoolT, v4SubnetT, namespaceT).
+					WithIndex(&spiderpoolv2beta1.SpiderIPPool{}, "spec.poolGroup.name", func(raw client.Object) []string {
+						ipPool := raw.(*spiderpoolv2beta1.SpiderIPPool)
+						if ipPool.Spec.PoolGroup == nil {
+							return nil
+						}
+						return []string{ipPool.Spec.PoolGroup.Name}
+					}).
+					Build(),
+				EnableIPv4:         true,
+				EnableIPv6:         false,
+				EnableSpiderSubnet: true,
+			}
+		})
+
+		It("passes the existing IPPools", func() {
+			podT.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["v4-pool"]}`
+
+			err := podWebhook.ValidateCreate(ctx, podT)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("refuses the malformed IPPools annotation", func() {
+			podT.Annotations[constant.AnnoPodIPPools] = `[{"interface":"eth0","ipv4":["v4-pool"]},{"interface":"eth0"}]`
+
+			err := podWebhook.ValidateCreate(ctx, podT)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		})
+
+		It("refuses the IPPool which does not exist", func() {
+			podT.Annotations[constant.AnnoPodIPPools] = `[{"interface":"eth0","ipv4":["v4-pool","missing-pool"]}]`
+
+			err := podWebhook.ValidateCreate(ctx, podT)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("IPPool missing-pool does not exist"))
+		})
+
+		It("refuses the IPPool of another IP version", func() {
+			podT.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["v6-pool"]}`
+
+			err := podWebhook.ValidateCreate(ctx, podT)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("the version of the IPPool v6-pool is IPv6"))
+		})
+
+		It("refuses the IPPool which does not allow the Namespace", func() {
+			v4PoolT.Spec.NamespaceAffinity = &metav1.LabelSelector{
+				MatchLabels: map[string]string{"foo": "baz"},
+			}
+			err := podWebhook.Client.Update(ctx, v4PoolT)
+			Expect(err).NotTo(HaveOccurred())
+			podT.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["v4-pool"]}`
+
+			err = podWebhook.ValidateCreate(ctx, podT)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("unmatched Namespace affinity of IPPool v4-pool"))
+		})
+
+		It("passes if one of the IPPools allows the Namespace", func() {
+			v6PoolT.Spec.IPVersion = v4PoolT.Spec.IPVersion
+			v6PoolT.Spec.NamespaceAffinity = &metav1.LabelSelector{
+				MatchLabels: map[string]string{"foo": "bar"},
+			}
+			v4PoolT.Spec.NamespaceAffinity = &metav1.LabelSelector{
+				MatchLabels: map[string]string{"foo": "baz"},
+			}
+			err := podWebhook.Client.Update(ctx, v6PoolT)
+			Expect(err).NotTo(HaveOccurred())
+			err = podWebhook.Client.Update(ctx, v4PoolT)
+			Expect(err).NotTo(HaveOccurred())
+			podT.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["v4-pool","v6-pool"]}`
+
+			err = podWebhook.ValidateCreate(ctx, podT)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("passes the existing Subnet", func() {
+			podT.Annotations[constant.AnnoSpiderSubnet] = `{"ipv4":["v4-subnet"]}`
+			podT.Annotations[constant.AnnoSpiderSubnetPoolIPNumber] = "+1"
+
+			err := podWebhook.ValidateCreate(ctx, podT)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("refuses the Subnet which does not exist", func() {
+			podT.Annotations[constant.AnnoSpiderSubnet] = `{"ipv4":["missing-subnet"]}`
+
+			err := podWebhook.ValidateCreate(ctx, podT)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("Subnet missing-subnet does not exist"))
+		})
+
+		It("refuses the Subnet annotation without the IPv4 Subnet", func() {
+			podT.Annotations[constant.AnnoSpiderSubnets] = `[{"interface":"eth0","ipv6":["v6-subnet"]}]`
+
+			err := podWebhook.ValidateCreate(ctx, podT)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("no IPv4 Subnet specified"))
+		})
+
+		It("refuses invalid IP number of the auto-created IPPools", func() {
+			podT.Annotations[constant.AnnoSpiderSubnet] = `{"ipv4":["v4-subnet"]}`
+			podT.Annotations[constant.AnnoSpiderSubnetPoolIPNumber] = "-1"
+
+			err := podWebhook.ValidateCreate(ctx, podT)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		})
+
+		It("refuses invalid IP number without the Subnet annotation", func() {
+			podWebhook.EnableSpiderSubnet = false
+			podT.Annotations[constant.AnnoSpiderSubnetPoolIPNumber] = "abc"
+
+			err := podWebhook.ValidateCreate(ctx, podT)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(constant.AnnoSpiderSubnetPoolIPNumber))
+		})
+
+		Context("IPPool group", func() {
+			BeforeEach(func() {
+				v4PoolT.Spec.PoolGroup = &spiderpoolv2beta1.PoolGroup{
+					Name:          "group",
+					TopologyKey:   "topology.kubernetes.io/zone",
+					TopologyValue: "zone-a",
+				}
+			})
+
+			It("passes the existing IPPool group", func() {
+				podT.Annotations[constant.AnnoPodIPPoolGroup] = "group"
+
+				err := podWebhook.ValidateCreate(ctx, podT)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("refuses the empty IPPool group", func() {
+				podT.Annotations[constant.AnnoPodIPPoolGroup] = " "
+
+				err := podWebhook.ValidateCreate(ctx, podT)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				Expect(err.Error()).To(ContainSubstring("value requires the name of IPPool group"))
+			})
+
+			It("refuses the IPPool group which does not exist", func() {
+				podT.Annotations[constant.AnnoPodIPPoolGroup] = "missing-group"
+
+				err := podWebhook.ValidateCreate(ctx, podT)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				Expect(err.Error()).To(ContainSubstring("IPPool group missing-group does not exist"))
+			})
+
+			It("refuses the IPPool group without the IPPool of the enabled IP version", func() {
+				podWebhook.EnableIPv6 = true
+				podT.Annotations[constant.AnnoPodIPPoolGroup] = "group"
+
+				err := podWebhook.ValidateCreate(ctx, podT)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				Expect(err.Error()).To(ContainSubstring("no IPv6 IPPool in IPPool group group"))
+			})
+
+			It("refuses the IPPool group which does not allow the Namespace", func() {
+				v4PoolT.Spec.NamespaceAffinity = &metav1.LabelSelector{
+					MatchLabels: map[string]string{"foo": "baz"},
+				}
+				err := podWebhook.Client.Update(ctx, v4PoolT)
+				Expect(err).NotTo(HaveOccurred())
+				podT.Annotations[constant.AnnoPodIPPoolGroup] = "group"
+
+				err = podWebhook.ValidateCreate(ctx, podT)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue())
+				Expect(err.Error()).To(ContainSubstring("unmatched Namespace affinity of IPPool v4-pool"))
+			})
+		})
+
+		It("refuses the changed annotation of the IPPool which does not exist", func() {
+			podT.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["v4-pool"]}`
+			newPodT := podT.DeepCopy()
+			newPodT.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["missing-pool"]}`
+
+			err := podWebhook.ValidateUpdate(ctx, podT, newPodT)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		})
+	})
+})
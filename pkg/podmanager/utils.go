@@ -4,7 +4,18 @@
 package podmanager
 
 import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/sys/unix"
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolip "github.com/spidernet-io/spiderpool/pkg/ip"
+	"github.com/spidernet-io/spiderpool/pkg/networking/networking"
+	"github.com/spidernet-io/spiderpool/pkg/types"
 )
 
 func IsPodAlive(pod *corev1.Pod) bool {
@@ -30,3 +41,202 @@ func IsPodAlive(pod *corev1.Pod) bool {
 
 	return true
 }
+
+// ParsePodCoordinatorAnnotation parses the value of Pod annotation
+// "ipam.spidernet.io/coordinator". Only the fields of
+// types.AnnoPodCoordinatorValue are allowed to be overridden.
+func ParsePodCoordinatorAnnotation(value string) (*types.AnnoPodCoordinatorValue, error) {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+
+	var anno types.AnnoPodCoordinatorValue
+	if err := decoder.Decode(&anno); err != nil {
+		return nil, fmt.Errorf("failed to parse coordinator config: %v", err)
+	}
+
+	if anno.PodDefaultRouteNIC != nil && *anno.PodDefaultRouteNIC == "" {
+		return nil, fmt.Errorf("podDefaultRouteNIC must not be empty")
+	}
+
+	for _, cidr := range anno.ExtraCIDR {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, fmt.Errorf("invalid extraCIDR %s: %v", cidr, err)
+		}
+	}
+
+	return &anno, nil
+}
+
+// ParsePodIPPoolsAnnotation parses the value of Pod annotation
+// "ipam.spidernet.io/ippools".
+func ParsePodIPPoolsAnnotation(value string) (types.AnnoPodIPPoolsValue, error) {
+	var ipPools types.AnnoPodIPPoolsValue
+	if err := json.Unmarshal([]byte(value), &ipPools); err != nil {
+		return nil, err
+	}
+	if len(ipPools) == 0 {
+		return nil, fmt.Errorf("value requires at least one item")
+	}
+
+	nics := map[string]struct{}{}
+	for _, item := range ipPools {
+		if item.NIC == "" {
+			return nil, fmt.Errorf("interface must be specified")
+		}
+		if _, ok := nics[item.NIC]; ok {
+			return nil, fmt.Errorf("duplicate interface %s", item.NIC)
+		}
+		nics[item.NIC] = struct{}{}
+	}
+
+	return ipPools, nil
+}
+
+// ParsePodIPPoolAnnotation parses the value of Pod annotation
+// "ipam.spidernet.io/ippool".
+func ParsePodIPPoolAnnotation(value string) (*types.AnnoPodIPPoolValue, error) {
+	var ipPool types.AnnoPodIPPoolValue
+	if err := json.Unmarshal([]byte(value), &ipPool); err != nil {
+		return nil, err
+	}
+
+	return &ipPool, nil
+}
+
+// ParsePodIPPoolGroupAnnotation parses the value of Pod annotation
+// "ipam.spidernet.io/ippool-group", which is the name of an IPPool group.
+func ParsePodIPPoolGroupAnnotation(value string) (string, error) {
+	group := strings.TrimSpace(value)
+	if group == "" {
+		return "", fmt.Errorf("value requires the name of IPPool group")
+	}
+
+	return group, nil
+}
+
+// ParsePodRoutesAnnotation parses the value of Pod annotation
+// "ipam.spidernet.io/routes".
+func ParsePodRoutesAnnotation(value string) (types.AnnoPodRoutesValue, error) {
+	var routes types.AnnoPodRoutesValue
+	if err := json.Unmarshal([]byte(value), &routes); err != nil {
+		return nil, err
+	}
+
+	for _, route := range routes {
+		if err := validateAnnoRoute(route); err != nil {
+			return nil, err
+		}
+	}
+
+	return routes, nil
+}
+
+// ParsePodBandwidthAnnotation parses the value of Pod annotation
+// "ipam.spidernet.io/bandwidth".
+func ParsePodBandwidthAnnotation(value string) (types.AnnoPodBandwidthValue, error) {
+	var bandwidth types.AnnoPodBandwidthValue
+	if err := json.Unmarshal([]byte(value), &bandwidth); err != nil {
+		return nil, err
+	}
+
+	nics := map[string]struct{}{}
+	for _, item := range bandwidth {
+		if item.NIC == "" {
+			return nil, fmt.Errorf("the interface of bandwidth must be specified")
+		}
+		if _, ok := nics[item.NIC]; ok {
+			return nil, fmt.Errorf("duplicate bandwidth of interface %s", item.NIC)
+		}
+		nics[item.NIC] = struct{}{}
+
+		if item.IngressRate != "" {
+			if _, err := networking.ParseBandwidthRate(item.IngressRate); err != nil {
+				return nil, fmt.Errorf("invalid ingressRate of interface %s: %v", item.NIC, err)
+			}
+		}
+		if item.EgressRate != "" {
+			if _, err := networking.ParseBandwidthRate(item.EgressRate); err != nil {
+				return nil, fmt.Errorf("invalid egressRate of interface %s: %v", item.NIC, err)
+			}
+		}
+		if item.DSCP != nil && (*item.DSCP < 0 || *item.DSCP > 63) {
+			return nil, fmt.Errorf("invalid dscp %d of interface %s, it must be in the range of 0 to 63", *item.DSCP, item.NIC)
+		}
+	}
+
+	return bandwidth, nil
+}
+
+// ParsePodStaticIPsAnnotation parses the value of Pod annotation
+// "ipam.spidernet.io/static-ips".
+func ParsePodStaticIPsAnnotation(value string) (types.AnnoPodStaticIPsValue, error) {
+	var staticIPs types.AnnoPodStaticIPsValue
+	if err := json.Unmarshal([]byte(value), &staticIPs); err != nil {
+		return nil, err
+	}
+
+	nics := map[string]struct{}{}
+	for _, item := range staticIPs {
+		if item.NIC == "" {
+			return nil, fmt.Errorf("the interface of static IPs must be specified")
+		}
+		if _, ok := nics[item.NIC]; ok {
+			return nil, fmt.Errorf("duplicate static IPs of interface %s", item.NIC)
+		}
+		nics[item.NIC] = struct{}{}
+
+		if item.IPv4 == "" && item.IPv6 == "" {
+			return nil, fmt.Errorf("no static IP of interface %s", item.NIC)
+		}
+		if item.IPv4 != "" {
+			if err := spiderpoolip.IsIP(constant.IPv4, item.IPv4); err != nil {
+				return nil, fmt.Errorf("invalid static IP of interface %s: %w", item.NIC, err)
+			}
+		}
+		if item.IPv6 != "" {
+			if err := spiderpoolip.IsIP(constant.IPv6, item.IPv6); err != nil {
+				return nil, fmt.Errorf("invalid static IP of interface %s: %w", item.NIC, err)
+			}
+		}
+	}
+
+	return staticIPs, nil
+}
+
+func validateAnnoRoute(route types.AnnoRouteItem) error {
+	switch route.Scope {
+	case "", constant.RouteScopeUniverse:
+		if err := spiderpoolip.IsRouteWithoutIPVersion(route.Dst, route.Gw); err != nil {
+			return err
+		}
+	case constant.RouteScopeLink, constant.RouteScopeHost:
+		if !spiderpoolip.IsIPv4CIDR(route.Dst) && !spiderpoolip.IsIPv6CIDR(route.Dst) {
+			return fmt.Errorf("%w 'dst: %s'", spiderpoolip.ErrInvalidRouteFormat, route.Dst)
+		}
+		if route.Gw != "" {
+			return fmt.Errorf("the route to %s with scope %s must not have gateway", route.Dst, route.Scope)
+		}
+	default:
+		return fmt.Errorf("unsupported scope %s of the route to %s, available options: [%s, %s, %s]",
+			route.Scope, route.Dst, constant.RouteScopeUniverse, constant.RouteScopeLink, constant.RouteScopeHost)
+	}
+
+	if !route.IsAdvanced() {
+		return nil
+	}
+
+	if route.NIC == "" {
+		return fmt.Errorf("the route to %s with metric, table, scope or policy must specify the interface", route.Dst)
+	}
+	if route.Metric != nil && *route.Metric < 0 {
+		return fmt.Errorf("the metric of the route to %s must not be negative", route.Dst)
+	}
+	if route.Table != nil && (*route.Table <= 0 || *route.Table == unix.RT_TABLE_LOCAL || *route.Table == unix.RT_TABLE_DEFAULT) {
+		return fmt.Errorf("invalid table %d of the route to %s", *route.Table, route.Dst)
+	}
+	if route.Policy && route.Table != nil && *route.Table == unix.RT_TABLE_MAIN {
+		return fmt.Errorf("the policy route to %s must not be installed in the main table", route.Dst)
+	}
+
+	return nil
+}
@@ -75,4 +75,193 @@ var _ = Describe("PodManager utils", Label("pod_manager_utils_test"), func() {
 			Expect(isAlive).To(BeTrue())
 		})
 	})
+
+	Describe("Test ParsePodCoordinatorAnnotation", func() {
+		It("parses the whitelisted fields", func() {
+			anno, err := podmanager.ParsePodCoordinatorAnnotation(`{"detectGateway":false,"detectIPConflict":true,"podDefaultRouteNIC":"net1","extraCIDR":["10.6.0.0/16"]}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*anno.DetectGateway).To(BeFalse())
+			Expect(*anno.DetectIPConflict).To(BeTrue())
+			Expect(*anno.PodDefaultRouteNIC).To(Equal("net1"))
+			Expect(anno.ExtraCIDR).To(Equal([]string{"10.6.0.0/16"}))
+		})
+
+		It("inputs invalid JSON", func() {
+			anno, err := podmanager.ParsePodCoordinatorAnnotation("invalid")
+			Expect(err).To(HaveOccurred())
+			Expect(anno).To(BeNil())
+		})
+
+		It("inputs the field out of the whitelist", func() {
+			anno, err := podmanager.ParsePodCoordinatorAnnotation(`{"hostRuleTable":100}`)
+			Expect(err).To(HaveOccurred())
+			Expect(anno).To(BeNil())
+		})
+
+		It("inputs empty podDefaultRouteNIC", func() {
+			anno, err := podmanager.ParsePodCoordinatorAnnotation(`{"podDefaultRouteNIC":""}`)
+			Expect(err).To(HaveOccurred())
+			Expect(anno).To(BeNil())
+		})
+
+		It("inputs invalid extraCIDR", func() {
+			anno, err := podmanager.ParsePodCoordinatorAnnotation(`{"extraCIDR":["invalid"]}`)
+			Expect(err).To(HaveOccurred())
+			Expect(anno).To(BeNil())
+		})
+	})
+
+	Describe("Test ParsePodRoutesAnnotation", func() {
+		It("parses the routes assigned by gateway", func() {
+			routes, err := podmanager.ParsePodRoutesAnnotation(`[{"dst":"10.0.0.0/16","gw":"172.18.40.1"}]`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(HaveLen(1))
+			Expect(routes[0].IsAdvanced()).To(BeFalse())
+		})
+
+		It("parses the advanced routes", func() {
+			routes, err := podmanager.ParsePodRoutesAnnotation(`[
+				{"dst":"10.0.0.0/8","gw":"172.18.40.1","interface":"net1","metric":100},
+				{"dst":"172.30.0.0/16","interface":"net2","scope":"link","table":200,"policy":true}
+			]`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(HaveLen(2))
+			Expect(routes[0].IsAdvanced()).To(BeTrue())
+			Expect(*routes[0].Metric).To(Equal(100))
+			Expect(routes[1].IsAdvanced()).To(BeTrue())
+			Expect(routes[1].Policy).To(BeTrue())
+		})
+
+		It("inputs invalid JSON", func() {
+			routes, err := podmanager.ParsePodRoutesAnnotation("invalid")
+			Expect(err).To(HaveOccurred())
+			Expect(routes).To(BeNil())
+		})
+
+		It("inputs the route without gateway in universe scope", func() {
+			routes, err := podmanager.ParsePodRoutesAnnotation(`[{"dst":"10.0.0.0/16"}]`)
+			Expect(err).To(HaveOccurred())
+			Expect(routes).To(BeNil())
+		})
+
+		It("inputs the link scope route with gateway", func() {
+			routes, err := podmanager.ParsePodRoutesAnnotation(`[{"dst":"10.0.0.0/16","gw":"172.18.40.1","interface":"net1","scope":"link"}]`)
+			Expect(err).To(HaveOccurred())
+			Expect(routes).To(BeNil())
+		})
+
+		It("inputs unsupported scope", func() {
+			routes, err := podmanager.ParsePodRoutesAnnotation(`[{"dst":"10.0.0.0/16","interface":"net1","scope":"site"}]`)
+			Expect(err).To(HaveOccurred())
+			Expect(routes).To(BeNil())
+		})
+
+		It("inputs the advanced route without interface", func() {
+			routes, err := podmanager.ParsePodRoutesAnnotation(`[{"dst":"10.0.0.0/16","gw":"172.18.40.1","metric":10}]`)
+			Expect(err).To(HaveOccurred())
+			Expect(routes).To(BeNil())
+		})
+
+		It("inputs invalid table", func() {
+			routes, err := podmanager.ParsePodRoutesAnnotation(`[{"dst":"10.0.0.0/16","gw":"172.18.40.1","interface":"net1","table":255}]`)
+			Expect(err).To(HaveOccurred())
+			Expect(routes).To(BeNil())
+		})
+
+		It("inputs the policy route in main table", func() {
+			routes, err := podmanager.ParsePodRoutesAnnotation(`[{"dst":"10.0.0.0/16","gw":"172.18.40.1","interface":"net1","table":254,"policy":true}]`)
+			Expect(err).To(HaveOccurred())
+			Expect(routes).To(BeNil())
+		})
+	})
+
+	Describe("Test ParsePodBandwidthAnnotation", func() {
+		It("parses the bandwidth of interfaces", func() {
+			bandwidth, err := podmanager.ParsePodBandwidthAnnotation(`[
+				{"interface":"net1","ingressRate":"100M","egressRate":"50M","dscp":46},
+				{"interface":"net2","egressRate":"1G"}
+			]`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bandwidth).To(HaveLen(2))
+			Expect(bandwidth[0].IngressRate).To(Equal("100M"))
+			Expect(*bandwidth[0].DSCP).To(Equal(46))
+			Expect(bandwidth[1].DSCP).To(BeNil())
+		})
+
+		It("inputs invalid JSON", func() {
+			bandwidth, err := podmanager.ParsePodBandwidthAnnotation("invalid")
+			Expect(err).To(HaveOccurred())
+			Expect(bandwidth).To(BeNil())
+		})
+
+		It("inputs the bandwidth without interface", func() {
+			bandwidth, err := podmanager.ParsePodBandwidthAnnotation(`[{"egressRate":"100M"}]`)
+			Expect(err).To(HaveOccurred())
+			Expect(bandwidth).To(BeNil())
+		})
+
+		It("inputs duplicate interfaces", func() {
+			bandwidth, err := podmanager.ParsePodBandwidthAnnotation(`[{"interface":"net1","egressRate":"100M"},{"interface":"net1","dscp":10}]`)
+			Expect(err).To(HaveOccurred())
+			Expect(bandwidth).To(BeNil())
+		})
+
+		It("inputs the rate out of range", func() {
+			bandwidth, err := podmanager.ParsePodBandwidthAnnotation(`[{"interface":"net1","ingressRate":"100"}]`)
+			Expect(err).To(HaveOccurred())
+			Expect(bandwidth).To(BeNil())
+		})
+
+		It("inputs invalid dscp", func() {
+			bandwidth, err := podmanager.ParsePodBandwidthAnnotation(`[{"interface":"net1","dscp":64}]`)
+			Expect(err).To(HaveOccurred())
+			Expect(bandwidth).To(BeNil())
+		})
+	})
+
+	Describe("Test ParsePodStaticIPsAnnotation", func() {
+		It("parses the static IPs of multiple interfaces", func() {
+			staticIPs, err := podmanager.ParsePodStaticIPsAnnotation(`[
+				{"interface":"eth0","ipv4":"172.18.40.10","ipv6":"fd00:172:18::10"},
+				{"interface":"net1","ipv4":"10.6.0.10"}
+			]`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(staticIPs).To(HaveLen(2))
+			Expect(staticIPs[0].IPv6).To(Equal("fd00:172:18::10"))
+			Expect(staticIPs[1].IPv6).To(BeEmpty())
+		})
+
+		It("inputs invalid JSON", func() {
+			staticIPs, err := podmanager.ParsePodStaticIPsAnnotation("invalid")
+			Expect(err).To(HaveOccurred())
+			Expect(staticIPs).To(BeNil())
+		})
+
+		It("inputs the static IPs without interface", func() {
+			staticIPs, err := podmanager.ParsePodStaticIPsAnnotation(`[{"ipv4":"172.18.40.10"}]`)
+			Expect(err).To(HaveOccurred())
+			Expect(staticIPs).To(BeNil())
+		})
+
+		It("inputs duplicate interfaces", func() {
+			staticIPs, err := podmanager.ParsePodStaticIPsAnnotation(`[
+				{"interface":"eth0","ipv4":"172.18.40.10"},
+				{"interface":"eth0","ipv6":"fd00:172:18::10"}
+			]`)
+			Expect(err).To(HaveOccurred())
+			Expect(staticIPs).To(BeNil())
+		})
+
+		It("inputs the interface without static IP", func() {
+			staticIPs, err := podmanager.ParsePodStaticIPsAnnotation(`[{"interface":"eth0"}]`)
+			Expect(err).To(HaveOccurred())
+			Expect(staticIPs).To(BeNil())
+		})
+
+		It("inputs the IPv6 address as static IPv4", func() {
+			staticIPs, err := podmanager.ParsePodStaticIPsAnnotation(`[{"interface":"eth0","ipv4":"fd00:172:18::10"}]`)
+			Expect(err).To(HaveOccurred())
+			Expect(staticIPs).To(BeNil())
+		})
+	})
 })
@@ -0,0 +1,152 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package podmanager
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+)
+
+// WorkloadWebhook validates the Spiderpool annotations of the Pod templates
+// of workloads, so that the invalid ones are rejected before any Pod of the
+// workload is created.
+type WorkloadWebhook struct {
+	Client client.Client
+
+	EnableIPv4         bool
+	EnableIPv6         bool
+	EnableSpiderSubnet bool
+}
+
+func (ww *WorkloadWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if WebhookLogger == nil {
+		WebhookLogger = logutils.Logger.Named("Pod-Webhook")
+	}
+
+	workloads := []client.Object{
+		&appsv1.Deployment{},
+		&appsv1.StatefulSet{},
+		&appsv1.DaemonSet{},
+		&appsv1.ReplicaSet{},
+		&batchv1.Job{},
+		&batchv1.CronJob{},
+	}
+	for _, workload := range workloads {
+		if err := ctrl.NewWebhookManagedBy(mgr).
+			For(workload).
+			WithValidator(ww).
+			Complete(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var _ webhook.CustomValidator = (*WorkloadWebhook)(nil)
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (ww *WorkloadWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return ww.validate(ctx, obj, "CREATE")
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (ww *WorkloadWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	_, oldTemplate, err := podTemplateOfWorkload(oldObj)
+	if err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+	_, newTemplate, err := podTemplateOfWorkload(newObj)
+	if err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+
+	// only validate the changed annotations, the unchanged ones have been
+	// validated or consumed by the existing Pods.
+	if reflect.DeepEqual(oldTemplate.Annotations, newTemplate.Annotations) {
+		return nil
+	}
+
+	return ww.validate(ctx, newObj, "UPDATE")
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (ww *WorkloadWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (ww *WorkloadWebhook) validate(ctx context.Context, obj runtime.Object, operation string) error {
+	gk, template, err := podTemplateOfWorkload(obj)
+	if err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+
+	workload := obj.(client.Object)
+	logger := WebhookLogger.Named("Validating").With(
+		zap.String("WorkloadKind", gk.Kind),
+		zap.String("WorkloadNamespace", workload.GetNamespace()),
+		zap.String("WorkloadName", workload.GetName()),
+		zap.String("Operation", operation),
+	)
+
+	pw := &PodWebhook{
+		Client:             ww.Client,
+		EnableIPv4:         ww.EnableIPv4,
+		EnableIPv6:         ww.EnableIPv6,
+		EnableSpiderSubnet: ww.EnableSpiderSubnet,
+	}
+
+	annoPath := templateField(gk.Kind).Child("metadata", "annotations")
+	errs := pw.validateAnnotations(ctx, annoPath, workload.GetNamespace(), template.Annotations)
+	if len(errs) != 0 {
+		logger.Sugar().Errorf("Failed to %s %s: %v", operation, gk.Kind, errs.ToAggregate().Error())
+		return apierrors.NewInvalid(gk, workload.GetName(), errs)
+	}
+
+	return nil
+}
+
+// podTemplateOfWorkload returns the Pod template of the workload.
+func podTemplateOfWorkload(obj runtime.Object) (schema.GroupKind, *metav1.ObjectMeta, error) {
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		return schema.GroupKind{Group: appsv1.GroupName, Kind: constant.KindDeployment}, &workload.Spec.Template.ObjectMeta, nil
+	case *appsv1.StatefulSet:
+		return schema.GroupKind{Group: appsv1.GroupName, Kind: constant.KindStatefulSet}, &workload.Spec.Template.ObjectMeta, nil
+	case *appsv1.DaemonSet:
+		return schema.GroupKind{Group: appsv1.GroupName, Kind: constant.KindDaemonSet}, &workload.Spec.Template.ObjectMeta, nil
+	case *appsv1.ReplicaSet:
+		return schema.GroupKind{Group: appsv1.GroupName, Kind: constant.KindReplicaSet}, &workload.Spec.Template.ObjectMeta, nil
+	case *batchv1.Job:
+		return schema.GroupKind{Group: batchv1.GroupName, Kind: constant.KindJob}, &workload.Spec.Template.ObjectMeta, nil
+	case *batchv1.CronJob:
+		return schema.GroupKind{Group: batchv1.GroupName, Kind: constant.KindCronJob}, &workload.Spec.JobTemplate.Spec.Template.ObjectMeta, nil
+	default:
+		return schema.GroupKind{}, nil, fmt.Errorf("unsupported workload %T", obj)
+	}
+}
+
+func templateField(kind string) *field.Path {
+	if kind == constant.KindCronJob {
+		return field.NewPath("spec", "jobTemplate", "spec", "template")
+	}
+
+	return field.NewPath("spec", "template")
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package podmanager_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+	"github.com/spidernet-io/spiderpool/pkg/podmanager"
+)
+
+var _ = Describe("WorkloadWebhook", Label("workload_webhook_test"), func() {
+	var ctx context.Context
+	var workloadWebhook *podmanager.WorkloadWebhook
+	var deployT *appsv1.Deployment
+
+	BeforeEach(func() {
+		podmanager.WebhookLogger = logutils.Logger.Named("Pod-Webhook")
+		workloadWebhook = &podmanager.WorkloadWebhook{
+			Client:     fake.NewClientBuilder().WithScheme(scheme).Build(),
+			EnableIPv4: true,
+		}
+
+		ctx = context.TODO()
+		deployT = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "deploy",
+				Namespace: "default",
+			},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{},
+					},
+				},
+			},
+		}
+	})
+
+	It("passes the workload without annotations", func() {
+		err := workloadWebhook.ValidateCreate(ctx, deployT)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("refuses the IPPool which does not exist in the Pod template", func() {
+		deployT.Spec.Template.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["missing-pool"]}`
+
+		err := workloadWebhook.ValidateCreate(ctx, deployT)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.template.metadata.annotations"))
+	})
+
+	It("refuses invalid annotations in the Pod template of CronJob", func() {
+		cronJob := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cronjob",
+				Namespace: "default",
+			},
+			Spec: batchv1.CronJobSpec{
+				JobTemplate: batchv1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							ObjectMeta: metav1.ObjectMeta{
+								Annotations: map[string]string{
+									constant.AnnoPodRoutes: `invalid`,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		err := workloadWebhook.ValidateCreate(ctx, cronJob)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.jobTemplate.spec.template.metadata.annotations"))
+	})
+
+	It("ignores the unchanged annotations", func() {
+		deployT.Spec.Template.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["missing-pool"]}`
+		newDeployT := deployT.DeepCopy()
+		replicas := int32(2)
+		newDeployT.Spec.Replicas = &replicas
+
+		err := workloadWebhook.ValidateUpdate(ctx, deployT, newDeployT)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("does nothing on deletion", func() {
+		err := workloadWebhook.ValidateDelete(ctx, deployT)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
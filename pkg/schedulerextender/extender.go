@@ -0,0 +1,199 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package schedulerextender
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/ipam"
+	"github.com/spidernet-io/spiderpool/pkg/logutils"
+)
+
+const (
+	FilterPath     = "/filter"
+	PrioritizePath = "/prioritize"
+)
+
+var logger *zap.Logger
+
+// Extender is a kube-scheduler extender, which filters out the Nodes where
+// the Pod cannot get IP addresses from its IPPool candidates, and prefers
+// the Nodes where the IPPool candidates have more free IP addresses.
+type Extender struct {
+	checker ipam.NodeChecker
+}
+
+func NewExtender(checker ipam.NodeChecker) (*Extender, error) {
+	if checker == nil {
+		return nil, fmt.Errorf("node checker %w", constant.ErrMissingRequiredParam)
+	}
+
+	logger = logutils.Logger.Named("Scheduler-Extender")
+
+	return &Extender{checker: checker}, nil
+}
+
+// Filter filters out the Nodes where the Pod cannot get IP addresses. The
+// Nodes whose IPPool candidates are only exhausted may become feasible after
+// preemption, the others are unresolvable.
+func (e *Extender) Filter(ctx context.Context, args *ExtenderArgs) *ExtenderFilterResult {
+	if args.Pod == nil {
+		return &ExtenderFilterResult{Error: "no Pod to schedule"}
+	}
+
+	log := logger.With(
+		zap.String("PodNamespace", args.Pod.Namespace),
+		zap.String("PodName", args.Pod.Name),
+	)
+	ctx = logutils.IntoContext(ctx, ipamLogger(log))
+
+	result := &ExtenderFilterResult{
+		FailedNodes:                map[string]string{},
+		FailedAndUnresolvableNodes: map[string]string{},
+	}
+	feasible := func(nodeName string) bool {
+		if _, err := e.checker.CheckNode(ctx, args.Pod, nodeName); err != nil {
+			log.Sugar().Debugf("Node %s is filtered out: %v", nodeName, err)
+			if errors.Is(err, constant.ErrIPUsedOut) {
+				result.FailedNodes[nodeName] = err.Error()
+			} else {
+				result.FailedAndUnresolvableNodes[nodeName] = err.Error()
+			}
+			return false
+		}
+		return true
+	}
+
+	if args.NodeNames != nil {
+		nodeNames := []string{}
+		for _, nodeName := range *args.NodeNames {
+			if feasible(nodeName) {
+				nodeNames = append(nodeNames, nodeName)
+			}
+		}
+		result.NodeNames = &nodeNames
+	} else if args.Nodes != nil {
+		nodes := &corev1.NodeList{}
+		for _, node := range args.Nodes.Items {
+			if feasible(node.Name) {
+				nodes.Items = append(nodes.Items, node)
+			}
+		}
+		result.Nodes = nodes
+	}
+
+	return result
+}
+
+// Prioritize scores the Nodes by the headroom of the IPPool candidates of
+// the Pod, the Node with the most free IP addresses gets the max score. All
+// Nodes get 0 if the IP addresses of the Pod do not depend on the Node.
+func (e *Extender) Prioritize(ctx context.Context, args *ExtenderArgs) (HostPriorityList, error) {
+	if args.Pod == nil {
+		return nil, fmt.Errorf("no Pod to schedule")
+	}
+
+	log := logger.With(
+		zap.String("PodNamespace", args.Pod.Namespace),
+		zap.String("PodName", args.Pod.Name),
+	)
+	ctx = logutils.IntoContext(ctx, ipamLogger(log))
+
+	var nodeNames []string
+	if args.NodeNames != nil {
+		nodeNames = *args.NodeNames
+	} else if args.Nodes != nil {
+		for _, node := range args.Nodes.Items {
+			nodeNames = append(nodeNames, node.Name)
+		}
+	}
+
+	var maxHeadroom int64
+	headrooms := make([]int64, len(nodeNames))
+	for j, nodeName := range nodeNames {
+		headroom, err := e.checker.CheckNode(ctx, args.Pod, nodeName)
+		if err != nil {
+			log.Sugar().Debugf("Node %s is not feasible: %v", nodeName, err)
+			headroom = 0
+		}
+		headrooms[j] = headroom
+		if headroom > maxHeadroom {
+			maxHeadroom = headroom
+		}
+	}
+
+	priorities := make(HostPriorityList, 0, len(nodeNames))
+	for j, nodeName := range nodeNames {
+		var score int64
+		if maxHeadroom > 0 && headrooms[j] > 0 {
+			score = headrooms[j] * MaxExtenderPriority / maxHeadroom
+		}
+		priorities = append(priorities, HostPriority{Host: nodeName, Score: score})
+	}
+
+	return priorities, nil
+}
+
+// Handler returns the HTTP handler serving the extender API at FilterPath
+// and PrioritizePath.
+func (e *Extender) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(FilterPath, func(w http.ResponseWriter, r *http.Request) {
+		var args ExtenderArgs
+		if err := decodeArgs(r, &args); err != nil {
+			writeResponse(w, http.StatusBadRequest, &ExtenderFilterResult{Error: err.Error()})
+			return
+		}
+		writeResponse(w, http.StatusOK, e.Filter(r.Context(), &args))
+	})
+	mux.HandleFunc(PrioritizePath, func(w http.ResponseWriter, r *http.Request) {
+		var args ExtenderArgs
+		if err := decodeArgs(r, &args); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		priorities, err := e.Prioritize(r.Context(), &args)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeResponse(w, http.StatusOK, priorities)
+	})
+
+	return mux
+}
+
+func decodeArgs(r *http.Request, args *ExtenderArgs) error {
+	if r.Method != http.MethodPost {
+		return fmt.Errorf("method %s not allowed", r.Method)
+	}
+	if err := json.NewDecoder(r.Body).Decode(args); err != nil {
+		return fmt.Errorf("failed to decode extender args: %w", err)
+	}
+
+	return nil
+}
+
+func writeResponse(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Sugar().Errorf("Failed to write response: %v", err)
+	}
+}
+
+// ipamLogger quiets the logs of the pool selection, which is done for every
+// Node in every scheduling cycle.
+func ipamLogger(log *zap.Logger) *zap.Logger {
+	return log.WithOptions(zap.IncreaseLevel(zapcore.ErrorLevel))
+}
@@ -0,0 +1,265 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package schedulerextender_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/ipam"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/schedulerextender"
+)
+
+var _ = Describe("Extender", Label("extender_test"), func() {
+	var ctx context.Context
+	var config ipam.IPAMConfig
+	var objs []client.Object
+
+	var podT *corev1.Pod
+	var nodeNames []string
+
+	newIPPool := func(name, zone string, total, allocated int64) *spiderpoolv2beta1.SpiderIPPool {
+		ipPool := &spiderpoolv2beta1.SpiderIPPool{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: spiderpoolv2beta1.IPPoolSpec{
+				IPVersion: pointer.Int64(constant.IPv4),
+				Subnet:    "172.18.40.0/24",
+				Default:   pointer.Bool(false),
+				Disable:   pointer.Bool(false),
+			},
+			Status: spiderpoolv2beta1.IPPoolStatus{
+				TotalIPCount:     pointer.Int64(total),
+				AllocatedIPCount: pointer.Int64(allocated),
+			},
+		}
+		if zone != "" {
+			ipPool.Spec.NodeAffinity = &metav1.LabelSelector{
+				MatchLabels: map[string]string{corev1.LabelTopologyZone: zone},
+			}
+		}
+		return ipPool
+	}
+
+	newNode := func(name, zone string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{corev1.LabelTopologyZone: zone},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		config = ipam.IPAMConfig{EnableIPv4: true}
+		objs = []client.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			newNode("node-a", "a"),
+			newNode("node-b", "b"),
+			newIPPool("pool-a", "a", 10, 0),
+			newIPPool("pool-b", "b", 10, 5),
+		}
+		nodeNames = []string{"node-a", "node-b"}
+
+		podT = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pod",
+				Namespace:   "default",
+				Annotations: map[string]string{},
+			},
+		}
+	})
+
+	// newExtender is called in each spec after the objects are set up.
+	newExtender := func() *schedulerextender.Extender {
+		extender, err := schedulerextender.NewExtender(newFakeNodeChecker(config, objs...))
+		Expect(err).NotTo(HaveOccurred())
+		return extender
+	}
+
+	It("fails to create the extender without node checker", func() {
+		_, err := schedulerextender.NewExtender(nil)
+		Expect(err).To(MatchError(constant.ErrMissingRequiredParam))
+	})
+
+	Describe("Filter", func() {
+		It("filters out the Node unmatched with the Node affinity of the IPPool", func() {
+			podT.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["pool-a"]}`
+
+			extender := newExtender()
+			result := extender.Filter(ctx, &schedulerextender.ExtenderArgs{Pod: podT, NodeNames: &nodeNames})
+			Expect(result.Error).To(BeEmpty())
+			Expect(*result.NodeNames).To(Equal([]string{"node-a"}))
+			Expect(result.FailedAndUnresolvableNodes).To(HaveKey("node-b"))
+			Expect(result.FailedNodes).To(BeEmpty())
+		})
+
+		It("filters out the Node where the IPPool is exhausted", func() {
+			objs = append(objs, newIPPool("pool-c", "b", 10, 10))
+			podT.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["pool-a","pool-c"]}`
+
+			extender := newExtender()
+			result := extender.Filter(ctx, &schedulerextender.ExtenderArgs{Pod: podT, NodeNames: &nodeNames})
+			Expect(*result.NodeNames).To(Equal([]string{"node-a"}))
+			Expect(result.FailedNodes).To(HaveKey("node-b"))
+		})
+
+		It("passes the Nodes served by any of the cluster default IPPools", func() {
+			objs[3].(*spiderpoolv2beta1.SpiderIPPool).Spec.Default = pointer.Bool(true)
+			objs[4].(*spiderpoolv2beta1.SpiderIPPool).Spec.Default = pointer.Bool(true)
+
+			extender := newExtender()
+			result := extender.Filter(ctx, &schedulerextender.ExtenderArgs{Pod: podT, NodeNames: &nodeNames})
+			Expect(*result.NodeNames).To(Equal(nodeNames))
+		})
+
+		It("fails all Nodes if the IPPool does not exist", func() {
+			podT.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["missing-pool"]}`
+
+			extender := newExtender()
+			result := extender.Filter(ctx, &schedulerextender.ExtenderArgs{Pod: podT, NodeNames: &nodeNames})
+			Expect(*result.NodeNames).To(BeEmpty())
+			Expect(result.FailedAndUnresolvableNodes).To(HaveLen(2))
+		})
+
+		It("passes all Nodes for the Pod using Subnets", func() {
+			config.EnableSpiderSubnet = true
+			podT.Annotations[constant.AnnoSpiderSubnet] = `{"ipv4":["subnet"]}`
+
+			extender := newExtender()
+			result := extender.Filter(ctx, &schedulerextender.ExtenderArgs{Pod: podT, NodeNames: &nodeNames})
+			Expect(*result.NodeNames).To(Equal(nodeNames))
+		})
+
+		It("passes all Nodes for the Pod in the host network", func() {
+			podT.Spec.HostNetwork = true
+			podT.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["missing-pool"]}`
+
+			extender := newExtender()
+			result := extender.Filter(ctx, &schedulerextender.ExtenderArgs{Pod: podT, NodeNames: &nodeNames})
+			Expect(*result.NodeNames).To(Equal(nodeNames))
+			Expect(result.FailedAndUnresolvableNodes).To(BeEmpty())
+		})
+
+		It("passes all Nodes for the Pod without annotation if there is no cluster default IPPool", func() {
+			extender := newExtender()
+			result := extender.Filter(ctx, &schedulerextender.ExtenderArgs{Pod: podT, NodeNames: &nodeNames})
+			Expect(*result.NodeNames).To(Equal(nodeNames))
+			Expect(result.FailedAndUnresolvableNodes).To(BeEmpty())
+		})
+
+		It("checks every interface of the IPPools annotation", func() {
+			podT.Annotations[constant.AnnoPodIPPools] = `[{"interface":"eth0","ipv4":["pool-a"]},{"interface":"net1","ipv4":["pool-b"]}]`
+
+			extender := newExtender()
+			result := extender.Filter(ctx, &schedulerextender.ExtenderArgs{Pod: podT, NodeNames: &nodeNames})
+			Expect(*result.NodeNames).To(BeEmpty())
+			Expect(result.FailedAndUnresolvableNodes).To(HaveLen(2))
+		})
+
+		It("filters the Node list", func() {
+			podT.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["pool-b"]}`
+			nodes := &corev1.NodeList{Items: []corev1.Node{*newNode("node-a", "a"), *newNode("node-b", "b")}}
+
+			extender := newExtender()
+			result := extender.Filter(ctx, &schedulerextender.ExtenderArgs{Pod: podT, Nodes: nodes})
+			Expect(result.NodeNames).To(BeNil())
+			Expect(result.Nodes.Items).To(HaveLen(1))
+			Expect(result.Nodes.Items[0].Name).To(Equal("node-b"))
+		})
+	})
+
+	Describe("Prioritize", func() {
+		It("scores the Nodes by the free IP addresses of the IPPools", func() {
+			podT.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["pool-a","pool-b"]}`
+
+			extender := newExtender()
+			priorities, err := extender.Prioritize(ctx, &schedulerextender.ExtenderArgs{Pod: podT, NodeNames: &nodeNames})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(priorities).To(Equal(schedulerextender.HostPriorityList{
+				{Host: "node-a", Score: schedulerextender.MaxExtenderPriority},
+				{Host: "node-b", Score: schedulerextender.MaxExtenderPriority / 2},
+			}))
+		})
+
+		It("scores 0 for the Pod using Subnets", func() {
+			config.EnableSpiderSubnet = true
+			podT.Annotations[constant.AnnoSpiderSubnet] = `{"ipv4":["subnet"]}`
+
+			extender := newExtender()
+			priorities, err := extender.Prioritize(ctx, &schedulerextender.ExtenderArgs{Pod: podT, NodeNames: &nodeNames})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(priorities).To(Equal(schedulerextender.HostPriorityList{
+				{Host: "node-a", Score: 0},
+				{Host: "node-b", Score: 0},
+			}))
+		})
+
+		It("refuses the arguments without Pod", func() {
+			extender := newExtender()
+			_, err := extender.Prioritize(ctx, &schedulerextender.ExtenderArgs{NodeNames: &nodeNames})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Handler", func() {
+		It("serves the filter request", func() {
+			podT.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["pool-a"]}`
+
+			extender := newExtender()
+
+			body, err := json.Marshal(&schedulerextender.ExtenderArgs{Pod: podT, NodeNames: &nodeNames})
+			Expect(err).NotTo(HaveOccurred())
+
+			req := httptest.NewRequest(http.MethodPost, schedulerextender.FilterPath, bytes.NewReader(body))
+			rec := httptest.NewRecorder()
+			extender.Handler().ServeHTTP(rec, req)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var result schedulerextender.ExtenderFilterResult
+			err = json.Unmarshal(rec.Body.Bytes(), &result)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*result.NodeNames).To(Equal([]string{"node-a"}))
+		})
+
+		It("serves the prioritize request", func() {
+			podT.Annotations[constant.AnnoPodIPPool] = `{"ipv4":["pool-a","pool-b"]}`
+
+			extender := newExtender()
+
+			body, err := json.Marshal(&schedulerextender.ExtenderArgs{Pod: podT, NodeNames: &nodeNames})
+			Expect(err).NotTo(HaveOccurred())
+
+			req := httptest.NewRequest(http.MethodPost, schedulerextender.PrioritizePath, bytes.NewReader(body))
+			rec := httptest.NewRecorder()
+			extender.Handler().ServeHTTP(rec, req)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var priorities schedulerextender.HostPriorityList
+			err = json.Unmarshal(rec.Body.Bytes(), &priorities)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(priorities).To(HaveLen(2))
+		})
+
+		It("refuses the request which is not POST", func() {
+			extender := newExtender()
+			req := httptest.NewRequest(http.MethodGet, schedulerextender.FilterPath, nil)
+			rec := httptest.NewRecorder()
+			extender.Handler().ServeHTTP(rec, req)
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
@@ -0,0 +1,97 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package schedulerextender_test
+
+import (
+	"strconv"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/spidernet-io/spiderpool/pkg/ipam"
+	"github.com/spidernet-io/spiderpool/pkg/ippoolmanager"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/namespacemanager"
+	"github.com/spidernet-io/spiderpool/pkg/nodemanager"
+	"github.com/spidernet-io/spiderpool/pkg/podmanager"
+	"github.com/spidernet-io/spiderpool/pkg/reservedipmanager"
+	"github.com/spidernet-io/spiderpool/pkg/statefulsetmanager"
+	"github.com/spidernet-io/spiderpool/pkg/subnetmanager"
+	"github.com/spidernet-io/spiderpool/pkg/workloadendpointmanager"
+)
+
+var scheme *runtime.Scheme
+
+func TestSchedulerExtender(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SchedulerExtender Suite", Label("schedulerextender", "unitest"))
+}
+
+var _ = BeforeSuite(func() {
+	scheme = runtime.NewScheme()
+	err := corev1.AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+	err = spiderpoolv2beta1.AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+})
+
+// newFakeNodeChecker returns the NodeChecker on a fake client holding the
+// objects, with the same field indexes as spiderpool-controller.
+func newFakeNodeChecker(config ipam.IPAMConfig, objs ...client.Object) ipam.NodeChecker {
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithIndex(&spiderpoolv2beta1.SpiderIPPool{}, "spec.default", func(raw client.Object) []string {
+			ipPool := raw.(*spiderpoolv2beta1.SpiderIPPool)
+			return []string{strconv.FormatBool(*ipPool.Spec.Default)}
+		}).
+		WithIndex(&spiderpoolv2beta1.SpiderIPPool{}, "spec.poolGroup.name", func(raw client.Object) []string {
+			ipPool := raw.(*spiderpoolv2beta1.SpiderIPPool)
+			if ipPool.Spec.PoolGroup == nil {
+				return nil
+			}
+			return []string{ipPool.Spec.PoolGroup.Name}
+		}).
+		WithIndex(&spiderpoolv2beta1.SpiderIPClaim{}, ippoolmanager.IPClaimIPPoolIndex, func(raw client.Object) []string {
+			ipClaim := raw.(*spiderpoolv2beta1.SpiderIPClaim)
+			return []string{ipClaim.Spec.IPPool}
+		}).
+		Build()
+
+	rIPManager, err := reservedipmanager.NewReservedIPManager(fakeClient, fakeClient)
+	Expect(err).NotTo(HaveOccurred())
+	ipPoolManager, err := ippoolmanager.NewIPPoolManager(ippoolmanager.IPPoolManagerConfig{}, fakeClient, fakeClient, rIPManager)
+	Expect(err).NotTo(HaveOccurred())
+	endpointManager, err := workloadendpointmanager.NewWorkloadEndpointManager(fakeClient, fakeClient)
+	Expect(err).NotTo(HaveOccurred())
+	nodeManager, err := nodemanager.NewNodeManager(fakeClient, fakeClient)
+	Expect(err).NotTo(HaveOccurred())
+	nsManager, err := namespacemanager.NewNamespaceManager(fakeClient, fakeClient)
+	Expect(err).NotTo(HaveOccurred())
+	podManager, err := podmanager.NewPodManager(fakeClient, fakeClient)
+	Expect(err).NotTo(HaveOccurred())
+	stsManager, err := statefulsetmanager.NewStatefulSetManager(fakeClient, fakeClient)
+	Expect(err).NotTo(HaveOccurred())
+	subnetManager, err := subnetmanager.NewSubnetManager(fakeClient, fakeClient, rIPManager)
+	Expect(err).NotTo(HaveOccurred())
+
+	checker, err := ipam.NewNodeChecker(
+		config,
+		ipPoolManager,
+		endpointManager,
+		nodeManager,
+		nsManager,
+		podManager,
+		stsManager,
+		subnetManager,
+	)
+	Expect(err).NotTo(HaveOccurred())
+
+	return checker
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package schedulerextender
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The types below are the wire format of the kube-scheduler extender API,
+// see k8s.io/kube-scheduler/extender/v1.
+
+// MaxExtenderPriority is the max score of a Node returned by Prioritize.
+const MaxExtenderPriority int64 = 10
+
+// ExtenderArgs is the arguments of Filter and Prioritize. Nodes is set
+// unless the extender is configured with 'nodeCacheCapable: true', then
+// NodeNames is set instead.
+type ExtenderArgs struct {
+	Pod       *corev1.Pod      `json:"pod"`
+	Nodes     *corev1.NodeList `json:"nodes,omitempty"`
+	NodeNames *[]string        `json:"nodenames,omitempty"`
+}
+
+// ExtenderFilterResult is the result of Filter.
+type ExtenderFilterResult struct {
+	Nodes                      *corev1.NodeList  `json:"nodes,omitempty"`
+	NodeNames                  *[]string         `json:"nodenames,omitempty"`
+	FailedNodes                map[string]string `json:"failedNodes,omitempty"`
+	FailedAndUnresolvableNodes map[string]string `json:"failedAndUnresolvableNodes,omitempty"`
+	Error                      string            `json:"error,omitempty"`
+}
+
+// HostPriority is the score of a Node.
+type HostPriority struct {
+	Host  string `json:"host"`
+	Score int64  `json:"score"`
+}
+
+// HostPriorityList is the result of Prioritize.
+type HostPriorityList []HostPriority
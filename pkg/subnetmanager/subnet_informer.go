@@ -12,6 +12,7 @@ import (
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -29,6 +30,7 @@ import (
 	"github.com/spidernet-io/spiderpool/pkg/applicationcontroller/applicationinformers"
 	"github.com/spidernet-io/spiderpool/pkg/constant"
 	"github.com/spidernet-io/spiderpool/pkg/election"
+	"github.com/spidernet-io/spiderpool/pkg/event"
 	spiderpoolip "github.com/spidernet-io/spiderpool/pkg/ip"
 	"github.com/spidernet-io/spiderpool/pkg/ippoolmanager"
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
@@ -39,6 +41,7 @@ import (
 	"github.com/spidernet-io/spiderpool/pkg/logutils"
 	"github.com/spidernet-io/spiderpool/pkg/metric"
 	spiderpooltypes "github.com/spidernet-io/spiderpool/pkg/types"
+	"github.com/spidernet-io/spiderpool/pkg/utilization"
 	"github.com/spidernet-io/spiderpool/pkg/utils/convert"
 )
 
@@ -68,11 +71,16 @@ type SubnetController struct {
 	ResyncPeriod            time.Duration
 	SubnetControllerWorkers int
 	MaxWorkqueueLength      int
+	UtilizationWindow       time.Duration
+	UtilizationThresholds   []int
 
 	DynamicClient    dynamic.Interface
 	dynamicFactory   dynamicinformer.DynamicSharedInformerFactory
 	dynamicWorkqueue workqueue.RateLimitingInterface
 	recordedResource sync.Map
+
+	usageTracker     *utilization.Tracker
+	usageTrackerOnce sync.Once
 }
 
 type thirdControllerKey struct {
@@ -348,6 +356,9 @@ func (sc *SubnetController) processDynamicNextWorkItem(ctx context.Context) bool
 func (sc *SubnetController) syncHandler(ctx context.Context, subnetName string) (err error) {
 	subnet, err := sc.SubnetsLister.Get(subnetName)
 	if err != nil {
+		if apierrors.IsNotFound(err) {
+			sc.forgetUsage(subnetName)
+		}
 		return client.IgnoreNotFound(err)
 	}
 
@@ -371,6 +382,10 @@ func (sc *SubnetController) syncHandler(ctx context.Context, subnetName string)
 		}
 	}
 
+	if err := sc.recordUsage(subnetCopy); err != nil {
+		InformerLogger.Sugar().Warnf("failed to record SpiderSubnet '%s' IP utilization: %v", subnetName, err)
+	}
+
 	if subnetCopy.Status.TotalIPCount != nil {
 		attr := attribute.String(constant.KindSpiderSubnet, subnetName)
 		metric.SubnetTotalIPCounts.Add(ctx, *subnetCopy.Status.TotalIPCount, attr)
@@ -635,3 +650,55 @@ func (sc *SubnetController) monitorThirdController(ctx context.Context, appNames
 
 	return nil
 }
+
+func (sc *SubnetController) getUsageTracker() *utilization.Tracker {
+	sc.usageTrackerOnce.Do(func() {
+		sc.usageTracker = utilization.NewTracker(sc.UtilizationWindow, sc.UtilizationThresholds)
+	})
+
+	return sc.usageTracker
+}
+
+// recordUsage reports the IP utilization of the Subnet with metrics, and
+// emits a Warning event once a utilization threshold is crossed.
+func (sc *SubnetController) recordUsage(subnet *spiderpoolv2beta1.SpiderSubnet) error {
+	if subnet.Spec.IPVersion == nil || subnet.Status.TotalIPCount == nil {
+		return nil
+	}
+
+	allIPs, err := spiderpoolip.ParseIPRanges(*subnet.Spec.IPVersion, subnet.Spec.IPs)
+	if err != nil {
+		return err
+	}
+
+	total := *subnet.Status.TotalIPCount
+	usage := metric.IPUsage{
+		Reserved: int64(len(allIPs)) - total,
+	}
+	if subnet.Status.AllocatedIPCount != nil {
+		usage.Allocated = *subnet.Status.AllocatedIPCount
+	}
+	usage.Free = total - usage.Allocated
+	if usage.Free < 0 {
+		usage.Free = 0
+	}
+
+	tracker := sc.getUsageTracker()
+	forecast := tracker.Observe(subnet.Name, usage.Allocated, usage.Free)
+	usage.AllocationRate = forecast.AllocationRate
+	usage.ReleaseRate = forecast.ReleaseRate
+	usage.ExhaustionSeconds = forecast.Seconds()
+	metric.SubnetUsage.Record(subnet.Name, usage)
+
+	if threshold, crossed := tracker.Cross(subnet.Name, total, usage.Allocated); crossed {
+		event.EventRecorder.Event(subnet, corev1.EventTypeWarning, constant.EventReasonIPUtilizationHigh, utilization.Message(total, usage.Allocated, threshold, forecast))
+	}
+
+	return nil
+}
+
+// forgetUsage stops reporting the IP utilization of the deleted Subnet.
+func (sc *SubnetController) forgetUsage(subnetName string) {
+	sc.getUsageTracker().Forget(subnetName)
+	metric.SubnetUsage.Delete(subnetName)
+}
@@ -39,6 +39,7 @@ type subnetManager struct {
 	client     client.Client
 	apiReader  client.Reader
 	rIPManager reservedipmanager.ReservedIPManager
+	store      ippoolmanager.AllocationStore
 }
 
 func NewSubnetManager(client client.Client, apiReader client.Reader, rIPManager reservedipmanager.ReservedIPManager) (SubnetManager, error) {
@@ -52,10 +53,16 @@ func NewSubnetManager(client client.Client, apiReader client.Reader, rIPManager
 		return nil, fmt.Errorf("reserved-IP manager %w", constant.ErrMissingRequiredParam)
 	}
 
+	store, err := ippoolmanager.NewAllocationStore(client, apiReader)
+	if err != nil {
+		return nil, err
+	}
+
 	return &subnetManager{
 		client:     client,
 		apiReader:  apiReader,
 		rIPManager: rIPManager,
+		store:      store,
 	}, nil
 }
 
@@ -241,7 +248,7 @@ func (sm *subnetManager) preAllocateIPsFromSubnet(ctx context.Context, subnet *s
 		// In the last reconcile process, the SpiderSubnet allocated IPs successfully but the pool creation process failed.
 		if desiredIPNum == len(subnetPoolIPs) {
 			poolAllocatedIPs, err := func() ([]net.IP, error) {
-				poolIPAllocations, err := convert.UnmarshalIPPoolAllocatedIPs(pool.Status.AllocatedIPs)
+				poolIPAllocations, err := sm.store.ListAllocations(ctx, pool)
 				if nil != err {
 					return nil, fmt.Errorf("%w: failed to parse IPPool %s Status AllocatedIPs: %v", constant.ErrWrongInput, pool.Name, err)
 				}
@@ -299,7 +306,7 @@ func (sm *subnetManager) preAllocateIPsFromSubnet(ctx context.Context, subnet *s
 			return subnetPoolAllocation.IPs, nil
 		} else if desiredIPNum < len(subnetPoolIPs) {
 			log.Sugar().Infof("IPPool %s decresed its desired IP number from %d to %d", pool.Name, len(subnetPoolIPs), desiredIPNum)
-			poolIPAllocations, err := convert.UnmarshalIPPoolAllocatedIPs(pool.Status.AllocatedIPs)
+			poolIPAllocations, err := sm.store.ListAllocations(ctx, pool)
 			if nil != err {
 				return nil, fmt.Errorf("%w: failed to parse IPPool %s Status AllocatedIPs: %v", constant.ErrWrongInput, pool.Name, err)
 			}
@@ -0,0 +1,145 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"reflect"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	attrKind      = attribute.Key("k8s.kind")
+	attrNamespace = attribute.Key("k8s.namespace")
+	attrName      = attribute.Key("k8s.name")
+)
+
+type tracedReader struct {
+	client.Reader
+}
+
+// NewReader returns a Reader recording each call in a span.
+func NewReader(reader client.Reader) client.Reader {
+	return &tracedReader{Reader: reader}
+}
+
+func (r *tracedReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) (err error) {
+	ctx, span := startObjectSpan(ctx, "Get", obj, key)
+	defer func() { End(span, err) }()
+
+	return r.Reader.Get(ctx, key, obj, opts...)
+}
+
+func (r *tracedReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) (err error) {
+	ctx, span := startObjectSpan(ctx, "List", list, client.ObjectKey{})
+	defer func() { End(span, err) }()
+
+	return r.Reader.List(ctx, list, opts...)
+}
+
+type tracedClient struct {
+	client.Client
+}
+
+// NewClient returns a Client recording each call to the API server or the
+// informer cache in a span.
+func NewClient(c client.Client) client.Client {
+	return &tracedClient{Client: c}
+}
+
+func (c *tracedClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) (err error) {
+	ctx, span := startObjectSpan(ctx, "Get", obj, key)
+	defer func() { End(span, err) }()
+
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *tracedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) (err error) {
+	ctx, span := startObjectSpan(ctx, "List", list, client.ObjectKey{})
+	defer func() { End(span, err) }()
+
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *tracedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) (err error) {
+	ctx, span := startObjectSpan(ctx, "Create", obj, client.ObjectKeyFromObject(obj))
+	defer func() { End(span, err) }()
+
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *tracedClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) (err error) {
+	ctx, span := startObjectSpan(ctx, "Delete", obj, client.ObjectKeyFromObject(obj))
+	defer func() { End(span, err) }()
+
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *tracedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) (err error) {
+	ctx, span := startObjectSpan(ctx, "Update", obj, client.ObjectKeyFromObject(obj))
+	defer func() { End(span, err) }()
+
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *tracedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) (err error) {
+	ctx, span := startObjectSpan(ctx, "Patch", obj, client.ObjectKeyFromObject(obj))
+	defer func() { End(span, err) }()
+
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *tracedClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) (err error) {
+	ctx, span := startObjectSpan(ctx, "DeleteAllOf", obj, client.ObjectKey{})
+	defer func() { End(span, err) }()
+
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *tracedClient) Status() client.SubResourceWriter {
+	return &tracedStatusWriter{SubResourceWriter: c.Client.Status()}
+}
+
+type tracedStatusWriter struct {
+	client.SubResourceWriter
+}
+
+func (w *tracedStatusWriter) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) (err error) {
+	ctx, span := startObjectSpan(ctx, "CreateStatus", obj, client.ObjectKeyFromObject(obj))
+	defer func() { End(span, err) }()
+
+	return w.SubResourceWriter.Create(ctx, obj, subResource, opts...)
+}
+
+func (w *tracedStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) (err error) {
+	ctx, span := startObjectSpan(ctx, "UpdateStatus", obj, client.ObjectKeyFromObject(obj))
+	defer func() { End(span, err) }()
+
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (w *tracedStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) (err error) {
+	ctx, span := startObjectSpan(ctx, "PatchStatus", obj, client.ObjectKeyFromObject(obj))
+	defer func() { End(span, err) }()
+
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// startObjectSpan starts a span named after the verb and the kind of the
+// object, like 'k8s Update SpiderIPPool'.
+func startObjectSpan(ctx context.Context, verb string, obj interface{}, key client.ObjectKey) (context.Context, trace.Span) {
+	kind := reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	attrs := []attribute.KeyValue{attrKind.String(kind)}
+	if key.Namespace != "" {
+		attrs = append(attrs, attrNamespace.String(key.Namespace))
+	}
+	if key.Name != "" {
+		attrs = append(attrs, attrName.String(key.Name))
+	}
+
+	return Start(ctx, "k8s "+verb+" "+kind, attrs...)
+}
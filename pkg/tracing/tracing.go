@@ -0,0 +1,156 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+)
+
+const instrumentationName = "github.com/spidernet-io/spiderpool"
+
+// CNIShutdownTimeout bounds the flush of the spans in the CNI plugins, so that
+// an unreachable collector never blocks the CNI call.
+const CNIShutdownTimeout = 2 * time.Second
+
+const (
+	// ExporterOTLP exports spans to an OTLP collector over HTTP.
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes spans to stdout as JSON, for local testing.
+	ExporterStdout = "stdout"
+	// ExporterFile appends spans to a file as JSON, for local testing.
+	ExporterFile = "file"
+)
+
+type Config struct {
+	// Exporter is one of ExporterOTLP, ExporterStdout and ExporterFile,
+	// tracing is disabled if it is empty.
+	Exporter string
+	// Endpoint is the 'host:port' of the OTLP collector.
+	Endpoint string
+	// Insecure disables TLS to the OTLP collector.
+	Insecure bool
+	// FilePath is the file spans are appended to.
+	FilePath string
+	// SampleRatio is the ratio of the traces sampled, the traces started
+	// remotely follow the sampling decision of the remote parent.
+	SampleRatio float64
+}
+
+// InitTracing sets up the global tracer provider exporting the spans of the
+// service, and the W3C trace context propagator. With an empty exporter, the
+// spans are not recorded but the remote trace context is still propagated.
+// The returned function flushes the spans and shuts the provider down.
+func InitTracing(ctx context.Context, serviceName string, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.Exporter == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closer, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	otelResource, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceNamespace(constant.SpiderpoolAPIGroup),
+		))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(otelResource),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			if cerr := closer.Close(); err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}, nil
+}
+
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, io.Closer, error) {
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		if cfg.Endpoint == "" {
+			return nil, nil, fmt.Errorf("%w, the endpoint of %s exporter", constant.ErrMissingRequiredParam, cfg.Exporter)
+		}
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		return exporter, nil, err
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		return exporter, nil, err
+	case ExporterFile:
+		if cfg.FilePath == "" {
+			return nil, nil, fmt.Errorf("%w, the file path of %s exporter", constant.ErrMissingRequiredParam, cfg.Exporter)
+		}
+		f, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+		return exporter, f, nil
+	default:
+		return nil, nil, fmt.Errorf("%w, unknown trace exporter '%s', expect '%s', '%s' or '%s'", constant.ErrWrongInput, cfg.Exporter, ExporterOTLP, ExporterStdout, ExporterFile)
+	}
+}
+
+// Start starts a span as the child of the span in ctx. The span not recorded,
+// e.g. tracing is disabled or the trace is not sampled, is not stored in the
+// returned context, so that the context is left intact for the callee.
+func Start(ctx context.Context, spanName string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	spanCtx, span := otel.Tracer(instrumentationName).Start(ctx, spanName, trace.WithAttributes(attrs...))
+	if !span.IsRecording() {
+		return ctx, span
+	}
+
+	return spanCtx, span
+}
+
+// End records the error, if any, as the status of the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
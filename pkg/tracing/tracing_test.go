@@ -0,0 +1,135 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/tracing"
+)
+
+var _ = Describe("Tracing", Label("tracing_test"), func() {
+	var ctx context.Context
+	var recorder *tracetest.SpanRecorder
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+
+		_, err := tracing.InitTracing(ctx, "test", tracing.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		recorder = tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		otel.SetTracerProvider(provider)
+		DeferCleanup(func() {
+			otel.SetTracerProvider(trace.NewNoopTracerProvider())
+		})
+	})
+
+	Describe("InitTracing", func() {
+		It("rejects the invalid configuration", func() {
+			_, err := tracing.InitTracing(ctx, "test", tracing.Config{Exporter: "jaeger"})
+			Expect(err).To(MatchError(constant.ErrWrongInput))
+
+			_, err = tracing.InitTracing(ctx, "test", tracing.Config{Exporter: tracing.ExporterOTLP})
+			Expect(err).To(MatchError(constant.ErrMissingRequiredParam))
+
+			_, err = tracing.InitTracing(ctx, "test", tracing.Config{Exporter: tracing.ExporterFile})
+			Expect(err).To(MatchError(constant.ErrMissingRequiredParam))
+		})
+
+		It("exports the spans to file", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "spans.json")
+			shutdown, err := tracing.InitTracing(ctx, "test", tracing.Config{
+				Exporter:    tracing.ExporterFile,
+				FilePath:    path,
+				SampleRatio: 1,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, span := tracing.Start(ctx, "test span")
+			tracing.End(span, nil)
+			Expect(shutdown(ctx)).To(Succeed())
+
+			data, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"Name":"test span"`))
+		})
+	})
+
+	Describe("End", func() {
+		It("records the error as the status of span", func() {
+			_, span := tracing.Start(ctx, "failed")
+			tracing.End(span, errors.New("boom"))
+
+			spans := recorder.Ended()
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].Status().Code).To(Equal(codes.Error))
+			Expect(spans[0].Status().Description).To(Equal("boom"))
+		})
+	})
+
+	Describe("HTTP", func() {
+		It("propagates the trace context from client to server", func() {
+			var serverTraceID trace.TraceID
+			server := httptest.NewServer(tracing.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				serverTraceID = trace.SpanContextFromContext(r.Context()).TraceID()
+			})))
+			defer server.Close()
+
+			ctx, span := tracing.Start(ctx, "cni ADD")
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/ipam/ip", nil)
+			Expect(err).NotTo(HaveOccurred())
+			resp, err := (&http.Client{Transport: tracing.NewTransport(nil)}).Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+			tracing.End(span, nil)
+
+			Expect(serverTraceID).To(Equal(span.SpanContext().TraceID()))
+
+			var names []string
+			for _, s := range recorder.Ended() {
+				Expect(s.SpanContext().TraceID()).To(Equal(span.SpanContext().TraceID()))
+				names = append(names, s.Name())
+			}
+			Expect(names).To(ConsistOf("POST /ipam/ip", "HTTP POST /ipam/ip", "cni ADD"))
+		})
+	})
+
+	Describe("Client", func() {
+		It("records the calls to API server", func() {
+			ipPool := &spiderpoolv2beta1.SpiderIPPool{ObjectMeta: metav1.ObjectMeta{Name: "pool"}}
+			c := tracing.NewClient(fake.NewClientBuilder().WithScheme(scheme).WithObjects(ipPool).Build())
+
+			Expect(c.Get(ctx, types.NamespacedName{Name: "pool"}, ipPool)).To(Succeed())
+			Expect(c.Status().Update(ctx, ipPool)).To(Succeed())
+			err := c.Get(ctx, types.NamespacedName{Name: "missing"}, ipPool)
+			Expect(err).To(HaveOccurred())
+
+			spans := recorder.Ended()
+			Expect(spans).To(HaveLen(3))
+			Expect(spans[0].Name()).To(Equal("k8s Get SpiderIPPool"))
+			Expect(spans[1].Name()).To(Equal("k8s UpdateStatus SpiderIPPool"))
+			Expect(spans[2].Status().Code).To(Equal(codes.Error))
+		})
+	})
+})
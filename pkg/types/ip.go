@@ -15,6 +15,14 @@ type AllocationResult struct {
 	CleanGateway bool
 }
 
+// Attachment is the attachment of the interface of a Pod sandbox to a CNI
+// network.
+type Attachment struct {
+	ContainerID string
+	NIC         string
+	Network     string
+}
+
 type IPAndUID struct {
 	IP  string
 	UID string
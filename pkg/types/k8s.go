@@ -42,11 +42,59 @@ type AnnoIPPoolItem struct {
 	CleanGateway bool     `json:"cleangateway"`
 }
 
+type AnnoPodStaticIPsValue []AnnoStaticIPItem
+
+// AnnoStaticIPItem specifies the exact IP addresses to allocate to the
+// interface from its IPPools.
+type AnnoStaticIPItem struct {
+	NIC  string `json:"interface"`
+	IPv4 string `json:"ipv4,omitempty"`
+	IPv6 string `json:"ipv6,omitempty"`
+}
+
 type AnnoPodRoutesValue []AnnoRouteItem
 
 type AnnoRouteItem struct {
 	Dst string `json:"dst"`
-	Gw  string `json:"gw"`
+	Gw  string `json:"gw,omitempty"`
+
+	// NIC specifies the interface of the route, otherwise it is assigned to
+	// the interface whose subnet contains the gateway.
+	NIC string `json:"interface,omitempty"`
+
+	// The following fields could not be carried by CNI result, the route
+	// with any of them is installed by coordinator.
+	Metric *int   `json:"metric,omitempty"`
+	Table  *int   `json:"table,omitempty"`
+	Scope  string `json:"scope,omitempty"`
+	// Policy makes the traffic sourced from the IP addresses of the
+	// interface look up the route table of the route.
+	Policy bool `json:"policy,omitempty"`
+}
+
+// IsAdvanced reports whether the route should be installed by coordinator.
+func (in *AnnoRouteItem) IsAdvanced() bool {
+	return in.Metric != nil || in.Table != nil || in.Scope != "" || in.Policy
+}
+
+// AnnoPodCoordinatorValue is the subset of coordinator config which could be
+// overridden by a single Pod.
+type AnnoPodCoordinatorValue struct {
+	DetectGateway      *bool    `json:"detectGateway,omitempty"`
+	DetectIPConflict   *bool    `json:"detectIPConflict,omitempty"`
+	PodDefaultRouteNIC *string  `json:"podDefaultRouteNIC,omitempty"`
+	ExtraCIDR          []string `json:"extraCIDR,omitempty"`
+}
+
+// AnnoPodBandwidthValue limits the rates and marks the DSCP of the
+// interfaces of the Pod, which overrides the bandwidth of SpiderMultusConfig.
+type AnnoPodBandwidthValue []AnnoBandwidthItem
+
+type AnnoBandwidthItem struct {
+	NIC         string `json:"interface"`
+	IngressRate string `json:"ingressRate,omitempty"`
+	EgressRate  string `json:"egressRate,omitempty"`
+	DSCP        *int   `json:"dscp,omitempty"`
 }
 
 type AnnoNSDefautlV4PoolValue []string
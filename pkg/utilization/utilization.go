@@ -0,0 +1,229 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package utilization
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+	"github.com/spidernet-io/spiderpool/pkg/lock"
+)
+
+const (
+	// DefaultWindow is the period the allocation velocity is computed over.
+	DefaultWindow = time.Hour
+
+	// maxSamples bounds the samples kept for one SpiderIPPool or SpiderSubnet,
+	// the oldest samples are dropped once it is exceeded.
+	maxSamples = 256
+)
+
+// Forecast is the allocation velocity of a SpiderIPPool or SpiderSubnet in
+// the window, and the time its free IPs are expected to run out.
+type Forecast struct {
+	// AllocationRate is the IPs allocated per second.
+	AllocationRate float64
+	// ReleaseRate is the IPs released per second.
+	ReleaseRate float64
+	// TimeToExhaustion is negative if the allocated IPs are not growing.
+	TimeToExhaustion time.Duration
+}
+
+type sample struct {
+	time      time.Time
+	allocated int64
+}
+
+type state struct {
+	samples []sample
+	// level is the highest threshold crossed, 0 if none.
+	level int
+}
+
+// Tracker keeps the recent allocated IP counts of the SpiderIPPools or
+// SpiderSubnets, to forecast their exhaustion and detect the utilization
+// thresholds crossed.
+type Tracker struct {
+	window     time.Duration
+	thresholds []int
+
+	lock   lock.Mutex
+	states map[string]*state
+
+	// now is replaced in unit tests.
+	now func() time.Time
+}
+
+// NewTracker returns a Tracker computing the allocation velocity over window,
+// DefaultWindow is used if it is not positive. The thresholds are the
+// utilization percentages to detect, in any order.
+func NewTracker(window time.Duration, thresholds []int) *Tracker {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+
+	sorted := append([]int(nil), thresholds...)
+	sort.Ints(sorted)
+
+	return &Tracker{
+		window:     window,
+		thresholds: sorted,
+		states:     map[string]*state{},
+		now:        time.Now,
+	}
+}
+
+// Observe records the allocated IP count of the object and returns the
+// forecast computed with the samples in the window.
+func (t *Tracker) Observe(key string, allocated, free int64) Forecast {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.now()
+	s := t.getState(key)
+	s.samples = append(s.samples, sample{time: now, allocated: allocated})
+
+	// keep the latest sample before the window as the baseline
+	start := 0
+	for i := range s.samples {
+		if now.Sub(s.samples[i].time) > t.window {
+			start = i
+		}
+	}
+	if len(s.samples)-start > maxSamples {
+		start = len(s.samples) - maxSamples
+	}
+	s.samples = s.samples[start:]
+
+	return forecast(s.samples, free)
+}
+
+// Cross returns the highest threshold crossed upwards since the last call,
+// and false if no new threshold is crossed. The thresholds fall back once the
+// utilization drops below them, so that they can be reported again.
+func (t *Tracker) Cross(key string, total, allocated int64) (int, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	percent := Percent(total, allocated)
+	level := 0
+	for _, threshold := range t.thresholds {
+		if percent >= float64(threshold) {
+			level = threshold
+		}
+	}
+
+	s := t.getState(key)
+	crossed := level > s.level
+	s.level = level
+
+	return level, crossed
+}
+
+// Forget drops the samples of the deleted object.
+func (t *Tracker) Forget(key string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.states, key)
+}
+
+func (t *Tracker) getState(key string) *state {
+	s, ok := t.states[key]
+	if !ok {
+		s = &state{}
+		t.states[key] = s
+	}
+
+	return s
+}
+
+func forecast(samples []sample, free int64) Forecast {
+	f := Forecast{TimeToExhaustion: -1}
+	if len(samples) < 2 {
+		return f
+	}
+
+	var allocated, released int64
+	for i := 1; i < len(samples); i++ {
+		delta := samples[i].allocated - samples[i-1].allocated
+		if delta > 0 {
+			allocated += delta
+		} else {
+			released -= delta
+		}
+	}
+
+	elapsed := samples[len(samples)-1].time.Sub(samples[0].time).Seconds()
+	if elapsed <= 0 {
+		return f
+	}
+
+	f.AllocationRate = float64(allocated) / elapsed
+	f.ReleaseRate = float64(released) / elapsed
+
+	velocity := f.AllocationRate - f.ReleaseRate
+	if velocity > 0 {
+		if free <= 0 {
+			f.TimeToExhaustion = 0
+		} else {
+			f.TimeToExhaustion = time.Duration(float64(free) / velocity * float64(time.Second))
+		}
+	}
+
+	return f
+}
+
+// Seconds returns the seconds before the free IPs run out, -1 if the
+// allocated IPs are not growing.
+func (f Forecast) Seconds() float64 {
+	if f.TimeToExhaustion < 0 {
+		return -1
+	}
+
+	return f.TimeToExhaustion.Seconds()
+}
+
+// Message describes the utilization of the IPs and the forecast, for the
+// events emitted when a threshold is crossed.
+func Message(total, used int64, threshold int, f Forecast) string {
+	msg := fmt.Sprintf("%d of %d IPs (%.0f%%) are in use, crossing the utilization threshold %d%%", used, total, Percent(total, used), threshold)
+	if f.TimeToExhaustion >= 0 {
+		msg += fmt.Sprintf(", the free IPs are expected to run out in %s", f.TimeToExhaustion.Round(time.Second))
+	}
+
+	return msg
+}
+
+// Percent returns the percentage of the allocated IPs.
+func Percent(total, allocated int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+
+	return float64(allocated) * 100 / float64(total)
+}
+
+// ParseThresholds parses a comma separated list of percentages, like '80,95'.
+func ParseThresholds(s string) ([]int, error) {
+	var thresholds []int
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		threshold, err := strconv.Atoi(v)
+		if err != nil || threshold <= 0 || threshold > 100 {
+			return nil, fmt.Errorf("%w: invalid utilization threshold '%s', expect an integer in (0, 100]", constant.ErrWrongInput, v)
+		}
+		thresholds = append(thresholds, threshold)
+	}
+
+	return thresholds, nil
+}
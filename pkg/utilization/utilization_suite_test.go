@@ -0,0 +1,16 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package utilization
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestUtilization(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Utilization Suite", Label("utilization", "unitest"))
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Authors of spidernet-io
+// SPDX-License-Identifier: Apache-2.0
+
+package utilization
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/spidernet-io/spiderpool/pkg/constant"
+)
+
+var _ = Describe("Utilization", Label("utilization_test"), func() {
+	var tracker *Tracker
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		tracker = NewTracker(time.Hour, []int{95, 80})
+		tracker.now = func() time.Time { return now }
+	})
+
+	Describe("Observe", func() {
+		It("does not forecast with a single sample", func() {
+			f := tracker.Observe("pool", 10, 90)
+			Expect(f.AllocationRate).To(BeZero())
+			Expect(f.TimeToExhaustion).To(BeNumerically("<", 0))
+			Expect(f.Seconds()).To(Equal(float64(-1)))
+		})
+
+		It("forecasts the exhaustion with the allocation velocity", func() {
+			tracker.Observe("pool", 10, 90)
+			now = now.Add(10 * time.Minute)
+			tracker.Observe("pool", 40, 60)
+			now = now.Add(10 * time.Minute)
+			f := tracker.Observe("pool", 30, 70)
+
+			Expect(f.AllocationRate).To(BeNumerically("~", 30.0/1200, 1e-9))
+			Expect(f.ReleaseRate).To(BeNumerically("~", 10.0/1200, 1e-9))
+			// 20 IPs per 20 minutes
+			Expect(f.TimeToExhaustion).To(BeNumerically("~", 70*time.Minute, time.Second))
+		})
+
+		It("does not forecast if the allocated IPs are not growing", func() {
+			tracker.Observe("pool", 40, 60)
+			now = now.Add(10 * time.Minute)
+			f := tracker.Observe("pool", 20, 80)
+			Expect(f.ReleaseRate).To(BeNumerically(">", 0))
+			Expect(f.Seconds()).To(Equal(float64(-1)))
+		})
+
+		It("only uses the samples in the window", func() {
+			tracker.Observe("pool", 0, 100)
+			now = now.Add(time.Hour)
+			tracker.Observe("pool", 50, 50)
+			now = now.Add(90 * time.Minute)
+			f := tracker.Observe("pool", 50, 50)
+
+			// the first sample is dropped, the second is the baseline
+			Expect(f.AllocationRate).To(BeZero())
+			Expect(f.Seconds()).To(Equal(float64(-1)))
+		})
+
+		It("forgets the deleted objects", func() {
+			tracker.Observe("pool", 0, 100)
+			tracker.Forget("pool")
+			now = now.Add(time.Minute)
+			f := tracker.Observe("pool", 50, 50)
+			Expect(f.AllocationRate).To(BeZero())
+		})
+	})
+
+	Describe("Cross", func() {
+		It("reports each threshold crossed upwards once", func() {
+			_, crossed := tracker.Cross("pool", 100, 50)
+			Expect(crossed).To(BeFalse())
+
+			threshold, crossed := tracker.Cross("pool", 100, 85)
+			Expect(crossed).To(BeTrue())
+			Expect(threshold).To(Equal(80))
+
+			_, crossed = tracker.Cross("pool", 100, 86)
+			Expect(crossed).To(BeFalse())
+
+			threshold, crossed = tracker.Cross("pool", 100, 100)
+			Expect(crossed).To(BeTrue())
+			Expect(threshold).To(Equal(95))
+
+			_, crossed = tracker.Cross("pool", 100, 10)
+			Expect(crossed).To(BeFalse())
+			threshold, crossed = tracker.Cross("pool", 100, 81)
+			Expect(crossed).To(BeTrue())
+			Expect(threshold).To(Equal(80))
+		})
+	})
+
+	Describe("Message", func() {
+		It("describes the forecast", func() {
+			Expect(Message(100, 82, 80, Forecast{TimeToExhaustion: -1})).To(Equal("82 of 100 IPs (82%) are in use, crossing the utilization threshold 80%"))
+			Expect(Message(100, 82, 80, Forecast{TimeToExhaustion: 90 * time.Minute})).To(HaveSuffix("the free IPs are expected to run out in 1h30m0s"))
+		})
+	})
+
+	Describe("ParseThresholds", func() {
+		It("parses the thresholds", func() {
+			Expect(ParseThresholds(" 80, 95 ")).To(Equal([]int{80, 95}))
+			Expect(ParseThresholds("")).To(BeEmpty())
+		})
+
+		It("rejects the invalid thresholds", func() {
+			_, err := ParseThresholds("80,abc")
+			Expect(err).To(MatchError(constant.ErrWrongInput))
+			_, err = ParseThresholds("101")
+			Expect(err).To(MatchError(constant.ErrWrongInput))
+		})
+	})
+})
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/asaskevich/govalidator"
+	"k8s.io/utils/pointer"
 
 	"github.com/spidernet-io/spiderpool/api/v1/agent/models"
 	"github.com/spidernet-io/spiderpool/pkg/constant"
@@ -22,6 +23,10 @@ func ConvertIPDetailsToIPConfigsAndAllRoutes(details []spiderpoolv2beta1.IPAlloc
 	var routes []*models.Route
 	for _, d := range details {
 		nic := d.NIC
+		var mac string
+		if d.MAC != nil {
+			mac = *d.MAC
+		}
 		if d.IPv4 != nil {
 			version := constant.IPv4
 			var ipv4Gateway string
@@ -33,6 +38,7 @@ func ConvertIPDetailsToIPConfigsAndAllRoutes(details []spiderpoolv2beta1.IPAlloc
 				Address: d.IPv4,
 				Gateway: ipv4Gateway,
 				IPPool:  *d.IPv4Pool,
+				Mac:     mac,
 				Nic:     &nic,
 				Version: &version,
 				Vlan:    *d.Vlan,
@@ -50,6 +56,7 @@ func ConvertIPDetailsToIPConfigsAndAllRoutes(details []spiderpoolv2beta1.IPAlloc
 				Address: d.IPv6,
 				Gateway: ipv6Gateway,
 				IPPool:  *d.IPv6Pool,
+				Mac:     mac,
 				Nic:     &nic,
 				Version: &version,
 				Vlan:    *d.Vlan,
@@ -116,6 +123,12 @@ func ConvertResultsToIPDetails(results []*types.AllocationResult) []spiderpoolv2
 			*cleanGateway = r.CleanGateway
 		}
 
+		var mac *string
+		if r.IP.Mac != "" {
+			mac = new(string)
+			*mac = r.IP.Mac
+		}
+
 		address := *r.IP.Address
 		pool := r.IP.IPPool
 		vlan := r.IP.Vlan
@@ -127,11 +140,18 @@ func ConvertResultsToIPDetails(results []*types.AllocationResult) []spiderpoolv2
 				d.IPv4Pool = &pool
 				d.IPv4Gateway = gateway
 				d.Routes = append(d.Routes, routes...)
+				// The MAC address of the IPv4 address takes precedence.
+				if mac != nil {
+					d.MAC = mac
+				}
 			} else {
 				d.IPv6 = r.IP.Address
 				d.IPv6Pool = &r.IP.IPPool
 				d.IPv6Gateway = gateway
 				d.Routes = append(d.Routes, routes...)
+				if d.MAC == nil {
+					d.MAC = mac
+				}
 			}
 			continue
 		}
@@ -145,6 +165,7 @@ func ConvertResultsToIPDetails(results []*types.AllocationResult) []spiderpoolv2
 				IPv4Gateway:  gateway,
 				CleanGateway: cleanGateway,
 				Routes:       routes,
+				MAC:          mac,
 			}
 		} else {
 			nicToDetail[*r.IP.Nic] = &spiderpoolv2beta1.IPAllocationDetail{
@@ -155,6 +176,7 @@ func ConvertResultsToIPDetails(results []*types.AllocationResult) []spiderpoolv2
 				IPv6Gateway:  gateway,
 				CleanGateway: cleanGateway,
 				Routes:       routes,
+				MAC:          mac,
 			}
 		}
 	}
@@ -170,10 +192,11 @@ func ConvertResultsToIPDetails(results []*types.AllocationResult) []spiderpoolv2
 func ConvertAnnoPodRoutesToOAIRoutes(annoPodRoutes types.AnnoPodRoutesValue) []*models.Route {
 	var routes []*models.Route
 	for _, r := range annoPodRoutes {
+		nic := r.NIC
 		dst := r.Dst
 		gw := r.Gw
 		routes = append(routes, &models.Route{
-			IfName: new(string),
+			IfName: &nic,
 			Dst:    &dst,
 			Gw:     &gw,
 		})
@@ -182,6 +205,45 @@ func ConvertAnnoPodRoutesToOAIRoutes(annoPodRoutes types.AnnoPodRoutesValue) []*
 	return routes
 }
 
+func ConvertAnnoPodRoutesToOAIPodRoutes(annoPodRoutes types.AnnoPodRoutesValue) []*models.PodRoute {
+	var routes []*models.PodRoute
+	for _, r := range annoPodRoutes {
+		route := &models.PodRoute{
+			IfName: r.NIC,
+			Dst:    r.Dst,
+			Gw:     r.Gw,
+			Scope:  r.Scope,
+			Policy: r.Policy,
+		}
+		if r.Metric != nil {
+			route.Metric = pointer.Int64(int64(*r.Metric))
+		}
+		if r.Table != nil {
+			route.Table = pointer.Int64(int64(*r.Table))
+		}
+		routes = append(routes, route)
+	}
+
+	return routes
+}
+
+func ConvertAnnoPodBandwidthToOAIPodBandwidth(annoPodBandwidth types.AnnoPodBandwidthValue) []*models.PodBandwidth {
+	var bandwidth []*models.PodBandwidth
+	for _, b := range annoPodBandwidth {
+		item := &models.PodBandwidth{
+			IfName:      b.NIC,
+			IngressRate: b.IngressRate,
+			EgressRate:  b.EgressRate,
+		}
+		if b.DSCP != nil {
+			item.Dscp = pointer.Int64(int64(*b.DSCP))
+		}
+		bandwidth = append(bandwidth, item)
+	}
+
+	return bandwidth
+}
+
 func ConvertSpecRoutesToOAIRoutes(nic string, specRoutes []spiderpoolv2beta1.Route) []*models.Route {
 	var routes []*models.Route
 	for _, r := range specRoutes {
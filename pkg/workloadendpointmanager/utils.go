@@ -5,6 +5,7 @@ package workloadendpointmanager
 
 import (
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	"github.com/spidernet-io/spiderpool/pkg/types"
 )
 
 func RetrieveIPAllocation(uid, nic string, endpoint *spiderpoolv2beta1.SpiderEndpoint, isSTS bool) *spiderpoolv2beta1.PodIPAllocation {
@@ -22,3 +23,50 @@ func RetrieveIPAllocation(uid, nic string, endpoint *spiderpoolv2beta1.SpiderEnd
 
 	return nil
 }
+
+// RetrieveStaleIPAllocationDetails returns the IP allocation details of the
+// NICs attached to the network, which are not in the valid attachments of the
+// network supplied by CNI GC. The NICs attached to the other networks, or to
+// an unknown network, are left alone.
+func RetrieveStaleIPAllocationDetails(network string, validAttachments map[types.Attachment]struct{}, endpoint *spiderpoolv2beta1.SpiderEndpoint) []spiderpoolv2beta1.IPAllocationDetail {
+	if endpoint == nil || endpoint.Status.Current.ContainerID == "" {
+		return nil
+	}
+
+	var details []spiderpoolv2beta1.IPAllocationDetail
+	for _, d := range endpoint.Status.Current.IPs {
+		if d.Network == nil || *d.Network != network {
+			continue
+		}
+
+		attachment := types.Attachment{
+			ContainerID: endpoint.Status.Current.ContainerID,
+			NIC:         d.NIC,
+			Network:     network,
+		}
+		if _, ok := validAttachments[attachment]; !ok {
+			details = append(details, d)
+		}
+	}
+
+	return details
+}
+
+// setIPAllocationNetwork records the network of the NIC in the IP allocation
+// details, and returns true if any of them is changed.
+func setIPAllocationNetwork(details []spiderpoolv2beta1.IPAllocationDetail, nic, network string) bool {
+	if network == "" {
+		return false
+	}
+
+	changed := false
+	for j := range details {
+		if details[j].NIC != nic || (details[j].Network != nil && *details[j].Network == network) {
+			continue
+		}
+		details[j].Network = &network
+		changed = true
+	}
+
+	return changed
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/spidernet-io/spiderpool/pkg/constant"
 	spiderpoolv2beta1 "github.com/spidernet-io/spiderpool/pkg/k8s/apis/spiderpool.spidernet.io/v2beta1"
+	spiderpooltypes "github.com/spidernet-io/spiderpool/pkg/types"
 	"github.com/spidernet-io/spiderpool/pkg/workloadendpointmanager"
 )
 
@@ -89,4 +90,74 @@ var _ = Describe("WorkloadEndpointManager utils", Label("workloadendpoint_manage
 			Expect(*allocation).To(Equal(allocationT))
 		})
 	})
+
+	Describe("Test RetrieveStaleIPAllocationDetails", func() {
+		var containerID string
+		var network1, network2 string
+		var validAttachments map[spiderpooltypes.Attachment]struct{}
+
+		BeforeEach(func() {
+			containerID = "2f1c9a6d"
+			network1 = "macvlan-ens1"
+			network2 = "macvlan-ens2"
+			validAttachments = map[spiderpooltypes.Attachment]struct{}{}
+
+			endpointT.Status.Current = spiderpoolv2beta1.PodIPAllocation{
+				UID:         string(uuid.NewUUID()),
+				ContainerID: containerID,
+				IPs: []spiderpoolv2beta1.IPAllocationDetail{
+					{
+						NIC:      "eth0",
+						IPv4:     pointer.String("172.18.40.10/24"),
+						IPv4Pool: pointer.String("ipv4-ippool-1"),
+						Network:  pointer.String(network1),
+					},
+					{
+						NIC:      "net1",
+						IPv4:     pointer.String("192.168.40.9/24"),
+						IPv4Pool: pointer.String("ipv4-ippool-2"),
+						Network:  pointer.String(network2),
+					},
+				},
+			}
+		})
+
+		It("inputs nil Endpoint", func() {
+			details := workloadendpointmanager.RetrieveStaleIPAllocationDetails(network1, validAttachments, nil)
+			Expect(details).To(BeEmpty())
+		})
+
+		It("leaves the Endpoint without container ID alone", func() {
+			endpointT.Status.Current.ContainerID = ""
+
+			details := workloadendpointmanager.RetrieveStaleIPAllocationDetails(network1, validAttachments, endpointT)
+			Expect(details).To(BeEmpty())
+		})
+
+		It("retrieves nothing if the attachment of the network is valid", func() {
+			validAttachments[spiderpooltypes.Attachment{ContainerID: containerID, NIC: "eth0", Network: network1}] = struct{}{}
+
+			details := workloadendpointmanager.RetrieveStaleIPAllocationDetails(network1, validAttachments, endpointT)
+			Expect(details).To(BeEmpty())
+		})
+
+		It("only retrieves the NIC attached to the network being collected", func() {
+			// The valid attachments of network2 do not contain the NIC of
+			// network1, which must not be collected by the GC of network2.
+			validAttachments[spiderpooltypes.Attachment{ContainerID: "7b3e0c45", NIC: "net1", Network: network2}] = struct{}{}
+
+			details := workloadendpointmanager.RetrieveStaleIPAllocationDetails(network2, validAttachments, endpointT)
+			Expect(details).To(Equal(endpointT.Status.Current.IPs[1:]))
+
+			details = workloadendpointmanager.RetrieveStaleIPAllocationDetails(network1, map[spiderpooltypes.Attachment]struct{}{}, endpointT)
+			Expect(details).To(Equal(endpointT.Status.Current.IPs[:1]))
+		})
+
+		It("leaves the NIC of unknown network alone", func() {
+			endpointT.Status.Current.IPs[1].Network = nil
+
+			details := workloadendpointmanager.RetrieveStaleIPAllocationDetails(network2, validAttachments, endpointT)
+			Expect(details).To(BeEmpty())
+		})
+	})
 })
@@ -24,8 +24,10 @@ type WorkloadEndpointManager interface {
 	ListEndpoints(ctx context.Context, cached bool, opts ...client.ListOption) (*spiderpoolv2beta1.SpiderEndpointList, error)
 	DeleteEndpoint(ctx context.Context, endpoint *spiderpoolv2beta1.SpiderEndpoint) error
 	RemoveFinalizer(ctx context.Context, endpoint *spiderpoolv2beta1.SpiderEndpoint) error
-	PatchIPAllocationResults(ctx context.Context, results []*types.AllocationResult, endpoint *spiderpoolv2beta1.SpiderEndpoint, pod *corev1.Pod, podController types.PodTopController) error
+	PatchIPAllocationResults(ctx context.Context, attachment types.Attachment, results []*types.AllocationResult, endpoint *spiderpoolv2beta1.SpiderEndpoint, pod *corev1.Pod, podController types.PodTopController) error
 	ReallocateCurrentIPAllocation(ctx context.Context, uid, nodeName string, endpoint *spiderpoolv2beta1.SpiderEndpoint) error
+	UpdateAttachment(ctx context.Context, attachment types.Attachment, endpoint *spiderpoolv2beta1.SpiderEndpoint) error
+	RemoveNICIPAllocation(ctx context.Context, nic string, endpoint *spiderpoolv2beta1.SpiderEndpoint) error
 }
 
 type workloadEndpointManager struct {
@@ -102,11 +104,14 @@ func (em *workloadEndpointManager) RemoveFinalizer(ctx context.Context, endpoint
 	return nil
 }
 
-func (em *workloadEndpointManager) PatchIPAllocationResults(ctx context.Context, results []*types.AllocationResult, endpoint *spiderpoolv2beta1.SpiderEndpoint, pod *corev1.Pod, podController types.PodTopController) error {
+func (em *workloadEndpointManager) PatchIPAllocationResults(ctx context.Context, attachment types.Attachment, results []*types.AllocationResult, endpoint *spiderpoolv2beta1.SpiderEndpoint, pod *corev1.Pod, podController types.PodTopController) error {
 	if pod == nil {
 		return fmt.Errorf("pod %w", constant.ErrMissingRequiredParam)
 	}
 
+	details := convert.ConvertResultsToIPDetails(results)
+	setIPAllocationNetwork(details, attachment.NIC, attachment.Network)
+
 	if endpoint == nil {
 		endpoint = &spiderpoolv2beta1.SpiderEndpoint{
 			ObjectMeta: metav1.ObjectMeta{
@@ -115,9 +120,10 @@ func (em *workloadEndpointManager) PatchIPAllocationResults(ctx context.Context,
 			},
 			Status: spiderpoolv2beta1.WorkloadEndpointStatus{
 				Current: spiderpoolv2beta1.PodIPAllocation{
-					UID:  string(pod.UID),
-					Node: pod.Spec.NodeName,
-					IPs:  convert.ConvertResultsToIPDetails(results),
+					UID:         string(pod.UID),
+					Node:        pod.Spec.NodeName,
+					ContainerID: attachment.ContainerID,
+					IPs:         details,
 				},
 				OwnerControllerType: podController.Kind,
 				OwnerControllerName: podController.Name,
@@ -128,7 +134,16 @@ func (em *workloadEndpointManager) PatchIPAllocationResults(ctx context.Context,
 		// controlled by StatefulSet. Once the Pod of StatefulSet is recreated,
 		// we can immediately retrieve the old IP allocation results from the
 		// Endpoint without worrying about the cascading deletion of the Endpoint.
-		if podController.Kind != constant.KindStatefulSet {
+		//
+		// The standalone container has no Pod, its Endpoint inherits the
+		// owner label and the netns of the made-up Pod, and is deleted when
+		// the container is torn down.
+		switch podController.Kind {
+		case constant.KindStatefulSet:
+		case constant.KindStandalone:
+			endpoint.Labels = pod.Labels
+			endpoint.Annotations = pod.Annotations
+		default:
 			if err := controllerutil.SetOwnerReference(pod, endpoint, em.client.Scheme()); err != nil {
 				return err
 			}
@@ -142,7 +157,25 @@ func (em *workloadEndpointManager) PatchIPAllocationResults(ctx context.Context,
 	}
 
 	// TODO(iiiceoo): Only append records with different NIC.
-	endpoint.Status.Current.IPs = append(endpoint.Status.Current.IPs, convert.ConvertResultsToIPDetails(results)...)
+	endpoint.Status.Current.IPs = append(endpoint.Status.Current.IPs, details...)
+	endpoint.Status.Current.ContainerID = attachment.ContainerID
+	return em.client.Update(ctx, endpoint)
+}
+
+// UpdateAttachment records the Pod sandbox which reuses the current IP
+// allocation, such as the recreated sandbox of the Pod, and the network of
+// the NIC whose IP addresses are allocated along with the other NICs.
+func (em *workloadEndpointManager) UpdateAttachment(ctx context.Context, attachment types.Attachment, endpoint *spiderpoolv2beta1.SpiderEndpoint) error {
+	if endpoint == nil {
+		return fmt.Errorf("endpoint %w", constant.ErrMissingRequiredParam)
+	}
+
+	changed := setIPAllocationNetwork(endpoint.Status.Current.IPs, attachment.NIC, attachment.Network)
+	if endpoint.Status.Current.ContainerID == attachment.ContainerID && !changed {
+		return nil
+	}
+	endpoint.Status.Current.ContainerID = attachment.ContainerID
+
 	return em.client.Update(ctx, endpoint)
 }
 
@@ -160,3 +193,23 @@ func (em *workloadEndpointManager) ReallocateCurrentIPAllocation(ctx context.Con
 
 	return em.client.Update(ctx, endpoint)
 }
+
+func (em *workloadEndpointManager) RemoveNICIPAllocation(ctx context.Context, nic string, endpoint *spiderpoolv2beta1.SpiderEndpoint) error {
+	if endpoint == nil {
+		return fmt.Errorf("endpoint %w", constant.ErrMissingRequiredParam)
+	}
+
+	var details []spiderpoolv2beta1.IPAllocationDetail
+	for _, d := range endpoint.Status.Current.IPs {
+		if d.NIC != nic {
+			details = append(details, d)
+		}
+	}
+
+	if len(details) == len(endpoint.Status.Current.IPs) {
+		return nil
+	}
+	endpoint.Status.Current.IPs = details
+
+	return em.client.Update(ctx, endpoint)
+}
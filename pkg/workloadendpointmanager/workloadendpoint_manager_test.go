@@ -298,9 +298,15 @@ var _ = Describe("WorkloadEndpointManager", Label("workloadendpoint_manager_test
 		})
 
 		Describe("PatchIPAllocationResults", func() {
+			var attachment spiderpooltypes.Attachment
 			var podT *corev1.Pod
 
 			BeforeEach(func() {
+				attachment = spiderpooltypes.Attachment{
+					ContainerID: "2f1c9a6d",
+					NIC:         "eth0",
+					Network:     "macvlan-ens1",
+				}
 				podT = &corev1.Pod{
 					TypeMeta: metav1.TypeMeta{
 						Kind:       "Pod",
@@ -318,7 +324,7 @@ var _ = Describe("WorkloadEndpointManager", Label("workloadendpoint_manager_test
 			})
 
 			It("inputs nil Pod", func() {
-				err := endpointManager.PatchIPAllocationResults(ctx, []*spiderpooltypes.AllocationResult{}, nil, nil, spiderpooltypes.PodTopController{})
+				err := endpointManager.PatchIPAllocationResults(ctx, attachment, []*spiderpooltypes.AllocationResult{}, nil, nil, spiderpooltypes.PodTopController{})
 				Expect(err).To(MatchError(constant.ErrMissingRequiredParam))
 			})
 
@@ -326,7 +332,7 @@ var _ = Describe("WorkloadEndpointManager", Label("workloadendpoint_manager_test
 				patches := gomonkey.ApplyFuncReturn(controllerutil.SetOwnerReference, constant.ErrUnknown)
 				defer patches.Reset()
 
-				err := endpointManager.PatchIPAllocationResults(ctx, []*spiderpooltypes.AllocationResult{}, nil, podT, spiderpooltypes.PodTopController{})
+				err := endpointManager.PatchIPAllocationResults(ctx, attachment, []*spiderpooltypes.AllocationResult{}, nil, podT, spiderpooltypes.PodTopController{})
 				Expect(err).To(MatchError(constant.ErrUnknown))
 			})
 
@@ -334,13 +340,14 @@ var _ = Describe("WorkloadEndpointManager", Label("workloadendpoint_manager_test
 				patches := gomonkey.ApplyMethodReturn(fakeClient, "Create", constant.ErrUnknown)
 				defer patches.Reset()
 
-				err := endpointManager.PatchIPAllocationResults(ctx, []*spiderpooltypes.AllocationResult{}, nil, podT, spiderpooltypes.PodTopController{})
+				err := endpointManager.PatchIPAllocationResults(ctx, attachment, []*spiderpooltypes.AllocationResult{}, nil, podT, spiderpooltypes.PodTopController{})
 				Expect(err).To(MatchError(constant.ErrUnknown))
 			})
 
 			It("creates Endpoint for orphan Pod", func() {
 				err := endpointManager.PatchIPAllocationResults(
 					ctx,
+					attachment,
 					[]*spiderpooltypes.AllocationResult{},
 					nil,
 					podT,
@@ -363,12 +370,14 @@ var _ = Describe("WorkloadEndpointManager", Label("workloadendpoint_manager_test
 
 				owner := endpoint.GetOwnerReferences()[0]
 				Expect(owner.UID).To(Equal(podT.GetUID()))
+				Expect(endpoint.Status.Current.ContainerID).To(Equal(attachment.ContainerID))
 				Expect(controllerutil.ContainsFinalizer(&endpoint, constant.SpiderFinalizer))
 			})
 
 			It("creates Endpoint for StatefulSet Pod", func() {
 				err := endpointManager.PatchIPAllocationResults(
 					ctx,
+					attachment,
 					[]*spiderpooltypes.AllocationResult{},
 					nil,
 					podT,
@@ -394,11 +403,41 @@ var _ = Describe("WorkloadEndpointManager", Label("workloadendpoint_manager_test
 				Expect(controllerutil.ContainsFinalizer(&endpoint, constant.SpiderFinalizer))
 			})
 
+			It("creates Endpoint for standalone container", func() {
+				podT.Labels = map[string]string{constant.LabelStandaloneOwner: "nerdctl"}
+				podT.Annotations = map[string]string{constant.AnnoStandaloneNetns: "/var/run/netns/cni-1"}
+				err := endpointManager.PatchIPAllocationResults(
+					ctx,
+					attachment,
+					[]*spiderpooltypes.AllocationResult{},
+					nil,
+					podT,
+					spiderpooltypes.PodTopController{
+						AppNamespacedName: spiderpooltypes.AppNamespacedName{
+							Kind:      constant.KindStandalone,
+							Namespace: namespace,
+							Name:      "nerdctl",
+						},
+						UID: podT.UID,
+					},
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				var endpoint spiderpoolv2beta1.SpiderEndpoint
+				err = fakeClient.Get(ctx, types.NamespacedName{Namespace: podT.Namespace, Name: podT.Name}, &endpoint)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(endpoint.GetOwnerReferences()).To(BeEmpty())
+				Expect(endpoint.Labels).To(HaveKeyWithValue(constant.LabelStandaloneOwner, "nerdctl"))
+				Expect(endpoint.Annotations).To(HaveKeyWithValue(constant.AnnoStandaloneNetns, "/var/run/netns/cni-1"))
+				Expect(endpoint.Status.OwnerControllerType).To(Equal(constant.KindStandalone))
+			})
+
 			It("patches IP allocation results with different Pod UID", func() {
 				podT.SetUID(uuid.NewUUID())
 				endpointT.Status.Current.UID = string(uuid.NewUUID())
 
-				err := endpointManager.PatchIPAllocationResults(ctx, []*spiderpooltypes.AllocationResult{}, endpointT, podT, spiderpooltypes.PodTopController{})
+				err := endpointManager.PatchIPAllocationResults(ctx, attachment, []*spiderpooltypes.AllocationResult{}, endpointT, podT, spiderpooltypes.PodTopController{})
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -410,7 +449,7 @@ var _ = Describe("WorkloadEndpointManager", Label("workloadendpoint_manager_test
 				podT.SetUID(uid)
 				endpointT.Status.Current.UID = string(uid)
 
-				err := endpointManager.PatchIPAllocationResults(ctx, []*spiderpooltypes.AllocationResult{}, endpointT, podT, spiderpooltypes.PodTopController{})
+				err := endpointManager.PatchIPAllocationResults(ctx, attachment, []*spiderpooltypes.AllocationResult{}, endpointT, podT, spiderpooltypes.PodTopController{})
 				Expect(err).To(MatchError(constant.ErrUnknown))
 			})
 
@@ -456,5 +495,124 @@ var _ = Describe("WorkloadEndpointManager", Label("workloadendpoint_manager_test
 				Expect(endpointT.Status.Current.Node).To(Equal(nodeName))
 			})
 		})
+
+		Describe("UpdateAttachment", func() {
+			var attachment spiderpooltypes.Attachment
+
+			BeforeEach(func() {
+				attachment = spiderpooltypes.Attachment{
+					ContainerID: "2f1c9a6d",
+					NIC:         "net1",
+					Network:     "macvlan-ens2",
+				}
+				endpointT.Status.Current.IPs = []spiderpoolv2beta1.IPAllocationDetail{
+					{
+						NIC:     "eth0",
+						IPv4:    pointer.String("172.18.40.10/24"),
+						Network: pointer.String("macvlan-ens1"),
+					},
+					{
+						NIC:  "net1",
+						IPv4: pointer.String("192.168.40.9/24"),
+					},
+				}
+			})
+
+			It("inputs nil Endpoint", func() {
+				err := endpointManager.UpdateAttachment(ctx, attachment, nil)
+				Expect(err).To(MatchError(constant.ErrMissingRequiredParam))
+			})
+
+			It("updates the same attachment", func() {
+				patches := gomonkey.ApplyMethodReturn(fakeClient, "Update", constant.ErrUnknown)
+				defer patches.Reset()
+
+				endpointT.Status.Current.ContainerID = attachment.ContainerID
+				endpointT.Status.Current.IPs[1].Network = pointer.String(attachment.Network)
+
+				err := endpointManager.UpdateAttachment(ctx, attachment, endpointT)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("updates the container ID of the recreated Pod sandbox", func() {
+				endpointT.Status.Current.ContainerID = attachment.ContainerID
+				endpointT.Status.Current.IPs[1].Network = pointer.String(attachment.Network)
+
+				err := fakeClient.Create(ctx, endpointT)
+				Expect(err).NotTo(HaveOccurred())
+
+				attachment.ContainerID = "7b3e0c45"
+				err = endpointManager.UpdateAttachment(ctx, attachment, endpointT)
+				Expect(err).NotTo(HaveOccurred())
+
+				var endpoint spiderpoolv2beta1.SpiderEndpoint
+				err = fakeClient.Get(ctx, types.NamespacedName{Namespace: endpointT.Namespace, Name: endpointT.Name}, &endpoint)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(endpoint.Status.Current.ContainerID).To(Equal("7b3e0c45"))
+			})
+
+			It("records the network of the NIC allocated along with the other NICs", func() {
+				endpointT.Status.Current.ContainerID = attachment.ContainerID
+
+				err := fakeClient.Create(ctx, endpointT)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = endpointManager.UpdateAttachment(ctx, attachment, endpointT)
+				Expect(err).NotTo(HaveOccurred())
+
+				var endpoint spiderpoolv2beta1.SpiderEndpoint
+				err = fakeClient.Get(ctx, types.NamespacedName{Namespace: endpointT.Namespace, Name: endpointT.Name}, &endpoint)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(endpoint.Status.Current.IPs[0].Network).To(Equal(pointer.String("macvlan-ens1")))
+				Expect(endpoint.Status.Current.IPs[1].Network).To(Equal(pointer.String(attachment.Network)))
+			})
+		})
+
+		Describe("RemoveNICIPAllocation", func() {
+			BeforeEach(func() {
+				endpointT.Status.Current.UID = string(uuid.NewUUID())
+				endpointT.Status.Current.IPs = []spiderpoolv2beta1.IPAllocationDetail{
+					{
+						NIC:      "eth0",
+						IPv4:     pointer.String("172.18.40.10/24"),
+						IPv4Pool: pointer.String("default-ipv4-ippool"),
+					},
+					{
+						NIC:      "net1",
+						IPv4:     pointer.String("172.18.41.10/24"),
+						IPv4Pool: pointer.String("net1-ipv4-ippool"),
+					},
+				}
+			})
+
+			It("inputs nil Endpoint", func() {
+				err := endpointManager.RemoveNICIPAllocation(ctx, "eth0", nil)
+				Expect(err).To(MatchError(constant.ErrMissingRequiredParam))
+			})
+
+			It("removes the IP allocation of non-existent NIC", func() {
+				err := endpointManager.RemoveNICIPAllocation(ctx, "net2", endpointT)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(endpointT.Status.Current.IPs).To(HaveLen(2))
+			})
+
+			It("failed to update the status of Endpoint due to some unknown errors", func() {
+				patches := gomonkey.ApplyMethodReturn(fakeClient, "Update", constant.ErrUnknown)
+				defer patches.Reset()
+
+				err := endpointManager.RemoveNICIPAllocation(ctx, "net1", endpointT)
+				Expect(err).To(MatchError(constant.ErrUnknown))
+			})
+
+			It("removes the IP allocation of the NIC", func() {
+				err := fakeClient.Create(ctx, endpointT)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = endpointManager.RemoveNICIPAllocation(ctx, "net1", endpointT)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(endpointT.Status.Current.IPs).To(HaveLen(1))
+				Expect(endpointT.Status.Current.IPs[0].NIC).To(Equal("eth0"))
+			})
+		})
 	})
 })
@@ -147,6 +147,11 @@ elif [ "$TYPE"x == "detail"x ] ; then
     echo "-------- kubectl get spiderendpoint -o json "
     kubectl get spiderendpoint -A -o json --kubeconfig ${E2E_KUBECONFIG}
 
+    echo ""
+    echo "=============== spiderpool crd spideripclaims ============== "
+    echo "-------- kubectl get spideripclaims -o wide "
+    kubectl get spideripclaims -o wide --kubeconfig ${E2E_KUBECONFIG}
+
     echo ""
     echo "=============== spiderpool crd spiderreservedips ============== "
     echo "-------- kubectl get spiderreservedips -o wide "
@@ -14,5 +14,6 @@ done <<< "$ALL_EP_INFO"
 
 kubectl delete crd spiderendpoints.spiderpool.spidernet.io
 kubectl delete crd spiderippools.spiderpool.spidernet.io
+kubectl delete crd spideripclaims.spiderpool.spidernet.io
 kubectl delete crd spiderreservedips.spiderpool.spidernet.io
 kubectl delete crd spidersubnets.spiderpool.spidernet.io